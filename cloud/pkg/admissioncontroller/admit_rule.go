@@ -5,9 +5,11 @@ import (
 	"net/http"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
 	rulesv1 "github.com/kubeedge/api/apis/rules/v1"
+	routerutils "github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
 )
 
 var (
@@ -15,6 +17,12 @@ var (
 		{rulesv1.RuleEndpointTypeRest, rulesv1.RuleEndpointTypeEventBus},
 		{rulesv1.RuleEndpointTypeRest, rulesv1.RuleEndpointTypeServiceBus},
 		{rulesv1.RuleEndpointTypeEventBus, rulesv1.RuleEndpointTypeRest},
+		{rulesv1.RuleEndpointTypeEventBus, rulesv1.RuleEndpointTypeKafka},
+		{rulesv1.RuleEndpointTypeEventBus, rulesv1.RuleEndpointTypeNATS},
+		{rulesv1.RuleEndpointTypeRest, rulesv1.RuleEndpointTypeKafka},
+		{rulesv1.RuleEndpointTypeRest, rulesv1.RuleEndpointTypeNATS},
+		{rulesv1.RuleEndpointTypeKafka, rulesv1.RuleEndpointTypeEventBus},
+		{rulesv1.RuleEndpointTypeNATS, rulesv1.RuleEndpointTypeEventBus},
 	}
 )
 
@@ -48,6 +56,12 @@ func admitRule(review admissionv1.AdmissionReview) *admissionv1.AdmissionRespons
 }
 
 func validateRule(rule *rulesv1.Rule) error {
+	if err := validateRuleFilterAndTransform(rule); err != nil {
+		return err
+	}
+	if err := validateRateLimit(rule.Spec.RateLimit); err != nil {
+		return err
+	}
 	sourceKey := fmt.Sprintf("%s/%s", rule.Namespace, rule.Spec.Source)
 	sourceEndpoint, err := controller.getRuleEndpoint(rule.Namespace, rule.Spec.Source)
 	if err != nil {
@@ -68,19 +82,85 @@ func validateRule(rule *rulesv1.Rule) error {
 	if err = validateTargetRuleEndpoint(targetEndpoint, rule.Spec.TargetResource); err != nil {
 		return err
 	}
-	var exist bool
+	if !isValidSourceToTarget(sourceEndpoint.Spec.RuleEndpointType, targetEndpoint.Spec.RuleEndpointType) {
+		return fmt.Errorf("the rule which is from source ruleEndpoint type %s to target ruleEndpoint type %s is not validate ",
+			sourceEndpoint.Spec.RuleEndpointType, targetEndpoint.Spec.RuleEndpointType)
+	}
+	return validateRuleDelivery(rule, sourceEndpoint)
+}
+
+func isValidSourceToTarget(source, target rulesv1.RuleEndpointTypeDef) bool {
 	for _, s2t := range sourceToTarget {
-		if s2t[0] == sourceEndpoint.Spec.RuleEndpointType && s2t[1] == targetEndpoint.Spec.RuleEndpointType {
-			exist = true
-			break
+		if s2t[0] == source && s2t[1] == target {
+			return true
 		}
 	}
-	if !exist {
-		return fmt.Errorf("the rule which is from source ruleEndpoint type %s to target ruleEndpoint type %s is not validate ",
-			sourceEndpoint.Spec.RuleEndpointType, targetEndpoint.Spec.RuleEndpointType)
+	return false
+}
+
+// validateRuleDelivery checks the retry policy and the dead-letter target of rule
+func validateRuleDelivery(rule *rulesv1.Rule, sourceEndpoint *rulesv1.RuleEndpoint) error {
+	delivery := rule.Spec.Delivery
+	if delivery == nil {
+		return nil
+	}
+	switch delivery.Mode {
+	case "", rulesv1.RuleDeliveryAtMostOnce, rulesv1.RuleDeliveryAtLeastOnce:
+	default:
+		return fmt.Errorf("unsupported delivery mode %q", delivery.Mode)
+	}
+	if delivery.MaxRetries < 0 {
+		return fmt.Errorf("delivery maxRetries can not be negative")
+	}
+	for name, d := range map[string]*metav1.Duration{
+		"initialBackoff": delivery.InitialBackoff,
+		"maxBackoff":     delivery.MaxBackoff,
+		"ackTimeout":     delivery.AckTimeout,
+	} {
+		if d != nil && d.Duration <= 0 {
+			return fmt.Errorf("delivery %s should be positive", name)
+		}
+	}
+	if delivery.InitialBackoff != nil && delivery.MaxBackoff != nil &&
+		delivery.MaxBackoff.Duration < delivery.InitialBackoff.Duration {
+		return fmt.Errorf("delivery maxBackoff can not be less than initialBackoff")
+	}
+
+	if delivery.DeadLetter == nil {
+		return nil
+	}
+	deadLetterKey := fmt.Sprintf("%s/%s", rule.Namespace, delivery.DeadLetter.Target)
+	deadLetterEndpoint, err := controller.getRuleEndpoint(rule.Namespace, delivery.DeadLetter.Target)
+	if err != nil {
+		return fmt.Errorf("can't get dead-letter ruleEndpoint %s. Reason: %w", deadLetterKey, err)
+	} else if deadLetterEndpoint == nil {
+		return fmt.Errorf("dead-letter ruleEndpoint %s has not been created", deadLetterKey)
+	}
+	if err = validateTargetRuleEndpoint(deadLetterEndpoint, delivery.DeadLetter.TargetResource); err != nil {
+		return fmt.Errorf("invalid dead-letter target: %w", err)
+	}
+	if !isValidSourceToTarget(sourceEndpoint.Spec.RuleEndpointType, deadLetterEndpoint.Spec.RuleEndpointType) {
+		return fmt.Errorf("the dead-letter target ruleEndpoint type %s is not validate for source ruleEndpoint type %s",
+			deadLetterEndpoint.Spec.RuleEndpointType, sourceEndpoint.Spec.RuleEndpointType)
+	}
+	return nil
+}
+
+// validateRuleFilterAndTransform compiles the filter and transform of rule as the router does
+func validateRuleFilterAndTransform(rule *rulesv1.Rule) error {
+	if rule.Spec.Filter != "" {
+		if _, err := routerutils.NewMessageFilter(rule.Spec.Filter); err != nil {
+			return err
+		}
+	}
+	if rule.Spec.Transform != nil {
+		if _, err := routerutils.NewTransformer(rule.Spec.Transform); err != nil {
+			return fmt.Errorf("invalid transform: %v", err)
+		}
 	}
 	return nil
 }
+
 func validateSourceRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint, sourceResource map[string]string) error {
 	switch ruleEndpoint.Spec.RuleEndpointType {
 	case rulesv1.RuleEndpointTypeRest:
@@ -115,6 +195,30 @@ func validateSourceRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint, sourceResour
 				return fmt.Errorf("source properties exist in Rule %s/%s. Node_name: %s, topic: %s", r.Namespace, r.Name, sourceResource["node_name"], sourceResource["topic"])
 			}
 		}
+	case rulesv1.RuleEndpointTypeKafka:
+		if _, exist := sourceResource["topic"]; !exist {
+			return fmt.Errorf("\"topic\" property missed in sourceResource when ruleEndpoint is \"kafka\"")
+		}
+		if _, exist := sourceResource["node_name"]; !exist {
+			return fmt.Errorf("\"node_name\" property missed in sourceResource when ruleEndpoint is \"kafka\"")
+		}
+	case rulesv1.RuleEndpointTypeNATS:
+		if _, exist := sourceResource["subject"]; !exist {
+			return fmt.Errorf("\"subject\" property missed in sourceResource when ruleEndpoint is \"nats\"")
+		}
+		if _, exist := sourceResource["node_name"]; !exist {
+			return fmt.Errorf("\"node_name\" property missed in sourceResource when ruleEndpoint is \"nats\"")
+		}
+	case rulesv1.RuleEndpointTypeServiceBus:
+		if _, exist := sourceResource["target_url"]; !exist {
+			return fmt.Errorf("\"target_url\" property missed in sourceResource when ruleEndpoint is \"servicebus\"")
+		}
+		if _, exist := sourceResource["node_name"]; !exist {
+			return fmt.Errorf("\"node_name\" property missed in sourceResource when ruleEndpoint is \"servicebus\"")
+		}
+		if _, err := routerutils.ParseServiceAccounts(sourceResource["service_accounts"]); err != nil {
+			return fmt.Errorf("\"service_accounts\" property in sourceResource is invalid: %v", err)
+		}
 	}
 	return nil
 }
@@ -136,6 +240,63 @@ func validateTargetRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint, targetResour
 		if !exist {
 			return fmt.Errorf("\"path\" property missed in targetResource when ruleEndpoint is \"servicebus\"")
 		}
+		return validateServiceBusTarget(ruleEndpoint, targetResource)
+	case rulesv1.RuleEndpointTypeKafka:
+		if _, exist := targetResource["topic"]; !exist {
+			return fmt.Errorf("\"topic\" property missed in targetResource when ruleEndpoint is \"kafka\"")
+		}
+		return validateTargetTemplates(targetResource, "topic", "key")
+	case rulesv1.RuleEndpointTypeNATS:
+		if _, exist := targetResource["subject"]; !exist {
+			return fmt.Errorf("\"subject\" property missed in targetResource when ruleEndpoint is \"nats\"")
+		}
+		return validateTargetTemplates(targetResource, "subject")
+	}
+	return nil
+}
+
+// validateServiceBusTarget checks the edge service of servicebus target is allowed by ruleendpoint
+func validateServiceBusTarget(ruleEndpoint *rulesv1.RuleEndpoint, targetResource map[string]string) error {
+	port := ruleEndpoint.Spec.Properties["service_port"]
+	if p, exist := targetResource["port"]; exist {
+		if err := routerutils.ValidatePort(p); err != nil {
+			return fmt.Errorf("invalid \"port\" in targetResource: %v", err)
+		}
+		port = p
+	}
+	service, exist := targetResource["service"]
+	if !exist {
+		return nil
+	}
+	target, err := routerutils.ParseServiceTarget(service)
+	if err != nil {
+		return err
+	}
+	target.Port = port
+	allowed, err := routerutils.ParseAllowedTargets(ruleEndpoint.Spec.Properties[routerutils.PropertyAllowedTargets])
+	if err != nil {
+		return err
+	}
+	if !routerutils.IsServiceAllowed(allowed, target) {
+		return fmt.Errorf("service %s is not allowed by \"%s\" property of ruleEndpoint %s/%s",
+			target, routerutils.PropertyAllowedTargets, ruleEndpoint.Namespace, ruleEndpoint.Name)
+	}
+	return nil
+}
+
+// validateTargetTemplates checks the templates of target resource used by kafka and nats
+func validateTargetTemplates(targetResource map[string]string, keys ...string) error {
+	for _, key := range keys {
+		value, exist := targetResource[key]
+		if !exist {
+			continue
+		}
+		if _, err := routerutils.ParseTemplate(key, value); err != nil {
+			return fmt.Errorf("invalid template of %q in targetResource: %v", key, err)
+		}
+	}
+	if _, err := routerutils.ParseHeaderTemplates(targetResource); err != nil {
+		return fmt.Errorf("invalid headers in targetResource: %v", err)
 	}
 	return nil
 }
@@ -0,0 +1,125 @@
+package admissioncontroller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rulesv1 "github.com/kubeedge/api/apis/rules/v1"
+)
+
+func Test_validateRuleFilterAndTransform(t *testing.T) {
+	cases := []struct {
+		name    string
+		spec    rulesv1.RuleSpec
+		allowed bool
+	}{
+		{"no filter and transform", rulesv1.RuleSpec{}, true},
+		{"valid filter", rulesv1.RuleSpec{Filter: `payload.temperature > 30.0`}, true},
+		{"invalid filter", rulesv1.RuleSpec{Filter: `payload.temperature >`}, false},
+		{"non bool filter", rulesv1.RuleSpec{Filter: `topic`}, false},
+		{"valid transform", rulesv1.RuleSpec{Transform: &rulesv1.RuleTransform{
+			Extract: "$.data", Mapping: map[string]string{"t": "$.temperature"},
+			Envelope: &rulesv1.RuleEnvelope{Fields: map[string]string{"node": "{{.NodeName}}"}}}}, true},
+		{"invalid transform path", rulesv1.RuleSpec{Transform: &rulesv1.RuleTransform{Extract: "$.data[a]"}}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRuleFilterAndTransform(&rulesv1.Rule{Spec: tc.spec})
+			if tc.allowed && err != nil {
+				t.Fatalf("expect rule allowed, got error: %v", err)
+			}
+			if !tc.allowed && err == nil {
+				t.Fatalf("expect rule denied")
+			}
+		})
+	}
+}
+
+func Test_validateRuleDelivery(t *testing.T) {
+	source := &rulesv1.RuleEndpoint{Spec: rulesv1.RuleEndpointSpec{RuleEndpointType: rulesv1.RuleEndpointTypeEventBus}}
+	second := &metav1.Duration{Duration: time.Second}
+	minute := &metav1.Duration{Duration: time.Minute}
+	cases := []struct {
+		name     string
+		delivery *rulesv1.RuleDelivery
+		allowed  bool
+	}{
+		{"no delivery", nil, true},
+		{"at least once", &rulesv1.RuleDelivery{Mode: rulesv1.RuleDeliveryAtLeastOnce, MaxRetries: 3,
+			InitialBackoff: second, MaxBackoff: minute, AckTimeout: second}, true},
+		{"unknown mode", &rulesv1.RuleDelivery{Mode: "ExactlyOnce"}, false},
+		{"negative retries", &rulesv1.RuleDelivery{MaxRetries: -1}, false},
+		{"zero backoff", &rulesv1.RuleDelivery{InitialBackoff: &metav1.Duration{}}, false},
+		{"max backoff less than initial", &rulesv1.RuleDelivery{InitialBackoff: minute, MaxBackoff: second}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRuleDelivery(&rulesv1.Rule{Spec: rulesv1.RuleSpec{Delivery: tc.delivery}}, source)
+			if tc.allowed && err != nil {
+				t.Fatalf("expect rule allowed, got error: %v", err)
+			}
+			if !tc.allowed && err == nil {
+				t.Fatalf("expect rule denied")
+			}
+		})
+	}
+}
+
+func Test_validateServiceBusTarget(t *testing.T) {
+	ep := &rulesv1.RuleEndpoint{Spec: rulesv1.RuleEndpointSpec{
+		RuleEndpointType: rulesv1.RuleEndpointTypeServiceBus,
+		Properties:       map[string]string{"service_port": "80", "allowed_targets": "web.default:80,api.default"},
+	}}
+	cases := []struct {
+		name           string
+		targetResource map[string]string
+		allowed        bool
+	}{
+		{"loopback", map[string]string{"path": "/a"}, true},
+		{"allowed service", map[string]string{"path": "/a", "service": "web.default"}, true},
+		{"allowed service any port", map[string]string{"path": "/a", "service": "api.default", "port": "8080"}, true},
+		{"port not allowed", map[string]string{"path": "/a", "service": "web.default", "port": "8080"}, false},
+		{"service not allowed", map[string]string{"path": "/a", "service": "db.default"}, false},
+		{"invalid service", map[string]string{"path": "/a", "service": "web"}, false},
+		{"invalid port", map[string]string{"path": "/a", "port": "x"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTargetRuleEndpoint(ep, tc.targetResource)
+			if tc.allowed && err != nil {
+				t.Fatalf("expect rule allowed, got error: %v", err)
+			}
+			if !tc.allowed && err == nil {
+				t.Fatalf("expect rule denied")
+			}
+		})
+	}
+}
+
+func Test_validateServiceBusSource(t *testing.T) {
+	ep := &rulesv1.RuleEndpoint{Spec: rulesv1.RuleEndpointSpec{RuleEndpointType: rulesv1.RuleEndpointTypeServiceBus}}
+	cases := []struct {
+		name           string
+		sourceResource map[string]string
+		allowed        bool
+	}{
+		{"without service accounts", map[string]string{"target_url": "/api", "node_name": "edge-1"}, true},
+		{"service accounts", map[string]string{"target_url": "/api", "node_name": "edge-1", "service_accounts": "default/app,monitoring/*"}, true},
+		{"target url missed", map[string]string{"node_name": "edge-1"}, false},
+		{"node name missed", map[string]string{"target_url": "/api"}, false},
+		{"invalid service account", map[string]string{"target_url": "/api", "node_name": "edge-1", "service_accounts": "app"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSourceRuleEndpoint(ep, tc.sourceResource)
+			if tc.allowed && err != nil {
+				t.Fatalf("expect rule allowed, got error: %v", err)
+			}
+			if !tc.allowed && err == nil {
+				t.Fatalf("expect rule denied")
+			}
+		})
+	}
+}
@@ -3,12 +3,14 @@ package admissioncontroller
 import (
 	"fmt"
 	"net/http"
-	"strconv"
+	"strings"
 
+	"github.com/IBM/sarama"
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/klog/v2"
 
 	rulesv1 "github.com/kubeedge/api/apis/rules/v1"
+	routerutils "github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
 )
 
 func admitRuleEndpoint(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
@@ -40,19 +42,96 @@ func admitRuleEndpoint(review admissionv1.AdmissionReview) *admissionv1.Admissio
 }
 
 func validateRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint) error {
+	if err := validateRateLimit(ruleEndpoint.Spec.RateLimit); err != nil {
+		return err
+	}
 	switch ruleEndpoint.Spec.RuleEndpointType {
 	case rulesv1.RuleEndpointTypeServiceBus:
 		portStr, exist := ruleEndpoint.Spec.Properties["service_port"]
 		if !exist {
 			return fmt.Errorf("\"service_port\" property missed in property when ruleEndpoint is \"servicebus\"")
 		}
-		port, err := strconv.Atoi(portStr)
-		if err != nil {
-			return fmt.Errorf("port should be integer")
+		if err := routerutils.ValidatePort(portStr); err != nil {
+			return err
+		}
+		switch ruleEndpoint.Spec.Properties[routerutils.PropertyScheme] {
+		case "", "http", "https":
+		default:
+			return fmt.Errorf("\"scheme\" property should be http or https")
+		}
+		if _, err := routerutils.ParseAllowedTargets(ruleEndpoint.Spec.Properties[routerutils.PropertyAllowedTargets]); err != nil {
+			return fmt.Errorf("invalid \"%s\" property: %v", routerutils.PropertyAllowedTargets, err)
+		}
+		if _, err := routerutils.ParseMaxBodySize(ruleEndpoint.Spec.Properties[routerutils.PropertyMaxBodySize]); err != nil {
+			return fmt.Errorf("invalid \"%s\" property: %v", routerutils.PropertyMaxBodySize, err)
+		}
+		if _, err := routerutils.TLSConfigFromProperties(ruleEndpoint.Spec.Properties); err != nil {
+			return err
+		}
+	case rulesv1.RuleEndpointTypeKafka:
+		if strings.TrimSpace(ruleEndpoint.Spec.Properties["brokers"]) == "" {
+			return fmt.Errorf("\"brokers\" property missed in property when ruleEndpoint is \"kafka\"")
+		}
+		switch ruleEndpoint.Spec.Properties["acks"] {
+		case "", "0", "1", "-1", "all":
+		default:
+			return fmt.Errorf("\"acks\" property should be one of 0, 1, all")
+		}
+		if version := ruleEndpoint.Spec.Properties["version"]; version != "" {
+			if _, err := sarama.ParseKafkaVersion(version); err != nil {
+				return fmt.Errorf("invalid \"version\" property: %v", err)
+			}
+		}
+		if mechanism, exist := ruleEndpoint.Spec.Properties["sasl_mechanism"]; exist {
+			if mechanism != "PLAIN" {
+				return fmt.Errorf("unsupported SASL mechanism %q, only PLAIN is supported", mechanism)
+			}
+			if ruleEndpoint.Spec.Properties[routerutils.PropertyCredentialsSecret] == "" {
+				return fmt.Errorf("\"%s\" property missed in property when SASL is enabled", routerutils.PropertyCredentialsSecret)
+			}
+		}
+		if err := validateNoPlainCredentials(ruleEndpoint.Spec.Properties, "sasl_username", "sasl_password"); err != nil {
+			return err
+		}
+		if _, err := routerutils.TLSConfigFromProperties(ruleEndpoint.Spec.Properties); err != nil {
+			return err
+		}
+	case rulesv1.RuleEndpointTypeNATS:
+		if strings.TrimSpace(ruleEndpoint.Spec.Properties["servers"]) == "" {
+			return fmt.Errorf("\"servers\" property missed in property when ruleEndpoint is \"nats\"")
 		}
-		if port < 1 || port > 65535 {
-			return fmt.Errorf("port must be in range 1-65535")
+		if err := validateNoPlainCredentials(ruleEndpoint.Spec.Properties, "username", "password", "token"); err != nil {
+			return err
 		}
+		if _, err := routerutils.TLSConfigFromProperties(ruleEndpoint.Spec.Properties); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNoPlainCredentials rejects the credentials in plain properties, which are readable
+// by anyone who can read the ruleendpoint, they must be put in the credentials secret
+func validateNoPlainCredentials(properties map[string]string, keys ...string) error {
+	for _, key := range keys {
+		if _, exist := properties[key]; exist {
+			return fmt.Errorf("\"%s\" property is not allowed, put the credentials in the secret referenced by \"%s\"",
+				key, routerutils.PropertyCredentialsSecret)
+		}
+	}
+	return nil
+}
+
+// validateRateLimit checks the rate limit of rule and ruleendpoint
+func validateRateLimit(limit *rulesv1.RuleRateLimit) error {
+	if limit == nil {
+		return nil
+	}
+	if limit.QPS <= 0 {
+		return fmt.Errorf("rateLimit qps must be positive")
+	}
+	if limit.Burst < 0 {
+		return fmt.Errorf("rateLimit burst can not be negative")
 	}
 	return nil
 }
@@ -191,3 +191,99 @@ func Test_admitRuleEndpoint(t *testing.T) {
 		}
 	})
 }
+
+func Test_validateStreamingRuleEndpoint(t *testing.T) {
+	cases := []struct {
+		name         string
+		endpointType rulesv1.RuleEndpointTypeDef
+		properties   map[string]string
+		allowed      bool
+	}{
+		{"kafka with brokers", rulesv1.RuleEndpointTypeKafka, map[string]string{"brokers": "kafka:9092", "acks": "all"}, true},
+		{"kafka with SASL", rulesv1.RuleEndpointTypeKafka,
+			map[string]string{"brokers": "kafka:9092", "sasl_mechanism": "PLAIN", "credentials_secret": "kafka", "tls": "true"}, true},
+		{"kafka with SASL but no credentials secret", rulesv1.RuleEndpointTypeKafka,
+			map[string]string{"brokers": "kafka:9092", "sasl_mechanism": "PLAIN"}, false},
+		{"kafka with plain SASL password", rulesv1.RuleEndpointTypeKafka,
+			map[string]string{"brokers": "kafka:9092", "credentials_secret": "kafka", "sasl_password": "secret"}, false},
+		{"kafka with version", rulesv1.RuleEndpointTypeKafka, map[string]string{"brokers": "kafka:9092", "version": "3.6.0"}, true},
+		{"kafka with invalid version", rulesv1.RuleEndpointTypeKafka, map[string]string{"brokers": "kafka:9092", "version": "latest"}, false},
+		{"kafka without brokers", rulesv1.RuleEndpointTypeKafka, map[string]string{}, false},
+		{"kafka with invalid acks", rulesv1.RuleEndpointTypeKafka, map[string]string{"brokers": "kafka:9092", "acks": "2"}, false},
+		{"kafka with unsupported SASL", rulesv1.RuleEndpointTypeKafka,
+			map[string]string{"brokers": "kafka:9092", "sasl_mechanism": "GSSAPI", "credentials_secret": "kafka"}, false},
+		{"kafka with invalid tls", rulesv1.RuleEndpointTypeKafka, map[string]string{"brokers": "kafka:9092", "tls": "yes"}, false},
+		{"nats with servers", rulesv1.RuleEndpointTypeNATS, map[string]string{"servers": "nats://nats:4222"}, true},
+		{"nats without servers", rulesv1.RuleEndpointTypeNATS, map[string]string{"token": "t"}, false},
+		{"nats with credentials secret", rulesv1.RuleEndpointTypeNATS,
+			map[string]string{"servers": "nats:4222", "credentials_secret": "nats"}, true},
+		{"nats with plain token", rulesv1.RuleEndpointTypeNATS, map[string]string{"servers": "nats:4222", "token": "t"}, false},
+		{"servicebus with max body size", rulesv1.RuleEndpointTypeServiceBus,
+			map[string]string{"service_port": "8080", "max_body_size": "512Mi"}, true},
+		{"servicebus with invalid max body size", rulesv1.RuleEndpointTypeServiceBus,
+			map[string]string{"service_port": "8080", "max_body_size": "-1"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ruleEndpoint := &rulesv1.RuleEndpoint{
+				ObjectMeta: v1.ObjectMeta{Name: "stream", Namespace: "test"},
+				Spec:       rulesv1.RuleEndpointSpec{RuleEndpointType: tc.endpointType, Properties: tc.properties},
+			}
+			err := validateRuleEndpoint(ruleEndpoint)
+			if tc.allowed && err != nil {
+				t.Fatalf("expect ruleEndpoint allowed, got error: %v", err)
+			}
+			if !tc.allowed && err == nil {
+				t.Fatalf("expect ruleEndpoint denied")
+			}
+		})
+	}
+}
+
+func Test_validateStreamingTargetResource(t *testing.T) {
+	kafka := &rulesv1.RuleEndpoint{Spec: rulesv1.RuleEndpointSpec{RuleEndpointType: rulesv1.RuleEndpointTypeKafka}}
+	nats := &rulesv1.RuleEndpoint{Spec: rulesv1.RuleEndpointSpec{RuleEndpointType: rulesv1.RuleEndpointTypeNATS}}
+
+	if err := validateTargetRuleEndpoint(kafka, map[string]string{"topic": "{{.NodeName}}", "key": "{{.Param}}",
+		"header.id": "{{.MessageID}}"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := validateTargetRuleEndpoint(kafka, map[string]string{"key": "k"}); err == nil {
+		t.Fatalf("expect error when topic is missed")
+	}
+	if err := validateTargetRuleEndpoint(kafka, map[string]string{"topic": "t", "key": "{{.Param"}); err == nil {
+		t.Fatalf("expect error when key is an invalid template")
+	}
+	if err := validateTargetRuleEndpoint(nats, map[string]string{"subject": "a.b", "header.": "x"}); err == nil {
+		t.Fatalf("expect error when header name is empty")
+	}
+	if err := validateSourceRuleEndpoint(nats, map[string]string{"subject": "a.b"}); err == nil {
+		t.Fatalf("expect error when node_name is missed")
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	cases := []struct {
+		limit   *rulesv1.RuleRateLimit
+		allowed bool
+	}{
+		{nil, true},
+		{&rulesv1.RuleRateLimit{QPS: 10}, true},
+		{&rulesv1.RuleRateLimit{QPS: 10, Burst: 20}, true},
+		{&rulesv1.RuleRateLimit{QPS: 0}, false},
+		{&rulesv1.RuleRateLimit{QPS: 10, Burst: -1}, false},
+	}
+	for _, tc := range cases {
+		ep := &rulesv1.RuleEndpoint{Spec: rulesv1.RuleEndpointSpec{
+			RuleEndpointType: rulesv1.RuleEndpointTypeRest,
+			RateLimit:        tc.limit,
+		}}
+		err := validateRuleEndpoint(ep)
+		if tc.allowed && err != nil {
+			t.Errorf("expect rate limit %+v allowed, got error: %v", tc.limit, err)
+		}
+		if !tc.allowed && err == nil {
+			t.Errorf("expect rate limit %+v denied", tc.limit)
+		}
+	}
+}
@@ -84,7 +84,9 @@ func isKubeedgeResourceMessage(router beehivemodel.MessageRoute) bool {
 	case metaserver.MetaServerSource, cloudhubmodel.ResTwin:
 		return true
 	}
-	if router.Resource == beehivemodel.ResourceTypeK8sCA || common.IsVolumeResource(router.Resource) {
+	if router.Resource == beehivemodel.ResourceTypeK8sCA || router.Resource == commonconstants.ResourceTypeObjectResync ||
+		router.Resource == commonconstants.ResourceTypeObjectDigest || router.Resource == commonconstants.ResourceTypeBandwidthUsage ||
+		common.IsVolumeResource(router.Resource) {
 		return true
 	}
 
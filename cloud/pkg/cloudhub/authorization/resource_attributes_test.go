@@ -23,6 +23,7 @@ import (
 
 	"github.com/kubeedge/beehive/pkg/core/model"
 	cloudhubmodel "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	commonconstants "github.com/kubeedge/kubeedge/common/constants"
 )
 
 func TestGetBuiltinResourceAttributes(t *testing.T) {
@@ -124,6 +125,21 @@ func TestIsKubeedgeResourceMessage(t *testing.T) {
 			router: model.MessageRoute{Resource: model.ResourceTypeK8sCA},
 			result: true,
 		},
+		{
+			name:   "object resync message",
+			router: model.MessageRoute{Resource: commonconstants.ResourceTypeObjectResync},
+			result: true,
+		},
+		{
+			name:   "object digest message",
+			router: model.MessageRoute{Resource: commonconstants.ResourceTypeObjectDigest},
+			result: true,
+		},
+		{
+			name:   "bandwidth usage message",
+			router: model.MessageRoute{Resource: commonconstants.ResourceTypeBandwidthUsage},
+			result: true,
+		},
 		{
 			name:   "rule status message",
 			router: model.MessageRoute{Resource: "ns/rulestatus/rs"},
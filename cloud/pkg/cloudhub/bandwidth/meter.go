@@ -0,0 +1,230 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bandwidth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+)
+
+const (
+	DirectionDownstream = "downstream"
+	DirectionUpstream   = "upstream"
+
+	// dayLayout is the layout of the day of the usage
+	dayLayout = "2006-01-02"
+
+	// maxDeferral is the max delay of a deferred message, the message is checked against the
+	// budget again after it, so that the change of the budget takes effect in time
+	maxDeferral = 10 * time.Minute
+)
+
+// meters records the meters of the edge nodes, keyed by node name
+var meters sync.Map
+
+// Enabled returns whether the traffic of the edge nodes is metered
+func Enabled() bool {
+	budget := config.Config.BandwidthBudget
+	return budget != nil && budget.Enable
+}
+
+// GetMeter returns the meter of the edge node, it lives across the reconnections of the node
+func GetMeter(nodeID string) *Meter {
+	if m, ok := meters.Load(nodeID); ok {
+		return m.(*Meter)
+	}
+
+	var labels map[string]string
+	if nodeLister != nil {
+		if node, err := nodeLister.Get(nodeID); err == nil {
+			labels = node.Labels
+		}
+	}
+	m, _ := meters.LoadOrStore(nodeID, newMeter(nodeID, budgetOf(nodeID, labels)))
+	return m.(*Meter)
+}
+
+// budgetOf returns the budget of the edge node, the budget of the node itself takes precedence
+// over the one of its node group
+func budgetOf(nodeID string, labels map[string]string) v1alpha1.BandwidthBudget {
+	budgets := config.Config.BandwidthBudget
+	if budget, ok := budgets.Nodes[nodeID]; ok {
+		return budget
+	}
+	if group := labels[nodegroup.LabelBelongingTo]; group != "" {
+		if budget, ok := budgets.NodeGroups[group]; ok {
+			return budget
+		}
+	}
+	return budgets.Default
+}
+
+// Usage is the traffic of an edge node in a day, it is reported to the node annotation
+type Usage struct {
+	// Day is the day (UTC) of the usage
+	Day             string `json:"day"`
+	DownstreamBytes int64  `json:"downstreamBytes"`
+	UpstreamBytes   int64  `json:"upstreamBytes"`
+	// BytesPerDay is the daily budget of the node, 0 means no limit
+	BytesPerDay int64 `json:"bytesPerDay,omitempty"`
+}
+
+// Meter meters the traffic of an edge node and shapes the downstream traffic by its budget
+type Meter struct {
+	nodeID string
+	now    func() time.Time
+
+	lock    sync.Mutex
+	budget  v1alpha1.BandwidthBudget
+	limiter *rate.Limiter
+	usage   Usage
+	// reported is the usage reported to the node annotation last time
+	reported Usage
+	// restored indicates whether the usage is restored from the node annotation
+	restored bool
+}
+
+func newMeter(nodeID string, budget v1alpha1.BandwidthBudget) *Meter {
+	m := &Meter{
+		nodeID: nodeID,
+		now:    time.Now,
+	}
+	m.SetBudget(budget)
+	return m
+}
+
+// SetBudget sets the budget of the meter, the bandwidth reserved is kept if the rate is not changed
+func (m *Meter) SetBudget(budget v1alpha1.BandwidthBudget) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch {
+	case budget.BytesPerSecond <= 0:
+		m.limiter = nil
+	case m.limiter == nil:
+		m.limiter = rate.NewLimiter(rate.Limit(budget.BytesPerSecond), int(budget.BytesPerSecond))
+	case budget.BytesPerSecond != m.budget.BytesPerSecond:
+		m.limiter.SetLimit(rate.Limit(budget.BytesPerSecond))
+		m.limiter.SetBurst(int(budget.BytesPerSecond))
+	}
+	m.budget = budget
+	monitor.NodeDailyBudgetBytes.WithLabelValues(m.nodeID).Set(float64(budget.BytesPerDay))
+}
+
+// Admit reserves the bandwidth for the downstream message of size bytes, and returns how long
+// the message is deferred. The control messages are never deferred and do not wait, the bulk
+// messages are deferred if the daily budget is used up or the bandwidth is not available at
+// once, and the other messages wait for the bandwidth.
+func (m *Meter) Admit(ctx context.Context, priority v1alpha1.MessagePriority, size int) time.Duration {
+	m.lock.Lock()
+	now := m.now()
+	m.rollover(now)
+	if priority == v1alpha1.MessagePriorityBulk && m.exceeded() {
+		m.lock.Unlock()
+		return m.deferred(nextDay(now).Sub(now))
+	}
+	if m.limiter == nil {
+		m.lock.Unlock()
+		return 0
+	}
+
+	r := m.limiter.ReserveN(now, min(size, m.limiter.Burst()))
+	delay := r.DelayFrom(now)
+	if priority == v1alpha1.MessagePriorityBulk && delay > 0 {
+		r.CancelAt(now)
+		m.lock.Unlock()
+		return m.deferred(delay)
+	}
+	m.lock.Unlock()
+
+	if priority == v1alpha1.MessagePriorityControl || delay <= 0 {
+		return 0
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return 0
+}
+
+func (m *Meter) deferred(delay time.Duration) time.Duration {
+	monitor.DeferredMessages.WithLabelValues(m.nodeID).Inc()
+	return min(delay, maxDeferral)
+}
+
+// RecordDownstream records the bytes sent to the edge node
+func (m *Meter) RecordDownstream(bytes int64) {
+	m.record(bytes, DirectionDownstream)
+}
+
+// RecordUpstream records the bytes reported by the edge node
+func (m *Meter) RecordUpstream(bytes int64) {
+	m.record(bytes, DirectionUpstream)
+}
+
+func (m *Meter) record(bytes int64, direction string) {
+	m.lock.Lock()
+	m.rollover(m.now())
+	if direction == DirectionDownstream {
+		m.usage.DownstreamBytes += bytes
+	} else {
+		m.usage.UpstreamBytes += bytes
+	}
+	total := m.usage.DownstreamBytes + m.usage.UpstreamBytes
+	m.lock.Unlock()
+
+	monitor.NodeTrafficBytes.WithLabelValues(m.nodeID, direction).Add(float64(bytes))
+	monitor.NodeDailyTrafficBytes.WithLabelValues(m.nodeID).Set(float64(total))
+}
+
+// Usage returns the usage of the edge node today
+func (m *Meter) Usage() Usage {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.rollover(m.now())
+	usage := m.usage
+	usage.BytesPerDay = m.budget.BytesPerDay
+	return usage
+}
+
+// rollover resets the usage on a new day, it must be called with the lock held
+func (m *Meter) rollover(now time.Time) {
+	if day := now.UTC().Format(dayLayout); day != m.usage.Day {
+		m.usage = Usage{Day: day}
+	}
+}
+
+// exceeded returns whether the daily budget is used up, it must be called with the lock held
+func (m *Meter) exceeded() bool {
+	return m.budget.BytesPerDay > 0 && m.usage.DownstreamBytes+m.usage.UpstreamBytes >= m.budget.BytesPerDay
+}
+
+// nextDay returns the beginning of the next day (UTC) of t
+func nextDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bandwidth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+)
+
+func setBudgets(t *testing.T, budgets *v1alpha1.CloudHubBandwidthBudget) {
+	origin := config.Config.BandwidthBudget
+	config.Config.BandwidthBudget = budgets
+	t.Cleanup(func() {
+		config.Config.BandwidthBudget = origin
+		meters.Clear()
+	})
+}
+
+func TestBudgetOf(t *testing.T) {
+	setBudgets(t, &v1alpha1.CloudHubBandwidthBudget{
+		Enable:     true,
+		Default:    v1alpha1.BandwidthBudget{BytesPerDay: 1},
+		NodeGroups: map[string]v1alpha1.BandwidthBudget{"group": {BytesPerDay: 2}},
+		Nodes:      map[string]v1alpha1.BandwidthBudget{"node": {BytesPerDay: 3}},
+	})
+	group := map[string]string{nodegroup.LabelBelongingTo: "group"}
+
+	assert.True(t, Enabled())
+	assert.Equal(t, int64(3), budgetOf("node", group).BytesPerDay)
+	assert.Equal(t, int64(2), budgetOf("other", group).BytesPerDay)
+	assert.Equal(t, int64(1), budgetOf("other", nil).BytesPerDay)
+	assert.Equal(t, int64(1), budgetOf("other", map[string]string{nodegroup.LabelBelongingTo: "unknown"}).BytesPerDay)
+
+	m := GetMeter("node")
+	assert.Same(t, m, GetMeter("node"))
+	assert.Equal(t, int64(3), m.Usage().BytesPerDay)
+}
+
+func TestAdmitDailyBudget(t *testing.T) {
+	now := time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)
+	m := newMeter("node", v1alpha1.BandwidthBudget{BytesPerDay: 100})
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.RecordDownstream(60)
+	assert.Equal(t, time.Duration(0), m.Admit(ctx, v1alpha1.MessagePriorityBulk, 10))
+
+	m.RecordUpstream(40)
+	assert.Equal(t, maxDeferral, m.Admit(ctx, v1alpha1.MessagePriorityBulk, 10))
+	assert.Equal(t, time.Duration(0), m.Admit(ctx, v1alpha1.MessagePriorityWorkload, 10))
+	assert.Equal(t, time.Duration(0), m.Admit(ctx, v1alpha1.MessagePriorityControl, 10))
+
+	now = now.Add(59 * time.Minute)
+	assert.Equal(t, time.Minute, m.Admit(ctx, v1alpha1.MessagePriorityBulk, 10))
+
+	// the usage is reset on the next day
+	now = now.Add(time.Minute)
+	assert.Equal(t, Usage{Day: "2026-01-02", BytesPerDay: 100}, m.Usage())
+	assert.Equal(t, time.Duration(0), m.Admit(ctx, v1alpha1.MessagePriorityBulk, 10))
+}
+
+func TestAdmitRate(t *testing.T) {
+	m := newMeter("node", v1alpha1.BandwidthBudget{BytesPerSecond: 1000})
+	ctx := context.Background()
+
+	// the bandwidth is used up
+	assert.Equal(t, time.Duration(0), m.Admit(ctx, v1alpha1.MessagePriorityControl, 2000))
+
+	delay := m.Admit(ctx, v1alpha1.MessagePriorityBulk, 100)
+	assert.Positive(t, delay)
+	assert.LessOrEqual(t, delay, time.Second)
+
+	start := time.Now()
+	assert.Equal(t, time.Duration(0), m.Admit(ctx, v1alpha1.MessagePriorityWorkload, 100))
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	start = time.Now()
+	assert.Equal(t, time.Duration(0), m.Admit(cancelled, v1alpha1.MessagePriorityDevice, 100))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// the rate is not limited any more
+	m.SetBudget(v1alpha1.BandwidthBudget{})
+	assert.Equal(t, time.Duration(0), m.Admit(ctx, v1alpha1.MessagePriorityBulk, 100))
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bandwidth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+// UsageAnnotation is the annotation of the node which records its bandwidth usage of the day
+const UsageAnnotation = "node.kubeedge.io/bandwidth-usage"
+
+// nodeLister is used to look up the node groups of the nodes, it is set by Start
+var nodeLister corelisters.NodeLister
+
+// Start reports the usages of the meters to the node annotations periodically until ctx is done,
+// and refreshes the budgets of the meters at the same time.
+func Start(ctx context.Context, kubeClient kubernetes.Interface, lister corelisters.NodeLister) {
+	nodeLister = lister
+	interval := time.Duration(config.Config.BandwidthBudget.ReportInterval) * time.Second
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		report(ctx, kubeClient)
+	}, interval)
+}
+
+func report(ctx context.Context, kubeClient kubernetes.Interface) {
+	meters.Range(func(key, value any) bool {
+		m := value.(*Meter)
+		node, err := nodeLister.Get(m.nodeID)
+		if apierrors.IsNotFound(err) {
+			meters.Delete(key)
+			monitor.NodeDailyTrafficBytes.DeleteLabelValues(m.nodeID)
+			monitor.NodeDailyBudgetBytes.DeleteLabelValues(m.nodeID)
+			return true
+		}
+		if err != nil {
+			klog.Errorf("failed to get node %s: %v", m.nodeID, err)
+			return true
+		}
+
+		m.SetBudget(budgetOf(node.Name, node.Labels))
+		m.restore(node.Annotations[UsageAnnotation])
+
+		usage := m.Usage()
+		if usage == m.lastReported() {
+			return true
+		}
+		if err := patchUsage(ctx, kubeClient, node.Name, usage); err != nil {
+			klog.Errorf("failed to report bandwidth usage of node %s: %v", node.Name, err)
+			return true
+		}
+		m.setReported(usage)
+		return true
+	})
+}
+
+// restore restores the usage from the node annotation once, so that the usage of the day
+// is not lost when CloudCore restarts
+func (m *Meter) restore(annotation string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.restored {
+		return
+	}
+	m.restored = true
+	if annotation == "" {
+		return
+	}
+
+	var usage Usage
+	if err := json.Unmarshal([]byte(annotation), &usage); err != nil {
+		klog.Warningf("invalid bandwidth usage annotation of node %s: %v", m.nodeID, err)
+		return
+	}
+	m.rollover(m.now())
+	if usage.Day == m.usage.Day {
+		m.usage.DownstreamBytes = max(m.usage.DownstreamBytes, usage.DownstreamBytes)
+		m.usage.UpstreamBytes = max(m.usage.UpstreamBytes, usage.UpstreamBytes)
+	}
+}
+
+func (m *Meter) lastReported() Usage {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.reported
+}
+
+func (m *Meter) setReported(usage Usage) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.reported = usage
+}
+
+func patchUsage(ctx context.Context, kubeClient kubernetes.Interface, nodeName string, usage Usage) error {
+	value, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{UsageAnnotation: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := kubeClient.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("patch node: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bandwidth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+)
+
+func TestReport(t *testing.T) {
+	setBudgets(t, &v1alpha1.CloudHubBandwidthBudget{
+		Enable:     true,
+		NodeGroups: map[string]v1alpha1.BandwidthBudget{"group": {BytesPerDay: 1000}},
+	})
+	today := time.Now().UTC().Format(dayLayout)
+	restored, _ := json.Marshal(Usage{Day: today, DownstreamBytes: 300, UpstreamBytes: 200})
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node",
+			Labels:      map[string]string{nodegroup.LabelBelongingTo: "group"},
+			Annotations: map[string]string{UsageAnnotation: string(restored)},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(node)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(node))
+	nodeLister = corelisters.NewNodeLister(indexer)
+	defer func() { nodeLister = nil }()
+
+	m := GetMeter("node")
+	assert.Equal(t, int64(1000), m.Usage().BytesPerDay)
+	m.RecordDownstream(100)
+	// the meter of the deleted node is removed
+	GetMeter("deleted")
+
+	ctx := context.Background()
+	report(ctx, kubeClient)
+
+	expected := Usage{Day: today, DownstreamBytes: 300, UpstreamBytes: 200, BytesPerDay: 1000}
+	assert.Equal(t, expected, m.Usage())
+	got, err := kubeClient.CoreV1().Nodes().Get(ctx, "node", metav1.GetOptions{})
+	require.NoError(t, err)
+	var usage Usage
+	require.NoError(t, json.Unmarshal([]byte(got.Annotations[UsageAnnotation]), &usage))
+	assert.Equal(t, expected, usage)
+	_, ok := meters.Load("deleted")
+	assert.False(t, ok)
+
+	// the usage is not reported again if it is not changed
+	kubeClient.ClearActions()
+	report(ctx, kubeClient)
+	assert.Empty(t, kubeClient.Actions())
+
+	// the usage restored only once
+	m.RecordUpstream(100)
+	report(ctx, kubeClient)
+	assert.Equal(t, int64(300), m.Usage().UpstreamBytes)
+	assert.Len(t, kubeClient.Actions(), 1)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubeapiserver/authorizer/modes"
@@ -13,9 +14,11 @@ import (
 	"github.com/kubeedge/beehive/pkg/core"
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/authorization"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/bandwidth"
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/handler"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/udsserver"
@@ -23,6 +26,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 )
 
 var DoneTLSTunnelCerts = make(chan bool, 1)
@@ -34,6 +38,8 @@ type cloudHub struct {
 
 	messageHandler handler.Handler
 	dispatcher     dispatcher.MessageDispatcher
+	// nodeLister is only set if the bandwidth budget is enabled
+	nodeLister corelisters.NodeLister
 }
 
 var _ core.Module = (*cloudHub)(nil)
@@ -71,6 +77,12 @@ func newCloudHub(enable bool) *cloudHub {
 	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, clusterObjectSyncInformer.Informer().HasSynced)
 	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, objectSyncInformer.Informer().HasSynced)
 
+	if bandwidth.Enabled() {
+		nodeInformer := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes()
+		ch.nodeLister = nodeInformer.Lister()
+		ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, nodeInformer.Informer().HasSynced)
+	}
+
 	return ch
 }
 
@@ -127,6 +139,21 @@ func (ch *cloudHub) Start() {
 		}
 	}()
 
+	if bandwidth.Enabled() {
+		bandwidth.Start(ctx, client.GetKubeClient(), ch.nodeLister)
+	}
+
+	// load the revocation list before accepting edge connections, and disconnect
+	// the edge nodes whose certificates are revoked later
+	err := revocation.Start(ctx, client.GetKubeClient(), func() {
+		sessionMgr.TerminateSessions(func(nodeID string, connection conn.Connection) bool {
+			return revocation.IsRevokedPeer(nodeID, connection.ConnectionState().PeerCertificates)
+		})
+	})
+	if err != nil {
+		klog.Exit(err)
+	}
+
 	servers.StartCloudHub(ch.messageHandler)
 
 	if hubconfig.Config.UnixSocket.Enable {
@@ -27,9 +27,12 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	edgecon "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
@@ -143,3 +146,35 @@ func NoAckMessageKeyFunc(obj interface{}) (string, error) {
 
 	return msg.Header.ID, nil
 }
+
+// GetMessagePriority returns the priority class of the message sent to edge node
+func GetMessagePriority(msg *beehivemodel.Message) v1alpha1.MessagePriority {
+	// the twin and membership messages are all in the twin group
+	if msg.GetGroup() == deviceconst.GroupTwin {
+		return v1alpha1.MessagePriorityDevice
+	}
+	resourceType, _ := messagelayer.GetResourceType(*msg)
+	switch resourceType {
+	case deviceconst.ResourceTypeDevice, deviceconst.ResourceTypeDeviceModel, deviceconst.ResourceTypeDeviceMapper:
+		return v1alpha1.MessagePriorityDevice
+	}
+
+	// the edge node is waiting for the responses
+	switch msg.GetOperation() {
+	case beehivemodel.DeleteOperation, beehivemodel.ResponseOperation, beehivemodel.ResponseErrorOperation:
+		return v1alpha1.MessagePriorityControl
+	}
+	if msg.GetGroup() == modules.UserGroup {
+		return v1alpha1.MessagePriorityBulk
+	}
+
+	switch resourceType {
+	case beehivemodel.ResourceTypeNode, beehivemodel.ResourceTypeLease,
+		beehivemodel.ResourceTypeServiceAccountToken, beehivemodel.ResourceTypeCSR:
+		return v1alpha1.MessagePriorityControl
+	case beehivemodel.ResourceTypeConfigmap, beehivemodel.ResourceTypeSecret, beehivemodel.ResourceTypePodlist,
+		beehivemodel.ResourceTypeRule, beehivemodel.ResourceTypeRuleEndpoint:
+		return v1alpha1.MessagePriorityBulk
+	}
+	return v1alpha1.MessagePriorityWorkload
+}
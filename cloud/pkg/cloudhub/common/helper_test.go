@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
@@ -278,3 +279,104 @@ func TestNoAckMessageKeyFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMessagePriority(t *testing.T) {
+	tests := []struct {
+		name      string
+		group     string
+		resource  string
+		operation string
+		want      v1alpha1.MessagePriority
+	}{
+		{
+			name:      "device twin",
+			group:     "twin",
+			resource:  "device/dev1/twin/cloud_updated",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityDevice,
+		},
+		{
+			name:      "device membership",
+			group:     "twin",
+			resource:  "node/edge-node/membership",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityDevice,
+		},
+		{
+			name:      "device",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/device/sensor",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityDevice,
+		},
+		{
+			name:      "device model",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/devicemodel/sensor",
+			operation: beehivemodel.InsertOperation,
+			want:      v1alpha1.MessagePriorityDevice,
+		},
+		{
+			name:      "pod named after device",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/pod/device-twin-agent",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityWorkload,
+		},
+		{
+			name:      "configmap in device namespace",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/device-membership/configmap/twin",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityBulk,
+		},
+		{
+			name:      "response",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/pod/test",
+			operation: beehivemodel.ResponseOperation,
+			want:      v1alpha1.MessagePriorityControl,
+		},
+		{
+			name:      "pod deletion",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/pod/test",
+			operation: beehivemodel.DeleteOperation,
+			want:      v1alpha1.MessagePriorityControl,
+		},
+		{
+			name:      "node",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/node/test",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityControl,
+		},
+		{
+			name:      "configmap",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/configmap/test",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityBulk,
+		},
+		{
+			name:      "user",
+			group:     modules.UserGroup,
+			resource:  "rule/test",
+			operation: beehivemodel.UploadOperation,
+			want:      v1alpha1.MessagePriorityBulk,
+		},
+		{
+			name:      "pod",
+			group:     edgecon.GroupResource,
+			resource:  "node/edge-node/default/pod/test",
+			operation: beehivemodel.UpdateOperation,
+			want:      v1alpha1.MessagePriorityWorkload,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := beehivemodel.NewMessage("").BuildRouter("edgecontroller", tt.group, tt.resource, tt.operation)
+			assert.Equal(t, tt.want, GetMessagePriority(msg))
+		})
+	}
+}
@@ -22,7 +22,9 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 )
 
 // NodeMessagePool is a collection of all downstream messages sent to an
@@ -44,14 +46,36 @@ type NodeMessagePool struct {
 	NoAckMessageQueue workqueue.RateLimitingInterface
 }
 
-// InitNodeMessagePool init node message pool for node
+// InitNodeMessagePool init node message pool for node, the messages are queued in
+// priority lanes if the message QoS of CloudHub is enabled
 func InitNodeMessagePool(nodeID string) *NodeMessagePool {
-	return &NodeMessagePool{
+	nmp := &NodeMessagePool{
 		AckMessageStore:   cache.NewStore(AckMessageKeyFunc),
-		AckMessageQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID),
 		NoAckMessageStore: cache.NewStore(NoAckMessageKeyFunc),
-		NoAckMessageQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID),
 	}
+	qos := config.Config.MessageQoS
+	if qos == nil || !qos.Enable {
+		nmp.AckMessageQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID)
+		nmp.NoAckMessageQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID)
+		return nmp
+	}
+
+	nmp.AckMessageQueue = newPriorityQueue(nodeID+"-ack", qos, func(item interface{}) v1alpha1.MessagePriority {
+		return messagePriority(nmp.GetAckMessage(item.(string)))
+	})
+	nmp.NoAckMessageQueue = newPriorityQueue(nodeID+"-noack", qos, func(item interface{}) v1alpha1.MessagePriority {
+		return messagePriority(nmp.GetNoAckMessage(item.(string)))
+	})
+	return nmp
+}
+
+// messagePriority returns the priority of the message got from the store, the message
+// is always added into the store before its key is added into the queue
+func messagePriority(msg *beehivemodel.Message, err error) v1alpha1.MessagePriority {
+	if err != nil {
+		return v1alpha1.MessagePriorityWorkload
+	}
+	return GetMessagePriority(msg)
 }
 
 // GetAckMessage get message that requires ack with the key
@@ -24,7 +24,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/rand"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	edgecon "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
 )
 
@@ -53,6 +55,32 @@ func TestInitNodeMessagePool(t *testing.T) {
 	assert.False(t, pool.NoAckMessageQueue.ShuttingDown(), "NoAckMessageQueue should not be shutting down when initialized")
 }
 
+func TestInitNodeMessagePoolWithQoS(t *testing.T) {
+	qos := config.Config.MessageQoS
+	defer func() { config.Config.MessageQoS = qos }()
+	config.Config.MessageQoS = &v1alpha1.CloudHubMessageQoS{Enable: true}
+
+	nodeID := "test-node-" + rand.String(5)
+	pool := InitNodeMessagePool(nodeID)
+	defer pool.ShutDown()
+
+	assert.IsType(t, &priorityQueue{}, pool.AckMessageQueue)
+	assert.IsType(t, &priorityQueue{}, pool.NoAckMessageQueue)
+
+	msg := beehivemodel.NewMessage("").
+		BuildRouter("edgecontroller", "resource", "default/node/"+nodeID, beehivemodel.UpdateOperation).
+		FillBody(&TestMessageObj{ObjectMeta: metav1.ObjectMeta{Name: nodeID, UID: "uid"}})
+	key, err := NoAckMessageKeyFunc(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, pool.NoAckMessageStore.Add(msg))
+	pool.NoAckMessageQueue.Add(key)
+
+	item, quit := pool.NoAckMessageQueue.Get()
+	assert.False(t, quit)
+	assert.Equal(t, key, item)
+	pool.NoAckMessageQueue.Done(item)
+}
+
 func TestGetAckMessage(t *testing.T) {
 	nodeID := "test-node-" + rand.String(5)
 	pool := InitNodeMessagePool(nodeID)
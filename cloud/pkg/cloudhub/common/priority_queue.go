@@ -0,0 +1,312 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// priorityQueue is a workqueue.RateLimitingInterface which queues the items in
+// the lanes of their priorities. The items of the lanes are got in the order of
+// smooth weighted round-robin, and each lane can be rate limited separately.
+// As in workqueue, an item is never handed out twice at the same time: it is in
+// flight from the time it is handed to the items of a lane until it is done, and
+// it is added again once it is done if it is added in the meantime.
+type priorityQueue struct {
+	lanes []*priorityLane
+	// classify returns the lane index of item
+	classify func(item interface{}) int
+
+	lock sync.Mutex
+	// credits are the current credits of the lanes in weighted round-robin
+	credits []int
+	// queued records the lane which item is queued in
+	queued map[interface{}]int
+	// stale records the lanes in bits which item is moved from to a higher lane,
+	// the item is dropped when it is got from them
+	stale map[interface{}]uint
+	// processing records the lane which the item in flight is got from
+	processing map[interface{}]int
+	// dirty records the adds of the items in flight, they are replayed once the items are done
+	dirty map[interface{}][]func(lane workqueue.RateLimitingInterface)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	cases  []reflect.SelectCase
+}
+
+type priorityLane struct {
+	queue   workqueue.RateLimitingInterface
+	weight  int
+	limiter *rate.Limiter
+	// items holds the item got from queue and waiting to be taken
+	items chan interface{}
+}
+
+// newPriorityQueue returns a priority queue with the lanes of v1alpha1.MessagePriorities,
+// classify returns the priority of item
+func newPriorityQueue(name string, qos *v1alpha1.CloudHubMessageQoS,
+	classify func(item interface{}) v1alpha1.MessagePriority) *priorityQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &priorityQueue{
+		credits:    make([]int, len(v1alpha1.MessagePriorities)),
+		queued:     make(map[interface{}]int),
+		stale:      make(map[interface{}]uint),
+		processing: make(map[interface{}]int),
+		dirty:      make(map[interface{}][]func(lane workqueue.RateLimitingInterface)),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	index := make(map[v1alpha1.MessagePriority]int, len(v1alpha1.MessagePriorities))
+	for i, priority := range v1alpha1.MessagePriorities {
+		index[priority] = i
+		config := qos.Lanes[priority]
+		lane := &priorityLane{
+			queue:  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), name+"-"+string(priority)),
+			weight: int(config.Weight),
+			items:  make(chan interface{}, 1),
+		}
+		if lane.weight <= 0 {
+			lane.weight = 1
+		}
+		if config.QPS > 0 {
+			burst := int(config.Burst)
+			if burst <= 0 {
+				burst = int(math.Ceil(float64(config.QPS)))
+			}
+			lane.limiter = rate.NewLimiter(rate.Limit(config.QPS), burst)
+		}
+		q.lanes = append(q.lanes, lane)
+		q.cases = append(q.cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(lane.items)})
+	}
+	q.cases = append(q.cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	q.classify = func(item interface{}) int {
+		if i, ok := index[classify(item)]; ok {
+			return i
+		}
+		return index[v1alpha1.MessagePriorityWorkload]
+	}
+	// the lanes are forwarded once they are all set up
+	for i := range q.lanes {
+		go q.forward(i)
+	}
+	return q
+}
+
+// forward moves the items of the lane to its items channel one by one
+func (q *priorityQueue) forward(i int) {
+	lane := q.lanes[i]
+	for {
+		if lane.limiter != nil {
+			if err := lane.limiter.Wait(q.ctx); err != nil {
+				return
+			}
+		}
+		item, quit := lane.queue.Get()
+		if quit {
+			return
+		}
+
+		q.lock.Lock()
+		if q.stale[item]&(1<<i) != 0 {
+			// the item is moved to a higher lane
+			q.clearStale(item, i)
+			q.lock.Unlock()
+			lane.queue.Forget(item)
+			lane.queue.Done(item)
+			continue
+		}
+		if queuedLane, ok := q.queued[item]; ok && queuedLane == i {
+			delete(q.queued, item)
+		}
+		if _, ok := q.processing[item]; ok {
+			// the item is in flight from another lane, it is added again once it is done
+			q.dirty[item] = append(q.dirty[item], func(lane workqueue.RateLimitingInterface) { lane.Add(item) })
+			q.lock.Unlock()
+			lane.queue.Done(item)
+			continue
+		}
+		q.processing[item] = i
+		q.lock.Unlock()
+
+		select {
+		case lane.items <- item:
+		case <-q.ctx.Done():
+			q.lock.Lock()
+			delete(q.processing, item)
+			q.lock.Unlock()
+			lane.queue.Done(item)
+			return
+		}
+	}
+}
+
+// add adds item to the lane of its priority, the item stays in the higher lane if it
+// is already queued there, and it is added once it is done if it is in flight
+func (q *priorityQueue) add(item interface{}, add func(lane workqueue.RateLimitingInterface)) {
+	i := q.classify(item)
+	q.lock.Lock()
+	if _, ok := q.processing[item]; ok {
+		q.dirty[item] = append(q.dirty[item], add)
+		q.lock.Unlock()
+		return
+	}
+	if queuedLane, ok := q.queued[item]; ok {
+		if queuedLane < i {
+			i = queuedLane
+		} else if queuedLane > i {
+			q.stale[item] |= 1 << queuedLane
+		}
+	}
+	// the item left in the lane is valid again
+	q.clearStale(item, i)
+	q.queued[item] = i
+	q.lock.Unlock()
+	add(q.lanes[i].queue)
+}
+
+func (q *priorityQueue) clearStale(item interface{}, i int) {
+	if stale, ok := q.stale[item]; ok {
+		if stale &^= 1 << i; stale == 0 {
+			delete(q.stale, item)
+		} else {
+			q.stale[item] = stale
+		}
+	}
+}
+
+func (q *priorityQueue) Add(item interface{}) {
+	q.add(item, func(lane workqueue.RateLimitingInterface) { lane.Add(item) })
+}
+
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.add(item, func(lane workqueue.RateLimitingInterface) { lane.AddAfter(item, duration) })
+}
+
+func (q *priorityQueue) AddRateLimited(item interface{}) {
+	q.add(item, func(lane workqueue.RateLimitingInterface) { lane.AddRateLimited(item) })
+}
+
+func (q *priorityQueue) Len() int {
+	var n int
+	for _, lane := range q.lanes {
+		n += lane.queue.Len() + len(lane.items)
+	}
+	return n
+}
+
+// Get blocks until an item is ready, the ready lane with the most credits is taken
+// if several lanes are ready
+func (q *priorityQueue) Get() (interface{}, bool) {
+	if q.ShuttingDown() {
+		return nil, true
+	}
+	if item, ok := q.next(); ok {
+		return item, false
+	}
+
+	chosen, item, ok := reflect.Select(q.cases)
+	if chosen == len(q.lanes) || !ok {
+		return nil, true
+	}
+	return item.Interface(), false
+}
+
+// next takes an item from the ready lanes in smooth weighted round-robin
+func (q *priorityQueue) next() (interface{}, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	chosen, total := -1, 0
+	for i, lane := range q.lanes {
+		if len(lane.items) == 0 {
+			continue
+		}
+		q.credits[i] += lane.weight
+		total += lane.weight
+		if chosen < 0 || q.credits[i] > q.credits[chosen] {
+			chosen = i
+		}
+	}
+	if chosen < 0 {
+		return nil, false
+	}
+	q.credits[chosen] -= total
+
+	select {
+	case item := <-q.lanes[chosen].items:
+		return item, true
+	default:
+		return nil, false
+	}
+}
+
+func (q *priorityQueue) Done(item interface{}) {
+	q.lock.Lock()
+	i, ok := q.processing[item]
+	delete(q.processing, item)
+	adds := q.dirty[item]
+	delete(q.dirty, item)
+	q.lock.Unlock()
+	if !ok {
+		i = q.classify(item)
+	}
+	q.lanes[i].queue.Done(item)
+	for _, add := range adds {
+		q.add(item, add)
+	}
+}
+
+func (q *priorityQueue) Forget(item interface{}) {
+	for _, lane := range q.lanes {
+		lane.queue.Forget(item)
+	}
+}
+
+func (q *priorityQueue) NumRequeues(item interface{}) int {
+	var n int
+	for _, lane := range q.lanes {
+		n = max(n, lane.queue.NumRequeues(item))
+	}
+	return n
+}
+
+func (q *priorityQueue) ShutDown() {
+	q.cancel()
+	for _, lane := range q.lanes {
+		lane.queue.ShutDown()
+	}
+}
+
+// ShutDownWithDrain does not wait for the items being processed, since the items
+// waiting in the lanes are never processed after the queue is shut down
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.ShutDown()
+}
+
+func (q *priorityQueue) ShuttingDown() bool {
+	return q.ctx.Err() != nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// testPriorities classifies the items by their prefixes
+type testPriorities struct {
+	sync.Mutex
+	priorities map[string]v1alpha1.MessagePriority
+}
+
+func (p *testPriorities) set(item string, priority v1alpha1.MessagePriority) {
+	p.Lock()
+	defer p.Unlock()
+	p.priorities[item] = priority
+}
+
+func (p *testPriorities) classify(item interface{}) v1alpha1.MessagePriority {
+	p.Lock()
+	defer p.Unlock()
+	if priority, ok := p.priorities[item.(string)]; ok {
+		return priority
+	}
+	return v1alpha1.MessagePriority(strings.Split(item.(string), "-")[0])
+}
+
+func newTestPriorityQueue(t *testing.T, lanes map[v1alpha1.MessagePriority]v1alpha1.MessageLane) (*priorityQueue, *testPriorities) {
+	p := &testPriorities{priorities: map[string]v1alpha1.MessagePriority{}}
+	q := newPriorityQueue("test", &v1alpha1.CloudHubMessageQoS{Enable: true, Lanes: lanes}, p.classify)
+	t.Cleanup(q.ShutDown)
+	return q, p
+}
+
+// waitReady waits until the lanes of priorities have items ready to be got
+func waitReady(t *testing.T, q *priorityQueue, priorities ...v1alpha1.MessagePriority) {
+	assert.Eventually(t, func() bool {
+		for _, priority := range priorities {
+			for i, p := range v1alpha1.MessagePriorities {
+				if p == priority && len(q.lanes[i].items) == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+func getItem(t *testing.T, q *priorityQueue) string {
+	item, quit := q.Get()
+	require.False(t, quit)
+	q.Done(item)
+	return item.(string)
+}
+
+func TestPriorityQueueWeights(t *testing.T) {
+	q, _ := newTestPriorityQueue(t, map[v1alpha1.MessagePriority]v1alpha1.MessageLane{
+		v1alpha1.MessagePriorityControl: {Weight: 3},
+		v1alpha1.MessagePriorityBulk:    {Weight: 1},
+	})
+	for _, item := range []string{"bulk-1", "bulk-2", "bulk-3", "bulk-4", "control-1", "control-2", "control-3", "control-4"} {
+		q.Add(item)
+	}
+	assert.Equal(t, 8, q.Len())
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		waitReady(t, q, v1alpha1.MessagePriorityControl, v1alpha1.MessagePriorityBulk)
+		got = append(got, getItem(t, q))
+	}
+	// the lanes are served in 3:1 and the items of a lane keep their order
+	assert.Equal(t, []string{"control-1", "control-2", "bulk-1", "control-3"}, got)
+}
+
+func TestPriorityQueueMoveToHigherLane(t *testing.T) {
+	q, p := newTestPriorityQueue(t, nil)
+	// block the bulk lane with the item being processed
+	q.Add("bulk-0")
+	waitReady(t, q, v1alpha1.MessagePriorityBulk)
+	q.Add("item")
+	p.set("item", v1alpha1.MessagePriorityControl)
+	q.Add("item")
+
+	waitReady(t, q, v1alpha1.MessagePriorityControl)
+	assert.Equal(t, "item", getItem(t, q))
+	assert.Equal(t, "bulk-0", getItem(t, q))
+	// the item left in the bulk lane is dropped
+	assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, time.Millisecond)
+
+	// the item is added to the bulk lane again after it is sent
+	p.set("item", v1alpha1.MessagePriorityBulk)
+	q.Add("item")
+	assert.Equal(t, "item", getItem(t, q))
+}
+
+func TestPriorityQueueAddInFlight(t *testing.T) {
+	q, p := newTestPriorityQueue(t, nil)
+	q.Add("workload-item")
+	waitReady(t, q, v1alpha1.MessagePriorityWorkload)
+
+	// the item handed to the workload lane is added with another priority
+	p.set("workload-item", v1alpha1.MessagePriorityControl)
+	q.Add("workload-item")
+	item, quit := q.Get()
+	require.False(t, quit)
+	assert.Equal(t, "workload-item", item)
+	q.Add("workload-item")
+
+	// it is not handed out again until it is done
+	assert.Never(t, func() bool { return q.Len() > 0 }, 100*time.Millisecond, time.Millisecond)
+	q.Done(item)
+	waitReady(t, q, v1alpha1.MessagePriorityControl)
+	assert.Equal(t, "workload-item", getItem(t, q))
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestPriorityQueueRateLimit(t *testing.T) {
+	q, _ := newTestPriorityQueue(t, map[v1alpha1.MessagePriority]v1alpha1.MessageLane{
+		v1alpha1.MessagePriorityBulk: {QPS: 0.001, Burst: 1},
+	})
+	q.Add("bulk-1")
+	q.Add("bulk-2")
+	assert.Equal(t, "bulk-1", getItem(t, q))
+	q.Add("control-1")
+	assert.Equal(t, "control-1", getItem(t, q))
+	// the bulk lane runs out of its tokens, while the other lanes are not affected
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 1, q.Len())
+	q.Add("workload-1")
+	assert.Equal(t, "workload-1", getItem(t, q))
+}
+
+func TestPriorityQueueShutDown(t *testing.T) {
+	q, _ := newTestPriorityQueue(t, nil)
+	done := make(chan bool)
+	go func() {
+		_, quit := q.Get()
+		done <- quit
+	}()
+	q.ShutDown()
+	select {
+	case quit := <-done:
+		assert.True(t, quit)
+	case <-time.After(time.Second):
+		t.Fatal("Get is not returned after the queue is shut down")
+	}
+	assert.True(t, q.ShuttingDown())
+}
@@ -18,12 +18,14 @@ package dispatcher
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
@@ -31,6 +33,7 @@ import (
 	synclisters "github.com/kubeedge/api/client/listers/reliablesyncs/v1alpha1"
 	beehivecontext "github.com/kubeedge/beehive/pkg/core/context"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/bandwidth"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
@@ -40,6 +43,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
 	taskutil "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/v1alpha1/util"
 	commonconst "github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
 	"github.com/kubeedge/kubeedge/pkg/metaserver"
 	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
@@ -107,6 +111,9 @@ type messageDispatcher struct {
 
 	// clusterObjectSyncLister can list/get clusterObjectSync from the shared informer's store
 	clusterObjectSyncLister synclisters.ClusterObjectSyncLister
+
+	// statusQueue queues the resource versions of objectSyncs to be updated
+	statusQueue workqueue.RateLimitingInterface
 }
 
 // NewMessageDispatcher initializes a new MessageDispatcher
@@ -120,10 +127,21 @@ func NewMessageDispatcher(
 		clusterObjectSyncLister: clusterObjectSyncLister,
 		reliableClient:          reliableClient,
 		SessionManager:          sessionManager,
+		statusQueue:             newStatusQueue(),
 	}
 }
 
+func newStatusQueue() workqueue.RateLimitingInterface {
+	return workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "objectsync-status")
+}
+
 func (md *messageDispatcher) DispatchDownstream() {
+	go md.runStatusWorker()
+	go func() {
+		<-beehivecontext.Done()
+		md.statusQueue.ShutDown()
+	}()
+
 	for {
 		select {
 		case <-beehivecontext.Done():
@@ -196,6 +214,38 @@ func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, inf
 			BuildRouter(modules.CloudHubModuleName, "resource", fmt.Sprintf("node/%s/%s", info.NodeID, message.GetResource()), beehivemodel.ResponseOperation)
 		beehivecontext.Send(modules.CloudHubModuleName, *respMsg)
 
+	case message.GetResource() == commonconst.ResourceTypeObjectResync:
+		respMsg := beehivemodel.NewMessage(message.GetID()).
+			BuildRouter(modules.CloudHubModuleName, "resource", fmt.Sprintf("node/%s/%s", info.NodeID, message.GetResource()), beehivemodel.ResponseOperation)
+		count, err := md.resyncObjects(info.NodeID)
+		if err != nil {
+			klog.Errorf("failed to resync objects of node %s: %v", info.NodeID, err)
+			respMsg.SetResourceOperation(respMsg.GetResource(), beehivemodel.ResponseErrorOperation)
+			respMsg.FillBody(err.Error())
+		} else {
+			klog.Infof("%d objects of node %s are queued to resync", count, info.NodeID)
+			respMsg.FillBody(fmt.Sprintf("%d objects are queued to resync", count))
+		}
+		beehivecontext.Send(modules.CloudHubModuleName, *respMsg)
+
+	case message.GetResource() == commonconst.ResourceTypeObjectDigest:
+		respMsg := beehivemodel.NewMessage(message.GetID()).
+			BuildRouter(modules.CloudHubModuleName, "resource", fmt.Sprintf("node/%s/%s", info.NodeID, message.GetResource()), beehivemodel.ResponseOperation)
+		resp, err := md.handleObjectDigest(info.NodeID, message)
+		if err != nil {
+			klog.Errorf("failed to sync object digest of node %s: %v", info.NodeID, err)
+			respMsg.SetResourceOperation(respMsg.GetResource(), beehivemodel.ResponseErrorOperation)
+			respMsg.FillBody(err.Error())
+		} else {
+			respMsg.FillBody(resp)
+		}
+		beehivecontext.Send(modules.CloudHubModuleName, *respMsg)
+
+	case message.GetResource() == commonconst.ResourceTypeBandwidthUsage:
+		if err := recordBandwidthUsage(info.NodeID, message); err != nil {
+			klog.Errorf("failed to record bandwidth usage of node %s: %v", info.NodeID, err)
+		}
+
 	default:
 		err := md.PubToController(info, message)
 		if err != nil {
@@ -416,6 +466,20 @@ func (md *messageDispatcher) enqueueNamespacedResource(nodeID string, msg *beehi
 	return false
 }
 
+// resyncObjects resets the resource version in the status of all the objectSyncs and
+// clusterObjectSyncs of the node, so that the sync controller resends all the objects
+// to the node whose database is rebuilt. The count of objectSyncs queued is returned.
+func (md *messageDispatcher) resyncObjects(nodeID string) (int, error) {
+	syncs, err := md.listNodeObjectSyncs(nodeID)
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range syncs {
+		md.setResourceVersion(s, "0")
+	}
+	return len(syncs), nil
+}
+
 func isDeleteMessage(msg *beehivemodel.Message) bool {
 	if msg.GetOperation() == beehivemodel.DeleteOperation {
 		return true
@@ -458,6 +522,10 @@ func noAckRequired(msg *beehivemodel.Message) bool {
 		return true
 	case strings.Contains(msgResource, beehivemodel.ResourceTypeK8sCA):
 		return true
+	case strings.Contains(msgResource, commonconst.ResourceTypeObjectResync):
+		return true
+	case strings.Contains(msgResource, commonconst.ResourceTypeObjectDigest):
+		return true
 	case isVolumeOperation(msg.GetOperation()):
 		return true
 	case msg.Router.Operation == metaserver.ApplicationResp:
@@ -547,3 +615,20 @@ func (md *messageDispatcher) Publish(msg *beehivemodel.Message) error {
 	}
 	return nil
 }
+
+// recordBandwidthUsage counts the upstream bytes reported by the edge node into its budget
+func recordBandwidthUsage(nodeID string, message *beehivemodel.Message) error {
+	if !bandwidth.Enabled() {
+		return nil
+	}
+	data, err := message.GetContentData()
+	if err != nil {
+		return err
+	}
+	var usage types.BandwidthUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return err
+	}
+	bandwidth.GetMeter(nodeID).RecordUpstream(usage.UpstreamBytes)
+	return nil
+}
@@ -25,14 +25,19 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	"github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
 	"github.com/kubeedge/api/client/clientset/versioned/fake"
 	syncinformer "github.com/kubeedge/api/client/informers/externalversions"
 	synclisters "github.com/kubeedge/api/client/listers/reliablesyncs/v1alpha1"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/bandwidth"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
+	"github.com/kubeedge/kubeedge/common/types"
 	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
 )
 
@@ -92,6 +97,11 @@ func TestNoAckRequired(t *testing.T) {
 			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/default/node/edge-node", "response").FillBody(fmt.Errorf("error")),
 			want:    true,
 		},
+		{
+			name:    "object resync message",
+			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/objectresync", "response"),
+			want:    true,
+		},
 		{
 			name:    "normal pod update",
 			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/default/pod/test-pod", "update").SetRoute("edgecontroller", "resource"),
@@ -361,6 +371,76 @@ func TestEnqueueAckMessage(t *testing.T) {
 	}
 }
 
+func TestResyncObjects(t *testing.T) {
+	client := &fake.Clientset{}
+	nodeObjectSync := tf.NewObjectSync(tf.NewTestPodResource(tf.TestPodName, tf.TestPodUID, "2"), "Pod")
+	otherObjectSync := tf.NewObjectSync(tf.NewTestPodResource(tf.TestPodName, tf.TestDiffPodUID, "3"), "Pod")
+	otherObjectSync.Name = synccontroller.BuildObjectSyncName(tf.TestNodeID+".other", tf.TestDiffPodUID)
+
+	objectSyncIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = objectSyncIndexer.Add(nodeObjectSync)
+	_ = objectSyncIndexer.Add(otherObjectSync)
+	reactor := tf.NewObjectSyncReactor(client, tf.NoErrors)
+	reactor.AddObjectSyncs([]*v1alpha1.ObjectSync{nodeObjectSync, otherObjectSync})
+
+	dispatcher := &messageDispatcher{
+		reliableClient:          client,
+		objectSyncLister:        synclisters.NewObjectSyncLister(objectSyncIndexer),
+		clusterObjectSyncLister: synclisters.NewClusterObjectSyncLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		statusQueue:             newStatusQueue(),
+	}
+
+	count, err := dispatcher.resyncObjects(tf.TestNodeID)
+	if err != nil {
+		t.Fatalf("resyncObjects() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("resyncObjects() = %d, want 1", count)
+	}
+	// the status is not updated until the status worker processes the queue
+	if err := reactor.CheckObjectSyncs([]*v1alpha1.ObjectSync{nodeObjectSync, otherObjectSync}); err != nil {
+		t.Error(err)
+	}
+	drainStatusQueue(dispatcher)
+	expected := nodeObjectSync.DeepCopy()
+	expected.Status.ObjectResourceVersion = "0"
+	if err := reactor.CheckObjectSyncs([]*v1alpha1.ObjectSync{expected, otherObjectSync}); err != nil {
+		t.Error(err)
+	}
+}
+
+// drainStatusQueue processes the status updates queued
+func drainStatusQueue(md *messageDispatcher) {
+	for md.statusQueue.Len() > 0 {
+		md.processNextStatusUpdate()
+	}
+}
+
+func TestRecordBandwidthUsage(t *testing.T) {
+	budget := config.Config.BandwidthBudget
+	defer func() { config.Config.BandwidthBudget = budget }()
+	nodeID := "bandwidth-node"
+	msg := beehivemodel.NewMessage("").FillBody(types.BandwidthUsage{UpstreamBytes: 100})
+
+	config.Config.BandwidthBudget = nil
+	if err := recordBandwidthUsage(nodeID, msg); err != nil {
+		t.Errorf("recordBandwidthUsage() error = %v", err)
+	}
+
+	config.Config.BandwidthBudget = &cloudcorev1alpha1.CloudHubBandwidthBudget{Enable: true}
+	// the meter lives across the tests
+	before := bandwidth.GetMeter(nodeID).Usage().UpstreamBytes
+	if err := recordBandwidthUsage(nodeID, msg); err != nil {
+		t.Errorf("recordBandwidthUsage() error = %v", err)
+	}
+	if got := bandwidth.GetMeter(nodeID).Usage().UpstreamBytes - before; got != 100 {
+		t.Errorf("upstream bytes = %d, want 100", got)
+	}
+	if err := recordBandwidthUsage(nodeID, beehivemodel.NewMessage("").FillBody("invalid")); err == nil {
+		t.Errorf("recordBandwidthUsage() expects error for invalid content")
+	}
+}
+
 func TestGetAddNodeMessagePool(t *testing.T) {
 	// Initialize the dispatcher
 	client := &fake.Clientset{}
@@ -0,0 +1,267 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+)
+
+// maxStatusUpdateRetries is the max times to retry updating the status of an objectSync
+const maxStatusUpdateRetries = 5
+
+// nodeObjectSync is an objectSync or clusterObjectSync of the node
+type nodeObjectSync struct {
+	objectSync        *v1alpha1.ObjectSync
+	clusterObjectSync *v1alpha1.ClusterObjectSync
+}
+
+func (s nodeObjectSync) spec() v1alpha1.ObjectSyncSpec {
+	if s.objectSync != nil {
+		return s.objectSync.Spec
+	}
+	return s.clusterObjectSync.Spec
+}
+
+func (s nodeObjectSync) key() string {
+	if s.objectSync != nil {
+		return util.DigestKey(s.objectSync.Namespace, s.objectSync.Spec.ObjectName)
+	}
+	return util.DigestKey("", s.clusterObjectSync.Spec.ObjectName)
+}
+
+// resourceVersion returns the resource version of the object sent to the node
+func (s nodeObjectSync) resourceVersion() string {
+	if s.objectSync != nil {
+		return s.objectSync.Status.ObjectResourceVersion
+	}
+	return s.clusterObjectSync.Status.ObjectResourceVersion
+}
+
+// listNodeObjectSyncs lists the objectSyncs and clusterObjectSyncs of the node
+func (md *messageDispatcher) listNodeObjectSyncs(nodeID string) ([]nodeObjectSync, error) {
+	var syncs []nodeObjectSync
+	objectSyncs, err := md.objectSyncLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objectSyncs: %v", err)
+	}
+	for _, objectSync := range objectSyncs {
+		if synccontroller.NodeNameOfObjectSync(objectSync.Name) == nodeID {
+			syncs = append(syncs, nodeObjectSync{objectSync: objectSync})
+		}
+	}
+	clusterObjectSyncs, err := md.clusterObjectSyncLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusterObjectSyncs: %v", err)
+	}
+	for _, clusterObjectSync := range clusterObjectSyncs {
+		if synccontroller.NodeNameOfObjectSync(clusterObjectSync.Name) == nodeID {
+			syncs = append(syncs, nodeObjectSync{clusterObjectSync: clusterObjectSync})
+		}
+	}
+	return syncs, nil
+}
+
+// statusUpdate is the resource version to be set in the status of an objectSync, or a
+// clusterObjectSync if the namespace is empty
+type statusUpdate struct {
+	namespace       string
+	name            string
+	resourceVersion string
+}
+
+// setResourceVersion queues the update of the resource version of the object sent to the
+// node, the sync controller resends the object if it is older than the one in K8s. The
+// status is updated by the status worker, so that the upstream messages are not blocked
+// by the requests to K8s.
+func (md *messageDispatcher) setResourceVersion(s nodeObjectSync, resourceVersion string) {
+	update := statusUpdate{resourceVersion: resourceVersion}
+	if s.objectSync != nil {
+		update.namespace, update.name = s.objectSync.Namespace, s.objectSync.Name
+	} else {
+		update.name = s.clusterObjectSync.Name
+	}
+	md.statusQueue.Add(update)
+}
+
+// runStatusWorker updates the status of objectSyncs queued until the queue is shut down
+func (md *messageDispatcher) runStatusWorker() {
+	for md.processNextStatusUpdate() {
+	}
+}
+
+func (md *messageDispatcher) processNextStatusUpdate() bool {
+	item, shutdown := md.statusQueue.Get()
+	if shutdown {
+		return false
+	}
+	defer md.statusQueue.Done(item)
+
+	update := item.(statusUpdate)
+	err := md.updateResourceVersion(update)
+	switch {
+	case err == nil:
+		md.statusQueue.Forget(item)
+	case md.statusQueue.NumRequeues(item) < maxStatusUpdateRetries:
+		klog.Warningf("failed to update resource version of objectSync %s/%s, retry later: %v", update.namespace, update.name, err)
+		md.statusQueue.AddRateLimited(item)
+	default:
+		klog.Errorf("failed to update resource version of objectSync %s/%s: %v", update.namespace, update.name, err)
+		md.statusQueue.Forget(item)
+	}
+	return true
+}
+
+// updateResourceVersion updates the status of the latest objectSync in cache, the update
+// is skipped if the objectSync is deleted
+func (md *messageDispatcher) updateResourceVersion(update statusUpdate) error {
+	if update.namespace != "" {
+		objectSync, err := md.objectSyncLister.ObjectSyncs(update.namespace).Get(update.name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		objectSync = objectSync.DeepCopy()
+		objectSync.Status.ObjectResourceVersion = update.resourceVersion
+		_, err = md.reliableClient.
+			ReliablesyncsV1alpha1().
+			ObjectSyncs(objectSync.Namespace).
+			UpdateStatus(context.Background(), objectSync, metav1.UpdateOptions{})
+		return err
+	}
+
+	clusterObjectSync, err := md.clusterObjectSyncLister.Get(update.name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	clusterObjectSync = clusterObjectSync.DeepCopy()
+	clusterObjectSync.Status.ObjectResourceVersion = update.resourceVersion
+	_, err = md.reliableClient.
+		ReliablesyncsV1alpha1().
+		ClusterObjectSyncs().
+		UpdateStatus(context.Background(), clusterObjectSync, metav1.UpdateOptions{})
+	return err
+}
+
+func (md *messageDispatcher) handleObjectDigest(nodeID string, message *beehivemodel.Message) (*commontypes.ObjectDigestResponse, error) {
+	content, err := message.GetContentData()
+	if err != nil {
+		return nil, err
+	}
+	var digest commontypes.ObjectDigest
+	if err := json.Unmarshal(content, &digest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal object digest: %v", err)
+	}
+	resp, err := md.syncObjectDigest(nodeID, &digest)
+	if err != nil {
+		return nil, err
+	}
+	if digest.Versions == nil {
+		klog.V(2).Infof("object digest of node %s mismatches in resources %v", nodeID, resp.Mismatched)
+	} else {
+		klog.Infof("%d objects are resynced to node %s by object digest", resp.Resynced, nodeID)
+	}
+	return resp, nil
+}
+
+// syncObjectDigest compares the object digest of the node with the objectSyncs of the
+// resources compared by digest. The mismatched resources are returned if only the digests
+// are sent by the node, otherwise the versions of the objectSyncs are lowered to the versions
+// sent by the node, so that only the objects missing or outdated in the node are resent.
+func (md *messageDispatcher) syncObjectDigest(nodeID string, digest *commontypes.ObjectDigest) (*commontypes.ObjectDigestResponse, error) {
+	syncs, err := md.listNodeObjectSyncs(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	syncsByResource := make(map[string][]nodeObjectSync)
+	for _, s := range syncs {
+		// the objectSync just created is not sent to the node yet
+		if s.resourceVersion() == "" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(s.spec().ObjectAPIVersion)
+		if err != nil {
+			continue
+		}
+		gvr := gv.WithResource(util.UnsafeKindToResource(s.spec().ObjectKind)).String()
+		if util.IsDigestResource(gvr) {
+			syncsByResource[gvr] = append(syncsByResource[gvr], s)
+		}
+	}
+	resourceDigest := func(syncs []nodeObjectSync) commontypes.ResourceDigest {
+		versions := make(map[string]string, len(syncs))
+		for _, s := range syncs {
+			versions[s.key()] = s.resourceVersion()
+		}
+		return util.NewResourceDigest(versions)
+	}
+
+	resp := &commontypes.ObjectDigestResponse{}
+	if digest.Versions == nil {
+		for gvr, syncs := range syncsByResource {
+			if resourceDigest(syncs) != digest.Digests[gvr] {
+				resp.Mismatched = append(resp.Mismatched, gvr)
+			}
+		}
+		sort.Strings(resp.Mismatched)
+		return resp, nil
+	}
+
+	for gvr, versions := range digest.Versions {
+		syncs := syncsByResource[gvr]
+		// the objects are not resent if the resource is changed back in the meantime
+		if resourceDigest(syncs) == util.NewResourceDigest(versions) {
+			continue
+		}
+		for _, s := range syncs {
+			version, ok := versions[s.key()]
+			if _, err := strconv.ParseUint(version, 10, 64); !ok || err != nil {
+				version = "0"
+			}
+			if version == s.resourceVersion() {
+				continue
+			}
+			// the versions are never raised, since the node may send the versions of its offline
+			// writes, which would make the later updates of the objects be taken as delivered
+			if _, err := strconv.ParseUint(s.resourceVersion(), 10, 64); err == nil &&
+				synccontroller.CompareResourceVersion(version, s.resourceVersion()) > 0 {
+				continue
+			}
+			md.setResourceVersion(s, version)
+			resp.Resynced++
+		}
+	}
+	return resp, nil
+}
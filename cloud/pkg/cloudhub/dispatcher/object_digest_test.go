@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
+	"github.com/kubeedge/api/client/clientset/versioned/fake"
+	synclisters "github.com/kubeedge/api/client/listers/reliablesyncs/v1alpha1"
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+)
+
+const podsGVR = "/v1, Resource=pods"
+
+func TestSyncObjectDigest(t *testing.T) {
+	pod1 := tf.NewObjectSync(tf.NewTestPodResource("pod-1", tf.TestPodUID, "2"), "Pod")
+	pod2 := tf.NewObjectSync(tf.NewTestPodResource("pod-2", tf.TestDiffPodUID, "3"), "Pod")
+	matched := map[string]commontypes.ResourceDigest{
+		podsGVR: util.NewResourceDigest(map[string]string{
+			util.DigestKey(tf.TestNamespace, "pod-1"): "2",
+			util.DigestKey(tf.TestNamespace, "pod-2"): "3",
+		}),
+	}
+	withVersion := func(objectSync *v1alpha1.ObjectSync, version string) *v1alpha1.ObjectSync {
+		objectSync = objectSync.DeepCopy()
+		objectSync.Status.ObjectResourceVersion = version
+		return objectSync
+	}
+
+	tests := []struct {
+		name                string
+		digest              *commontypes.ObjectDigest
+		expectedResponse    *commontypes.ObjectDigestResponse
+		expectedObjectSyncs []*v1alpha1.ObjectSync
+	}{
+		{
+			name:                "digest matches",
+			digest:              &commontypes.ObjectDigest{Digests: matched},
+			expectedResponse:    &commontypes.ObjectDigestResponse{},
+			expectedObjectSyncs: []*v1alpha1.ObjectSync{pod1, pod2},
+		},
+		{
+			name:                "digest mismatches",
+			digest:              &commontypes.ObjectDigest{Digests: map[string]commontypes.ResourceDigest{}},
+			expectedResponse:    &commontypes.ObjectDigestResponse{Mismatched: []string{podsGVR}},
+			expectedObjectSyncs: []*v1alpha1.ObjectSync{pod1, pod2},
+		},
+		{
+			name: "versions are lowered to the versions in edge",
+			digest: &commontypes.ObjectDigest{Versions: map[string]map[string]string{
+				podsGVR: {
+					util.DigestKey(tf.TestNamespace, "pod-1"): "2",
+					// the update of the object is lost in edge
+					util.DigestKey(tf.TestNamespace, "pod-2"): "1",
+				},
+			}},
+			expectedResponse:    &commontypes.ObjectDigestResponse{Resynced: 1},
+			expectedObjectSyncs: []*v1alpha1.ObjectSync{pod1, withVersion(pod2, "1")},
+		},
+		{
+			name: "versions are not raised to the versions in edge",
+			digest: &commontypes.ObjectDigest{Versions: map[string]map[string]string{
+				podsGVR: {
+					util.DigestKey(tf.TestNamespace, "pod-1"): "2",
+					// the object written offline in edge
+					util.DigestKey(tf.TestNamespace, "pod-2"): "5",
+				},
+			}},
+			expectedResponse:    &commontypes.ObjectDigestResponse{},
+			expectedObjectSyncs: []*v1alpha1.ObjectSync{pod1, pod2},
+		},
+		{
+			name: "missing objects are resent",
+			digest: &commontypes.ObjectDigest{Versions: map[string]map[string]string{
+				podsGVR: {util.DigestKey(tf.TestNamespace, "pod-1"): "invalid"},
+			}},
+			expectedResponse:    &commontypes.ObjectDigestResponse{Resynced: 2},
+			expectedObjectSyncs: []*v1alpha1.ObjectSync{withVersion(pod1, "0"), withVersion(pod2, "0")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fake.Clientset{}
+			otherNode := tf.NewObjectSync(tf.NewTestPodResource("pod-3", "other-uid", "4"), "Pod")
+			otherNode.Name = "other-node.other-uid"
+			// the rules are not stored in meta_v2 of edge, they are never compared by digest
+			rule := tf.NewObjectSync(tf.NewTestPodResource("rule-1", "rule-uid", "7"), "Rule")
+			rule.Spec.ObjectAPIVersion = "rules.kubeedge.io/v1"
+			initial := []*v1alpha1.ObjectSync{pod1, pod2, otherNode, rule}
+
+			objectSyncIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, objectSync := range initial {
+				_ = objectSyncIndexer.Add(objectSync)
+			}
+			reactor := tf.NewObjectSyncReactor(client, tf.NoErrors)
+			reactor.AddObjectSyncs(initial)
+
+			dispatcher := &messageDispatcher{
+				reliableClient:          client,
+				objectSyncLister:        synclisters.NewObjectSyncLister(objectSyncIndexer),
+				clusterObjectSyncLister: synclisters.NewClusterObjectSyncLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+				statusQueue:             newStatusQueue(),
+			}
+
+			resp, err := dispatcher.syncObjectDigest(tf.TestNodeID, test.digest)
+			if err != nil {
+				t.Fatalf("syncObjectDigest() error = %v", err)
+			}
+			drainStatusQueue(dispatcher)
+			if !reflect.DeepEqual(resp, test.expectedResponse) {
+				t.Errorf("syncObjectDigest() = %+v, want %+v", resp, test.expectedResponse)
+			}
+			if err := reactor.CheckObjectSyncs(append(test.expectedObjectSyncs, otherNode, rule)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
 	"github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/controller"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
@@ -126,6 +127,19 @@ func (mh *messageHandler) HandleConnection(connection conn.Connection) {
 		return
 	}
 
+	// the revoked certificates are rejected in the TLS handshake, but the connections
+	// without client certificates, e.g. over QUIC, are checked by the node ID
+	if revocation.IsRevokedPeer(nodeID, connection.ConnectionState().PeerCertificates) {
+		klog.Errorf("The connection is rejected by CloudHub: node=%q is revoked", nodeID)
+		return
+	}
+
+	// the node reconnects before its session is closed, e.g. its address is changed
+	if mh.SessionManager.ResumeSession(nodeID, connection) {
+		klog.Infof("edge node %s for project %s reconnected", nodeID, projectID)
+		return
+	}
+
 	if mh.SessionManager.ReachLimit() {
 		klog.Errorf("Fail to serve node %s, reach node limit", nodeID)
 		return
@@ -205,5 +219,5 @@ func (mh *messageHandler) OnReadTransportErr(nodeID, projectID string) {
 		return
 	}
 
-	nodeSession.Terminating()
+	nodeSession.OnTransportErr()
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -28,6 +29,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	cloudcoreConfig "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	reliableclient "github.com/kubeedge/api/client/clientset/versioned"
@@ -35,8 +40,11 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
 	commonclient "github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/mux"
 )
@@ -253,6 +261,24 @@ func TestHandleConnectionEarlyReturnBranches(t *testing.T) {
 		assert.Equal(t, 0, d.publishCount)
 	})
 
+	t.Run("revoked node without certificate", func(t *testing.T) {
+		l := certs.NewRevocationList()
+		l.RevokeNode("node-revoked")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: certs.RevokedCertsSecretName, Namespace: constants.SystemNamespace},
+			Data:       l.Data(),
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, revocation.Start(ctx, fake.NewSimpleClientset(secret), nil))
+
+		d := &fakeDispatcher{}
+		mh := newMessageHandlerForTest(session.NewSessionManager(10), d, &fakeAuthorizer{})
+
+		mh.HandleConnection(newFakeConnection("node-revoked", "project-revoked"))
+		assert.Equal(t, 0, d.publishCount)
+	})
+
 	t.Run("reach limit", func(t *testing.T) {
 		d := &fakeDispatcher{}
 		a := &fakeAuthorizer{}
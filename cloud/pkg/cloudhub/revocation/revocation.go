@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// current is the revocation list loaded from the secret, it is empty until Start
+var current atomic.Pointer[certs.RevocationList]
+
+func init() {
+	current.Store(certs.NewRevocationList())
+}
+
+// Start watches the secret of the revocation list until ctx is done, onChange is called
+// after the list is changed. It returns once the list is loaded. The nodes revoked without
+// a time of revocation, e.g. by keadm, are revoked at the time they are loaded.
+func Start(ctx context.Context, kubeClient kubernetes.Interface, onChange func()) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithNamespace(constants.SystemNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", certs.RevokedCertsSecretName).String()
+		}))
+	informer := factory.Core().V1().Secrets().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			load(ctx, kubeClient, obj.(*corev1.Secret), onChange)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			load(ctx, kubeClient, newObj.(*corev1.Secret), onChange)
+		},
+		DeleteFunc: func(interface{}) {
+			load(ctx, kubeClient, nil, onChange)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch the revocation list: %v", err)
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return errors.New("failed to load the revocation list")
+	}
+	return nil
+}
+
+func load(ctx context.Context, kubeClient kubernetes.Interface, secret *corev1.Secret, onChange func()) {
+	var data map[string][]byte
+	if secret != nil {
+		data = secret.Data
+	}
+	l, err := certs.ParseRevocationList(data)
+	if err != nil {
+		// keep the last valid list rather than accepting the revoked certificates
+		klog.Errorf("failed to parse the revocation list in secret %s: %v", certs.RevokedCertsSecretName, err)
+		return
+	}
+	// the certificates are issued on the clock of CloudCore, so is the time of revocation
+	if l.SetRevocationTime(time.Now()) {
+		updated := secret.DeepCopy()
+		updated.Data = l.Data()
+		// the list is reloaded once it is updated, the update conflicting with
+		// another CloudCore is dropped since the list is updated by it
+		if _, err := kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("failed to set the revocation time in secret %s: %v", certs.RevokedCertsSecretName, err)
+		}
+	}
+	current.Store(l)
+	klog.Infof("revocation list is loaded, %d certificates and %d nodes are revoked", len(l.Serials), len(l.Nodes))
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// IsRevoked returns whether the certificate is revoked
+func IsRevoked(cert *x509.Certificate) bool {
+	return current.Load().IsRevoked(cert)
+}
+
+// VerifyPeerCertificate rejects the TLS handshake with a revoked client certificate,
+// it is used as tls.Config.VerifyPeerCertificate
+func VerifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse client certificate: %v", err)
+	}
+	if IsRevoked(cert) {
+		return fmt.Errorf("client certificate %s of %s is revoked", certs.SerialString(cert.SerialNumber), cert.Subject.CommonName)
+	}
+	return nil
+}
+
+// IsRevokedPeer returns whether the edge node connected with the certificates is revoked.
+// The node is checked by its ID if it presents no certificate, e.g. over QUIC, so a revoked
+// node is refused over QUIC until it is removed from the revocation list.
+func IsRevokedPeer(nodeID string, peerCerts []*x509.Certificate) bool {
+	if len(peerCerts) > 0 {
+		return IsRevoked(peerCerts[0])
+	}
+	return current.Load().IsRevokedNode(nodeID)
+}
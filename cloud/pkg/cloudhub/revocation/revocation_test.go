@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestStart(t *testing.T) {
+	nodeCert := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "system:node:edge-1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: certs.RevokedCertsSecretName, Namespace: constants.SystemNamespace},
+		Data:       certs.NewRevocationList().Data(),
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	changed := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, Start(ctx, client, func() { changed <- struct{}{} }))
+	<-changed
+	assert.False(t, IsRevoked(nodeCert))
+	assert.False(t, IsRevokedPeer("edge-1", []*x509.Certificate{nodeCert}))
+	assert.False(t, IsRevokedPeer("edge-1", nil))
+
+	// revoke the node without the time of revocation
+	l := certs.NewRevocationList()
+	l.RevokeNode("edge-1")
+	secret.Data = l.Data()
+	before := time.Now().Truncate(time.Second)
+	_, err := client.CoreV1().Secrets(constants.SystemNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	select {
+	case <-changed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the revocation list reloaded")
+	}
+	assert.True(t, IsRevoked(nodeCert))
+	assert.True(t, IsRevokedPeer("edge-1", []*x509.Certificate{nodeCert}))
+	// the connections without certificates are checked by the node ID
+	assert.True(t, IsRevokedPeer("edge-1", nil))
+	assert.False(t, IsRevokedPeer("edge-2", nil))
+
+	// the time of revocation is set by CloudCore
+	assert.Eventually(t, func() bool {
+		stored, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(ctx, secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		stamped, err := certs.ParseRevocationList(stored.Data)
+		if err != nil {
+			return false
+		}
+		at := stamped.Nodes["edge-1"]
+		return !at.IsZero() && !at.Before(before) && !at.After(time.Now())
+	}, 10*time.Second, 10*time.Millisecond)
+	// the certificates issued after that are accepted
+	newCert := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "system:node:edge-1"},
+		NotBefore:    time.Now().Add(time.Hour),
+	}
+	assert.Eventually(t, func() bool {
+		return !IsRevoked(newCert)
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.True(t, IsRevoked(nodeCert))
+
+	secret, err = client.CoreV1().Secrets(constants.SystemNamespace).Get(ctx, secret.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// the invalid list is ignored
+	secret.Data = map[string][]byte{"foo": nil}
+	_, err = client.CoreV1().Secrets(constants.SystemNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, IsRevoked(nodeCert))
+
+	// all the certificates are accepted when the list is deleted
+	err = client.CoreV1().Secrets(constants.SystemNamespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return !IsRevoked(nodeCert)
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestVerifyPeerCertificate(t *testing.T) {
+	assert.NoError(t, VerifyPeerCertificate(nil, nil))
+	assert.Error(t, VerifyPeerCertificate([][]byte{[]byte("invalid")}, nil))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+// bootstrapToken is the bootstrap token in the request of EdgeCore
+type bootstrapToken struct {
+	id     string
+	secret string
+}
+
+// getBootstrapToken returns the bootstrap token in the authorization header,
+// ok is false if the header carries the shared token.
+func getBootstrapToken(authorization string) (bt bootstrapToken, ok bool) {
+	bearerToken, found := strings.CutPrefix(authorization, "Bearer ")
+	if !found {
+		return bt, false
+	}
+	bt.id, bt.secret, ok = token.ParseBootstrapTokenString(bearerToken)
+	return bt, ok
+}
+
+// verifyBootstrapToken verifies that the bootstrap token allows the node to join the cluster,
+// and records the usage of the token if use is true.
+func verifyBootstrapToken(ctx context.Context, kubeClient kubernetes.Interface,
+	bt bootstrapToken, nodeName string, use bool) (int, error) {
+	if nodeName == "" {
+		return http.StatusUnauthorized, errors.New("bootstrap token validation failure, node name is empty")
+	}
+	secrets := kubeClient.CoreV1().Secrets(constants.SystemNamespace)
+	code := http.StatusInternalServerError
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := secrets.Get(ctx, token.BootstrapTokenSecretPrefix+bt.id, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				code = http.StatusUnauthorized
+				return fmt.Errorf("bootstrap token %s not found", bt.id)
+			}
+			return err
+		}
+		t, err := token.ParseBootstrapToken(secret.Data)
+		if err != nil {
+			code = http.StatusUnauthorized
+			return fmt.Errorf("invalid bootstrap token %s: %v", bt.id, err)
+		}
+
+		var nodeLabels labels.Set
+		if len(t.NodeLabels) > 0 {
+			node, err := kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			if err == nil {
+				nodeLabels = node.Labels
+			}
+		}
+		now := time.Now()
+		if err := t.Verify(bt.secret, nodeName, nodeLabels, now); err != nil {
+			code = http.StatusUnauthorized
+			return err
+		}
+		if !use {
+			return nil
+		}
+
+		t.Use(nodeName, now)
+		secret.Data = t.Data()
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("edge node %s joined with bootstrap token %s, the token has been used %d times",
+			nodeName, bt.id, len(t.UsedBy))
+		return nil
+	})
+	if err != nil {
+		return code, fmt.Errorf("bootstrap token validation failure, err: %v", err)
+	}
+	return http.StatusOK, nil
+}
+
+// verifyCSRSubject verifies the CSR requests the certificate of the node,
+// the nodes joined with a bootstrap token can't request certificates for other nodes.
+func verifyCSRSubject(payload []byte, nodeName string) error {
+	csr, err := x509.ParseCertificateRequest(payload)
+	if err != nil {
+		return fmt.Errorf("failed to parse the CSR: %v", err)
+	}
+	if csr.Subject.CommonName != fmt.Sprintf("system:node:%s", nodeName) {
+		return fmt.Errorf("CSR common name %s is not match with node %s", csr.Subject.CommonName, nodeName)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+func TestGetBootstrapToken(t *testing.T) {
+	bt, ok := getBootstrapToken("Bearer bootstrap.abcdef.0123456789abcdef")
+	require.True(t, ok)
+	assert.Equal(t, bootstrapToken{id: "abcdef", secret: "0123456789abcdef"}, bt)
+
+	for _, authorization := range []string{"", "bootstrap.abcdef.0123456789abcdef", "Bearer header.payload.signature"} {
+		_, ok := getBootstrapToken(authorization)
+		assert.False(t, ok, authorization)
+	}
+}
+
+func TestVerifyBootstrapToken(t *testing.T) {
+	ctx := context.Background()
+	newToken := func(mutate func(*token.BootstrapToken)) (*token.BootstrapToken, *corev1.Secret) {
+		bt, err := token.NewBootstrapToken(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		mutate(bt)
+		return bt, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: bt.SecretName(), Namespace: constants.SystemNamespace},
+			Data:       bt.Data(),
+		}
+	}
+	singleUse, singleUseSecret := newToken(func(bt *token.BootstrapToken) {
+		bt.UsageLimit = 1
+		bt.NodeNames = []string{"edge-1"}
+	})
+	labeled, labeledSecret := newToken(func(bt *token.BootstrapToken) {
+		bt.NodeLabels = labels.Set{"region": "east"}
+	})
+	expired, expiredSecret := newToken(func(bt *token.BootstrapToken) {
+		bt.Expiration = time.Now().Add(-time.Hour)
+	})
+	client := fake.NewSimpleClientset(singleUseSecret, labeledSecret, expiredSecret, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge-east", Labels: map[string]string{"region": "east"}},
+	})
+	request := func(bt *token.BootstrapToken) bootstrapToken {
+		return bootstrapToken{id: bt.ID, secret: bt.Secret}
+	}
+
+	cases := []struct {
+		name     string
+		token    bootstrapToken
+		nodeName string
+		wantCode int
+	}{
+		{name: "empty node name", token: request(singleUse), wantCode: http.StatusUnauthorized},
+		{name: "not found", token: bootstrapToken{id: "000000", secret: "x"}, nodeName: "edge-1", wantCode: http.StatusUnauthorized},
+		{name: "invalid secret", token: bootstrapToken{id: singleUse.ID, secret: "x"}, nodeName: "edge-1", wantCode: http.StatusUnauthorized},
+		{name: "other node", token: request(singleUse), nodeName: "edge-2", wantCode: http.StatusUnauthorized},
+		{name: "expired", token: request(expired), nodeName: "edge-1", wantCode: http.StatusUnauthorized},
+		{name: "node without labels", token: request(labeled), nodeName: "edge-1", wantCode: http.StatusUnauthorized},
+		{name: "node with labels", token: request(labeled), nodeName: "edge-east", wantCode: http.StatusOK},
+		{name: "allowed node", token: request(singleUse), nodeName: "edge-1", wantCode: http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			code, err := verifyBootstrapToken(ctx, client, c.token, c.nodeName, false)
+			assert.Equal(t, c.wantCode, code, "unexpected error: %v", err)
+		})
+	}
+
+	// the single-use token is consumed and recorded
+	code, err := verifyBootstrapToken(ctx, client, request(singleUse), "edge-1", true)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = verifyBootstrapToken(ctx, client, request(singleUse), "edge-1", true)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	secret, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(ctx, singleUse.SecretName(), metav1.GetOptions{})
+	require.NoError(t, err)
+	used, err := token.ParseBootstrapToken(secret.Data)
+	require.NoError(t, err)
+	require.Len(t, used.UsedBy, 1)
+	assert.Equal(t, "edge-1", used.UsedBy[0].NodeName)
+}
+
+func TestVerifyCSRSubject(t *testing.T) {
+	pk, err := certs.GetCAHandler(certs.CAHandlerTypeX509).GenPrivateKey()
+	require.NoError(t, err)
+	csrPem, err := certs.GetHandler(certs.HandlerTypeX509).CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:edge-1",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	assert.NoError(t, verifyCSRSubject(csrPem.Bytes, "edge-1"))
+	assert.Error(t, verifyCSRSubject(csrPem.Bytes, "edge-2"))
+	assert.Error(t, verifyCSRSubject([]byte("invalid"), "edge-1"))
+}
@@ -31,7 +31,9 @@ import (
 	"k8s.io/klog/v2"
 
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
@@ -47,6 +49,7 @@ func GetCA(_ *restful.Request, response *restful.Response) {
 func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 	r := request.Request
 	nodeName := r.Header.Get(types.HeaderNodeName)
+	var bootstrap *bootstrapToken
 
 	if cert := r.TLS.PeerCertificates; len(cert) > 0 {
 		if err := verifyCert(cert[0], nodeName); err != nil {
@@ -57,7 +60,14 @@ func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 		}
 	} else {
 		authorization := r.Header.Get(types.HeaderAuthorization)
-		if code, err := verifyAuthorization(authorization); err != nil {
+		if bt, ok := getBootstrapToken(authorization); ok {
+			bootstrap = &bt
+			if code, err := verifyBootstrapToken(r.Context(), client.GetKubeClient(), bt, nodeName, false); err != nil {
+				klog.Errorf("edge node %s failed to join with bootstrap token %s, err: %v", nodeName, bt.id, err)
+				resps.Error(response, code, err)
+				return
+			}
+		} else if code, err := verifyAuthorization(authorization); err != nil {
 			klog.Error(err)
 			resps.Error(response, code, err)
 			return
@@ -66,13 +76,37 @@ func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 
 	usagesStr := r.Header.Get(types.HeaderExtKeyUsages)
 	reader := http.MaxBytesReader(response, r.Body, constants.MaxRespBodyLength)
-	certBlock, err := signEdgeCert(reader, usagesStr)
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		message := fmt.Sprintf("failed to read the CSR of edgenode %s, err: %v", nodeName, err)
+		klog.Error(message)
+		resps.ErrorMessage(response, http.StatusBadRequest, message)
+		return
+	}
+	if bootstrap != nil {
+		if err := verifyCSRSubject(payload, nodeName); err != nil {
+			message := fmt.Sprintf("failed to verify the CSR of edgenode %s, err: %v", nodeName, err)
+			klog.Error(message)
+			resps.ErrorMessage(response, http.StatusUnauthorized, message)
+			return
+		}
+	}
+	certBlock, err := signEdgeCert(payload, usagesStr)
 	if err != nil {
 		message := fmt.Sprintf("failed to sign certs for edgenode %s, err: %v", nodeName, err)
 		klog.Error(message)
 		resps.ErrorMessage(response, http.StatusInternalServerError, message)
 		return
 	}
+	if bootstrap != nil {
+		// consume the token only when the certificate is issued, the usage limit is checked again
+		// in case the token has been used by other nodes in the meantime
+		if code, err := verifyBootstrapToken(r.Context(), client.GetKubeClient(), *bootstrap, nodeName, true); err != nil {
+			klog.Errorf("edge node %s failed to join with bootstrap token %s, err: %v", nodeName, bootstrap.id, err)
+			resps.Error(response, code, err)
+			return
+		}
+	}
 	resps.OK(response, certBlock.Bytes)
 }
 
@@ -93,6 +127,9 @@ func verifyCert(cert *x509.Certificate, nodeName string) error {
 	if _, err := cert.Verify(opts); err != nil {
 		return fmt.Errorf("failed to verify edge certificate: %v", err)
 	}
+	if revocation.IsRevoked(cert) {
+		return fmt.Errorf("edge certificate %s is revoked", certs.SerialString(cert.SerialNumber))
+	}
 	return verifyCertSubject(cert, nodeName)
 }
 
@@ -131,7 +168,7 @@ func verifyAuthorization(authorization string) (int, error) {
 }
 
 // signEdgeCert signs the CSR from EdgeCore
-func signEdgeCert(r io.ReadCloser, usagesStr string) (*pem.Block, error) {
+func signEdgeCert(payload []byte, usagesStr string) (*pem.Block, error) {
 	klog.V(4).Infof("receive sign crt request, ExtKeyUsages: %s", usagesStr)
 	var usages []x509.ExtKeyUsage
 	if usagesStr == "" {
@@ -142,10 +179,6 @@ func signEdgeCert(r io.ReadCloser, usagesStr string) (*pem.Block, error) {
 			return nil, fmt.Errorf("unmarshal http header ExtKeyUsages fail, err: %v", err)
 		}
 	}
-	payload, err := io.ReadAll(r)
-	if err != nil {
-		return nil, fmt.Errorf("fail to read file when signing the cert, err: %v", err)
-	}
 	edgeCertSigningDuration := hubconfig.Config.CloudHub.EdgeCertSigningDuration * time.Hour * 24
 	h := certs.GetHandler(certs.HandlerTypeX509)
 	certBlock, err := h.SignCerts(certs.SignCertsOptionsWithCSR(
@@ -1,6 +1,7 @@
 package certificate
 
 import (
+	"context"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/base64"
@@ -10,8 +11,13 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
+	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 )
 
@@ -43,11 +49,24 @@ func TestVerifyCert(t *testing.T) {
 	require.NoError(t, err)
 
 	hubconfig.Config.Ca = caPem.Bytes
-	certs, err := x509.ParseCertificate(certPrm.Bytes)
+	cert, err := x509.ParseCertificate(certPrm.Bytes)
 	require.NoError(t, err)
 
-	err = verifyCert(certs, "testnode")
+	err = verifyCert(cert, "testnode")
 	require.NoError(t, err)
+
+	// the renewal with a revoked certificate is rejected
+	revoked := certs.NewRevocationList()
+	revoked.RevokeSerial(cert.SerialNumber)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = revocation.Start(ctx, fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: certs.RevokedCertsSecretName, Namespace: constants.SystemNamespace},
+		Data:       revoked.Data(),
+	}), nil)
+	require.NoError(t, err)
+	err = verifyCert(cert, "testnode")
+	require.Error(t, err)
 }
 
 func TestVerifyAuthorization(t *testing.T) {
@@ -5,13 +5,19 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"time"
 
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/handler"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/server"
 )
 
@@ -47,6 +53,8 @@ func createTLSConfig(ca, cert, key []byte) tls.Config {
 		ClientAuth:   tls.RequireAndVerifyClientCert,
 		Certificates: []tls.Certificate{certificate},
 		MinVersion:   tls.VersionTLS12,
+		// reject the edge nodes with revoked certificates
+		VerifyPeerCertificate: revocation.VerifyPeerCertificate,
 		// has to match cipher used by NewPrivateKey method, currently is ECDSA
 		CipherSuites: []uint16{
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
@@ -66,6 +74,8 @@ func startWebsocketServer(messageHandler handler.Handler) {
 		OnReadTransportErr: messageHandler.OnReadTransportErr,
 		Addr:               fmt.Sprintf("%s:%d", hubconfig.Config.WebSocket.Address, hubconfig.Config.WebSocket.Port),
 		ExOpts:             api.WSServerOption{Path: "/"},
+		Batch:              batchOptions(),
+		Lanes:              laneOptions(),
 	}
 	klog.Infof("Starting cloudhub %s server on %s", api.ProtocolTypeWS, svc.Addr)
 	klog.Exit(svc.ListenAndServeTLS("", ""))
@@ -80,8 +90,44 @@ func startQuicServer(messageHandler handler.Handler) {
 		ConnNotify:         messageHandler.HandleConnection,
 		OnReadTransportErr: messageHandler.OnReadTransportErr,
 		Addr:               fmt.Sprintf("%s:%d", hubconfig.Config.Quic.Address, hubconfig.Config.Quic.Port),
-		ExOpts:             api.QuicServerOption{MaxIncomingStreams: int(hubconfig.Config.Quic.MaxIncomingStreams)},
+		ExOpts: api.QuicServerOption{
+			MaxIncomingStreams: int(hubconfig.Config.Quic.MaxIncomingStreams),
+			IdleTimeout:        time.Duration(hubconfig.Config.Quic.IdleTimeout) * time.Second,
+		},
+		Batch: batchOptions(),
+		Lanes: laneOptions(),
 	}
 	klog.Infof("Starting cloudhub %s server on %s", api.ProtocolTypeQuic, svc.Addr)
 	klog.Exit(svc.ListenAndServeTLS("", ""))
 }
+
+// batchOptions returns how the messages sent to edge nodes are batched, nil if disabled
+func batchOptions() *conn.BatchOptions {
+	batch := hubconfig.Config.MessageBatch
+	if batch == nil || !batch.Enable {
+		return nil
+	}
+	return &conn.BatchOptions{
+		Window:      time.Duration(batch.WindowMilliseconds) * time.Millisecond,
+		MaxMessages: int(batch.MaxMessages),
+		MaxBytes:    int(batch.MaxBytes),
+	}
+}
+
+// laneOptions returns the priority lanes of the messages sent to edge nodes, nil if the message QoS is disabled
+func laneOptions() *conn.LaneOptions {
+	qos := hubconfig.Config.MessageQoS
+	if qos == nil || !qos.Enable {
+		return nil
+	}
+	index := make(map[v1alpha1.MessagePriority]int, len(v1alpha1.MessagePriorities))
+	opts := &conn.LaneOptions{}
+	for i, priority := range v1alpha1.MessagePriorities {
+		index[priority] = i
+		opts.Weights = append(opts.Weights, int(qos.Lanes[priority].Weight))
+	}
+	opts.Classify = func(msg *beehivemodel.Message) int {
+		return index[common.GetMessagePriority(msg)]
+	}
+	return opts
+}
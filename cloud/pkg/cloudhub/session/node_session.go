@@ -31,15 +31,19 @@ import (
 	"github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
 	reliableclient "github.com/kubeedge/api/client/clientset/versioned"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/bandwidth"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	edgeconst "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
 	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/translator"
 )
 
 var sendRetryInterval = 5 * time.Second
@@ -63,9 +67,20 @@ type NodeSession struct {
 	// projectID is the project ID to which the edge node belongs
 	projectID string
 
-	// connection is the underlying net connection (websocket or QUIC)
+	// connection is the underlying net connection (websocket or QUIC),
+	// it is replaced when the session is resumed
 	connection conn.Connection
 
+	// connLock protects connection and connChanged
+	connLock sync.Mutex
+
+	// connChanged is closed when the connection is replaced
+	connChanged chan struct{}
+
+	// resumeTimeout is how long the session waits for the edge node to reconnect once
+	// the connection is lost, the session is closed at once if it is 0
+	resumeTimeout time.Duration
+
 	// keepaliveInterval is the interval in seconds that keepalive messages
 	// are received from the peer.
 	keepaliveInterval time.Duration
@@ -82,6 +97,9 @@ type NodeSession struct {
 	// reliableClient the objectSync client for interacting with Kubernetes API servers
 	reliableClient reliableclient.Interface
 
+	// meter meters the traffic sent to the edge node, it is nil if the bandwidth budget is disabled
+	meter *bandwidth.Meter
+
 	// terminateErr records the error type of session termination
 	terminateErr int32
 
@@ -100,20 +118,36 @@ func NewNodeSession(
 	reliableClient reliableclient.Interface,
 ) *NodeSession {
 	ctx, cancelFunc := context.WithCancel(context.Background())
+	var meter *bandwidth.Meter
+	if bandwidth.Enabled() {
+		meter = bandwidth.GetMeter(nodeID)
+	}
 	return &NodeSession{
 		ctx:               ctx,
 		cancelFunc:        cancelFunc,
 		nodeID:            nodeID,
 		projectID:         projectID,
 		connection:        connection,
+		connChanged:       make(chan struct{}),
+		resumeTimeout:     getResumeTimeout(),
 		keepaliveInterval: keepaliveInterval,
 		keepaliveChan:     make(chan struct{}, 1),
 		nodeMessagePool:   nodeMessagePool,
 		reliableClient:    reliableClient,
+		meter:             meter,
 		terminateErr:      NoErr,
 	}
 }
 
+// getResumeTimeout returns how long a session waits for the edge node to reconnect
+func getResumeTimeout() time.Duration {
+	resumption := hubconfig.Config.SessionResumption
+	if resumption == nil || !resumption.Enable {
+		return 0
+	}
+	return time.Duration(resumption.Timeout) * time.Second
+}
+
 // KeepAliveMessage receive keepalive message from edge node
 func (ns *NodeSession) KeepAliveMessage() {
 	select {
@@ -132,6 +166,11 @@ func (ns *NodeSession) ReceiveMessageAck(parentID string) {
 	}
 }
 
+// PendingNoAckMessages returns the number of messages not requiring ack which wait to be sent to edge node
+func (ns *NodeSession) PendingNoAckMessages() int {
+	return ns.nodeMessagePool.NoAckMessageQueue.Len()
+}
+
 // Start the main goroutine responsible for serving node session
 func (ns *NodeSession) Start() {
 	klog.Infof("Start session for edge node %s", ns.nodeID)
@@ -173,6 +212,12 @@ func (ns *NodeSession) KeepAliveCheck() {
 		case <-keepaliveTimer.C:
 			klog.Errorf("timeout to receive keepalive for node %s", ns.nodeID)
 
+			// the keepalive check is paused until the edge node reconnects if the session is
+			// resumable, so that the session is not closed before the resume window ends
+			if ns.waitResume(ns.getConnection()) {
+				continue
+			}
+
 			ns.SetTerminateErr(TransportErr)
 
 			// Terminating node session
@@ -238,10 +283,90 @@ func (ns *NodeSession) Terminating() {
 		ns.nodeMessagePool.ShutDown()
 
 		// ignore close error
-		_ = ns.connection.Close()
+		_ = ns.getConnection().Close()
 	})
 }
 
+// Resume replaces the connection of the session with the new connection of the edge node,
+// the messages waiting to be sent are sent through it. It returns false if the session is
+// not resumable or has been closed.
+func (ns *NodeSession) Resume(connection conn.Connection) bool {
+	if ns.resumeTimeout == 0 || ns.ctx.Err() != nil {
+		return false
+	}
+
+	ns.connLock.Lock()
+	old := ns.connection
+	ns.connection = connection
+	close(ns.connChanged)
+	ns.connChanged = make(chan struct{})
+	ns.connLock.Unlock()
+
+	klog.Infof("session of edge node %s is resumed", ns.nodeID)
+	// ignore close error
+	_ = old.Close()
+	return true
+}
+
+// OnTransportErr is called when reading from the connection of the session failed,
+// the session waits for the edge node to reconnect if it is resumable
+func (ns *NodeSession) OnTransportErr() {
+	if ns.resumeTimeout == 0 {
+		ns.Terminating()
+		return
+	}
+
+	connection := ns.getConnection()
+	// the error is from the connection which has been replaced
+	if connection.ConnectionState().State != api.StatDisconnected {
+		return
+	}
+	go func() {
+		if !ns.waitResume(connection) {
+			ns.SetTerminateErr(TransportErr)
+			ns.Terminating()
+		}
+	}()
+}
+
+func (ns *NodeSession) getConnection() conn.Connection {
+	ns.connLock.Lock()
+	defer ns.connLock.Unlock()
+	return ns.connection
+}
+
+// waitResume waits for the failed connection to be replaced, it returns false
+// if the edge node does not reconnect in time
+func (ns *NodeSession) waitResume(failed conn.Connection) bool {
+	if ns.resumeTimeout == 0 {
+		return false
+	}
+
+	ns.connLock.Lock()
+	if ns.connection != failed {
+		ns.connLock.Unlock()
+		return true
+	}
+	changed := ns.connChanged
+	ns.connLock.Unlock()
+
+	// ignore close error
+	_ = failed.Close()
+
+	klog.Warningf("connection of edge node %s is lost, wait %s for it to reconnect", ns.nodeID, ns.resumeTimeout)
+	timer := time.NewTimer(ns.resumeTimeout)
+	defer timer.Stop()
+	select {
+	case <-changed:
+		return true
+	case <-timer.C:
+		klog.Errorf("edge node %s did not reconnect in %s", ns.nodeID, ns.resumeTimeout)
+		return false
+	case <-ns.ctx.Done():
+		return false
+	}
+}
+
 func (ns *NodeSession) SetTerminateErr(terminateErr int32) {
 	if atomic.LoadInt32(&ns.terminateErr) != NoErr {
 		return
@@ -273,7 +398,13 @@ func (ns *NodeSession) syncNoAckMessage() (bool, error) {
 		return false, err
 	}
 
+	var kept bool
 	defer func() {
+		// the deferred message is kept to be sent later, and the batched message
+		// is kept until the batch carrying it is written
+		if kept {
+			return
+		}
 		// delete message from the store
 		if err := ns.nodeMessagePool.NoAckMessageStore.Delete(msg); err != nil {
 			klog.Errorf("failed to delete message from store, err: %v", err)
@@ -288,9 +419,25 @@ func (ns *NodeSession) syncNoAckMessage() (bool, error) {
 
 	klog.V(4).Infof("send message to node %s, %s, content %s", ns.nodeID, msg.String(), msg.Content)
 
+	// the message is admitted before it is trimmed, since its priority depends on the full resource,
+	// and the deferred message is kept in the store as it is
+	delay, size := ns.admit(msg)
+	if delay > 0 {
+		kept = true
+		klog.V(4).Infof("message %s to node %s is deferred for %s by bandwidth budget", msg.GetID(), ns.nodeID, delay)
+		ns.nodeMessagePool.NoAckMessageQueue.AddAfter(key, delay)
+		return false, nil
+	}
+
+	connection := ns.getConnection()
+	if writer, ok := connection.(conn.BatchWriter); ok {
+		kept = true
+		return ns.writeBatchedMessage(connection, writer, key, msg, size)
+	}
+
 	common.TrimMessage(msg)
 
-	if err := ns.connection.WriteMessageAsync(msg); err != nil {
+	if err := ns.writeMessage(msg, size); err != nil {
 		ns.SetTerminateErr(TransportErr)
 		return true, fmt.Errorf("send message to edge node %s err: %v", ns.nodeID, err)
 	}
@@ -313,10 +460,17 @@ func (ns *NodeSession) syncAckMessage() (bool, error) {
 
 	klog.V(4).Infof("send message to node %s, %s, content %s", ns.nodeID, msg.String(), msg.Content)
 
+	delay, size := ns.admit(msg)
+	if delay > 0 {
+		klog.V(4).Infof("message %s to node %s is deferred for %s by bandwidth budget", msg.GetID(), ns.nodeID, delay)
+		ns.nodeMessagePool.AckMessageQueue.AddAfter(key, delay)
+		return false, nil
+	}
+
 	copyMsg := common.DeepCopy(msg)
 	common.TrimMessage(copyMsg)
 
-	err = ns.sendMessageWithRetry(copyMsg, msg)
+	err = ns.sendMessageWithRetry(copyMsg, msg, size)
 	switch {
 	case err == nil:
 		// no err, forget this key and return
@@ -335,7 +489,7 @@ func (ns *NodeSession) syncAckMessage() (bool, error) {
 	}
 }
 
-func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message) error {
+func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message, size int) error {
 	ackChan := make(chan struct{})
 	ns.ackMessageCache.Store(copyMsg.GetID(), ackChan)
 
@@ -343,7 +497,7 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 	retryCount := 0
 	ticker := time.NewTimer(sendRetryInterval)
 
-	err := ns.connection.WriteMessageAsync(copyMsg)
+	err := ns.writeMessage(copyMsg, size)
 	if err != nil {
 		return err
 	}
@@ -359,7 +513,7 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 				return ErrWaitTimeout
 			}
 
-			err := ns.connection.WriteMessageAsync(copyMsg)
+			err := ns.writeMessage(copyMsg, size)
 			if err != nil {
 				return err
 			}
@@ -370,6 +524,71 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 	}
 }
 
+// admit waits for the bandwidth to send msg by the budget of the edge node, and returns the
+// delay if msg is deferred and the size of msg
+func (ns *NodeSession) admit(msg *beehivemodel.Message) (time.Duration, int) {
+	if ns.meter == nil {
+		return 0, 0
+	}
+	size := translator.Size(msg)
+	return ns.meter.Admit(ns.ctx, common.GetMessagePriority(msg), size), size
+}
+
+// writeMessage sends msg of size bytes to the edge node and meters it, msg is sent again
+// through the new connection if the session is resumed after the write failed
+func (ns *NodeSession) writeMessage(msg *beehivemodel.Message, size int) error {
+	for {
+		connection := ns.getConnection()
+		err := connection.WriteMessageAsync(msg)
+		if err == nil {
+			break
+		}
+		if !ns.waitResume(connection) {
+			return err
+		}
+	}
+	if ns.meter != nil {
+		ns.meter.RecordDownstream(int64(size))
+	}
+	return nil
+}
+
+// writeBatchedMessage sends the message of key through the connection which batches the messages.
+// The message is deleted from the store once it is written, or queued again if the batch carrying
+// it failed, so that it is sent through the resumed connection.
+func (ns *NodeSession) writeBatchedMessage(connection conn.Connection, writer conn.BatchWriter,
+	key interface{}, msg *beehivemodel.Message, size int) (bool, error) {
+	copyMsg := common.DeepCopy(msg)
+	common.TrimMessage(copyMsg)
+
+	result := make(chan error, 1)
+	writer.WriteMessageBatched(copyMsg, func(err error) {
+		if err != nil {
+			klog.Errorf("failed to send message %s to node %s, it is queued again: %v", msg.GetID(), ns.nodeID, err)
+			ns.nodeMessagePool.NoAckMessageQueue.AddRateLimited(key)
+		} else {
+			if ns.meter != nil {
+				ns.meter.RecordDownstream(int64(size))
+			}
+			if err := ns.nodeMessagePool.NoAckMessageStore.Delete(msg); err != nil {
+				klog.Errorf("failed to delete message from store, err: %v", err)
+			}
+		}
+		result <- err
+	})
+
+	// the connection is broken if the message failed at once
+	select {
+	case err := <-result:
+		if err != nil && !ns.waitResume(connection) {
+			ns.SetTerminateErr(TransportErr)
+			return true, fmt.Errorf("send message to edge node %s err: %v", ns.nodeID, err)
+		}
+	default:
+	}
+	return false, nil
+}
+
 func (ns *NodeSession) saveSuccessPoint(msg *beehivemodel.Message) {
 	switch {
 	case msg.GetGroup() == deviceconst.GroupTwin:
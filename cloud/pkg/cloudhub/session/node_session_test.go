@@ -18,6 +18,7 @@ package session
 
 import (
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -25,12 +26,16 @@ import (
 	"github.com/golang/mock/gomock"
 	"k8s.io/klog/v2"
 
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	"github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
 	reliableclient "github.com/kubeedge/api/client/clientset/versioned"
 	"github.com/kubeedge/api/client/clientset/versioned/fake"
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
 )
 
@@ -411,3 +416,245 @@ func evaluateTestResults(reactor *tf.ObjectSyncReactor, test tf.TestCase, t *tes
 		t.Errorf("Test %q: %v", test.Name, err)
 	}
 }
+
+func TestNodeSessionResume(t *testing.T) {
+	client := &fake.Clientset{}
+	msg := beehivemodel.NewMessage("").BuildRouter("edgecontroller", "resource", "node/test/default/pod/p", beehivemodel.UpdateOperation)
+
+	t.Run("not resumable", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		mockConn := mockcon.NewMockConnection(mockController)
+		mockConn.EXPECT().WriteMessageAsync(gomock.Any()).Return(errors.New("write err")).Times(1)
+		session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockConn, tf.KeepaliveInterval,
+			common.InitNodeMessagePool(tf.TestNodeID), client)
+
+		if session.Resume(mockcon.NewMockConnection(mockController)) {
+			t.Errorf("expected the session not resumable")
+		}
+		if err := session.writeMessage(msg, 0); err == nil {
+			t.Errorf("expected write err but got nil")
+		}
+	})
+
+	t.Run("resumed after write failed", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		failedConn := mockcon.NewMockConnection(mockController)
+		failedConn.EXPECT().WriteMessageAsync(gomock.Any()).Return(errors.New("write err")).Times(1)
+		failedConn.EXPECT().Close().Return(nil).AnyTimes()
+		newConn := mockcon.NewMockConnection(mockController)
+		newConn.EXPECT().WriteMessageAsync(gomock.Any()).Return(nil).Times(1)
+		session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, failedConn, tf.KeepaliveInterval,
+			common.InitNodeMessagePool(tf.TestNodeID), client)
+		session.resumeTimeout = time.Minute
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- session.writeMessage(msg, 0)
+		}()
+		time.Sleep(50 * time.Millisecond)
+		if !session.Resume(newConn) {
+			t.Fatalf("expected the session resumed")
+		}
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Errorf("expected no err but got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("message is not sent after the session is resumed")
+		}
+	})
+
+	t.Run("not reconnected in time", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		mockConn := mockcon.NewMockConnection(mockController)
+		mockConn.EXPECT().WriteMessageAsync(gomock.Any()).Return(errors.New("write err")).Times(1)
+		mockConn.EXPECT().Close().Return(nil).AnyTimes()
+		session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockConn, tf.KeepaliveInterval,
+			common.InitNodeMessagePool(tf.TestNodeID), client)
+		session.resumeTimeout = 50 * time.Millisecond
+
+		if err := session.writeMessage(msg, 0); err == nil {
+			t.Errorf("expected write err but got nil")
+		}
+	})
+
+	t.Run("read error of the replaced connection", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		oldConn := mockcon.NewMockConnection(mockController)
+		oldConn.EXPECT().Close().Return(nil).AnyTimes()
+		newConn := mockcon.NewMockConnection(mockController)
+		newConn.EXPECT().ConnectionState().Return(conn.ConnectionState{State: api.StatConnected}).AnyTimes()
+		session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, oldConn, tf.KeepaliveInterval,
+			common.InitNodeMessagePool(tf.TestNodeID), client)
+		session.resumeTimeout = 50 * time.Millisecond
+
+		if !session.Resume(newConn) {
+			t.Fatalf("expected the session resumed")
+		}
+		session.OnTransportErr()
+		time.Sleep(100 * time.Millisecond)
+		if session.ctx.Err() != nil {
+			t.Errorf("expected the session not closed by the error of the replaced connection")
+		}
+	})
+}
+
+func TestNodeSessionDeferBulkMessage(t *testing.T) {
+	budget := hubconfig.Config.BandwidthBudget
+	defer func() { hubconfig.Config.BandwidthBudget = budget }()
+	nodeID := "deferred-node"
+	hubconfig.Config.BandwidthBudget = &cloudcorev1alpha1.CloudHubBandwidthBudget{
+		Enable: true,
+		Nodes:  map[string]cloudcorev1alpha1.BandwidthBudget{nodeID: {BytesPerDay: 1}},
+	}
+
+	mockController := gomock.NewController(t)
+	mockConn := mockcon.NewMockConnection(mockController)
+	session := NewNodeSession(nodeID, tf.TestProjectID, mockConn, tf.KeepaliveInterval,
+		common.InitNodeMessagePool(nodeID), &fake.Clientset{})
+	// the daily budget is used up
+	session.meter.RecordDownstream(1)
+
+	configMapMsg := tf.NewConfigMapMessage(tf.NewTestConfigMapResource(tf.TestConfigMapName, tf.TestConfigMapUID, "1"), "update")
+	resource := configMapMsg.GetResource()
+	enqueueNoAckMessage(session.nodeMessagePool, configMapMsg)
+	if exit, err := session.syncNoAckMessage(); exit || err != nil {
+		t.Fatalf("syncNoAckMessage() = %v, %v", exit, err)
+	}
+	// the bulk message is deferred and kept in the store untrimmed
+	deferred, err := session.nodeMessagePool.GetNoAckMessage(configMapMsg.GetID())
+	if err != nil {
+		t.Fatalf("expected the deferred message kept in the store, but got %v", err)
+	}
+	if deferred.GetResource() != resource {
+		t.Errorf("expected the deferred message with resource %s, got %s", resource, deferred.GetResource())
+	}
+
+	// the workload message is sent trimmed
+	podMsg := tf.NewPodMessage(tf.NewTestPodResource(tf.TestPodName, tf.TestPodUID, "1"), "update")
+	mockConn.EXPECT().WriteMessageAsync(gomock.Any()).DoAndReturn(func(msg *beehivemodel.Message) error {
+		if msg.GetID() != podMsg.GetID() || strings.HasPrefix(msg.GetResource(), "node/") {
+			t.Errorf("unexpected message %s sent", msg.String())
+		}
+		return nil
+	}).Times(1)
+	enqueueNoAckMessage(session.nodeMessagePool, podMsg)
+	if exit, err := session.syncNoAckMessage(); exit || err != nil {
+		t.Fatalf("syncNoAckMessage() = %v, %v", exit, err)
+	}
+	if _, err := session.nodeMessagePool.GetNoAckMessage(podMsg.GetID()); err == nil {
+		t.Errorf("expected the sent message deleted from the store")
+	}
+}
+
+// batchConn is a connection which batches the messages, the pending batch is written by flush
+type batchConn struct {
+	*mockcon.MockConnection
+	lock    sync.Mutex
+	pending []func(error)
+	// err fails the messages at once if it is set
+	err error
+}
+
+func (c *batchConn) WriteMessageBatched(_ *beehivemodel.Message, done func(error)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.err != nil {
+		done(c.err)
+		return
+	}
+	c.pending = append(c.pending, done)
+}
+
+func (c *batchConn) flush(err error) {
+	c.lock.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.lock.Unlock()
+	for _, done := range pending {
+		done(err)
+	}
+}
+
+func TestNodeSessionSendBatchedMessage(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockConn := mockcon.NewMockConnection(mockController)
+	mockConn.EXPECT().Close().Return(nil).AnyTimes()
+	connection := &batchConn{MockConnection: mockConn}
+	session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, connection, tf.KeepaliveInterval,
+		common.InitNodeMessagePool(tf.TestNodeID), &fake.Clientset{})
+	pool := session.nodeMessagePool
+
+	msg := tf.NewPodMessage(tf.NewTestPodResource(tf.TestPodName, tf.TestPodUID, "1"), "update")
+	enqueueNoAckMessage(pool, msg)
+	if exit, err := session.syncNoAckMessage(); exit || err != nil {
+		t.Fatalf("syncNoAckMessage() = %v, %v", exit, err)
+	}
+	if _, err := pool.GetNoAckMessage(msg.GetID()); err != nil {
+		t.Fatalf("expected the message kept until the batch is written, but got %v", err)
+	}
+
+	// the message of the failed batch is queued again
+	connection.flush(errors.New("broken"))
+	if _, err := pool.GetNoAckMessage(msg.GetID()); err != nil {
+		t.Fatalf("expected the message kept after the batch failed, but got %v", err)
+	}
+	if exit, err := session.syncNoAckMessage(); exit || err != nil {
+		t.Fatalf("syncNoAckMessage() = %v, %v", exit, err)
+	}
+	connection.flush(nil)
+	if _, err := pool.GetNoAckMessage(msg.GetID()); err == nil {
+		t.Errorf("expected the message deleted once the batch is written")
+	}
+
+	// the session is closed if the message failed at once and the session is not resumable
+	connection.err = errors.New("broken")
+	enqueueNoAckMessage(pool, tf.NewPodMessage(tf.NewTestPodResource(tf.TestPodName, tf.TestPodUID, "2"), "update"))
+	if exit, err := session.syncNoAckMessage(); !exit || err == nil {
+		t.Errorf("syncNoAckMessage() = %v, %v, expected exit with error", exit, err)
+	}
+	if session.GetTerminateErr() != TransportErr {
+		t.Errorf("expected %d got %d", TransportErr, session.GetTerminateErr())
+	}
+}
+
+func TestNodeSessionKeepAliveResume(t *testing.T) {
+	mockController := gomock.NewController(t)
+	oldConn := mockcon.NewMockConnection(mockController)
+	oldConn.EXPECT().Close().Return(nil).AnyTimes()
+	newConn := mockcon.NewMockConnection(mockController)
+	newConn.EXPECT().Close().Return(nil).AnyTimes()
+	keepaliveInterval := 100 * time.Millisecond
+	session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, oldConn, keepaliveInterval,
+		common.InitNodeMessagePool(tf.TestNodeID), &fake.Clientset{})
+	session.resumeTimeout = time.Second
+	go session.KeepAliveCheck()
+	defer session.Terminating()
+
+	// the session survives a gap of keepalive longer than the interval in the resume window
+	time.Sleep(3 * keepaliveInterval)
+	if session.ctx.Err() != nil {
+		t.Fatalf("expected the session kept in the resume window")
+	}
+	if !session.Resume(newConn) {
+		t.Fatalf("expected the session resumed")
+	}
+	for i := 0; i < 3; i++ {
+		session.KeepAliveMessage()
+		time.Sleep(keepaliveInterval / 2)
+	}
+	if session.ctx.Err() != nil {
+		t.Fatalf("expected the resumed session alive")
+	}
+
+	// the session is closed once the resume window ends
+	select {
+	case <-session.ctx.Done():
+	case <-time.After(keepaliveInterval + 2*session.resumeTimeout):
+		t.Fatalf("expected the session closed after the resume window")
+	}
+	if session.GetTerminateErr() != TransportErr {
+		t.Errorf("expected %d got %d", TransportErr, session.GetTerminateErr())
+	}
+}
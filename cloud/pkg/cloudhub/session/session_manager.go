@@ -24,6 +24,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 )
 
 type Manager struct {
@@ -62,6 +63,29 @@ func (sm *Manager) AddSession(session *NodeSession) {
 	monitor.ConnectedNodes.Set(float64(atomic.AddInt32(&sm.NodeNumber, 1)))
 }
 
+// ResumeSession resumes the session of the node with the new connection,
+// it returns false if there is no resumable session for the node
+func (sm *Manager) ResumeSession(nodeID string, connection conn.Connection) bool {
+	session, exist := sm.GetSession(nodeID)
+	if !exist {
+		return false
+	}
+	return session.Resume(connection)
+}
+
+// TerminateSessions terminates the sessions whose connection matches, e.g. the connection
+// with a revoked certificate
+func (sm *Manager) TerminateSessions(match func(nodeID string, connection conn.Connection) bool) {
+	sm.NodeSessions.Range(func(_, value interface{}) bool {
+		session := value.(*NodeSession)
+		if match(session.nodeID, session.getConnection()) {
+			klog.Warningf("terminate the session of node %s", session.nodeID)
+			session.Terminating()
+		}
+		return true
+	})
+}
+
 // DeleteSession delete the node session from session manager
 func (sm *Manager) DeleteSession(session *NodeSession) {
 	cacheSession, exist := sm.GetSession(session.nodeID)
@@ -18,12 +18,14 @@ package session
 
 import (
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"github.com/kubeedge/api/client/clientset/versioned/fake"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
 )
 
@@ -104,3 +106,58 @@ func TestManager_ReceiveMessageAck(t *testing.T) {
 		t.Errorf("expected err but got nil")
 	}
 }
+
+func TestManager_ResumeSession(t *testing.T) {
+	client := &fake.Clientset{}
+	mockController := gomock.NewController(t)
+	mockConn := mockcon.NewMockConnection(mockController)
+	mockConn.EXPECT().Close().Return(nil).AnyTimes()
+	session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockConn, tf.KeepaliveInterval,
+		common.InitNodeMessagePool(tf.TestNodeID), client)
+
+	manager := NewSessionManager(10)
+	if manager.ResumeSession(tf.TestNodeID, mockcon.NewMockConnection(mockController)) {
+		t.Errorf("expected no session resumed for the node without session")
+	}
+
+	manager.AddSession(session)
+	if manager.ResumeSession(tf.TestNodeID, mockcon.NewMockConnection(mockController)) {
+		t.Errorf("expected no session resumed when resumption is disabled")
+	}
+
+	session.resumeTimeout = time.Minute
+	newConn := mockcon.NewMockConnection(mockController)
+	if !manager.ResumeSession(tf.TestNodeID, newConn) {
+		t.Errorf("expected the session resumed")
+	}
+	if session.getConnection() != newConn {
+		t.Errorf("expected the connection of the session replaced")
+	}
+}
+
+func TestManager_TerminateSessions(t *testing.T) {
+	client := &fake.Clientset{}
+	mockController := gomock.NewController(t)
+	revokedConn := mockcon.NewMockConnection(mockController)
+	revokedConn.EXPECT().Close().Return(nil).Times(1)
+	otherConn := mockcon.NewMockConnection(mockController)
+	otherConn.EXPECT().Close().Times(0)
+
+	manager := NewSessionManager(10)
+	revoked := NewNodeSession("revoked-node", tf.TestProjectID, revokedConn, tf.KeepaliveInterval,
+		common.InitNodeMessagePool("revoked-node"), client)
+	other := NewNodeSession("other-node", tf.TestProjectID, otherConn, tf.KeepaliveInterval,
+		common.InitNodeMessagePool("other-node"), client)
+	manager.AddSession(revoked)
+	manager.AddSession(other)
+
+	manager.TerminateSessions(func(_ string, connection conn.Connection) bool {
+		return connection == revokedConn
+	})
+	if revoked.ctx.Err() == nil {
+		t.Errorf("expected the session with the matched connection terminated")
+	}
+	if other.ctx.Err() != nil {
+		t.Errorf("expected the other session not terminated")
+	}
+}
@@ -43,6 +43,7 @@ const (
 	ResourceTypeTwinEdgeUpdated  = "twin/edge_updated"
 	ResourceTypeMembershipDetail = "membership/detail"
 	ResourceDeviceStateUpdated   = "state/update"
+	ResourceTypeDiscoveredDevice = "discovereddevice/report"
 )
 
 // BuildResource return a string as "beehive/pkg/core/model".Message.Router.Resource
@@ -146,6 +147,8 @@ func GetResourceTypeForDevice(resource string) (string, error) {
 		return ResourceTypeMembershipDetail, nil
 	} else if strings.Contains(resource, ResourceDeviceStateUpdated) {
 		return ResourceDeviceStateUpdated, nil
+	} else if strings.Contains(resource, ResourceTypeDiscoveredDevice) {
+		return ResourceTypeDiscoveredDevice, nil
 	}
 	return "", fmt.Errorf("unknown resource, found: %s", resource)
 }
@@ -188,6 +188,14 @@ func TestGetResourceTypeForDevice(t *testing.T) {
 			ResourceTypeMembershipDetail,
 			nil,
 		},
+		{
+			"GetResourceTypeForDevice() ResourceTypeDiscoveredDevice: success",
+			args{
+				resource: fmt.Sprintf("node/%s/%s", "nid", ResourceTypeDiscoveredDevice),
+			},
+			ResourceTypeDiscoveredDevice,
+			nil,
+		},
 		{
 			"GetResourceTypeForDevice() Case 2: no resourceType",
 			args{
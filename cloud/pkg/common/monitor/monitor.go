@@ -47,6 +47,46 @@ var (
 			Help:      "Number of nodes that connected to the cloudHub instance",
 		},
 	)
+
+	NodeTrafficBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "node_traffic_bytes_total",
+			Help:      "Bytes of the traffic between the cloudHub instance and the edge nodes metered by their bandwidth budgets",
+		},
+		[]string{"node", "direction"},
+	)
+
+	NodeDailyTrafficBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "node_daily_traffic_bytes",
+			Help:      "Bytes of the traffic of the edge nodes in the current day (UTC) against their daily budgets",
+		},
+		[]string{"node"},
+	)
+
+	NodeDailyBudgetBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "node_daily_budget_bytes",
+			Help:      "Daily bandwidth budgets of the edge nodes, 0 means no limit",
+		},
+		[]string{"node"},
+	)
+
+	DeferredMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "deferred_messages_total",
+			Help:      "Number of the messages deferred since the edge nodes are over their bandwidth budgets",
+		},
+		[]string{"node"},
+	)
 )
 
 var registerOnce sync.Once
@@ -56,6 +96,10 @@ func registerMetrics() {
 	registerOnce.Do(func() {
 		prometheus.MustRegister(
 			ConnectedNodes,
+			NodeTrafficBytes,
+			NodeDailyTrafficBytes,
+			NodeDailyBudgetBytes,
+			DeferredMessages,
 		)
 	})
 }
@@ -34,4 +34,7 @@ const (
 	KindTypeDeviceModel  = "DeviceModel"
 	KindTypeDeviceStatus = "DeviceStatus"
 	UnixNetworkType      = "unix"
+
+	// LabelDiscoveredDevice is the label of device provisioned for the discovered device
+	LabelDiscoveredDevice = "devices.kubeedge.io/discovered-device"
 )
@@ -5,6 +5,7 @@ const (
 	ResourceTypeTwinEdgeUpdated  = "twin/edge_updated"
 	ResourceTypeMembershipDetail = "membership/detail"
 	ResourceDeviceStateUpdated   = "state/update"
+	ResourceTypeDiscoveredDevice = "discovereddevice/report"
 
 	// Group
 	GroupTwin     = "twin"
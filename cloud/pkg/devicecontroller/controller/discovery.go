@@ -0,0 +1,330 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/devices/v1beta1"
+	crdClientset "github.com/kubeedge/api/client/clientset/versioned"
+	crdinformers "github.com/kubeedge/api/client/informers/externalversions"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/manager"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
+	"github.com/kubeedge/kubeedge/pkg/util"
+)
+
+const (
+	// discoveredDeviceSeenInterval is the minimal interval to refresh the LastSeenTime of discovered device
+	discoveredDeviceSeenInterval = time.Minute
+
+	maxProtocolNameLength = 16
+	maxNodeNameLength     = 36
+)
+
+// DiscoveryController provisions devices for discovered devices matched by DeviceDiscoveryRules
+type DiscoveryController struct {
+	crdClient crdClientset.Interface
+
+	discoveredDeviceManager    *manager.DiscoveredDeviceManager
+	deviceDiscoveryRuleManager *manager.DeviceDiscoveryRuleManager
+	// downstream controller to lookup device models in cache
+	dc *DownstreamController
+}
+
+// syncDiscovery is used to get discovered device and device discovery rule events from informer
+func (dsc *DiscoveryController) syncDiscovery() {
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Info("Stop syncDiscovery")
+			return
+		case e := <-dsc.discoveredDeviceManager.Events():
+			discoveredDevice, ok := e.Object.(*v1beta1.DiscoveredDevice)
+			if !ok {
+				klog.Warningf("Object type: %T unsupported", e.Object)
+				continue
+			}
+			switch e.Type {
+			case watch.Added, watch.Modified:
+				dsc.discoveredDeviceManager.DiscoveredDevice.Store(discoveredDevice.Name, discoveredDevice)
+				dsc.reconcile(discoveredDevice)
+			case watch.Deleted:
+				// the provisioned device is kept, it is owned by the user after provisioning
+				dsc.discoveredDeviceManager.DiscoveredDevice.Delete(discoveredDevice.Name)
+			default:
+				klog.Warningf("DiscoveredDevice event type: %s unsupported", e.Type)
+			}
+		case e := <-dsc.deviceDiscoveryRuleManager.Events():
+			rule, ok := e.Object.(*v1beta1.DeviceDiscoveryRule)
+			if !ok {
+				klog.Warningf("Object type: %T unsupported", e.Object)
+				continue
+			}
+			ruleID := util.GetResourceID(rule.Namespace, rule.Name)
+			switch e.Type {
+			case watch.Added, watch.Modified:
+				dsc.deviceDiscoveryRuleManager.DeviceDiscoveryRule.Store(ruleID, rule)
+			case watch.Deleted:
+				dsc.deviceDiscoveryRuleManager.DeviceDiscoveryRule.Delete(ruleID)
+			default:
+				klog.Warningf("DeviceDiscoveryRule event type: %s unsupported", e.Type)
+				continue
+			}
+			dsc.reconcileAll()
+		}
+	}
+}
+
+// reconcileAll re-evaluates all the discovered devices which are not provisioned
+func (dsc *DiscoveryController) reconcileAll() {
+	dsc.discoveredDeviceManager.DiscoveredDevice.Range(func(_, value interface{}) bool {
+		if discoveredDevice, ok := value.(*v1beta1.DiscoveredDevice); ok {
+			dsc.reconcile(discoveredDevice)
+		}
+		return true
+	})
+}
+
+// reconcile matches the discovered device against the rules and provisions the device when it is approved
+func (dsc *DiscoveryController) reconcile(discoveredDevice *v1beta1.DiscoveredDevice) {
+	if discoveredDevice.Status.Phase == v1beta1.DiscoveredDeviceProvisioned {
+		return
+	}
+
+	status := discoveredDevice.Status.DeepCopy()
+	rule := matchDeviceDiscoveryRule(discoveredDevice, dsc.listDeviceDiscoveryRules())
+	switch {
+	case rule == nil:
+		status.Phase = v1beta1.DiscoveredDevicePending
+		status.MatchedRule = nil
+		status.Message = "no DeviceDiscoveryRule matches the device"
+	case !rule.Spec.AutoApprove && !discoveredDevice.Spec.Approved:
+		status.Phase = v1beta1.DiscoveredDeviceMatched
+		status.MatchedRule = &v1beta1.ObjectReference{Namespace: rule.Namespace, Name: rule.Name}
+		status.Message = "waiting for approval"
+	default:
+		status.MatchedRule = &v1beta1.ObjectReference{Namespace: rule.Namespace, Name: rule.Name}
+		device, err := dsc.provision(discoveredDevice, rule)
+		if err != nil {
+			klog.Warningf("Failed to provision discovered device %s with rule %s/%s: %v", discoveredDevice.Name, rule.Namespace, rule.Name, err)
+			status.Phase = v1beta1.DiscoveredDeviceFailed
+			status.Message = err.Error()
+			break
+		}
+		status.Phase = v1beta1.DiscoveredDeviceProvisioned
+		status.DeviceRef = &v1beta1.ObjectReference{Namespace: device.Namespace, Name: device.Name}
+		status.Message = ""
+	}
+
+	if reflect.DeepEqual(status, &discoveredDevice.Status) {
+		return
+	}
+	newDiscoveredDevice := discoveredDevice.DeepCopy()
+	newDiscoveredDevice.Status = *status
+	result, err := dsc.crdClient.DevicesV1beta1().DiscoveredDevices().UpdateStatus(context.Background(), newDiscoveredDevice, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to update status of discovered device %s: %v", discoveredDevice.Name, err)
+		return
+	}
+	dsc.discoveredDeviceManager.DiscoveredDevice.Store(result.Name, result)
+}
+
+// provision creates the device for the discovered device from the template of rule
+func (dsc *DiscoveryController) provision(discoveredDevice *v1beta1.DiscoveredDevice, rule *v1beta1.DeviceDiscoveryRule) (*v1beta1.Device, error) {
+	if rule.Spec.DeviceModelRef == nil || rule.Spec.DeviceModelRef.Name == "" {
+		return nil, fmt.Errorf("deviceModelRef of DeviceDiscoveryRule %s/%s is empty", rule.Namespace, rule.Name)
+	}
+	deviceModelID := util.GetResourceID(rule.Namespace, rule.Spec.DeviceModelRef.Name)
+	if _, ok := dsc.dc.deviceModelManager.DeviceModel.Load(deviceModelID); !ok {
+		return nil, fmt.Errorf("device model %s does not exist", deviceModelID)
+	}
+
+	device := buildDeviceFromRule(discoveredDevice, rule)
+	created, err := dsc.crdClient.DevicesV1beta1().Devices(device.Namespace).Create(context.Background(), device, metav1.CreateOptions{})
+	if err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		existing, err := dsc.crdClient.DevicesV1beta1().Devices(device.Namespace).Get(context.Background(), device.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if existing.Labels[constants.LabelDiscoveredDevice] != discoveredDevice.Name {
+			return nil, fmt.Errorf("device %s/%s already exists and is not provisioned for the discovered device", device.Namespace, device.Name)
+		}
+		return existing, nil
+	}
+	klog.Infof("Provisioned device %s/%s for discovered device %s", created.Namespace, created.Name, discoveredDevice.Name)
+	return created, nil
+}
+
+// listDeviceDiscoveryRules returns the rules in cache sorted by namespace and name
+func (dsc *DiscoveryController) listDeviceDiscoveryRules() []*v1beta1.DeviceDiscoveryRule {
+	var rules []*v1beta1.DeviceDiscoveryRule
+	dsc.deviceDiscoveryRuleManager.DeviceDiscoveryRule.Range(func(_, value interface{}) bool {
+		if rule, ok := value.(*v1beta1.DeviceDiscoveryRule); ok {
+			rules = append(rules, rule)
+		}
+		return true
+	})
+	sort.Slice(rules, func(i, j int) bool {
+		return util.GetResourceID(rules[i].Namespace, rules[i].Name) < util.GetResourceID(rules[j].Namespace, rules[j].Name)
+	})
+	return rules
+}
+
+// matchDeviceDiscoveryRule returns the first rule matching the discovered device, rules must be sorted
+func matchDeviceDiscoveryRule(discoveredDevice *v1beta1.DiscoveredDevice, rules []*v1beta1.DeviceDiscoveryRule) *v1beta1.DeviceDiscoveryRule {
+	for _, rule := range rules {
+		if isDeviceDiscoveryRuleMatched(discoveredDevice, rule) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func isDeviceDiscoveryRuleMatched(discoveredDevice *v1beta1.DiscoveredDevice, rule *v1beta1.DeviceDiscoveryRule) bool {
+	if rule.Spec.Protocol != discoveredDevice.Spec.Protocol.ProtocolName {
+		return false
+	}
+	if len(rule.Spec.NodeNames) > 0 {
+		matched := false
+		for _, nodeName := range rule.Spec.NodeNames {
+			if nodeName == discoveredDevice.Spec.NodeName {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for k, v := range rule.Spec.MatchAttributes {
+		if value, ok := discoveredDevice.Spec.Attributes[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// buildDeviceFromRule builds the device for the discovered device from the template of rule
+func buildDeviceFromRule(discoveredDevice *v1beta1.DiscoveredDevice, rule *v1beta1.DeviceDiscoveryRule) *v1beta1.Device {
+	template := rule.Spec.Template.DeepCopy()
+	device := &v1beta1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        discoveredDevice.Name,
+			Namespace:   rule.Namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: template.Spec,
+	}
+	if device.Labels == nil {
+		device.Labels = make(map[string]string)
+	}
+	device.Labels[constants.LabelDiscoveredDevice] = discoveredDevice.Name
+	device.Spec.NodeName = discoveredDevice.Spec.NodeName
+	device.Spec.Protocol = *discoveredDevice.Spec.Protocol.DeepCopy()
+	device.Spec.DeviceModelRef = rule.Spec.DeviceModelRef.DeepCopy()
+	return device
+}
+
+// DiscoveredDeviceName returns a stable name of the device discovered on the node
+func DiscoveredDeviceName(protocol, nodeName, deviceID string) string {
+	h := fnv.New32a()
+	h.Write([]byte(protocol + "/" + nodeName + "/" + deviceID))
+	return fmt.Sprintf("%s-%s-%08x", sanitizeName(protocol, maxProtocolNameLength),
+		sanitizeName(nodeName, maxNodeNameLength), h.Sum32())
+}
+
+// sanitizeName converts s to a valid part of DNS-1123 label with at most maxLength characters
+func sanitizeName(s string, maxLength int) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(s))
+	if len(name) > maxLength {
+		name = name[:maxLength]
+	}
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "x"
+	}
+	return name
+}
+
+// buildDiscoveredDevice builds the discovered device reported by mapper on the node
+func buildDiscoveredDevice(nodeName, mapperName string, reported *types.DiscoveredDevice) *v1beta1.DiscoveredDevice {
+	return &v1beta1.DiscoveredDevice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DiscoveredDeviceName(reported.Protocol.ProtocolName, nodeName, reported.ID),
+		},
+		Spec: v1beta1.DiscoveredDeviceSpec{
+			NodeName:   nodeName,
+			MapperName: mapperName,
+			DeviceID:   reported.ID,
+			Protocol:   reported.Protocol,
+			Attributes: reported.Attributes,
+		},
+	}
+}
+
+// Start DiscoveryController
+func (dsc *DiscoveryController) Start() error {
+	klog.Info("Start discovery devicecontroller")
+
+	go dsc.syncDiscovery()
+	return nil
+}
+
+// NewDiscoveryController create a DiscoveryController from config
+func NewDiscoveryController(crdInformerFactory crdinformers.SharedInformerFactory, dc *DownstreamController) (*DiscoveryController, error) {
+	discoveredDeviceManager, err := manager.NewDiscoveredDeviceManager(crdInformerFactory.Devices().V1beta1().DiscoveredDevices().Informer())
+	if err != nil {
+		klog.Warningf("Create discovered device manager failed with error: %s", err)
+		return nil, err
+	}
+
+	deviceDiscoveryRuleManager, err := manager.NewDeviceDiscoveryRuleManager(crdInformerFactory.Devices().V1beta1().DeviceDiscoveryRules().Informer())
+	if err != nil {
+		klog.Warningf("Create device discovery rule manager failed with error: %s", err)
+		return nil, err
+	}
+
+	return &DiscoveryController{
+		crdClient:                  client.GetCRDClient(),
+		discoveredDeviceManager:    discoveredDeviceManager,
+		deviceDiscoveryRuleManager: deviceDiscoveryRuleManager,
+		dc:                         dc,
+	}, nil
+}
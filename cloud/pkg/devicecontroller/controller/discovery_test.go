@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kubeedge/api/apis/devices/v1beta1"
+	"github.com/kubeedge/api/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/manager"
+)
+
+func newTestDiscoveredDevice(approved bool) *v1beta1.DiscoveredDevice {
+	return &v1beta1.DiscoveredDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: DiscoveredDeviceName("modbus", "edge-node", "sn-001")},
+		Spec: v1beta1.DiscoveredDeviceSpec{
+			NodeName:   "edge-node",
+			MapperName: "modbus-mapper",
+			DeviceID:   "sn-001",
+			Protocol:   v1beta1.ProtocolConfig{ProtocolName: "modbus"},
+			Attributes: map[string]string{"vendor": "acme", "model": "t100"},
+			Approved:   approved,
+		},
+	}
+}
+
+func newTestDeviceDiscoveryRule(namespace, name string, autoApprove bool) *v1beta1.DeviceDiscoveryRule {
+	return &v1beta1.DeviceDiscoveryRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1beta1.DeviceDiscoveryRuleSpec{
+			Protocol:        "modbus",
+			MatchAttributes: map[string]string{"vendor": "acme"},
+			DeviceModelRef:  &v1.LocalObjectReference{Name: "thermometer"},
+			AutoApprove:     autoApprove,
+			Template: v1beta1.DeviceTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "thermometer"}},
+				Spec: v1beta1.DeviceSpec{
+					NodeName:   "ignored",
+					Properties: []v1beta1.DeviceProperty{{Name: "temperature"}},
+				},
+			},
+		},
+	}
+}
+
+func TestIsDeviceDiscoveryRuleMatched(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(rule *v1beta1.DeviceDiscoveryRule)
+		want   bool
+	}{
+		{
+			name:   "matched",
+			modify: func(rule *v1beta1.DeviceDiscoveryRule) {},
+			want:   true,
+		},
+		{
+			name:   "protocol mismatched",
+			modify: func(rule *v1beta1.DeviceDiscoveryRule) { rule.Spec.Protocol = "opcua" },
+			want:   false,
+		},
+		{
+			name:   "node matched",
+			modify: func(rule *v1beta1.DeviceDiscoveryRule) { rule.Spec.NodeNames = []string{"other", "edge-node"} },
+			want:   true,
+		},
+		{
+			name:   "node mismatched",
+			modify: func(rule *v1beta1.DeviceDiscoveryRule) { rule.Spec.NodeNames = []string{"other"} },
+			want:   false,
+		},
+		{
+			name:   "attribute value mismatched",
+			modify: func(rule *v1beta1.DeviceDiscoveryRule) { rule.Spec.MatchAttributes["vendor"] = "other" },
+			want:   false,
+		},
+		{
+			name:   "attribute missing",
+			modify: func(rule *v1beta1.DeviceDiscoveryRule) { rule.Spec.MatchAttributes["firmware"] = "1.0" },
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := newTestDeviceDiscoveryRule("default", "rule", true)
+			tt.modify(rule)
+			assert.Equal(t, tt.want, isDeviceDiscoveryRuleMatched(newTestDiscoveredDevice(false), rule))
+		})
+	}
+}
+
+func TestMatchDeviceDiscoveryRule(t *testing.T) {
+	mismatched := newTestDeviceDiscoveryRule("default", "a", true)
+	mismatched.Spec.Protocol = "opcua"
+	first := newTestDeviceDiscoveryRule("default", "b", true)
+	second := newTestDeviceDiscoveryRule("default", "c", true)
+
+	rule := matchDeviceDiscoveryRule(newTestDiscoveredDevice(false), []*v1beta1.DeviceDiscoveryRule{mismatched, first, second})
+	assert.Equal(t, first, rule)
+	assert.Nil(t, matchDeviceDiscoveryRule(newTestDiscoveredDevice(false), []*v1beta1.DeviceDiscoveryRule{mismatched}))
+}
+
+func TestBuildDeviceFromRule(t *testing.T) {
+	discoveredDevice := newTestDiscoveredDevice(false)
+	rule := newTestDeviceDiscoveryRule("default", "rule", true)
+
+	device := buildDeviceFromRule(discoveredDevice, rule)
+	assert.Equal(t, discoveredDevice.Name, device.Name)
+	assert.Equal(t, "default", device.Namespace)
+	assert.Equal(t, "thermometer", device.Labels["app"])
+	assert.Equal(t, discoveredDevice.Name, device.Labels[constants.LabelDiscoveredDevice])
+	assert.Equal(t, "edge-node", device.Spec.NodeName)
+	assert.Equal(t, "modbus", device.Spec.Protocol.ProtocolName)
+	assert.Equal(t, "thermometer", device.Spec.DeviceModelRef.Name)
+	assert.Len(t, device.Spec.Properties, 1)
+	// the template of rule must not be modified
+	assert.Equal(t, "ignored", rule.Spec.Template.Spec.NodeName)
+	assert.NotContains(t, rule.Spec.Template.Labels, constants.LabelDiscoveredDevice)
+}
+
+func TestDiscoveredDeviceName(t *testing.T) {
+	name := DiscoveredDeviceName("Modbus_RTU", "Edge.Node-01", "00:1A:2B:3C")
+	assert.Equal(t, name, DiscoveredDeviceName("Modbus_RTU", "Edge.Node-01", "00:1A:2B:3C"))
+	assert.NotEqual(t, name, DiscoveredDeviceName("Modbus_RTU", "Edge.Node-01", "00:1A:2B:3D"))
+	assert.Empty(t, validation.IsDNS1123Label(name))
+
+	long := DiscoveredDeviceName("a-very-long-protocol-name-for-test", "a-very-long-node-name-which-exceeds-the-limit-of-the-name", "id")
+	assert.Empty(t, validation.IsDNS1123Label(long))
+	assert.Empty(t, validation.IsDNS1123Label(DiscoveredDeviceName("___", "", "id")))
+}
+
+func newTestDiscoveryController(objects ...*v1beta1.DiscoveredDevice) (*DiscoveryController, *fake.Clientset) {
+	client := fake.NewSimpleClientset()
+	for _, obj := range objects {
+		_, _ = client.DevicesV1beta1().DiscoveredDevices().Create(context.Background(), obj, metav1.CreateOptions{})
+	}
+	dc := &DownstreamController{deviceModelManager: &manager.DeviceModelManager{}}
+	return &DiscoveryController{
+		crdClient:                  client,
+		discoveredDeviceManager:    &manager.DiscoveredDeviceManager{},
+		deviceDiscoveryRuleManager: &manager.DeviceDiscoveryRuleManager{},
+		dc:                         dc,
+	}, client
+}
+
+func TestDiscoveryControllerReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no rule matched", func(t *testing.T) {
+		discoveredDevice := newTestDiscoveredDevice(false)
+		dsc, client := newTestDiscoveryController(discoveredDevice)
+		dsc.reconcile(discoveredDevice)
+
+		got, err := client.DevicesV1beta1().DiscoveredDevices().Get(ctx, discoveredDevice.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.DiscoveredDevicePending, got.Status.Phase)
+		assert.Nil(t, got.Status.MatchedRule)
+	})
+
+	t.Run("waiting for approval", func(t *testing.T) {
+		discoveredDevice := newTestDiscoveredDevice(false)
+		dsc, client := newTestDiscoveryController(discoveredDevice)
+		dsc.deviceDiscoveryRuleManager.DeviceDiscoveryRule.Store("default/rule", newTestDeviceDiscoveryRule("default", "rule", false))
+		dsc.reconcile(discoveredDevice)
+
+		got, err := client.DevicesV1beta1().DiscoveredDevices().Get(ctx, discoveredDevice.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.DiscoveredDeviceMatched, got.Status.Phase)
+		assert.Equal(t, &v1beta1.ObjectReference{Namespace: "default", Name: "rule"}, got.Status.MatchedRule)
+		devices, err := client.DevicesV1beta1().Devices("default").List(ctx, metav1.ListOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, devices.Items)
+	})
+
+	t.Run("device model not found", func(t *testing.T) {
+		discoveredDevice := newTestDiscoveredDevice(true)
+		dsc, client := newTestDiscoveryController(discoveredDevice)
+		dsc.deviceDiscoveryRuleManager.DeviceDiscoveryRule.Store("default/rule", newTestDeviceDiscoveryRule("default", "rule", false))
+		dsc.reconcile(discoveredDevice)
+
+		got, err := client.DevicesV1beta1().DiscoveredDevices().Get(ctx, discoveredDevice.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.DiscoveredDeviceFailed, got.Status.Phase)
+		assert.NotEmpty(t, got.Status.Message)
+	})
+
+	t.Run("provisioned", func(t *testing.T) {
+		discoveredDevice := newTestDiscoveredDevice(false)
+		dsc, client := newTestDiscoveryController(discoveredDevice)
+		dsc.deviceDiscoveryRuleManager.DeviceDiscoveryRule.Store("default/rule", newTestDeviceDiscoveryRule("default", "rule", true))
+		dsc.dc.deviceModelManager.DeviceModel.Store("default/thermometer", &v1beta1.DeviceModel{})
+		dsc.reconcile(discoveredDevice)
+
+		got, err := client.DevicesV1beta1().DiscoveredDevices().Get(ctx, discoveredDevice.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.DiscoveredDeviceProvisioned, got.Status.Phase)
+		assert.Equal(t, &v1beta1.ObjectReference{Namespace: "default", Name: discoveredDevice.Name}, got.Status.DeviceRef)
+		device, err := client.DevicesV1beta1().Devices("default").Get(ctx, discoveredDevice.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "edge-node", device.Spec.NodeName)
+
+		// provisioned devices are not reconciled again
+		dsc.deviceDiscoveryRuleManager.DeviceDiscoveryRule.Delete("default/rule")
+		dsc.reconcile(got)
+		got, err = client.DevicesV1beta1().DiscoveredDevices().Get(ctx, discoveredDevice.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1beta1.DiscoveredDeviceProvisioned, got.Status.Phase)
+	})
+}
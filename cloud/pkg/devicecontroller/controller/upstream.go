@@ -19,8 +19,12 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"strconv"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/devices/v1beta1"
@@ -71,8 +75,12 @@ type UpstreamController struct {
 	deviceTwinsChan chan model.Message
 	// deviceStates message channel
 	deviceStatesChan chan model.Message
+	// discoveredDevices message channel
+	discoveredDevicesChan chan model.Message
 	// downstream controller to update device status in cache
 	dc *DownstreamController
+	// discovery controller to lookup discovered devices in cache
+	dsc *DiscoveryController
 }
 
 // Start UpstreamController
@@ -81,7 +89,9 @@ func (uc *UpstreamController) Start() error {
 
 	uc.deviceTwinsChan = make(chan model.Message, config.Config.Buffer.UpdateDeviceTwins)
 	uc.deviceStatesChan = make(chan model.Message, config.Config.Buffer.UpdateDeviceStates)
+	uc.discoveredDevicesChan = make(chan model.Message, config.Config.Buffer.ReportDiscoveredDevices)
 	go uc.dispatchMessage()
+	go uc.updateDiscoveredDevices()
 
 	for i := 0; i < int(config.Config.Load.UpdateDeviceStatusWorkers); i++ {
 		go uc.updateDeviceStatus()
@@ -117,6 +127,8 @@ func (uc *UpstreamController) dispatchMessage() {
 			uc.deviceTwinsChan <- msg
 		case constants.ResourceDeviceStateUpdated:
 			uc.deviceStatesChan <- msg
+		case constants.ResourceTypeDiscoveredDevice:
+			uc.discoveredDevicesChan <- msg
 		case constants.ResourceTypeMembershipDetail:
 		default:
 			klog.Warningf("Message: %s, with resource type: %s not intended for device controller", msg.GetID(), resourceType)
@@ -310,6 +322,97 @@ func (uc *UpstreamController) updateDeviceStatus() {
 	}
 }
 
+func (uc *UpstreamController) updateDiscoveredDevices() {
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Info("Stop updateDiscoveredDevices")
+			return
+		case msg := <-uc.discoveredDevicesChan:
+			klog.V(4).Infof("Message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			report, err := uc.unmarshalDiscoveredDevicesMessage(msg)
+			if err != nil {
+				klog.Warningf("Unmarshall failed due to error %v", err)
+				continue
+			}
+			nodeID, err := messagelayer.GetNodeID(msg)
+			if err != nil {
+				klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
+				continue
+			}
+			for i := range report.Devices {
+				discovered := buildDiscoveredDevice(nodeID, report.MapperName, &report.Devices[i])
+				if err := uc.upsertDiscoveredDevice(discovered); err != nil {
+					klog.Errorf("Failed to update discovered device %s reported by mapper %s on node %s, err: %v",
+						discovered.Spec.DeviceID, report.MapperName, nodeID, err)
+				}
+			}
+		}
+	}
+}
+
+// upsertDiscoveredDevice creates or updates the discovered device and refreshes its LastSeenTime
+func (uc *UpstreamController) upsertDiscoveredDevice(discovered *v1beta1.DiscoveredDevice) error {
+	ctx := context.Background()
+	client := uc.crdClient.DevicesV1beta1().DiscoveredDevices()
+
+	var current *v1beta1.DiscoveredDevice
+	if cached, ok := uc.dsc.discoveredDeviceManager.DiscoveredDevice.Load(discovered.Name); ok {
+		current, _ = cached.(*v1beta1.DiscoveredDevice)
+	}
+	if current == nil {
+		created, err := client.Create(ctx, discovered, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		if err == nil {
+			current = created
+		} else if current, err = client.Get(ctx, discovered.Name, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+
+	if current.Spec.MapperName != discovered.Spec.MapperName ||
+		!reflect.DeepEqual(current.Spec.Protocol, discovered.Spec.Protocol) ||
+		!reflect.DeepEqual(current.Spec.Attributes, discovered.Spec.Attributes) {
+		updated := current.DeepCopy()
+		updated.Spec.MapperName = discovered.Spec.MapperName
+		updated.Spec.Protocol = discovered.Spec.Protocol
+		updated.Spec.Attributes = discovered.Spec.Attributes
+		result, err := client.Update(ctx, updated, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		current = result
+	}
+
+	now := metav1.Now()
+	if current.Status.LastSeenTime != nil && now.Sub(current.Status.LastSeenTime.Time) < discoveredDeviceSeenInterval {
+		return nil
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"lastSeenTime": now},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.Patch(ctx, current.Name, apimachinerytypes.MergePatchType, body, metav1.PatchOptions{}, "status")
+	return err
+}
+
+func (uc *UpstreamController) unmarshalDiscoveredDevicesMessage(msg model.Message) (*types.DiscoveredDeviceReport, error) {
+	contentData, err := msg.GetContentData()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &types.DiscoveredDeviceReport{}
+	if err := json.Unmarshal(contentData, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
 func (uc *UpstreamController) unmarshalDeviceStatusMessage(msg model.Message) (*types.DeviceTwinUpdate, error) {
 	contentData, err := msg.GetContentData()
 	if err != nil {
@@ -337,11 +440,12 @@ func (uc *UpstreamController) unmarshalDeviceStatesMessage(msg model.Message) (*
 }
 
 // NewUpstreamController create UpstreamController from config
-func NewUpstreamController(dc *DownstreamController) (*UpstreamController, error) {
+func NewUpstreamController(dc *DownstreamController, dsc *DiscoveryController) (*UpstreamController, error) {
 	uc := &UpstreamController{
 		crdClient:    keclient.GetCRDClient(),
 		messageLayer: messagelayer.DeviceControllerMessageLayer(),
 		dc:           dc,
+		dsc:          dsc,
 	}
 	return uc, nil
 }
@@ -28,17 +28,20 @@ func TestNewUpstreamController(t *testing.T) {
 	assert := assert.New(t)
 
 	dc := &DownstreamController{}
-	uc, err := NewUpstreamController(dc)
+	dsc := &DiscoveryController{}
+	uc, err := NewUpstreamController(dc, dsc)
 	assert.NoError(err)
 	assert.NotNil(uc)
 
 	assert.NotNil(uc.messageLayer)
 	assert.NotNil(uc.dc)
 	assert.Equal(dc, uc.dc)
+	assert.Equal(dsc, uc.dsc)
 
 	// Channels are not initialized (they should be initialized in Start())
 	assert.Nil(uc.deviceTwinsChan)
 	assert.Nil(uc.deviceStatesChan)
+	assert.Nil(uc.discoveredDevicesChan)
 }
 
 func TestFindOrCreateTwinByName(t *testing.T) {
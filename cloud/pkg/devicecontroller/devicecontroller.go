@@ -17,6 +17,7 @@ import (
 type DeviceController struct {
 	downstream *controller.DownstreamController
 	upstream   *controller.UpstreamController
+	discovery  *controller.DiscoveryController
 	enable     bool
 }
 
@@ -30,13 +31,18 @@ func newDeviceController(enable bool) *DeviceController {
 	if err != nil {
 		klog.Exitf("New downstream controller failed with error: %s", err)
 	}
-	upstream, err := controller.NewUpstreamController(downstream)
+	discovery, err := controller.NewDiscoveryController(informers.GetInformersManager().GetKubeEdgeInformerFactory(), downstream)
+	if err != nil {
+		klog.Exitf("New discovery controller failed with error: %s", err)
+	}
+	upstream, err := controller.NewUpstreamController(downstream, discovery)
 	if err != nil {
 		klog.Exitf("New upstream controller failed with error: %s", err)
 	}
 	return &DeviceController{
 		downstream: downstream,
 		upstream:   upstream,
+		discovery:  discovery,
 		enable:     enable,
 	}
 }
@@ -76,4 +82,7 @@ func (dc *DeviceController) Start() {
 	if err := dc.upstream.Start(); err != nil {
 		klog.Exitf("Start upstream failed with error: %s", err)
 	}
+	if err := dc.discovery.Start(); err != nil {
+		klog.Exitf("Start discovery failed with error: %s", err)
+	}
 }
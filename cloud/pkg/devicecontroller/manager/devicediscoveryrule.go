@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/config"
+)
+
+// DeviceDiscoveryRuleManager is a manager watch DeviceDiscoveryRule change event
+type DeviceDiscoveryRuleManager struct {
+	// events from watch kubernetes api server
+	events chan watch.Event
+
+	// DeviceDiscoveryRule, key is DeviceDiscoveryRule.Namespace+"/"+DeviceDiscoveryRule.Name, value is *v1beta1.DeviceDiscoveryRule{}
+	DeviceDiscoveryRule sync.Map
+}
+
+// Events return a channel, can receive all DeviceDiscoveryRule event
+func (drm *DeviceDiscoveryRuleManager) Events() chan watch.Event {
+	return drm.events
+}
+
+// NewDeviceDiscoveryRuleManager create DeviceDiscoveryRuleManager from config
+func NewDeviceDiscoveryRuleManager(si cache.SharedIndexInformer) (*DeviceDiscoveryRuleManager, error) {
+	events := make(chan watch.Event, config.Config.Buffer.DeviceDiscoveryRuleEvent)
+	rh := NewCommonResourceEventHandler(events)
+	_, err := si.AddEventHandler(rh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeviceDiscoveryRuleManager{events: events}, nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/config"
+)
+
+// DiscoveredDeviceManager is a manager watch DiscoveredDevice change event
+type DiscoveredDeviceManager struct {
+	// events from watch kubernetes api server
+	events chan watch.Event
+
+	// DiscoveredDevice, key is DiscoveredDevice.Name, value is *v1beta1.DiscoveredDevice{}
+	DiscoveredDevice sync.Map
+}
+
+// Events return a channel, can receive all DiscoveredDevice event
+func (dim *DiscoveredDeviceManager) Events() chan watch.Event {
+	return dim.events
+}
+
+// NewDiscoveredDeviceManager create DiscoveredDeviceManager from config
+func NewDiscoveredDeviceManager(si cache.SharedIndexInformer) (*DiscoveredDeviceManager, error) {
+	events := make(chan watch.Event, config.Config.Buffer.DiscoveredDeviceEvent)
+	rh := NewCommonResourceEventHandler(events)
+	_, err := si.AddEventHandler(rh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiscoveredDeviceManager{events: events}, nil
+}
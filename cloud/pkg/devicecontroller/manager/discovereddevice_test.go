@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/api/apis/devices/v1beta1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/config"
+)
+
+func TestNewDiscoveredDeviceManager(t *testing.T) {
+	config.Config = config.Configure{
+		DeviceController: v1alpha1.DeviceController{
+			Buffer: &v1alpha1.DeviceControllerBuffer{
+				DiscoveredDeviceEvent: 1,
+			},
+		},
+	}
+
+	_, err := NewDiscoveredDeviceManager(newMockDeviceStatusInformer(true))
+	assert.Error(t, err)
+
+	informer := newMockDeviceStatusInformer(false)
+	m, err := NewDiscoveredDeviceManager(informer)
+	assert.NoError(t, err)
+	assert.NotNil(t, m)
+	assert.Equal(t, 1, cap(m.Events()))
+
+	obj := &v1beta1.DiscoveredDevice{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	informer.handler.OnAdd(obj, false)
+	event := <-m.Events()
+	assert.Equal(t, watch.Added, event.Type)
+	assert.Equal(t, obj, event.Object)
+}
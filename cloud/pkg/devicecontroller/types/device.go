@@ -1,5 +1,7 @@
 package types
 
+import "github.com/kubeedge/api/apis/devices/v1beta1"
+
 // Device the struct of device
 type Device struct {
 	ID             string              `json:"id,omitempty"`
@@ -112,3 +114,18 @@ type DeviceStateUpdate struct {
 	BaseMessage
 	Device Device
 }
+
+// DiscoveredDeviceReport the struct of devices discovered by mapper
+type DiscoveredDeviceReport struct {
+	BaseMessage
+	MapperName string             `json:"mapperName"`
+	Protocol   string             `json:"protocol"`
+	Devices    []DiscoveredDevice `json:"devices"`
+}
+
+// DiscoveredDevice the struct of device discovered by mapper
+type DiscoveredDevice struct {
+	ID         string                 `json:"id"`
+	Protocol   v1beta1.ProtocolConfig `json:"protocol"`
+	Attributes map[string]string      `json:"attributes,omitempty"`
+}
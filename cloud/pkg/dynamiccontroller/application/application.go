@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
@@ -353,6 +354,56 @@ func (c *Center) checkNodePermission(app *metaserver.Application) error {
 	if !config.Config.EnableAuthorization {
 		return nil
 	}
+
+	allowed, err := c.subjectAccessReview(app, constants.NodesUserPrefix+app.Nodename, []string{constants.NodesGroup})
+	if err != nil {
+		return fmt.Errorf("node %s permission check failed: %v", app.Nodename, err)
+	}
+	if allowed {
+		return nil
+	}
+	if app.ServiceAccount == "" {
+		return fmt.Errorf("node %q is not allowed to access this resource", app.Nodename)
+	}
+	return c.checkServiceAccountPermission(app)
+}
+
+// checkServiceAccountPermission authorizes the service account of the edge client, the service
+// account is trusted only when it is used by a pod running on the node of application
+func (c *Center) checkServiceAccountPermission(app *metaserver.Application) error {
+	ns, name, err := serviceaccount.SplitUsername(app.ServiceAccount)
+	if err != nil {
+		return fmt.Errorf("invalid service account %q: %v", app.ServiceAccount, err)
+	}
+
+	pods, err := c.kubeClient.CoreV1().Pods(ns).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", app.Nodename).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("list pods of node %s failed: %v", app.Nodename, err)
+	}
+	used := false
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == app.Nodename && pod.Spec.ServiceAccountName == name {
+			used = true
+			break
+		}
+	}
+	if !used {
+		return fmt.Errorf("service account %q is not used by any pod on node %q", app.ServiceAccount, app.Nodename)
+	}
+
+	allowed, err := c.subjectAccessReview(app, app.ServiceAccount, serviceaccount.MakeGroupNames(ns))
+	if err != nil {
+		return fmt.Errorf("service account %s permission check failed: %v", app.ServiceAccount, err)
+	}
+	if !allowed {
+		return fmt.Errorf("node %q and service account %q are not allowed to access this resource", app.Nodename, app.ServiceAccount)
+	}
+	return nil
+}
+
+func (c *Center) subjectAccessReview(app *metaserver.Application, user string, groups []string) (bool, error) {
 	gvr, ns, name := metaserver.ParseKey(app.Key)
 
 	subjectAccessReview := &authorizationv1.SubjectAccessReview{
@@ -366,19 +417,15 @@ func (c *Center) checkNodePermission(app *metaserver.Application) error {
 				Subresource: app.Subresource,
 				Name:        name,
 			},
-			User:   constants.NodesUserPrefix + app.Nodename,
-			Groups: []string{constants.NodesGroup},
+			User:   user,
+			Groups: groups,
 		},
 	}
 	ret, err := c.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), subjectAccessReview, metav1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("node %s permission check failed: %v", app.Nodename, err)
+		return false, err
 	}
-	if !ret.Status.Allowed {
-		return fmt.Errorf("node %q is not allowed to access this resource", app.Nodename)
-	}
-
-	return nil
+	return ret.Status.Allowed, nil
 }
 
 func applicationToListener(app *metaserver.Application) (*SelectorListener, error) {
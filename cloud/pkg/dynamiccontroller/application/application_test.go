@@ -28,6 +28,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -310,6 +311,69 @@ func TestCheckNodePermission(t *testing.T) {
 	}
 }
 
+func TestCheckServiceAccountPermission(t *testing.T) {
+	originalEnableAuthorization := config.Config.EnableAuthorization
+	config.Config.EnableAuthorization = true
+	defer func() {
+		config.Config.EnableAuthorization = originalEnableAuthorization
+	}()
+
+	newPod := func(name, nodeName, serviceAccount string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: nodeName, ServiceAccountName: serviceAccount},
+		}
+	}
+	fakeClientSet := fake.NewSimpleClientset(
+		newPod("operator", "test-node", "operator"),
+		newPod("other", "other-node", "other"),
+	)
+	// only the operator service account is allowed to access the custom resources
+	fakeClientSet.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		sar := action.(clienttesting.CreateAction).GetObject().(*v1.SubjectAccessReview)
+		allowed := sar.Spec.User == "system:serviceaccount:default:operator"
+		return true, &v1.SubjectAccessReview{Status: v1.SubjectAccessReviewStatus{Allowed: allowed}}, nil
+	})
+	center := &Center{kubeClient: fakeClientSet}
+
+	tests := []struct {
+		name           string
+		serviceAccount string
+		wantErr        bool
+	}{
+		{
+			name:    "node without service account",
+			wantErr: true,
+		}, {
+			name:           "service account of pod on node",
+			serviceAccount: "system:serviceaccount:default:operator",
+			wantErr:        false,
+		}, {
+			name:           "service account of pod on other node",
+			serviceAccount: "system:serviceaccount:default:other",
+			wantErr:        true,
+		}, {
+			name:           "invalid service account",
+			serviceAccount: "admin",
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &metaserver.Application{
+				Verb:           "watch",
+				Key:            "/apps.kubeedge.io/v1alpha1/nodegroups/default",
+				Nodename:       "test-node",
+				ServiceAccount: tt.serviceAccount,
+			}
+			err := center.checkNodePermission(app)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkNodePermission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestResponse(t *testing.T) {
 	app := &metaserver.Application{
 		ID:       "test-id",
@@ -679,8 +743,8 @@ func (m *mockHandlerCenter) DeleteListener(listener *SelectorListener) {
 	}
 }
 
-func (m *mockHandlerCenter) ForResource(_ schema.GroupVersionResource) *CommonResourceEventHandler {
-	return nil
+func (m *mockHandlerCenter) ForResource(_ schema.GroupVersionResource) (*CommonResourceEventHandler, error) {
+	return nil, nil
 }
 
 func (m *mockHandlerCenter) GetListenersForNode(nodeName string) map[string]*SelectorListener {
@@ -227,8 +227,9 @@ func TestHandlerCenter(t *testing.T) {
 
 		center.handlers[gvr] = mockHandler
 
-		handler := center.ForResource(gvr)
+		handler, err := center.ForResource(gvr)
 
+		assert.NoError(t, err)
 		assert.Equal(t, mockHandler, handler)
 	})
 
@@ -237,13 +238,13 @@ func TestHandlerCenter(t *testing.T) {
 		defer patches.Reset()
 
 		patches.ApplyFunc(NewCommonResourceEventHandler,
-			func(gvr schema.GroupVersionResource, lm *listenerManager, ml messagelayer.MessageLayer) *CommonResourceEventHandler {
+			func(gvr schema.GroupVersionResource, lm *listenerManager, ml messagelayer.MessageLayer) (*CommonResourceEventHandler, error) {
 				return &CommonResourceEventHandler{
 					listenerManager: lm,
 					messageLayer:    ml,
 					gvr:             gvr,
 					events:          make(chan watch.Event, 10),
-				}
+				}, nil
 			})
 
 		center := &handlerCenter{
@@ -253,8 +254,9 @@ func TestHandlerCenter(t *testing.T) {
 
 		gvr := schema.GroupVersionResource{Group: "new", Version: "v1", Resource: "resources"}
 
-		handler := center.ForResource(gvr)
+		handler, err := center.ForResource(gvr)
 
+		assert.NoError(t, err)
 		assert.NotNil(t, handler)
 		assert.Equal(t, gvr, handler.gvr)
 		assert.Contains(t, center.handlers, gvr)
@@ -266,7 +268,7 @@ func TestHandlerCenter(t *testing.T) {
 
 		handlerCreated := false
 		patches.ApplyFunc(NewCommonResourceEventHandler,
-			func(gvr schema.GroupVersionResource, lm *listenerManager, ml messagelayer.MessageLayer) *CommonResourceEventHandler {
+			func(gvr schema.GroupVersionResource, lm *listenerManager, ml messagelayer.MessageLayer) (*CommonResourceEventHandler, error) {
 				handlerCreated = true
 				handler := &CommonResourceEventHandler{
 					listenerManager: lm,
@@ -280,7 +282,7 @@ func TestHandlerCenter(t *testing.T) {
 						return nil
 					})
 
-				return handler
+				return handler, nil
 			})
 
 		gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
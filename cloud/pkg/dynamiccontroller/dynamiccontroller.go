@@ -18,6 +18,7 @@ package dynamiccontroller
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/klog/v2"
 
@@ -92,8 +93,14 @@ func newDynamicController(enable bool) *DynamicController {
 		dynamicSharedInformerFactory: informers.GetInformersManager().GetDynamicInformerFactory(),
 	}
 	dctl.applicationCenter = application.NewApplicationCenter(dctl.dynamicSharedInformerFactory)
-	dctl.applicationCenter.ForResource(v1.SchemeGroupVersion.WithResource("nodes"))
-	dctl.applicationCenter.ForResource(v1.SchemeGroupVersion.WithResource("services"))
+	for _, gvr := range []schema.GroupVersionResource{
+		v1.SchemeGroupVersion.WithResource("nodes"),
+		v1.SchemeGroupVersion.WithResource("services"),
+	} {
+		if _, err := dctl.applicationCenter.ForResource(gvr); err != nil {
+			klog.Exitf("failed to prepare resource event handler: %v", err)
+		}
+	}
 	return dctl
 }
 
@@ -378,11 +378,26 @@ func (uc *UpstreamController) updateRuleStatus() {
 				rule.Status.FailMessages++
 				errSlice := make([]string, 0)
 				rule.Status.Errors = append(errSlice, content.Error.Detail)
+				lastErrorTime := metaV1.NewTime(content.Error.Timestamp)
+				rule.Status.LastErrorTime = &lastErrorTime
+				if content.DeadLettered {
+					rule.Status.DeadLetterMessages++
+				}
+			}
+			if content.Status == "THROTTLED" {
+				rule.Status.ThrottledMessages++
+				rule.Status.Errors = []string{content.Error.Detail}
+				lastThrottledTime := metaV1.NewTime(content.Error.Timestamp)
+				rule.Status.LastThrottledTime = &lastThrottledTime
 			}
 			newStatus := &rulesv1.RuleStatus{
-				SuccessMessages: rule.Status.SuccessMessages,
-				FailMessages:    rule.Status.FailMessages,
-				Errors:          rule.Status.Errors,
+				SuccessMessages:    rule.Status.SuccessMessages,
+				FailMessages:       rule.Status.FailMessages,
+				Errors:             rule.Status.Errors,
+				LastErrorTime:      rule.Status.LastErrorTime,
+				DeadLetterMessages: rule.Status.DeadLetterMessages,
+				ThrottledMessages:  rule.Status.ThrottledMessages,
+				LastThrottledTime:  rule.Status.LastThrottledTime,
 			}
 			body, err := json.Marshal(newStatus)
 			if err != nil {
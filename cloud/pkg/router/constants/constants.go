@@ -5,9 +5,20 @@ const (
 	EventbusProvider   string = "eventbus"
 	GroupResource      string = "resource"
 	ServicebusProvider string = "servicebus"
+	KafkaProvider      string = "kafka"
+	NatsProvider       string = "nats"
 	TargetURL          string = "target_url"
 	NodeName           string = "node_name"
 	Topic              string = "topic"
 	Path               string = "path"
 	Resource           string = "resource"
+	Subject            string = "subject"
+	Queue              string = "queue"
+	Key                string = "key"
+	Brokers            string = "brokers"
+	Servers            string = "servers"
+	Service            string = "service"
+	Port               string = "port"
+	ServicePort        string = "service_port"
+	ServiceAccounts    string = "service_accounts"
 )
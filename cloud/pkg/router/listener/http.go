@@ -116,12 +116,20 @@ func (rh *RestHandler) httpHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	aReaderCloser := http.MaxBytesReader(w, r.Body, MaxMessageBytes)
-	b, err := io.ReadAll(aReaderCloser)
+	// read one more byte to know whether the body fits in a message
+	b, err := io.ReadAll(io.LimitReader(r.Body, MaxMessageBytes+1))
 	if err != nil {
 		writeErr(w, r, http.StatusBadRequest, err)
 		return
 	}
+	// the body larger than MaxMessageBytes is streamed to target, which can not be retried
+	var body io.Reader
+	attempts := uint(3)
+	if len(b) > MaxMessageBytes {
+		body = io.MultiReader(bytes.NewReader(b), r.Body)
+		b = nil
+		attempts = 1
+	}
 
 	edgeNodeName := uriSections[1]
 	err = retry.Do(
@@ -145,6 +153,9 @@ func (rh *RestHandler) httpHandler(w http.ResponseWriter, r *http.Request) {
 				}
 				url += ":" + strconv.Itoa(rh.port) + r.RequestURI
 				reqBody := io.NopCloser(bytes.NewBuffer(b))
+				if body != nil {
+					reqBody = io.NopCloser(body)
+				}
 				forwardReq, err := http.NewRequest(r.Method, url, reqBody)
 				if err != nil {
 					return fmt.Errorf("failed to create forward request: %v", err)
@@ -184,7 +195,11 @@ func (rh *RestHandler) httpHandler(w http.ResponseWriter, r *http.Request) {
 				params["messageID"] = msgID
 				params["request"] = r
 				params["timeout"] = rh.restTimeout
-				params["data"] = b
+				if body != nil {
+					params["body"] = body
+				} else {
+					params["data"] = b
+				}
 
 				v, err := handle(params)
 				if err != nil {
@@ -196,25 +211,39 @@ func (rh *RestHandler) httpHandler(w http.ResponseWriter, r *http.Request) {
 					klog.Errorf("response convert error, msg id: %s", msgID)
 					return nil
 				}
-				body, err := io.ReadAll(io.LimitReader(response.Body, MaxMessageBytes))
-				if err != nil {
-					klog.Errorf("response body read error, msg id: %s, reason: %v", msgID, err)
-					return nil
-				}
+				defer response.Body.Close()
 				for key, values := range response.Header {
 					for _, value := range values {
 						w.Header().Add(key, value)
 					}
 				}
 
+				// the throttled requests are not retried
+				if response.StatusCode == http.StatusTooManyRequests {
+					w.WriteHeader(response.StatusCode)
+					if _, err = io.Copy(w, io.LimitReader(response.Body, MaxMessageBytes)); err != nil {
+						klog.Errorf("response body write error, msg id: %s, reason: %v", msgID, err)
+					}
+					return nil
+				}
 				if response.StatusCode != http.StatusOK {
-					errMsg := string(body)
-					return errors.New(errMsg)
+					errMsg, err := io.ReadAll(io.LimitReader(response.Body, MaxMessageBytes))
+					if err != nil {
+						klog.Errorf("response body read error, msg id: %s, reason: %v", msgID, err)
+						return nil
+					}
+					return errors.New(string(errMsg))
 				}
 
 				w.WriteHeader(response.StatusCode)
-				if _, err = w.Write(body); err != nil {
-					klog.Errorf("response body write error, msg id: %s, reason: %v", msgID, err)
+				// the streamed body is stopped once the client disconnects
+				stop := context.AfterFunc(r.Context(), func() {
+					response.Body.Close()
+				})
+				defer stop()
+				written, err := copyResponse(w, response.Body)
+				if err != nil {
+					klog.Errorf("response body write error, msg id: %s, %d bytes written, reason: %v", msgID, written, err)
 					return nil
 				}
 				klog.Infof("response to client, msg id: %s, write result: success", msgID)
@@ -226,7 +255,7 @@ func (rh *RestHandler) httpHandler(w http.ResponseWriter, r *http.Request) {
 			return nil
 		},
 		retry.Delay(1*time.Second),
-		retry.Attempts(3),
+		retry.Attempts(attempts),
 		retry.DelayType(retry.FixedDelay),
 	)
 
@@ -303,6 +332,34 @@ func requestForward(targetCloudCoreIP string, w http.ResponseWriter, forwardReq
 	return nil
 }
 
+// copyResponse copies the response body to client, it is flushed after each write so that
+// the streamed body reaches the client in time.
+func copyResponse(w http.ResponseWriter, body io.Reader) (int64, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return io.Copy(w, body)
+	}
+	var written int64
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			nw, werr := w.Write(buf[:n])
+			written += int64(nw)
+			if werr != nil {
+				return written, werr
+			}
+			flusher.Flush()
+		}
+		if errors.Is(err, io.EOF) {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
 func writeErr(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
 	klog.Error(err.Error())
 	w.WriteHeader(statusCode)
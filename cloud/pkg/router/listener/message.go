@@ -11,6 +11,7 @@ import (
 
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
 )
 
 var MessageHandlerInstance = &MessageHandler{}
@@ -18,6 +19,8 @@ var MessageHandlerInstance = &MessageHandler{}
 type MessageHandler struct {
 	handlers         sync.Map
 	callbackHandlers sync.Map
+	// streamHandlers are called for each chunk of the streamed response body
+	streamHandlers sync.Map
 }
 
 func (mh *MessageHandler) AddListener(key interface{}, han Handle) {
@@ -77,6 +80,10 @@ func (mh *MessageHandler) HandleMessage(message *model.Message) error {
 	if message == nil {
 		return fmt.Errorf("nil message error")
 	}
+	if message.GetParentID() != "" && strings.HasSuffix(message.GetResource(), "/"+commonType.ResourceStreamChunk) {
+		mh.streamCallback(message)
+		return nil
+	}
 	if message.GetParentID() != "" {
 		mh.callback(message)
 		return nil
@@ -122,3 +129,27 @@ func (mh *MessageHandler) callback(message *model.Message) {
 	}
 	mh.callbackHandlers.Delete(pID)
 }
+
+// SetStreamCallback sets the callback of the chunks whose parent is messageID, unlike
+// SetCallback it is called for every chunk until DelStreamCallback
+func (mh *MessageHandler) SetStreamCallback(messageID string, callback func(message *model.Message)) {
+	mh.streamHandlers.Store(messageID, callback)
+}
+
+func (mh *MessageHandler) DelStreamCallback(messageID string) {
+	mh.streamHandlers.Delete(messageID)
+}
+
+func (mh *MessageHandler) streamCallback(message *model.Message) {
+	v, exist := mh.streamHandlers.Load(message.GetParentID())
+	if !exist {
+		klog.V(4).Infof("no stream for chunk message %s, parent id: %s", message.GetID(), message.GetParentID())
+		return
+	}
+	callback, ok := v.(func(message *model.Message))
+	if !ok {
+		klog.Warningf("invalid convert to model.Message")
+		return
+	}
+	callback(message)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	commonconst "github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
 )
 
@@ -95,7 +97,7 @@ func (eb *EventBus) Name() string {
 	return constants.EventbusProvider
 }
 
-func (*EventBus) Forward(target provider.Target, data interface{}) (response interface{}, err error) {
+func (eb *EventBus) Forward(target provider.Target, data interface{}) (response interface{}, err error) {
 	message, ok := data.(*model.Message)
 	if !ok {
 		klog.Errorf("message type %T error", data)
@@ -108,6 +110,13 @@ func (*EventBus) Forward(target provider.Target, data interface{}) (response int
 		return nil, fmt.Errorf("get message %s content err: %v", message.GetID(), err)
 	}
 	res["data"] = content
+	res["messageID"] = message.GetID()
+	res["nodeName"] = eb.nodeName
+	res["topic"] = eb.subTopic
+	// the MQTT 5 properties of message published to edge eventbus
+	if properties := message.GetProperties(); len(properties) > 0 {
+		res["properties"] = properties
+	}
 	resp, err := target.GoToTarget(res, nil)
 	if err != nil {
 		klog.Errorf("message is send to target failed. msgID: %s, target: %s, err:%v", message.GetID(), target.Name(), err)
@@ -144,6 +153,9 @@ func (eb *EventBus) GoToTarget(data map[string]interface{}, _ chan struct{}) (in
 	msg.SetResourceOperation(resource, publishOperation)
 	msg.FillBody(string(body))
 	msg.SetRoute(modules.RouterSourceEventBus, modules.UserGroup)
+	if properties, ok := data["properties"].(map[string]string); ok {
+		msg.SetProperties(properties)
+	}
 
 	sessionMgr, err := cloudhub.GetSessionManager()
 	if err != nil {
@@ -152,8 +164,42 @@ func (eb *EventBus) GoToTarget(data map[string]interface{}, _ chan struct{}) (in
 	if _, exists := sessionMgr.GetSession(nodeName); !exists {
 		return nil, fmt.Errorf("cloudcore doesn't have session for node:%s", nodeName)
 	}
+
+	// "ackTimeout" is set by rules in AtLeastOnce delivery mode
+	ackTimeout, _ := data["ackTimeout"].(time.Duration)
+	if ackTimeout <= 0 {
+		beehiveContext.Send(modules.CloudHubModuleName, *msg)
+		return nil, nil
+	}
+	return nil, sendAndWaitAck(msg, ackTimeout)
+}
+
+// sendAndWaitAck sends the message as a sync message, which the edge eventbus acknowledges
+// after the message is published to MQTT
+func sendAndWaitAck(msg *model.Message, timeout time.Duration) error {
+	msg.Header.Sync = true
+	ack := make(chan *model.Message, 1)
+	listener.MessageHandlerInstance.SetCallback(msg.GetID(), func(resp *model.Message) {
+		select {
+		case ack <- resp:
+		default:
+		}
+	})
+	defer listener.MessageHandlerInstance.DelCallback(msg.GetID())
 	beehiveContext.Send(modules.CloudHubModuleName, *msg)
-	return nil, nil
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-ack:
+		content, _ := resp.GetContent().(string)
+		if content != commonconst.MessageSuccessfulContent {
+			return fmt.Errorf("edge failed to publish message %s: %s", msg.GetID(), content)
+		}
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("wait for the ack of message %s timeout after %v", msg.GetID(), timeout)
+	}
 }
 
 func buildAndLogError(key string) error {
@@ -4,12 +4,18 @@ import (
 	"fmt"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	v1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/beehive/pkg/common"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
+	commonconst "github.com/kubeedge/kubeedge/common/constants"
 )
 
 func TestPathJoin(t *testing.T) {
@@ -263,3 +269,71 @@ func TestBuildAndLogError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "test-key")
 }
+
+type recordTarget struct {
+	data map[string]interface{}
+}
+
+func (*recordTarget) Name() string { return "record" }
+
+func (r *recordTarget) GoToTarget(data map[string]interface{}, _ chan struct{}) (interface{}, error) {
+	r.data = data
+	return nil, nil
+}
+
+func TestForwardMessageInfo(t *testing.T) {
+	eb := &EventBus{nodeName: "test-node", subTopic: "test-topic"}
+	msg := model.NewMessage("")
+	msg.Content = []byte("test-data")
+	target := &recordTarget{}
+	_, err := eb.Forward(target, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, msg.GetID(), target.data["messageID"])
+	assert.Equal(t, "test-node", target.data["nodeName"])
+	assert.Equal(t, []byte("test-data"), target.data["data"])
+}
+
+func TestForwardMessageProperties(t *testing.T) {
+	eb := &EventBus{nodeName: "test-node", subTopic: "test-topic"}
+	msg := model.NewMessage("").SetProperties(map[string]string{"tenant": "t1"})
+	msg.Content = []byte("test-data")
+	target := &recordTarget{}
+	_, err := eb.Forward(target, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "t1"}, target.data["properties"])
+
+	_, err = eb.Forward(target, model.NewMessage(""))
+	assert.NoError(t, err)
+	assert.NotContains(t, target.data, "properties")
+}
+
+func TestSendAndWaitAck(t *testing.T) {
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	beehiveContext.AddModule(&common.ModuleInfo{
+		ModuleName: modules.CloudHubModuleName,
+		ModuleType: common.MsgCtxTypeChannel,
+	})
+
+	// edge acknowledges the messages with the content of ack
+	ack := func(content string) {
+		msg, err := beehiveContext.Receive(modules.CloudHubModuleName)
+		if err != nil {
+			return
+		}
+		assert.True(t, msg.IsSync())
+		resp := model.NewMessage(msg.GetID()).SetRoute(modules.RouterSourceEventBus, modules.UserGroup).
+			SetResourceOperation("node/test-node", model.UploadOperation).FillBody(content)
+		assert.NoError(t, listener.MessageHandlerInstance.HandleMessage(resp))
+	}
+
+	go ack(commonconst.MessageSuccessfulContent)
+	assert.NoError(t, sendAndWaitAck(model.NewMessage(""), time.Second))
+
+	go ack("broker is unavailable")
+	assert.ErrorContains(t, sendAndWaitAck(model.NewMessage(""), time.Second), "broker is unavailable")
+
+	go func() {
+		_, _ = beehiveContext.Receive(modules.CloudHubModuleName)
+	}()
+	assert.ErrorContains(t, sendAndWaitAck(model.NewMessage(""), 50*time.Millisecond), "timeout")
+}
@@ -0,0 +1,468 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+)
+
+// The properties of kafka RuleEndpoint, the SASL credentials are read from
+// the secret referenced by utils.PropertyCredentialsSecret
+const (
+	PropertyClientID      = "client_id"
+	PropertyAcks          = "acks"
+	PropertyVersion       = "version"
+	PropertySASLMechanism = "sasl_mechanism"
+)
+
+// retryInterval is the interval to recreate the consumer of a source after it fails
+const retryInterval = 5 * time.Second
+
+type kafkaFactory struct {
+	// getKubeClient returns the client to read the credentials secrets
+	getKubeClient func() kubernetes.Interface
+	// newProducer and newConsumer connect the brokers, they are replaced in tests
+	newProducer func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error)
+	newConsumer func(brokers []string, config *sarama.Config) (sarama.Consumer, error)
+
+	// clients caches the client of every RuleEndpoint, keyed by namespace/name
+	mu      sync.Mutex
+	clients map[string]*kafkaClient
+	// consumers holds the stop channels of running sources, a source is
+	// unregistered with another instance returned by GetSource
+	consumers sync.Map
+}
+
+// kafkaClient holds the config of a RuleEndpoint and the producer shared by its targets,
+// the producer connects the brokers on first use.
+type kafkaClient struct {
+	factory       *kafkaFactory
+	properties    map[string]string
+	secretVersion string
+	brokers       []string
+	config        *sarama.Config
+
+	mu       sync.Mutex
+	producer sarama.SyncProducer
+	// closed is closed after the properties of RuleEndpoint are changed
+	closed chan struct{}
+}
+
+// Kafka is the source which consumes records from a topic, or the target
+// which produces records to a topic.
+type Kafka struct {
+	client    *kafkaClient
+	namespace string
+
+	// source
+	factory     *kafkaFactory
+	listenerKey string
+	topic       string
+	nodeName    string
+
+	// target
+	topicTemplate *template.Template
+	keyTemplate   *template.Template
+	headers       map[string]*template.Template
+}
+
+func init() {
+	factory := &kafkaFactory{
+		getKubeClient: client.GetKubeClient,
+		newProducer:   sarama.NewSyncProducer,
+		newConsumer:   sarama.NewConsumer,
+		clients:       make(map[string]*kafkaClient),
+	}
+	provider.RegisterSource(factory)
+	provider.RegisterTarget(factory)
+}
+
+func (*kafkaFactory) Type() v1.RuleEndpointTypeDef {
+	return v1.RuleEndpointTypeKafka
+}
+
+// ConfigFromProperties builds the sarama config from the properties of RuleEndpoint
+// and the credentials in the secret it references
+func ConfigFromProperties(properties map[string]string, creds utils.Credentials) ([]string, *sarama.Config, error) {
+	var brokers []string
+	for _, broker := range strings.Split(properties[constants.Brokers], ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, nil, fmt.Errorf("property %q can not be empty", constants.Brokers)
+	}
+
+	config := sarama.NewConfig()
+	if clientID := properties[PropertyClientID]; clientID != "" {
+		config.ClientID = clientID
+	}
+	if version := properties[PropertyVersion]; version != "" {
+		v, err := sarama.ParseKafkaVersion(version)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid property %q: %v", PropertyVersion, err)
+		}
+		config.Version = v
+	}
+	config.Producer.Return.Successes = true
+	// the same partitioner as the java client, so the records with the same key
+	// go to the same partition whichever client produces them
+	config.Producer.Partitioner = sarama.NewReferenceHashPartitioner
+	switch acks := properties[PropertyAcks]; acks {
+	case "", "1":
+		config.Producer.RequiredAcks = sarama.WaitForLocal
+	case "0":
+		config.Producer.RequiredAcks = sarama.NoResponse
+	case "all", "-1":
+		config.Producer.RequiredAcks = sarama.WaitForAll
+	default:
+		return nil, nil, fmt.Errorf("property %q should be one of 0, 1, all", PropertyAcks)
+	}
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+
+	if mechanism := properties[PropertySASLMechanism]; mechanism != "" {
+		if mechanism != sarama.SASLTypePlaintext {
+			return nil, nil, fmt.Errorf("unsupported SASL mechanism %q, only %s is supported", mechanism, sarama.SASLTypePlaintext)
+		}
+		if creds.Username == "" {
+			return nil, nil, fmt.Errorf("%q is required in the credentials secret when SASL is enabled", utils.CredentialsUsername)
+		}
+		config.Net.SASL.Enable = true
+		config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		config.Net.SASL.User = creds.Username
+		config.Net.SASL.Password = creds.Password
+	}
+
+	tlsConfig, err := utils.TLSConfigFromProperties(properties)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tlsConfig != nil {
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return brokers, config, nil
+}
+
+// getClient returns the client of RuleEndpoint, the client is recreated when the properties
+// or the credentials secret are changed
+func (factory *kafkaFactory) getClient(ep *v1.RuleEndpoint) (*kafkaClient, error) {
+	creds, err := utils.GetCredentials(factory.getKubeClient(), ep.Namespace, ep.Spec.Properties)
+	if err != nil {
+		return nil, err
+	}
+	key := ep.Namespace + "/" + ep.Name
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+	cached, ok := factory.clients[key]
+	if ok && cached.secretVersion == creds.ResourceVersion && equalProperties(cached.properties, ep.Spec.Properties) {
+		return cached, nil
+	}
+	brokers, config, err := ConfigFromProperties(ep.Spec.Properties, creds)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// the sources consuming with the old client stop as well
+		cached.close()
+	}
+	c := &kafkaClient{
+		factory:       factory,
+		properties:    ep.Spec.Properties,
+		secretVersion: creds.ResourceVersion,
+		brokers:       brokers,
+		config:        config,
+		closed:        make(chan struct{}),
+	}
+	factory.clients[key] = c
+	return c, nil
+}
+
+func equalProperties(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// produce sends the message and waits for the acks, the producer is created
+// on the first call, and again if the brokers could not be connected.
+func (c *kafkaClient) produce(msg *sarama.ProducerMessage) (int32, int64, error) {
+	c.mu.Lock()
+	select {
+	case <-c.closed:
+		c.mu.Unlock()
+		return 0, 0, errors.New("kafka client is closed")
+	default:
+	}
+	if c.producer == nil {
+		producer, err := c.factory.newProducer(c.brokers, c.config)
+		if err != nil {
+			c.mu.Unlock()
+			return 0, 0, fmt.Errorf("failed to connect kafka brokers %v: %v", c.brokers, err)
+		}
+		c.producer = producer
+	}
+	producer := c.producer
+	c.mu.Unlock()
+	return producer.SendMessage(msg)
+}
+
+func (c *kafkaClient) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.closed)
+	if c.producer != nil {
+		if err := c.producer.Close(); err != nil {
+			klog.Warningf("failed to close kafka producer: %v", err)
+		}
+		c.producer = nil
+	}
+}
+
+// consume consumes the records produced to every partition of the topic after it starts,
+// until stopCh or the client is closed. The consumer is recreated after it fails.
+func (c *kafkaClient) consume(stopCh <-chan struct{}, topic string, handle func(*sarama.ConsumerMessage)) {
+	for {
+		err := c.consumeOnce(stopCh, topic, handle)
+		if err == nil {
+			return
+		}
+		klog.Errorf("failed to consume kafka topic %s: %v, retry in %v", topic, err, retryInterval)
+		select {
+		case <-stopCh:
+			return
+		case <-c.closed:
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (c *kafkaClient) consumeOnce(stopCh <-chan struct{}, topic string, handle func(*sarama.ConsumerMessage)) error {
+	consumer, err := c.factory.newConsumer(c.brokers, c.config)
+	if err != nil {
+		return fmt.Errorf("failed to connect kafka brokers %v: %v", c.brokers, err)
+	}
+	defer consumer.Close()
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	partitionConsumers := make([]sarama.PartitionConsumer, 0, len(partitions))
+	defer func() {
+		for _, pc := range partitionConsumers {
+			pc.AsyncClose()
+		}
+		wg.Wait()
+	}()
+	for _, partition := range partitions {
+		pc, err := consumer.ConsumePartition(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return fmt.Errorf("failed to consume partition %d: %v", partition, err)
+		}
+		partitionConsumers = append(partitionConsumers, pc)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range pc.Messages() {
+				handle(msg)
+			}
+		}()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopCh:
+		return nil
+	case <-c.closed:
+		return nil
+	case <-stopped:
+		return errors.New("partition consumers stopped")
+	}
+}
+
+func (factory *kafkaFactory) GetSource(ep *v1.RuleEndpoint, sourceResource map[string]string) provider.Source {
+	topic, exist := sourceResource[constants.Topic]
+	if !exist {
+		klog.Errorf("source resource attributes \"topic\" does not exist")
+		return nil
+	}
+	nodeName, exist := sourceResource[constants.NodeName]
+	if !exist {
+		klog.Errorf("source resource attributes \"node_name\" does not exist")
+		return nil
+	}
+	client, err := factory.getClient(ep)
+	if err != nil {
+		klog.Errorf("failed to create kafka client of ruleendpoint %s/%s: %v", ep.Namespace, ep.Name, err)
+		return nil
+	}
+	return &Kafka{
+		client:      client,
+		namespace:   ep.Namespace,
+		factory:     factory,
+		listenerKey: fmt.Sprintf("%s/%s/%s/%s", ep.Namespace, ep.Name, nodeName, topic),
+		topic:       topic,
+		nodeName:    nodeName,
+	}
+}
+
+func (factory *kafkaFactory) GetTarget(ep *v1.RuleEndpoint, targetResource map[string]string) provider.Target {
+	topic, exist := targetResource[constants.Topic]
+	if !exist {
+		klog.Errorf("target resource attributes \"topic\" does not exist")
+		return nil
+	}
+	topicTemplate, err := utils.ParseTemplate(constants.Topic, topic)
+	if err != nil {
+		klog.Errorf("invalid template of target resource attributes \"topic\": %v", err)
+		return nil
+	}
+	var keyTemplate *template.Template
+	if key, exist := targetResource[constants.Key]; exist {
+		if keyTemplate, err = utils.ParseTemplate(constants.Key, key); err != nil {
+			klog.Errorf("invalid template of target resource attributes \"key\": %v", err)
+			return nil
+		}
+	}
+	headers, err := utils.ParseHeaderTemplates(targetResource)
+	if err != nil {
+		klog.Errorf("invalid target resource headers: %v", err)
+		return nil
+	}
+	client, err := factory.getClient(ep)
+	if err != nil {
+		klog.Errorf("failed to create kafka client of ruleendpoint %s/%s: %v", ep.Namespace, ep.Name, err)
+		return nil
+	}
+	return &Kafka{
+		client:        client,
+		namespace:     ep.Namespace,
+		topicTemplate: topicTemplate,
+		keyTemplate:   keyTemplate,
+		headers:       headers,
+	}
+}
+
+func (*Kafka) Name() string {
+	return constants.KafkaProvider
+}
+
+func (k *Kafka) RegisterListener(handle listener.Handle) error {
+	stopCh := make(chan struct{})
+	if old, loaded := k.factory.consumers.Swap(k.listenerKey, stopCh); loaded {
+		close(old.(chan struct{}))
+	}
+	go k.client.consume(stopCh, k.topic, func(record *sarama.ConsumerMessage) {
+		if _, err := handle(record); err != nil {
+			klog.Errorf("failed to handle record of kafka topic %s partition %d offset %d: %v",
+				record.Topic, record.Partition, record.Offset, err)
+		}
+	})
+	return nil
+}
+
+func (k *Kafka) UnregisterListener() {
+	if stopCh, loaded := k.factory.consumers.LoadAndDelete(k.listenerKey); loaded {
+		close(stopCh.(chan struct{}))
+	}
+}
+
+func (k *Kafka) Forward(target provider.Target, data interface{}) (interface{}, error) {
+	record, ok := data.(*sarama.ConsumerMessage)
+	if !ok {
+		klog.Errorf("record type %T error", data)
+		return nil, fmt.Errorf("record type %T error", data)
+	}
+	res := make(map[string]interface{})
+	res["messageID"] = uuid.New().String()
+	res["nodeName"] = k.nodeName
+	res["data"] = record.Value
+	res["topic"] = record.Topic
+	header := make(http.Header, len(record.Headers))
+	for _, h := range record.Headers {
+		header.Add(string(h.Key), string(h.Value))
+	}
+	res["header"] = header
+	resp, err := target.GoToTarget(res, nil)
+	if err != nil {
+		klog.Errorf("record is send to target failed. topic: %s, partition: %d, offset: %d, target: %s, err: %v",
+			record.Topic, record.Partition, record.Offset, target.Name(), err)
+		return nil, err
+	}
+	klog.V(4).Infof("record is send to target successfully. topic: %s, partition: %d, offset: %d, target: %s",
+		record.Topic, record.Partition, record.Offset, target.Name())
+	return resp, nil
+}
+
+func (k *Kafka) GoToTarget(data map[string]interface{}, _ chan struct{}) (interface{}, error) {
+	body, ok := data["data"].([]byte)
+	if !ok {
+		return nil, errors.New("input data does not exist valid value \"data\"")
+	}
+	// use zero value if not found
+	messageID, _ := data["messageID"].(string)
+	nodeName, _ := data["nodeName"].(string)
+	param, _ := data["param"].(string)
+	sourceTopic, _ := data["topic"].(string)
+	tmplData := utils.TemplateData{MessageID: messageID, NodeName: nodeName, Namespace: k.namespace, Param: param, Topic: sourceTopic}
+
+	topic, err := utils.RenderTemplate(k.topicTemplate, tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render topic: %v", err)
+	}
+	record := &sarama.ProducerMessage{Topic: topic, Value: sarama.ByteEncoder(body)}
+	key, err := utils.RenderTemplate(k.keyTemplate, tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render key: %v", err)
+	}
+	if key != "" {
+		record.Key = sarama.StringEncoder(key)
+	}
+	for name, tmpl := range k.headers {
+		value, err := utils.RenderTemplate(tmpl, tmplData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render header %s: %v", name, err)
+		}
+		record.Headers = append(record.Headers, sarama.RecordHeader{Key: []byte(name), Value: []byte(value)})
+	}
+
+	partition, offset, err := k.client.produce(record)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("message %s is produced to kafka topic %s partition %d offset %d", messageID, topic, partition, offset)
+	return nil, nil
+}
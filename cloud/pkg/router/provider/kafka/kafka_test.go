@@ -0,0 +1,236 @@
+package kafka
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+)
+
+type recordTarget struct {
+	ch chan map[string]interface{}
+}
+
+func (*recordTarget) Name() string { return "record" }
+
+func (r *recordTarget) GoToTarget(data map[string]interface{}, _ chan struct{}) (interface{}, error) {
+	r.ch <- data
+	return nil, nil
+}
+
+func newRuleEndpoint(properties map[string]string) *v1.RuleEndpoint {
+	return &v1.RuleEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "default"},
+		Spec: v1.RuleEndpointSpec{
+			RuleEndpointType: v1.RuleEndpointTypeKafka,
+			Properties:       properties,
+		},
+	}
+}
+
+func newFactory(kubeClient kubernetes.Interface) *kafkaFactory {
+	return &kafkaFactory{
+		getKubeClient: func() kubernetes.Interface { return kubeClient },
+		clients:       make(map[string]*kafkaClient),
+	}
+}
+
+func TestConfigFromProperties(t *testing.T) {
+	cases := []struct {
+		name       string
+		properties map[string]string
+		creds      utils.Credentials
+		expectErr  bool
+		check      func(t *testing.T, brokers []string, config *sarama.Config)
+	}{
+		{
+			name:       "brokers and default acks",
+			properties: map[string]string{constants.Brokers: "a:9092, b:9092"},
+			check: func(t *testing.T, brokers []string, config *sarama.Config) {
+				assert.Equal(t, []string{"a:9092", "b:9092"}, brokers)
+				assert.Equal(t, sarama.WaitForLocal, config.Producer.RequiredAcks)
+				assert.False(t, config.Net.TLS.Enable)
+				assert.False(t, config.Net.SASL.Enable)
+			},
+		},
+		{
+			name: "acks all with version, SASL and TLS",
+			properties: map[string]string{constants.Brokers: "a:9092", PropertyAcks: "all", PropertyVersion: "3.6.0",
+				"tls": "true", PropertySASLMechanism: "PLAIN", utils.PropertyCredentialsSecret: "kafka-credentials"},
+			creds: utils.Credentials{Username: "user", Password: "secret"},
+			check: func(t *testing.T, _ []string, config *sarama.Config) {
+				assert.Equal(t, sarama.WaitForAll, config.Producer.RequiredAcks)
+				assert.Equal(t, sarama.V3_6_0_0, config.Version)
+				assert.True(t, config.Net.TLS.Enable)
+				assert.True(t, config.Net.SASL.Enable)
+				assert.Equal(t, "user", config.Net.SASL.User)
+				assert.Equal(t, "secret", config.Net.SASL.Password)
+			},
+		},
+		{
+			name:       "missing brokers",
+			properties: map[string]string{},
+			expectErr:  true,
+		},
+		{
+			name:       "invalid acks",
+			properties: map[string]string{constants.Brokers: "a:9092", PropertyAcks: "2"},
+			expectErr:  true,
+		},
+		{
+			name:       "invalid version",
+			properties: map[string]string{constants.Brokers: "a:9092", PropertyVersion: "latest"},
+			expectErr:  true,
+		},
+		{
+			name:       "unsupported SASL mechanism",
+			properties: map[string]string{constants.Brokers: "a:9092", PropertySASLMechanism: "SCRAM-SHA-512"},
+			creds:      utils.Credentials{Username: "user"},
+			expectErr:  true,
+		},
+		{
+			name:       "SASL without credentials",
+			properties: map[string]string{constants.Brokers: "a:9092", PropertySASLMechanism: "PLAIN"},
+			expectErr:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			brokers, config, err := ConfigFromProperties(tc.properties, tc.creds)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, brokers, config)
+		})
+	}
+}
+
+func TestGetClientWithCredentialsSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-credentials", Namespace: "default", ResourceVersion: "1"},
+		Data:       map[string][]byte{utils.CredentialsUsername: []byte("user"), utils.CredentialsPassword: []byte("secret")},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+	factory := newFactory(kubeClient)
+	ep := newRuleEndpoint(map[string]string{constants.Brokers: "a:9092", PropertySASLMechanism: "PLAIN",
+		utils.PropertyCredentialsSecret: "kafka-credentials"})
+
+	c, err := factory.getClient(ep)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", c.config.Net.SASL.Password)
+	cached, err := factory.getClient(ep)
+	require.NoError(t, err)
+	assert.Same(t, c, cached)
+
+	// the client is recreated with the new password after the secret is changed
+	secret.ResourceVersion = "2"
+	secret.Data[utils.CredentialsPassword] = []byte("rotated")
+	_, err = kubeClient.CoreV1().Secrets("default").Update(context.TODO(), secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	updated, err := factory.getClient(ep)
+	require.NoError(t, err)
+	assert.NotSame(t, c, updated)
+	assert.Equal(t, "rotated", updated.config.Net.SASL.Password)
+	select {
+	case <-c.closed:
+	default:
+		t.Fatal("the old client is not closed")
+	}
+
+	_, err = newFactory(fake.NewSimpleClientset()).getClient(ep)
+	assert.Error(t, err)
+}
+
+func TestGoToTarget(t *testing.T) {
+	factory := newFactory(fake.NewSimpleClientset())
+	var producer *mocks.SyncProducer
+	factory.newProducer = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, error) {
+		assert.Equal(t, []string{"a:9092"}, brokers)
+		producer = mocks.NewSyncProducer(t, config)
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			assert.Equal(t, "node-1.events", msg.Topic)
+			assert.Equal(t, sarama.StringEncoder("node-1/temperature"), msg.Key)
+			assert.Equal(t, sarama.ByteEncoder("25"), msg.Value)
+			assert.Equal(t, []sarama.RecordHeader{{Key: []byte("message-id"), Value: []byte("id-1")}}, msg.Headers)
+			return nil
+		})
+		producer.ExpectSendMessageAndFail(sarama.ErrUnknownTopicOrPartition)
+		return producer, nil
+	}
+	target := factory.GetTarget(newRuleEndpoint(map[string]string{constants.Brokers: "a:9092"}), map[string]string{
+		constants.Topic:     "{{.NodeName}}.events",
+		constants.Key:       "{{.NodeName}}/{{.Param}}",
+		"header.message-id": "{{.MessageID}}",
+	})
+	require.NotNil(t, target)
+	assert.Equal(t, constants.KafkaProvider, target.Name())
+
+	_, err := target.GoToTarget(map[string]interface{}{
+		"messageID": "id-1",
+		"nodeName":  "node-1",
+		"param":     "temperature",
+		"data":      []byte("25"),
+	}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, producer)
+
+	// the error of broker is returned
+	_, err = target.GoToTarget(map[string]interface{}{"data": []byte("x")}, nil)
+	assert.ErrorIs(t, err, sarama.ErrUnknownTopicOrPartition)
+	_, err = target.GoToTarget(map[string]interface{}{}, nil)
+	assert.Error(t, err)
+	require.NoError(t, producer.Close())
+}
+
+func TestSource(t *testing.T) {
+	factory := newFactory(fake.NewSimpleClientset())
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"commands": {0, 1}})
+	consumer.ExpectConsumePartition("commands", 0, sarama.OffsetNewest)
+	consumer.ExpectConsumePartition("commands", 1, sarama.OffsetNewest).
+		YieldMessage(&sarama.ConsumerMessage{Topic: "commands", Partition: 1, Value: []byte("open"),
+			Headers: []*sarama.RecordHeader{{Key: []byte("trace-id"), Value: []byte("t-1")}}})
+	factory.newConsumer = func([]string, *sarama.Config) (sarama.Consumer, error) {
+		return consumer, nil
+	}
+
+	ep := newRuleEndpoint(map[string]string{constants.Brokers: "a:9092"})
+	assert.Nil(t, factory.GetSource(ep, map[string]string{constants.Topic: "commands"}))
+	source := factory.GetSource(ep, map[string]string{constants.Topic: "commands", constants.NodeName: "node-1"})
+	require.NotNil(t, source)
+
+	target := &recordTarget{ch: make(chan map[string]interface{}, 1)}
+	require.NoError(t, source.RegisterListener(func(data interface{}) (interface{}, error) {
+		return source.Forward(target, data)
+	}))
+
+	select {
+	case data := <-target.ch:
+		assert.Equal(t, "node-1", data["nodeName"])
+		assert.Equal(t, []byte("open"), data["data"])
+		assert.Equal(t, "t-1", data["header"].(http.Header).Get("trace-id"))
+		assert.NotEmpty(t, data["messageID"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for forwarded record")
+	}
+
+	// the source is unregistered with another instance, as the rule does when it is deleted
+	factory.GetSource(ep, map[string]string{constants.Topic: "commands", constants.NodeName: "node-1"}).UnregisterListener()
+	_, loaded := factory.consumers.Load("default/kafka/node-1/commands")
+	assert.False(t, loaded)
+}
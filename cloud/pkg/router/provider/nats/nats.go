@@ -0,0 +1,333 @@
+package nats
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+)
+
+// The properties of nats RuleEndpoint, the username and password or token are read
+// from the secret referenced by utils.PropertyCredentialsSecret
+const (
+	PropertyName = "name"
+)
+
+// flushTimeout is the timeout to wait for the server to receive a published message
+const flushTimeout = 10 * time.Second
+
+// connection is the part of *nats.Conn used by the provider
+type connection interface {
+	PublishMsg(msg *nats.Msg) error
+	FlushTimeout(timeout time.Duration) error
+	subscribe(subject, queue string, handle nats.MsgHandler) (unsubscribe func() error, err error)
+	Close()
+}
+
+type natsConn struct {
+	*nats.Conn
+}
+
+func (c natsConn) subscribe(subject, queue string, handle nats.MsgHandler) (func() error, error) {
+	sub, err := c.QueueSubscribe(subject, queue, handle)
+	if err != nil {
+		return nil, err
+	}
+	return sub.Unsubscribe, nil
+}
+
+func connect(options nats.Options) (connection, error) {
+	conn, err := options.Connect()
+	if err != nil {
+		return nil, err
+	}
+	return natsConn{Conn: conn}, nil
+}
+
+type natsFactory struct {
+	// getKubeClient returns the client to read the credentials secrets
+	getKubeClient func() kubernetes.Interface
+	// connect connects the servers, it is replaced in tests
+	connect func(options nats.Options) (connection, error)
+
+	// clients caches the client of every RuleEndpoint, keyed by namespace/name
+	mu      sync.Mutex
+	clients map[string]*cachedClient
+	// subscriptions holds the unsubscribe functions of running sources, a source is
+	// unregistered with another instance returned by GetSource
+	subscriptions sync.Map
+}
+
+type cachedClient struct {
+	properties    map[string]string
+	secretVersion string
+	conn          connection
+}
+
+// Nats is the source which subscribes a subject, or the target which publishes
+// messages to a subject.
+type Nats struct {
+	conn      connection
+	namespace string
+
+	// source
+	factory     *natsFactory
+	listenerKey string
+	subject     string
+	queue       string
+	nodeName    string
+
+	// target
+	subjectTemplate *template.Template
+	headers         map[string]*template.Template
+}
+
+func init() {
+	factory := &natsFactory{
+		getKubeClient: client.GetKubeClient,
+		connect:       connect,
+		clients:       make(map[string]*cachedClient),
+	}
+	provider.RegisterSource(factory)
+	provider.RegisterTarget(factory)
+}
+
+func (*natsFactory) Type() v1.RuleEndpointTypeDef {
+	return v1.RuleEndpointTypeNATS
+}
+
+// OptionsFromProperties builds the nats options from the properties of RuleEndpoint
+// and the credentials in the secret it references
+func OptionsFromProperties(properties map[string]string, creds utils.Credentials) (nats.Options, error) {
+	options := nats.GetDefaultOptions()
+	options.Name = properties[PropertyName]
+	// keep reconnecting, the rules can't recreate the connection
+	options.MaxReconnect = -1
+	for _, server := range strings.Split(properties[constants.Servers], ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			options.Servers = append(options.Servers, server)
+		}
+	}
+	if len(options.Servers) == 0 {
+		return options, fmt.Errorf("property %q can not be empty", constants.Servers)
+	}
+	if creds.Token != "" && creds.Username != "" {
+		return options, fmt.Errorf("%q and %q can not be set at the same time in the credentials secret",
+			utils.CredentialsToken, utils.CredentialsUsername)
+	}
+	options.User = creds.Username
+	options.Password = creds.Password
+	options.Token = creds.Token
+
+	tlsConfig, err := utils.TLSConfigFromProperties(properties)
+	if err != nil {
+		return options, err
+	}
+	if tlsConfig != nil {
+		options.Secure = true
+		options.TLSConfig = tlsConfig
+	}
+	return options, nil
+}
+
+// getClient returns the connection of RuleEndpoint, the connection is recreated when the properties
+// or the credentials secret are changed
+func (factory *natsFactory) getClient(ep *v1.RuleEndpoint) (connection, error) {
+	creds, err := utils.GetCredentials(factory.getKubeClient(), ep.Namespace, ep.Spec.Properties)
+	if err != nil {
+		return nil, err
+	}
+	key := ep.Namespace + "/" + ep.Name
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+	cached, ok := factory.clients[key]
+	if ok && cached.secretVersion == creds.ResourceVersion && equalProperties(cached.properties, ep.Spec.Properties) {
+		return cached.conn, nil
+	}
+	options, err := OptionsFromProperties(ep.Spec.Properties, creds)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := factory.connect(options)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		cached.conn.Close()
+	}
+	factory.clients[key] = &cachedClient{properties: ep.Spec.Properties, secretVersion: creds.ResourceVersion, conn: conn}
+	return conn, nil
+}
+
+func equalProperties(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (factory *natsFactory) GetSource(ep *v1.RuleEndpoint, sourceResource map[string]string) provider.Source {
+	subject, exist := sourceResource[constants.Subject]
+	if !exist {
+		klog.Errorf("source resource attributes \"subject\" does not exist")
+		return nil
+	}
+	nodeName, exist := sourceResource[constants.NodeName]
+	if !exist {
+		klog.Errorf("source resource attributes \"node_name\" does not exist")
+		return nil
+	}
+	conn, err := factory.getClient(ep)
+	if err != nil {
+		klog.Errorf("failed to connect nats of ruleendpoint %s/%s: %v", ep.Namespace, ep.Name, err)
+		return nil
+	}
+	return &Nats{
+		conn:        conn,
+		namespace:   ep.Namespace,
+		factory:     factory,
+		listenerKey: fmt.Sprintf("%s/%s/%s/%s", ep.Namespace, ep.Name, nodeName, subject),
+		subject:     subject,
+		queue:       sourceResource[constants.Queue],
+		nodeName:    nodeName,
+	}
+}
+
+func (factory *natsFactory) GetTarget(ep *v1.RuleEndpoint, targetResource map[string]string) provider.Target {
+	subject, exist := targetResource[constants.Subject]
+	if !exist {
+		klog.Errorf("target resource attributes \"subject\" does not exist")
+		return nil
+	}
+	subjectTemplate, err := utils.ParseTemplate(constants.Subject, subject)
+	if err != nil {
+		klog.Errorf("invalid template of target resource attributes \"subject\": %v", err)
+		return nil
+	}
+	headers, err := utils.ParseHeaderTemplates(targetResource)
+	if err != nil {
+		klog.Errorf("invalid target resource headers: %v", err)
+		return nil
+	}
+	conn, err := factory.getClient(ep)
+	if err != nil {
+		klog.Errorf("failed to connect nats of ruleendpoint %s/%s: %v", ep.Namespace, ep.Name, err)
+		return nil
+	}
+	return &Nats{
+		conn:            conn,
+		namespace:       ep.Namespace,
+		subjectTemplate: subjectTemplate,
+		headers:         headers,
+	}
+}
+
+func (*Nats) Name() string {
+	return constants.NatsProvider
+}
+
+func (n *Nats) RegisterListener(handle listener.Handle) error {
+	unsubscribe, err := n.conn.subscribe(n.subject, n.queue, func(msg *nats.Msg) {
+		if _, err := handle(msg); err != nil {
+			klog.Errorf("failed to handle message of nats subject %s: %v", msg.Subject, err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if old, loaded := n.factory.subscriptions.Swap(n.listenerKey, unsubscribe); loaded {
+		unsubscribeSubject(old.(func() error), n.subject)
+	}
+	return nil
+}
+
+func (n *Nats) UnregisterListener() {
+	if unsubscribe, loaded := n.factory.subscriptions.LoadAndDelete(n.listenerKey); loaded {
+		unsubscribeSubject(unsubscribe.(func() error), n.subject)
+	}
+}
+
+func unsubscribeSubject(unsubscribe func() error, subject string) {
+	if err := unsubscribe(); err != nil {
+		klog.Warningf("failed to unsubscribe nats subject %s: %v", subject, err)
+	}
+}
+
+func (n *Nats) Forward(target provider.Target, data interface{}) (interface{}, error) {
+	msg, ok := data.(*nats.Msg)
+	if !ok {
+		klog.Errorf("message type %T error", data)
+		return nil, fmt.Errorf("message type %T error", data)
+	}
+	res := make(map[string]interface{})
+	res["messageID"] = uuid.New().String()
+	res["nodeName"] = n.nodeName
+	res["data"] = msg.Data
+	res["topic"] = msg.Subject
+	res["header"] = http.Header(msg.Header)
+	resp, err := target.GoToTarget(res, nil)
+	if err != nil {
+		klog.Errorf("message is send to target failed. subject: %s, target: %s, err: %v", msg.Subject, target.Name(), err)
+		return nil, err
+	}
+	klog.V(4).Infof("message is send to target successfully. subject: %s, target: %s", msg.Subject, target.Name())
+	return resp, nil
+}
+
+func (n *Nats) GoToTarget(data map[string]interface{}, _ chan struct{}) (interface{}, error) {
+	body, ok := data["data"].([]byte)
+	if !ok {
+		return nil, errors.New("input data does not exist valid value \"data\"")
+	}
+	// use zero value if not found
+	messageID, _ := data["messageID"].(string)
+	nodeName, _ := data["nodeName"].(string)
+	param, _ := data["param"].(string)
+	sourceTopic, _ := data["topic"].(string)
+	tmplData := utils.TemplateData{MessageID: messageID, NodeName: nodeName, Namespace: n.namespace, Param: param, Topic: sourceTopic}
+
+	subject, err := utils.RenderTemplate(n.subjectTemplate, tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render subject: %v", err)
+	}
+	msg := &nats.Msg{Subject: subject, Data: body}
+	if len(n.headers) > 0 {
+		msg.Header = make(nats.Header, len(n.headers))
+		for name, tmpl := range n.headers {
+			value, err := utils.RenderTemplate(tmpl, tmplData)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render header %s: %v", name, err)
+			}
+			msg.Header[name] = []string{value}
+		}
+	}
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return nil, err
+	}
+	// wait for the server to receive the message, so failures are reported in rule status
+	if err := n.conn.FlushTimeout(flushTimeout); err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("message %s is published to nats subject %s", messageID, subject)
+	return nil, nil
+}
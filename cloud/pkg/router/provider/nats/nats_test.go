@@ -0,0 +1,207 @@
+package nats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+)
+
+// fakeConn delivers the published messages to the subscriptions of the same subject
+type fakeConn struct {
+	options nats.Options
+
+	mu            sync.Mutex
+	subscriptions map[string]nats.MsgHandler
+	closed        bool
+}
+
+func (c *fakeConn) PublishMsg(msg *nats.Msg) error {
+	c.mu.Lock()
+	handle := c.subscriptions[msg.Subject]
+	c.mu.Unlock()
+	if handle != nil {
+		go handle(msg)
+	}
+	return nil
+}
+
+func (*fakeConn) FlushTimeout(time.Duration) error { return nil }
+
+func (c *fakeConn) subscribe(subject, _ string, handle nats.MsgHandler) (func() error, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscriptions[subject] = handle
+	return func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subscriptions, subject)
+		return nil
+	}, nil
+}
+
+func (c *fakeConn) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+}
+
+func (c *fakeConn) subscribed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.subscriptions)
+}
+
+type recordTarget struct {
+	ch chan map[string]interface{}
+}
+
+func (*recordTarget) Name() string { return "record" }
+
+func (r *recordTarget) GoToTarget(data map[string]interface{}, _ chan struct{}) (interface{}, error) {
+	r.ch <- data
+	return nil, nil
+}
+
+func newRuleEndpoint(properties map[string]string) *v1.RuleEndpoint {
+	return &v1.RuleEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "default"},
+		Spec: v1.RuleEndpointSpec{
+			RuleEndpointType: v1.RuleEndpointTypeNATS,
+			Properties:       properties,
+		},
+	}
+}
+
+func newFactory(kubeClient kubernetes.Interface, conns *[]*fakeConn) *natsFactory {
+	return &natsFactory{
+		getKubeClient: func() kubernetes.Interface { return kubeClient },
+		connect: func(options nats.Options) (connection, error) {
+			conn := &fakeConn{options: options, subscriptions: make(map[string]nats.MsgHandler)}
+			*conns = append(*conns, conn)
+			return conn, nil
+		},
+		clients: make(map[string]*cachedClient),
+	}
+}
+
+func TestOptionsFromProperties(t *testing.T) {
+	options, err := OptionsFromProperties(map[string]string{constants.Servers: "nats://a:4222,b:4222"},
+		utils.Credentials{Username: "user", Password: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nats://a:4222", "b:4222"}, options.Servers)
+	assert.Equal(t, "user", options.User)
+	assert.Equal(t, "secret", options.Password)
+	assert.Equal(t, -1, options.MaxReconnect)
+	assert.False(t, options.Secure)
+
+	_, err = OptionsFromProperties(map[string]string{}, utils.Credentials{})
+	assert.Error(t, err)
+	_, err = OptionsFromProperties(map[string]string{constants.Servers: "a:4222"}, utils.Credentials{Username: "user", Token: "t"})
+	assert.Error(t, err)
+	_, err = OptionsFromProperties(map[string]string{constants.Servers: "a:4222", "tls_ca": "invalid"}, utils.Credentials{})
+	assert.Error(t, err)
+}
+
+func TestGetClientWithCredentialsSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "nats-credentials", Namespace: "default", ResourceVersion: "1"},
+		Data:       map[string][]byte{utils.CredentialsToken: []byte("secret")},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+	var conns []*fakeConn
+	factory := newFactory(kubeClient, &conns)
+	ep := newRuleEndpoint(map[string]string{constants.Servers: "a:4222", utils.PropertyCredentialsSecret: "nats-credentials"})
+
+	_, err := factory.getClient(ep)
+	require.NoError(t, err)
+	_, err = factory.getClient(ep)
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+	assert.Equal(t, "secret", conns[0].options.Token)
+
+	// the connection is recreated with the new token after the secret is changed
+	secret.ResourceVersion = "2"
+	secret.Data[utils.CredentialsToken] = []byte("rotated")
+	_, err = kubeClient.CoreV1().Secrets("default").Update(context.TODO(), secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = factory.getClient(ep)
+	require.NoError(t, err)
+	require.Len(t, conns, 2)
+	assert.Equal(t, "rotated", conns[1].options.Token)
+	assert.True(t, conns[0].closed)
+
+	_, err = newFactory(fake.NewSimpleClientset(), &conns).getClient(ep)
+	assert.Error(t, err)
+}
+
+func TestGoToTarget(t *testing.T) {
+	var conns []*fakeConn
+	factory := newFactory(fake.NewSimpleClientset(), &conns)
+	ep := newRuleEndpoint(map[string]string{constants.Servers: "a:4222"})
+
+	target := factory.GetTarget(ep, map[string]string{
+		constants.Subject: "telemetry.{{.NodeName}}",
+		"header.Node":     "{{.NodeName}}",
+	})
+	require.NotNil(t, target)
+	assert.Equal(t, constants.NatsProvider, target.Name())
+
+	received := make(chan *nats.Msg, 1)
+	_, err := conns[0].subscribe("telemetry.node-1", "", func(msg *nats.Msg) { received <- msg })
+	require.NoError(t, err)
+	_, err = target.GoToTarget(map[string]interface{}{"messageID": "id-1", "nodeName": "node-1", "data": []byte("25")}, nil)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "telemetry.node-1", msg.Subject)
+		assert.Equal(t, []byte("25"), msg.Data)
+		assert.Equal(t, "node-1", msg.Header.Get("Node"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for published message")
+	}
+
+	assert.Nil(t, factory.GetTarget(ep, map[string]string{}))
+	assert.Nil(t, factory.GetTarget(ep, map[string]string{constants.Subject: "{{.Unknown"}))
+}
+
+func TestSource(t *testing.T) {
+	var conns []*fakeConn
+	factory := newFactory(fake.NewSimpleClientset(), &conns)
+	ep := newRuleEndpoint(map[string]string{constants.Servers: "a:4222"})
+	resource := map[string]string{constants.Subject: "commands.node-1", constants.NodeName: "node-1"}
+	source := factory.GetSource(ep, resource)
+	require.NotNil(t, source)
+
+	target := &recordTarget{ch: make(chan map[string]interface{}, 1)}
+	require.NoError(t, source.RegisterListener(func(data interface{}) (interface{}, error) {
+		return source.Forward(target, data)
+	}))
+	assert.Equal(t, 1, conns[0].subscribed())
+
+	require.NoError(t, conns[0].PublishMsg(&nats.Msg{Subject: "commands.node-1", Data: []byte("open")}))
+	select {
+	case data := <-target.ch:
+		assert.Equal(t, "node-1", data["nodeName"])
+		assert.Equal(t, []byte("open"), data["data"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for forwarded message")
+	}
+
+	// the source is unregistered with another instance, as the rule does when it is deleted
+	factory.GetSource(ep, resource).UnregisterListener()
+	assert.Equal(t, 0, conns[0].subscribed())
+}
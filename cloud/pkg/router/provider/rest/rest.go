@@ -111,8 +111,12 @@ func (r *Rest) Forward(target provider.Target, data interface{}) (interface{}, e
 	res["messageID"] = messageID
 	res["param"] = strings.TrimPrefix(uri[3], r.Path)
 	res["data"] = d["data"]
+	if body, streamed := d["body"]; streamed {
+		res["body"] = body
+	}
 	res["nodeName"] = strings.Split(request.RequestURI, "/")[1]
 	res["header"] = request.Header
+	res["topic"] = uri[3]
 	res["method"] = request.Method
 	stop := make(chan struct{})
 	respch := make(chan interface{})
@@ -126,6 +130,10 @@ func (r *Rest) Forward(target provider.Target, data interface{}) (interface{}, e
 		respch <- resp
 	}()
 	timer := time.NewTimer(timeout)
+	if _, streamed := res["body"]; streamed {
+		// uploading the streamed body may take longer than timeout, it is canceled by the client
+		timer.Stop()
+	}
 	var httpResponse = &http.Response{
 		Request: request,
 		Header:  http.Header{},
@@ -139,6 +147,10 @@ func (r *Rest) Forward(target provider.Target, data interface{}) (interface{}, e
 		if resp == nil {
 			httpResponse.StatusCode = http.StatusOK
 			httpResponse.Body = io.NopCloser(strings.NewReader("message delivered"))
+		} else if streamResponse, ok := resp.(*http.Response); ok {
+			// the body of streamed response is read by the listener
+			streamResponse.Request = request
+			return streamResponse, nil
 		} else {
 			msg, ok := resp.(*model.Message)
 			if !ok {
@@ -169,8 +181,13 @@ func (r *Rest) Forward(target provider.Target, data interface{}) (interface{}, e
 	case err := <-errch:
 		timer.Stop()
 		httpResponse.StatusCode = http.StatusInternalServerError
+		if errors.Is(err, provider.ErrThrottled) {
+			httpResponse.StatusCode = http.StatusTooManyRequests
+		}
 		httpResponse.Body = io.NopCloser(strings.NewReader(err.Error()))
 		klog.Errorf("failed to get response, msg id: %s, write result: %v", messageID, err)
+		// the response is still written to client, err is returned for the status of rule
+		return httpResponse, err
 	case _, ok := <-timer.C:
 		if !ok {
 			return nil, errors.New("failed to get timer channel")
@@ -197,8 +214,10 @@ func (r *Rest) GoToTarget(data map[string]interface{}, _ chan struct{}) (interfa
 	if !exist {
 		return nil, errors.New("input data does not exist value \"data\"")
 	}
+	// the request proxied from edge keeps its method, header and query, and its body may be empty
+	proxied, _ := data["proxy"].(bool)
 	content, ok := v.([]byte)
-	if !ok || len(content) == 0 {
+	if !ok || (len(content) == 0 && !proxied) {
 		return nil, errors.New("invalid convert to []byte")
 	}
 	nodeName, ok := data["nodeName"].(string)
@@ -206,10 +225,30 @@ func (r *Rest) GoToTarget(data map[string]interface{}, _ chan struct{}) (interfa
 		err := fmt.Errorf("input data does not exist valid value \"nodeName\"")
 		klog.Warning(err.Error())
 	}
-	req, err := httpUtils.BuildRequest(http.MethodPost, r.Endpoint, bytes.NewReader(content), "", nodeName)
+	method, endpoint := http.MethodPost, r.Endpoint
+	if proxied {
+		if m, _ := data["method"].(string); m != "" {
+			method = m
+		}
+		if query, _ := data["param"].(string); query != "" {
+			sep := "?"
+			if strings.Contains(endpoint, "?") {
+				sep = "&"
+			}
+			endpoint += sep + query
+		}
+	}
+	req, err := httpUtils.BuildRequest(method, endpoint, bytes.NewReader(content), "", nodeName)
 	if err != nil {
 		return nil, err
 	}
+	if header, ok := data["header"].(http.Header); ok && proxied {
+		for key, values := range header {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
 
 	client := httpUtils.NewHTTPClient()
 	return httpUtils.SendRequest(req, client)
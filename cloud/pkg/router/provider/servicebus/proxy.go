@@ -0,0 +1,183 @@
+package servicebus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+	commonconstants "github.com/kubeedge/kubeedge/common/constants"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+const (
+	// proxySource is the source of http requests proxied by edge servicebus to cloud services
+	proxySource = "servicebus_proxy"
+
+	tokenReviewCacheSize = 1024
+	tokenReviewCacheTTL  = time.Minute
+)
+
+var (
+	// tokenReviews caches the authenticated token reviews by the hash of token
+	tokenReviews = cache.NewLRUExpireCache(tokenReviewCacheSize)
+
+	// reviewToken authenticates the service account token by kube-apiserver, it is replaced in tests
+	reviewToken = func(ctx context.Context, token string) (*authenticationv1.TokenReviewStatus, error) {
+		review, err := client.GetKubeClient().AuthenticationV1().TokenReviews().Create(ctx,
+			&authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &review.Status, nil
+	}
+
+	// getPodNodeName returns the node of pod, it is replaced in tests
+	getPodNodeName = func(ctx context.Context, namespace, name string) (string, error) {
+		pod, err := client.GetKubeClient().CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return pod.Spec.NodeName, nil
+	}
+)
+
+// proxyError is the failure of proxied request with the status code responded to edge
+type proxyError struct {
+	statusCode int
+	err        error
+}
+
+func (e *proxyError) Error() string {
+	return e.err.Error()
+}
+
+// authorize authenticates the service account token of the request proxied from edge, the
+// service account must be allowed by rule, and the token must be bound to a pod on the node.
+func (sb *ServiceBus) authorize(ctx context.Context, authorization string) error {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return &proxyError{http.StatusUnauthorized, errors.New("service account token is required")}
+	}
+	if len(sb.serviceAccounts) == 0 {
+		return &proxyError{http.StatusForbidden, fmt.Errorf("no service account is allowed to call %s", sb.TargetURL)}
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+	var status *authenticationv1.TokenReviewStatus
+	if v, ok := tokenReviews.Get(key); ok {
+		status = v.(*authenticationv1.TokenReviewStatus)
+	} else {
+		var err error
+		if status, err = reviewToken(ctx, token); err != nil {
+			klog.Errorf("failed to review service account token: %v", err)
+			return &proxyError{http.StatusServiceUnavailable, errors.New("failed to review service account token")}
+		}
+		if !status.Authenticated {
+			return &proxyError{http.StatusUnauthorized, fmt.Errorf("invalid service account token: %s", status.Error)}
+		}
+		tokenReviews.Add(key, status, tokenReviewCacheTTL)
+	}
+
+	namespace, name, err := serviceaccount.SplitUsername(status.User.Username)
+	if err != nil {
+		return &proxyError{http.StatusForbidden, fmt.Errorf("user %s is not a service account", status.User.Username)}
+	}
+	if !utils.IsServiceAccountAllowed(sb.serviceAccounts, namespace, name) {
+		return &proxyError{http.StatusForbidden, fmt.Errorf("service account %s/%s is not allowed to call %s", namespace, name, sb.TargetURL)}
+	}
+
+	var nodeName string
+	if values := status.User.Extra[serviceaccount.NodeNameKey]; len(values) > 0 {
+		nodeName = values[0]
+	} else if values := status.User.Extra[serviceaccount.PodNameKey]; len(values) > 0 {
+		if nodeName, err = getPodNodeName(ctx, namespace, values[0]); err != nil {
+			klog.Errorf("failed to get pod %s/%s: %v", namespace, values[0], err)
+			return &proxyError{http.StatusServiceUnavailable, fmt.Errorf("failed to get pod %s/%s", namespace, values[0])}
+		}
+	}
+	if nodeName != sb.nodeName {
+		return &proxyError{http.StatusForbidden, fmt.Errorf("service account token is not bound to a pod on node %s", sb.nodeName)}
+	}
+	return nil
+}
+
+// forwardProxy forwards the http request proxied from edge to target, and responds the
+// response of target to edge
+func (sb *ServiceBus) forwardProxy(target provider.Target, message *model.Message) (interface{}, error) {
+	respond := func(statusCode int, header http.Header, body []byte) {
+		response := commonType.HTTPResponse{Header: header, StatusCode: statusCode, Body: body}
+		sendToEdge(message.NewRespByMessage(message, response))
+	}
+	fail := func(err error) (interface{}, error) {
+		statusCode := http.StatusBadGateway
+		var pe *proxyError
+		if errors.As(err, &pe) {
+			statusCode = pe.statusCode
+		}
+		respond(statusCode, nil, []byte(err.Error()))
+		return nil, err
+	}
+
+	content, err := message.GetContentData()
+	if err != nil {
+		return fail(&proxyError{http.StatusBadRequest, fmt.Errorf("get message %s content err: %v", message.GetID(), err)})
+	}
+	var request commonType.HTTPRequest
+	if err := json.Unmarshal(content, &request); err != nil {
+		return fail(&proxyError{http.StatusBadRequest, fmt.Errorf("message %s content can not convert to HTTPRequest: %v", message.GetID(), err)})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := sb.authorize(ctx, request.Header.Get(commonType.HeaderAuthorization)); err != nil {
+		klog.Warningf("request %s proxied from node %s is denied: %v", message.GetID(), sb.nodeName, err)
+		return fail(err)
+	}
+
+	// the token of edge pod and the node name claimed by it are not passed to cloud service
+	header := request.Header.Clone()
+	header.Del(commonType.HeaderAuthorization)
+	header.Del(commonType.HeaderNodeName)
+	res := map[string]interface{}{
+		"messageID": message.GetID(),
+		"nodeName":  sb.nodeName,
+		"data":      request.Body,
+		"header":    header,
+		"method":    request.Method,
+		"param":     request.URL,
+		"proxy":     true,
+	}
+	resp, err := target.GoToTarget(res, nil)
+	if err != nil {
+		klog.Errorf("request %s proxied from node %s failed to go to target %s: %v", message.GetID(), sb.nodeName, target.Name(), err)
+		return fail(err)
+	}
+	httpResp, ok := resp.(*http.Response)
+	if !ok {
+		return fail(fmt.Errorf("invalid response of target %s", target.Name()))
+	}
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, commonconstants.MaxRespBodyLength))
+	if err != nil {
+		return fail(fmt.Errorf("failed to read response of target %s: %v", target.Name(), err))
+	}
+	respond(httpResp.StatusCode, httpResp.Header, body)
+	klog.Infof("request %s proxied from node %s is sent to target %s", message.GetID(), sb.nodeName, target.Name())
+	return httpResp, nil
+}
@@ -0,0 +1,172 @@
+package servicebus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+// proxyTarget records the data and responds with the status code
+type proxyTarget struct {
+	data       map[string]interface{}
+	statusCode int
+	err        error
+}
+
+func (*proxyTarget) Name() string { return "proxy" }
+
+func (p *proxyTarget) GoToTarget(data map[string]interface{}, _ chan struct{}) (interface{}, error) {
+	p.data = data
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &http.Response{
+		StatusCode: p.statusCode,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("pong")),
+	}, nil
+}
+
+func stubTokenReview(t *testing.T, reviews map[string]*authenticationv1.TokenReviewStatus, pods map[string]string) {
+	t.Helper()
+	origReview, origPod := reviewToken, getPodNodeName
+	t.Cleanup(func() {
+		reviewToken, getPodNodeName = origReview, origPod
+		tokenReviews.RemoveAll(func(interface{}) bool { return true })
+	})
+	reviewToken = func(_ context.Context, token string) (*authenticationv1.TokenReviewStatus, error) {
+		status, ok := reviews[token]
+		if !ok {
+			return nil, errors.New("apiserver is unavailable")
+		}
+		return status, nil
+	}
+	getPodNodeName = func(_ context.Context, namespace, name string) (string, error) {
+		nodeName, ok := pods[namespace+"/"+name]
+		if !ok {
+			return "", errors.New("pod not found")
+		}
+		return nodeName, nil
+	}
+}
+
+func authenticated(username string, extra map[string]authenticationv1.ExtraValue) *authenticationv1.TokenReviewStatus {
+	return &authenticationv1.TokenReviewStatus{
+		Authenticated: true,
+		User:          authenticationv1.UserInfo{Username: username, Extra: extra},
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	stubTokenReview(t, map[string]*authenticationv1.TokenReviewStatus{
+		"node-bound": authenticated("system:serviceaccount:default:app",
+			map[string]authenticationv1.ExtraValue{serviceaccount.NodeNameKey: {"edge-1"}}),
+		"pod-bound": authenticated("system:serviceaccount:monitoring:agent",
+			map[string]authenticationv1.ExtraValue{serviceaccount.PodNameKey: {"agent-0"}}),
+		"other-node": authenticated("system:serviceaccount:default:app",
+			map[string]authenticationv1.ExtraValue{serviceaccount.NodeNameKey: {"edge-2"}}),
+		"unbound":     authenticated("system:serviceaccount:default:app", nil),
+		"not-allowed": authenticated("system:serviceaccount:default:other", nil),
+		"user":        authenticated("admin", nil),
+		"invalid":     {Error: "token expired"},
+	}, map[string]string{"monitoring/agent-0": "edge-1"})
+
+	allowed, err := utils.ParseServiceAccounts("default/app,monitoring/*")
+	require.NoError(t, err)
+	sb := &ServiceBus{nodeName: "edge-1", TargetURL: "/api", serviceAccounts: allowed}
+
+	tests := []struct {
+		authorization string
+		statusCode    int
+	}{
+		{"Bearer node-bound", 0},
+		{"Bearer pod-bound", 0},
+		{"", http.StatusUnauthorized},
+		{"Basic node-bound", http.StatusUnauthorized},
+		{"Bearer invalid", http.StatusUnauthorized},
+		{"Bearer unknown", http.StatusServiceUnavailable},
+		{"Bearer other-node", http.StatusForbidden},
+		{"Bearer unbound", http.StatusForbidden},
+		{"Bearer not-allowed", http.StatusForbidden},
+		{"Bearer user", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.authorization, func(t *testing.T) {
+			err := sb.authorize(context.Background(), tt.authorization)
+			if tt.statusCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var pe *proxyError
+			require.ErrorAs(t, err, &pe)
+			assert.Equal(t, tt.statusCode, pe.statusCode)
+		})
+	}
+
+	// no service account is allowed by default
+	sb.serviceAccounts = nil
+	assert.Error(t, sb.authorize(context.Background(), "Bearer node-bound"))
+}
+
+func TestForwardProxy(t *testing.T) {
+	stubTokenReview(t, map[string]*authenticationv1.TokenReviewStatus{
+		"token": authenticated("system:serviceaccount:default:app",
+			map[string]authenticationv1.ExtraValue{serviceaccount.NodeNameKey: {"edge-1"}}),
+	}, nil)
+	sent := stubEdge(t)
+	sb := &ServiceBus{nodeName: "edge-1", TargetURL: "/api",
+		serviceAccounts: []utils.ServiceAccount{{Namespace: "default", Name: "app"}}}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("NodeName", "edge-2")
+	header.Set("X-Trace", "1")
+	msg := model.NewMessage("").SetRoute(proxySource, "user").SetResourceOperation("node/edge-1/api", model.UploadOperation).
+		FillBody(commonType.HTTPRequest{Method: http.MethodGet, Header: header, URL: "q=1"})
+
+	target := &proxyTarget{statusCode: http.StatusOK}
+	resp, err := sb.Forward(target, msg)
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, http.MethodGet, target.data["method"])
+	assert.Equal(t, "q=1", target.data["param"])
+	assert.Equal(t, true, target.data["proxy"])
+	forwarded := target.data["header"].(http.Header)
+	assert.Empty(t, forwarded.Get("Authorization"))
+	assert.Empty(t, forwarded.Get("NodeName"))
+	assert.Equal(t, "1", forwarded.Get("X-Trace"))
+
+	require.Len(t, *sent, 1)
+	response := (*sent)[0].GetContent().(commonType.HTTPResponse)
+	assert.Equal(t, msg.GetID(), (*sent)[0].GetParentID())
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "pong", string(response.Body))
+
+	// the denied request does not go to target
+	header.Del("Authorization")
+	target = &proxyTarget{statusCode: http.StatusOK}
+	_, err = sb.Forward(target, msg)
+	assert.Error(t, err)
+	assert.Nil(t, target.data)
+	require.Len(t, *sent, 2)
+	assert.Equal(t, http.StatusUnauthorized, (*sent)[1].GetContent().(commonType.HTTPResponse).StatusCode)
+
+	// the failure of target is responded as bad gateway
+	header.Set("Authorization", "Bearer token")
+	_, err = sb.Forward(&proxyTarget{err: errors.New("unreachable")}, msg)
+	assert.Error(t, err)
+	require.Len(t, *sent, 3)
+	assert.Equal(t, http.StatusBadGateway, (*sent)[2].GetContent().(commonType.HTTPResponse).StatusCode)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -18,8 +19,10 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
 	commonconstants "github.com/kubeedge/kubeedge/common/constants"
 	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/util/httpstream"
 )
 
 type servicebusFactory struct{}
@@ -29,6 +32,15 @@ type ServiceBus struct {
 	servicePort string
 	nodeName    string
 	TargetURL   string
+	// service is the edge service <name>.<namespace>, the requests are sent to 127.0.0.1 if it is empty
+	service            string
+	scheme             string
+	ca                 []byte
+	insecureSkipVerify bool
+	// maxBodySize is the max size of request and response body streamed between cloud and edge
+	maxBodySize int64
+	// serviceAccounts are the service accounts of edge pods which can call the target by proxy
+	serviceAccounts []utils.ServiceAccount
 }
 
 func init() {
@@ -52,9 +64,15 @@ func (sf *servicebusFactory) GetSource(_ *v1.RuleEndpoint, sourceResource map[st
 		klog.Errorf("source resource attributes \"node_name\" does not exist")
 		return nil
 	}
+	serviceAccounts, err := utils.ParseServiceAccounts(sourceResource[constants.ServiceAccounts])
+	if err != nil {
+		klog.Errorf("source resource attributes \"service_accounts\" is invalid: %v", err)
+		return nil
+	}
 	cli := &ServiceBus{
-		nodeName:  nodeName,
-		TargetURL: targetURL,
+		nodeName:        nodeName,
+		TargetURL:       targetURL,
+		serviceAccounts: serviceAccounts,
 	}
 
 	return cli
@@ -62,6 +80,7 @@ func (sf *servicebusFactory) GetSource(_ *v1.RuleEndpoint, sourceResource map[st
 
 func (sb *ServiceBus) RegisterListener(handle listener.Handle) error {
 	listener.MessageHandlerInstance.AddListener(fmt.Sprintf("servicebus/%v/%v", path.Join("node", sb.nodeName), sb.TargetURL), handle)
+	listener.MessageHandlerInstance.AddListener(fmt.Sprintf("%s/%v/%v", proxySource, path.Join("node", sb.nodeName), sb.TargetURL), handle)
 	msg := model.NewMessage("")
 	msg.SetResourceOperation(fmt.Sprintf("%v/%v", path.Join("node", sb.nodeName), sb.TargetURL), "start")
 	msg.SetRoute(modules.RouterSourceServiceBus, modules.UserGroup)
@@ -75,6 +94,7 @@ func (sb *ServiceBus) UnregisterListener() {
 	msg.SetRoute(modules.RouterSourceServiceBus, modules.UserGroup)
 	beehiveContext.Send(modules.CloudHubModuleName, *msg)
 	listener.MessageHandlerInstance.RemoveListener(path.Join("servicebus/node", sb.nodeName, sb.TargetURL))
+	listener.MessageHandlerInstance.RemoveListener(path.Join(proxySource, "node", sb.nodeName, sb.TargetURL))
 }
 
 func (sb *ServiceBus) Name() string {
@@ -87,6 +107,9 @@ func (sb *ServiceBus) Forward(target provider.Target, data interface{}) (respons
 		klog.Errorf("message type %T error", data)
 		return nil, fmt.Errorf("message type %T error", data)
 	}
+	if message.GetSource() == proxySource {
+		return sb.forwardProxy(target, message)
+	}
 	res := make(map[string]interface{})
 	content, err := message.GetContentData()
 	if !ok {
@@ -116,7 +139,38 @@ func (sf *servicebusFactory) GetTarget(ep *v1.RuleEndpoint, targetResource map[s
 	}
 	cli := &ServiceBus{
 		targetPath:  targetPath,
-		servicePort: ep.Spec.Properties["service_port"],
+		servicePort: ep.Spec.Properties[constants.ServicePort],
+		scheme:      ep.Spec.Properties[utils.PropertyScheme],
+		ca:          []byte(ep.Spec.Properties[utils.PropertyTLSCA]),
+	}
+	if port, exist := targetResource[constants.Port]; exist {
+		cli.servicePort = port
+	}
+	insecure, err := strconv.ParseBool(ep.Spec.Properties[utils.PropertyTLSInsecureSkipVerify])
+	cli.insecureSkipVerify = err == nil && insecure
+	if cli.maxBodySize, err = utils.ParseMaxBodySize(ep.Spec.Properties[utils.PropertyMaxBodySize]); err != nil {
+		klog.Errorf("ruleendpoint %s/%s property %q is invalid: %v", ep.Namespace, ep.Name, utils.PropertyMaxBodySize, err)
+		return nil
+	}
+
+	// the services other than 127.0.0.1 must be allowed by ruleendpoint
+	if service, exist := targetResource[constants.Service]; exist {
+		target, err := utils.ParseServiceTarget(service)
+		if err != nil {
+			klog.Errorf("target resource attributes \"service\" is invalid: %v", err)
+			return nil
+		}
+		target.Port = cli.servicePort
+		allowed, err := utils.ParseAllowedTargets(ep.Spec.Properties[utils.PropertyAllowedTargets])
+		if err != nil {
+			klog.Errorf("ruleendpoint %s/%s property %q is invalid: %v", ep.Namespace, ep.Name, utils.PropertyAllowedTargets, err)
+			return nil
+		}
+		if !utils.IsServiceAllowed(allowed, target) {
+			klog.Errorf("service %s is not allowed by ruleendpoint %s/%s", target, ep.Namespace, ep.Name)
+			return nil
+		}
+		cli.service = target.Name + "." + target.Namespace
 	}
 	return cli
 }
@@ -126,10 +180,23 @@ func (sb *ServiceBus) GoToTarget(data map[string]interface{}, stop chan struct{}
 	messageID, ok := data["messageID"].(string)
 	param, ok := data["param"].(string)
 	nodeName, ok := data["nodeName"].(string)
-	request := commonType.HTTPRequest{}
+	request := commonType.HTTPRequest{
+		Service:            sb.service,
+		Scheme:             sb.scheme,
+		CA:                 sb.ca,
+		InsecureSkipVerify: sb.insecureSkipVerify,
+		StreamResponse:     stop != nil,
+		MaxBodySize:        sb.maxBodySize,
+	}
 	request.Method, ok = data["method"].(string)
 	request.Header, ok = data["header"].(http.Header)
-	request.Body, ok = data["data"].([]byte)
+	// the body too large for a message is streamed after the request
+	body, streamed := data["body"].(io.Reader)
+	if streamed {
+		request.Stream = true
+	} else {
+		request.Body, ok = data["data"].([]byte)
+	}
 	if !ok {
 		err := errors.New("data transform failed")
 		klog.Error(err.Error())
@@ -155,14 +222,40 @@ func (sb *ServiceBus) GoToTarget(data map[string]interface{}, stop chan struct{}
 	if _, exists := sessionMgr.GetSession(nodeName); !exists {
 		return nil, fmt.Errorf("cloudcore doesn't have session for node:%s", nodeName)
 	}
-	beehiveContext.Send(modules.CloudHubModuleName, *msg)
-	if stop != nil {
-		listener.MessageHandlerInstance.SetCallback(messageID, func(message *model.Message) {
-			response = message
-			stop <- struct{}{}
-		})
-		<-stop
-		listener.MessageHandlerInstance.DelCallback(messageID)
+	if stop == nil {
+		sendToEdge(msg)
+		return nil, nil
+	}
+
+	respCh := make(chan *model.Message, 1)
+	listener.MessageHandlerInstance.SetCallback(messageID, func(message *model.Message) {
+		respCh <- message
+	})
+	// the chunks of response body are held by stream until they are read
+	s := newStream(nodeName, messageID, sb.maxBodySize)
+	listener.MessageHandlerInstance.SetStreamCallback(messageID, s.receiveChunk)
+
+	sendToEdge(msg)
+	if streamed {
+		go func() {
+			sent, err := httpstream.Send(s.ctx, messageID, body, sb.maxBodySize, s.window, s.sendChunk)
+			if err != nil {
+				klog.Errorf("failed to stream request body of message %s to node %s, %d bytes sent: %v", messageID, nodeName, sent, err)
+				return
+			}
+			klog.Infof("request body of message %s is streamed to node %s, %d bytes sent", messageID, nodeName, sent)
+		}()
+	}
+
+	select {
+	case response = <-respCh:
+	case <-stop:
+		s.close(errStreamCanceled)
+		return nil, nil
+	}
+	if resp := s.response(response); resp != nil {
+		return resp, nil
 	}
+	s.close(nil)
 	return response, nil
 }
@@ -0,0 +1,49 @@
+package servicebus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+)
+
+func TestGetTarget(t *testing.T) {
+	factory := &servicebusFactory{}
+	ep := &v1.RuleEndpoint{Spec: v1.RuleEndpointSpec{
+		RuleEndpointType: v1.RuleEndpointTypeServiceBus,
+		Properties: map[string]string{
+			"service_port":             "8080",
+			"scheme":                   "https",
+			"tls_ca":                   "ca",
+			"tls_insecure_skip_verify": "true",
+			"allowed_targets":          "web.default,api.default:9090",
+		},
+	}}
+
+	target := factory.GetTarget(ep, map[string]string{"path": "/a"})
+	if assert.NotNil(t, target) {
+		sb := target.(*ServiceBus)
+		assert.Equal(t, "", sb.service)
+		assert.Equal(t, "8080", sb.servicePort)
+		assert.Equal(t, "https", sb.scheme)
+		assert.Equal(t, []byte("ca"), sb.ca)
+		assert.True(t, sb.insecureSkipVerify)
+		assert.Equal(t, utils.DefaultMaxBodySize, sb.maxBodySize)
+	}
+
+	target = factory.GetTarget(ep, map[string]string{"path": "/a", "service": "web.default"})
+	if assert.NotNil(t, target) {
+		assert.Equal(t, "web.default", target.(*ServiceBus).service)
+	}
+	target = factory.GetTarget(ep, map[string]string{"path": "/a", "service": "api.default", "port": "9090"})
+	if assert.NotNil(t, target) {
+		assert.Equal(t, "9090", target.(*ServiceBus).servicePort)
+	}
+
+	assert.Nil(t, factory.GetTarget(ep, map[string]string{"path": "/a", "service": "api.default"}))
+	assert.Nil(t, factory.GetTarget(ep, map[string]string{"path": "/a", "service": "db.default"}))
+	assert.Nil(t, factory.GetTarget(ep, map[string]string{"path": "/a", "service": "invalid"}))
+	assert.Nil(t, factory.GetTarget(ep, map[string]string{"service": "web.default"}))
+}
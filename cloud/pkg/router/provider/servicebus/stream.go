@@ -0,0 +1,171 @@
+package servicebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/util/httpstream"
+)
+
+const (
+	// maxPendingMessages is the max number of messages waiting to be sent to edge node before
+	// sending the next chunk of request body, it keeps the body from piling up in cloudhub
+	maxPendingMessages   = 16
+	pendingCheckInterval = 10 * time.Millisecond
+)
+
+var (
+	errStreamCanceled = errors.New("stream is canceled by cloud")
+
+	// pendingMessages returns the number of messages waiting to be sent to edge node, it is replaced in tests
+	pendingMessages = func(nodeName string) (int, error) {
+		sessionMgr, err := cloudhub.GetSessionManager()
+		if err != nil {
+			return 0, err
+		}
+		session, exists := sessionMgr.GetSession(nodeName)
+		if !exists {
+			return 0, fmt.Errorf("cloudcore doesn't have session for node:%s", nodeName)
+		}
+		return session.PendingNoAckMessages(), nil
+	}
+
+	// sendToEdge sends the message to edge node by cloudhub, it is replaced in tests
+	sendToEdge = func(msg *model.Message) {
+		beehiveContext.Send(modules.CloudHubModuleName, *msg)
+	}
+)
+
+// stream is a servicebus request whose request or response body is streamed between cloud
+// and edge, it is the body of streamed response.
+type stream struct {
+	nodeName  string
+	messageID string
+	receiver  *httpstream.Receiver
+	// window is the flow control window of the streamed request body
+	window *httpstream.Window
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+func newStream(nodeName, messageID string, maxBodySize int64) *stream {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &stream{
+		nodeName:  nodeName,
+		messageID: messageID,
+		window:    httpstream.NewWindow(),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	// the window updates of response body are not held back by the pending messages,
+	// edge waits for them to send more
+	s.receiver = httpstream.NewReceiver(messageID, maxBodySize, s.send)
+	return s
+}
+
+// receiveChunk receives the chunk of response body, or the window update of request body from edge
+func (s *stream) receiveChunk(message *model.Message) {
+	chunk, err := httpstream.ParseChunk(message)
+	if err == nil && httpstream.IsWindowUpdate(chunk) {
+		s.window.Update(chunk)
+		return
+	}
+	if err == nil {
+		err = s.receiver.Push(chunk)
+	}
+	if err != nil {
+		klog.Errorf("failed to receive response body of message %s: %v", s.messageID, err)
+		s.receiver.CloseWithError(err)
+	}
+}
+
+// sendChunk sends the chunk of request body to edge, it waits until cloudhub catches up
+func (s *stream) sendChunk(chunk *commonType.HTTPStreamChunk) error {
+	for chunk.Error == "" {
+		pending, err := pendingMessages(s.nodeName)
+		if err != nil {
+			return err
+		}
+		if pending < maxPendingMessages {
+			break
+		}
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-time.After(pendingCheckInterval):
+		}
+	}
+	s.send(chunk)
+	return nil
+}
+
+func (s *stream) send(chunk *commonType.HTTPStreamChunk) {
+	msg := model.NewMessage("")
+	msg.SetResourceOperation("node/"+s.nodeName+"/"+commonType.ResourceStreamChunk, model.UploadOperation)
+	msg.SetRoute(modules.RouterSourceServiceBus, modules.UserGroup)
+	msg.FillBody(chunk)
+	sendToEdge(msg)
+}
+
+// response returns the streamed response of message from edge, it is nil if the body is
+// carried by message.
+func (s *stream) response(message *model.Message) *http.Response {
+	content, err := message.GetContentData()
+	if err != nil {
+		return nil
+	}
+	var response commonType.HTTPResponse
+	if err := json.Unmarshal(content, &response); err != nil || !response.Stream {
+		return nil
+	}
+	return &http.Response{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		Body:       s,
+	}
+}
+
+func (s *stream) Read(p []byte) (int, error) {
+	return s.receiver.Read(p)
+}
+
+// Close stops the stream, edge is notified to cancel the request if the response body is
+// not received completely.
+func (s *stream) Close() error {
+	var reason error
+	if !s.receiver.Completed() {
+		reason = errStreamCanceled
+	}
+	s.close(reason)
+	return nil
+}
+
+// close releases the stream, and notifies edge to cancel the request if reason is not nil
+func (s *stream) close(reason error) {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		listener.MessageHandlerInstance.DelCallback(s.messageID)
+		listener.MessageHandlerInstance.DelStreamCallback(s.messageID)
+		s.receiver.Close()
+		if reason != nil {
+			if err := s.sendChunk(&commonType.HTTPStreamChunk{ID: s.messageID, Error: reason.Error()}); err != nil {
+				klog.Errorf("failed to cancel stream of message %s: %v", s.messageID, err)
+			}
+		}
+	})
+}
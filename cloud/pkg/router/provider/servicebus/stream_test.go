@@ -0,0 +1,98 @@
+package servicebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/util/httpstream"
+)
+
+func stubEdge(t *testing.T, pending ...int) *[]*model.Message {
+	t.Helper()
+	var sent []*model.Message
+	origPending, origSend := pendingMessages, sendToEdge
+	t.Cleanup(func() { pendingMessages, sendToEdge = origPending, origSend })
+	pendingMessages = func(string) (int, error) {
+		if len(pending) == 0 {
+			return 0, nil
+		}
+		n := pending[0]
+		pending = pending[1:]
+		return n, nil
+	}
+	sendToEdge = func(msg *model.Message) { sent = append(sent, msg) }
+	return &sent
+}
+
+func chunkOf(t *testing.T, msg *model.Message) *commonType.HTTPStreamChunk {
+	t.Helper()
+	content, err := json.Marshal(msg.GetContent())
+	require.NoError(t, err)
+	chunk, err := httpstream.ParseChunk(model.NewMessage("").FillBody(content))
+	require.NoError(t, err)
+	return chunk
+}
+
+func TestStreamSendChunk(t *testing.T) {
+	// the chunk waits until the messages of node are sent by cloudhub
+	sent := stubEdge(t, maxPendingMessages, maxPendingMessages-1)
+	s := newStream("node", "id", 0)
+	defer s.close(nil)
+
+	require.NoError(t, s.sendChunk(&commonType.HTTPStreamChunk{ID: "id", Data: []byte("a")}))
+	require.Len(t, *sent, 1)
+	assert.Equal(t, "node/node/"+commonType.ResourceStreamChunk, (*sent)[0].GetResource())
+	assert.Equal(t, "a", string(chunkOf(t, (*sent)[0]).Data))
+
+	pendingMessages = func(string) (int, error) { return 0, errors.New("no session") }
+	assert.Error(t, s.sendChunk(&commonType.HTTPStreamChunk{ID: "id"}))
+}
+
+func TestStreamResponse(t *testing.T) {
+	sent := stubEdge(t)
+	s := newStream("node", "id", 0)
+
+	msg := model.NewMessage("").FillBody(commonType.HTTPResponse{StatusCode: http.StatusOK, Body: []byte("a")})
+	assert.Nil(t, s.response(msg))
+
+	msg = model.NewMessage("").FillBody(commonType.HTTPResponse{StatusCode: http.StatusOK, Stream: true})
+	resp := s.response(msg)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for _, chunk := range []commonType.HTTPStreamChunk{{ID: "id", Seq: 1, Data: []byte("b"), EOF: true}, {ID: "id", Data: []byte("a")}} {
+		s.receiveChunk(model.NewMessage("").FillBody(chunk))
+	}
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ab", string(body))
+
+	// edge is not canceled after the body is received completely
+	require.NoError(t, resp.Body.Close())
+	assert.Empty(t, *sent)
+}
+
+func TestStreamCancel(t *testing.T) {
+	sent := stubEdge(t)
+	s := newStream("node", "id", 1)
+
+	// the body larger than max size aborts the stream
+	s.receiveChunk(model.NewMessage("").FillBody(commonType.HTTPStreamChunk{ID: "id", Data: []byte("ab")}))
+	_, err := io.ReadAll(s)
+	assert.ErrorIs(t, err, httpstream.ErrBodyTooLarge)
+
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Close())
+	require.Len(t, *sent, 1)
+	assert.Equal(t, errStreamCanceled.Error(), chunkOf(t, (*sent)[0]).Error)
+	assert.ErrorIs(t, s.ctx.Err(), context.Canceled)
+}
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"errors"
+
 	"k8s.io/klog/v2"
 
 	v1 "github.com/kubeedge/api/apis/rules/v1"
@@ -11,6 +13,9 @@ type TargetFactory interface {
 	GetTarget(ep *v1.RuleEndpoint, targetResource map[string]string) Target
 }
 
+// ErrThrottled is wrapped in the error of messages which are dropped by rate limits
+var ErrThrottled = errors.New("message is throttled")
+
 type Target interface {
 	Name() string
 	GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error)
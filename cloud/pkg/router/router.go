@@ -9,6 +9,8 @@ import (
 	routerconfig "github.com/kubeedge/kubeedge/cloud/pkg/router/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/provider/eventbus"   // init eventbus
+	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/provider/kafka"      // init kafka
+	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/provider/nats"       // init nats
 	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/provider/rest"       // init rest
 	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/provider/servicebus" // init servicebus
 	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/rule"                // init rule
@@ -0,0 +1,117 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	routerv1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+)
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+	defaultAckTimeout     = 10 * time.Second
+)
+
+// errDeadLettered is wrapped in the error of messages which are sent to the dead-letter target
+var errDeadLettered = errors.New("message is sent to dead-letter target")
+
+// deliveryTarget retries the messages which fail to go to target, and sends them to the
+// dead-letter target after all the retries fail
+type deliveryTarget struct {
+	provider.Target
+	ruleKey        string
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	// ackTimeout is passed to target as "ackTimeout" in AtLeastOnce mode
+	ackTimeout time.Duration
+	deadLetter provider.Target
+	// sleep waits between retries, it is replaced in tests
+	sleep func(time.Duration)
+}
+
+// newDeliveryTarget wraps target with the delivery policy of rule, the target is
+// returned as is if rule does not define it
+func newDeliveryTarget(rule *routerv1.Rule, target provider.Target) (provider.Target, error) {
+	delivery := rule.Spec.Delivery
+	if delivery == nil {
+		return target, nil
+	}
+	t := &deliveryTarget{
+		Target:         target,
+		ruleKey:        getKey(rule.Namespace, rule.Name),
+		maxRetries:     int(delivery.MaxRetries),
+		initialBackoff: durationOrDefault(delivery.InitialBackoff, defaultInitialBackoff),
+		maxBackoff:     durationOrDefault(delivery.MaxBackoff, defaultMaxBackoff),
+		sleep:          time.Sleep,
+	}
+	if delivery.Mode == routerv1.RuleDeliveryAtLeastOnce {
+		t.ackTimeout = durationOrDefault(delivery.AckTimeout, defaultAckTimeout)
+	}
+	if delivery.DeadLetter != nil {
+		deadLetter, err := getTarget(rule.Namespace, delivery.DeadLetter.Target, delivery.DeadLetter.TargetResource)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: failed to get dead-letter target: %v", t.ruleKey, err)
+		}
+		t.deadLetter = deadLetter
+	}
+	return t, nil
+}
+
+func durationOrDefault(d *metav1.Duration, def time.Duration) time.Duration {
+	if d == nil {
+		return def
+	}
+	return d.Duration
+}
+
+func (t *deliveryTarget) GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error) {
+	// the streamed body can not be read again, so it is neither retried nor dead-lettered
+	if _, streamed := data["body"]; streamed {
+		return t.Target.GoToTarget(data, stop)
+	}
+	if t.ackTimeout > 0 {
+		copied := make(map[string]interface{}, len(data)+1)
+		for k, v := range data {
+			copied[k] = v
+		}
+		copied["ackTimeout"] = t.ackTimeout
+		data = copied
+	}
+	messageID, _ := data["messageID"].(string)
+
+	backoff := t.initialBackoff
+	var err error
+	for i := 0; ; i++ {
+		var resp interface{}
+		resp, err = t.Target.GoToTarget(data, stop)
+		if err == nil {
+			return resp, nil
+		}
+		if i >= t.maxRetries {
+			break
+		}
+		klog.Warningf("message %s of rule %s failed to go to target, retry %d/%d after %v: %v",
+			messageID, t.ruleKey, i+1, t.maxRetries, backoff, err)
+		t.sleep(backoff)
+		if backoff *= 2; backoff > t.maxBackoff {
+			backoff = t.maxBackoff
+		}
+	}
+
+	if t.deadLetter == nil {
+		return nil, err
+	}
+	if _, dlErr := t.deadLetter.GoToTarget(data, nil); dlErr != nil {
+		klog.Errorf("message %s of rule %s failed to go to dead-letter target: %v", messageID, t.ruleKey, dlErr)
+		return nil, fmt.Errorf("%v, and failed to go to dead-letter target: %v", err, dlErr)
+	}
+	klog.Warningf("message %s of rule %s is sent to dead-letter target", messageID, t.ruleKey)
+	return nil, fmt.Errorf("%w: %v", errDeadLettered, err)
+}
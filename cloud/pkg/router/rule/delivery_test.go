@@ -0,0 +1,110 @@
+package rule
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	routerv1 "github.com/kubeedge/api/apis/rules/v1"
+)
+
+// failingTarget fails the first failures calls
+type failingTarget struct {
+	recordTarget
+	failures int
+}
+
+func (f *failingTarget) GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error) {
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("target is unavailable")
+	}
+	return f.recordTarget.GoToTarget(data, stop)
+}
+
+func newTestDeliveryTarget(t *testing.T, delivery *routerv1.RuleDelivery, target, deadLetter *failingTarget) (*deliveryTarget, *[]time.Duration) {
+	rule := &routerv1.Rule{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "default"},
+		Spec:       routerv1.RuleSpec{Delivery: delivery},
+	}
+	wrapped, err := newDeliveryTarget(rule, target)
+	require.NoError(t, err)
+	dt := wrapped.(*deliveryTarget)
+	if deadLetter != nil {
+		dt.deadLetter = deadLetter
+	}
+	var sleeps []time.Duration
+	dt.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return dt, &sleeps
+}
+
+func TestDeliveryTargetRetry(t *testing.T) {
+	target := &failingTarget{failures: 3}
+	dt, sleeps := newTestDeliveryTarget(t, &routerv1.RuleDelivery{
+		MaxRetries:     5,
+		InitialBackoff: &metav1.Duration{Duration: time.Second},
+		MaxBackoff:     &metav1.Duration{Duration: 3 * time.Second},
+	}, target, nil)
+
+	_, err := dt.GoToTarget(map[string]interface{}{"data": []byte("a")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, *sleeps)
+	require.Len(t, target.data, 1)
+	assert.NotContains(t, target.data[0], "ackTimeout")
+}
+
+func TestDeliveryTargetDeadLetter(t *testing.T) {
+	target := &failingTarget{failures: 10}
+	deadLetter := &failingTarget{}
+	dt, sleeps := newTestDeliveryTarget(t, &routerv1.RuleDelivery{
+		Mode:       routerv1.RuleDeliveryAtLeastOnce,
+		MaxRetries: 2,
+		AckTimeout: &metav1.Duration{Duration: time.Second},
+	}, target, deadLetter)
+
+	_, err := dt.GoToTarget(map[string]interface{}{"data": []byte("a")}, nil)
+	assert.ErrorIs(t, err, errDeadLettered)
+	assert.Len(t, *sleeps, 2)
+	assert.Equal(t, 7, target.failures)
+	require.Len(t, deadLetter.data, 1)
+	assert.Equal(t, time.Second, deadLetter.data[0]["ackTimeout"])
+
+	// the message is lost if dead-letter target fails
+	deadLetter.failures = 1
+	_, err = dt.GoToTarget(map[string]interface{}{"data": []byte("b")}, nil)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errDeadLettered)
+	assert.Len(t, deadLetter.data, 1)
+}
+
+func TestDeliveryTargetStreamedBody(t *testing.T) {
+	target := &failingTarget{failures: 1}
+	deadLetter := &failingTarget{}
+	dt, sleeps := newTestDeliveryTarget(t, &routerv1.RuleDelivery{MaxRetries: 2}, target, deadLetter)
+
+	// the streamed body is consumed by the first attempt
+	_, err := dt.GoToTarget(map[string]interface{}{"body": strings.NewReader("a")}, nil)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errDeadLettered)
+	assert.Empty(t, *sleeps)
+	assert.Empty(t, deadLetter.data)
+}
+
+func TestNewDeliveryTarget(t *testing.T) {
+	target := &recordTarget{}
+	rule := &routerv1.Rule{ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "default"}}
+	wrapped, err := newDeliveryTarget(rule, target)
+	require.NoError(t, err)
+	assert.Same(t, target, wrapped)
+
+	rule.Spec.Delivery = &routerv1.RuleDelivery{
+		DeadLetter: &routerv1.RuleDeadLetter{Target: "not-exist", TargetResource: map[string]string{"topic": "dlq"}},
+	}
+	_, err = newDeliveryTarget(rule, target)
+	assert.Error(t, err)
+}
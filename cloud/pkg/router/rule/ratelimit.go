@@ -0,0 +1,141 @@
+package rule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	routerv1 "github.com/kubeedge/api/apis/rules/v1"
+	routerConfig "github.com/kubeedge/kubeedge/cloud/pkg/router/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+)
+
+// defaultNamespaceRateLimitKey is the key of NamespaceRateLimits which applies to the namespaces not listed
+const defaultNamespaceRateLimitKey = "*"
+
+var (
+	// endpointLimiters are the rate limiters of ruleendpoints, they are shared by the rules
+	// whose target is the ruleendpoint
+	endpointLimiters sync.Map
+	// namespaceLimiters are the rate limiters of the rules in each namespace
+	namespaceLimiters sync.Map
+)
+
+func newLimiter(limit *routerv1.RuleRateLimit) *rate.Limiter {
+	if limit == nil || limit.QPS <= 0 {
+		return nil
+	}
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = limit.QPS
+	}
+	return rate.NewLimiter(rate.Limit(limit.QPS), int(burst))
+}
+
+// updateEndpointLimiter replaces the rate limiter of ruleendpoint, nil ruleEndpoint removes it
+func updateEndpointLimiter(key string, ruleEndpoint *routerv1.RuleEndpoint) {
+	var limiter *rate.Limiter
+	if ruleEndpoint != nil {
+		limiter = newLimiter(ruleEndpoint.Spec.RateLimit)
+	}
+	if limiter == nil {
+		endpointLimiters.Delete(key)
+		return
+	}
+	endpointLimiters.Store(key, limiter)
+}
+
+func getEndpointLimiter(key string) *rate.Limiter {
+	v, ok := endpointLimiters.Load(key)
+	if !ok {
+		return nil
+	}
+	return v.(*rate.Limiter)
+}
+
+// getNamespaceLimiter returns the rate limiter of namespace from the router config, nil if
+// the namespace is unlimited
+func getNamespaceLimiter(namespace string) *rate.Limiter {
+	if v, ok := namespaceLimiters.Load(namespace); ok {
+		return v.(*rate.Limiter)
+	}
+	limits := routerConfig.Config.NamespaceRateLimits
+	limit, ok := limits[namespace]
+	if !ok {
+		if limit, ok = limits[defaultNamespaceRateLimitKey]; !ok {
+			return nil
+		}
+	}
+	limiter := newRouterLimiter(limit)
+	if limiter == nil {
+		return nil
+	}
+	v, _ := namespaceLimiters.LoadOrStore(namespace, limiter)
+	return v.(*rate.Limiter)
+}
+
+func newRouterLimiter(limit v1alpha1.RouterRateLimit) *rate.Limiter {
+	return newLimiter(&routerv1.RuleRateLimit{QPS: limit.QPS, Burst: limit.Burst})
+}
+
+// throttleTarget drops the messages over the rate limits of rule, target ruleendpoint
+// and namespace before they go to target
+type throttleTarget struct {
+	provider.Target
+	ruleKey     string
+	namespace   string
+	endpointKey string
+	limiter     *rate.Limiter
+}
+
+// newThrottleTarget wraps target with the rate limits which apply to rule
+func newThrottleTarget(rule *routerv1.Rule, target provider.Target) provider.Target {
+	return &throttleTarget{
+		Target:      target,
+		ruleKey:     getKey(rule.Namespace, rule.Name),
+		namespace:   rule.Namespace,
+		endpointKey: getKey(rule.Namespace, rule.Spec.Target),
+		limiter:     newLimiter(rule.Spec.RateLimit),
+	}
+}
+
+func (t *throttleTarget) GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error) {
+	if err := t.allow(time.Now()); err != nil {
+		messageID, _ := data["messageID"].(string)
+		klog.V(4).Infof("message %s of rule %s is dropped: %v", messageID, t.ruleKey, err)
+		return nil, err
+	}
+	return t.Target.GoToTarget(data, stop)
+}
+
+// allow takes a token from every limiter, the tokens are returned if any of them is exhausted
+func (t *throttleTarget) allow(now time.Time) error {
+	limiters := []struct {
+		limiter *rate.Limiter
+		reason  string
+	}{
+		{t.limiter, "rule " + t.ruleKey},
+		{getEndpointLimiter(t.endpointKey), "ruleendpoint " + t.endpointKey},
+		{getNamespaceLimiter(t.namespace), "namespace " + t.namespace},
+	}
+	reservations := make([]*rate.Reservation, 0, len(limiters))
+	for _, l := range limiters {
+		if l.limiter == nil {
+			continue
+		}
+		r := l.limiter.ReserveN(now, 1)
+		if !r.OK() || r.DelayFrom(now) > 0 {
+			r.CancelAt(now)
+			for _, reserved := range reservations {
+				reserved.CancelAt(now)
+			}
+			return fmt.Errorf("%w by the rate limit of %s", provider.ErrThrottled, l.reason)
+		}
+		reservations = append(reservations, r)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package rule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	routerv1 "github.com/kubeedge/api/apis/rules/v1"
+	routerConfig "github.com/kubeedge/kubeedge/cloud/pkg/router/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+)
+
+func newRateLimitedRule(namespace, target string, limit *routerv1.RuleRateLimit) *routerv1.Rule {
+	return &routerv1.Rule{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: namespace},
+		Spec:       routerv1.RuleSpec{Target: target, RateLimit: limit},
+	}
+}
+
+func TestThrottleTargetRuleLimit(t *testing.T) {
+	target := &recordTarget{}
+	throttled := newThrottleTarget(newRateLimitedRule("rule-limit", "ep", &routerv1.RuleRateLimit{QPS: 1, Burst: 2}), target)
+
+	for i := 0; i < 2; i++ {
+		_, err := throttled.GoToTarget(map[string]interface{}{}, nil)
+		require.NoError(t, err)
+	}
+	_, err := throttled.GoToTarget(map[string]interface{}{}, nil)
+	assert.ErrorIs(t, err, provider.ErrThrottled)
+	assert.ErrorContains(t, err, "rule rule-limit/rule")
+	assert.Len(t, target.data, 2)
+}
+
+func TestThrottleTargetEndpointLimit(t *testing.T) {
+	key := getKey("endpoint-limit", "ep")
+	updateEndpointLimiter(key, &routerv1.RuleEndpoint{Spec: routerv1.RuleEndpointSpec{
+		RateLimit: &routerv1.RuleRateLimit{QPS: 1},
+	}})
+	defer updateEndpointLimiter(key, nil)
+
+	// the rules with the same target share the limit of ruleendpoint
+	first := newThrottleTarget(newRateLimitedRule("endpoint-limit", "ep", &routerv1.RuleRateLimit{QPS: 10}), &recordTarget{})
+	second := newThrottleTarget(newRateLimitedRule("endpoint-limit", "ep", nil), &recordTarget{})
+	_, err := first.GoToTarget(map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	_, err = second.GoToTarget(map[string]interface{}{}, nil)
+	assert.ErrorIs(t, err, provider.ErrThrottled)
+	assert.ErrorContains(t, err, "ruleendpoint endpoint-limit/ep")
+
+	// the token of rule is returned when ruleendpoint is exhausted
+	now := time.Now()
+	rule := first.(*throttleTarget)
+	tokens := rule.limiter.TokensAt(now)
+	assert.Error(t, rule.allow(now))
+	assert.Equal(t, tokens, rule.limiter.TokensAt(now))
+
+	updateEndpointLimiter(key, nil)
+	_, err = second.GoToTarget(map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+}
+
+func TestThrottleTargetNamespaceLimit(t *testing.T) {
+	origin := routerConfig.Config.NamespaceRateLimits
+	routerConfig.Config.NamespaceRateLimits = map[string]v1alpha1.RouterRateLimit{
+		"limited": {QPS: 1},
+		"*":       {QPS: 1, Burst: 2},
+	}
+	defer func() { routerConfig.Config.NamespaceRateLimits = origin }()
+
+	first := newThrottleTarget(newRateLimitedRule("limited", "ep1", nil), &recordTarget{})
+	second := newThrottleTarget(newRateLimitedRule("limited", "ep2", nil), &recordTarget{})
+	_, err := first.GoToTarget(map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	_, err = second.GoToTarget(map[string]interface{}{}, nil)
+	assert.ErrorIs(t, err, provider.ErrThrottled)
+	assert.ErrorContains(t, err, "namespace limited")
+
+	// the namespaces not listed use the default limit
+	other := newThrottleTarget(newRateLimitedRule("other", "ep", nil), &recordTarget{})
+	for i := 0; i < 2; i++ {
+		_, err = other.GoToTarget(map[string]interface{}{}, nil)
+		require.NoError(t, err)
+	}
+	_, err = other.GoToTarget(map[string]interface{}{}, nil)
+	assert.ErrorIs(t, err, provider.ErrThrottled)
+}
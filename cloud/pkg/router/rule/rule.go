@@ -1,6 +1,7 @@
 package rule
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -86,12 +87,14 @@ func handleRule(data interface{}) (interface{}, error) {
 func addRuleEndpoint(ruleEndpoint *routerv1.RuleEndpoint) {
 	key := getKey(ruleEndpoint.Namespace, ruleEndpoint.Name)
 	ruleEndpoints.Store(key, ruleEndpoint)
+	updateEndpointLimiter(key, ruleEndpoint)
 	klog.Infof("add ruleendpoint %s success.", key)
 }
 
 func deleteRuleEndpoint(namespace, name string) {
 	key := getKey(namespace, name)
 	ruleEndpoints.Delete(key)
+	updateEndpointLimiter(key, nil)
 	klog.Infof("delete ruleendpoint %s success.", key)
 }
 
@@ -107,18 +110,32 @@ func addRule(rule *routerv1.Rule) error {
 		klog.Error(err)
 		return err
 	}
+	if target, err = newDeliveryTarget(rule, target); err != nil {
+		klog.Error(err)
+		return err
+	}
+	target = newThrottleTarget(rule, target)
+	if target, err = newRuleTarget(rule, target); err != nil {
+		klog.Error(err)
+		return err
+	}
 
 	ruleKey := getKey(rule.Namespace, rule.Name)
 	if err := source.RegisterListener(func(data interface{}) (interface{}, error) {
 		//TODO Use goroutine pool later
 		var execResult ExecResult
 		resp, err := source.Forward(target, data)
-		if err != nil {
+		switch {
+		case errors.Is(err, provider.ErrThrottled):
+			errMsg := ErrorMsg{Detail: err.Error(), Timestamp: time.Now()}
+			execResult = ExecResult{RuleID: rule.Name, ProjectID: rule.Namespace, Status: "THROTTLED", Error: errMsg}
+		case err != nil:
 			// rule.Status.Fail++
 			// record error info for rule
 			errMsg := ErrorMsg{Detail: err.Error(), Timestamp: time.Now()}
-			execResult = ExecResult{RuleID: rule.Name, ProjectID: rule.Namespace, Status: "FAIL", Error: errMsg}
-		} else {
+			execResult = ExecResult{RuleID: rule.Name, ProjectID: rule.Namespace, Status: "FAIL", Error: errMsg,
+				DeadLettered: errors.Is(err, errDeadLettered)}
+		default:
 			execResult = ExecResult{RuleID: rule.Name, ProjectID: rule.Namespace, Status: "SUCCESS"}
 		}
 		ResultChannel <- execResult
@@ -180,7 +197,11 @@ func getSourceOfRule(rule *routerv1.Rule) (provider.Source, error) {
 }
 
 func getTargetOfRule(rule *routerv1.Rule) (provider.Target, error) {
-	targetKey := getKey(rule.Namespace, rule.Spec.Target)
+	return getTarget(rule.Namespace, rule.Spec.Target, rule.Spec.TargetResource)
+}
+
+func getTarget(namespace, name string, targetResource map[string]string) (provider.Target, error) {
+	targetKey := getKey(namespace, name)
 	v, exist := ruleEndpoints.Load(targetKey)
 	if !exist {
 		return nil, fmt.Errorf("target rule endpoint %s does not existing", targetKey)
@@ -192,9 +213,9 @@ func getTargetOfRule(rule *routerv1.Rule) (provider.Target, error) {
 		return nil, fmt.Errorf("target definition %s does not existing", targetEp.Spec.RuleEndpointType)
 	}
 
-	target := tf.GetTarget(targetEp, rule.Spec.TargetResource)
+	target := tf.GetTarget(targetEp, targetResource)
 	if target == nil {
-		return nil, fmt.Errorf("can't get target: %s", rule.Spec.Target)
+		return nil, fmt.Errorf("can't get target: %s", name)
 	}
 	return target, nil
 }
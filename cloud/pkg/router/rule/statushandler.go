@@ -17,6 +17,8 @@ type ExecResult struct {
 	ProjectID string
 	Status    string
 	Error     ErrorMsg
+	// DeadLettered is true if the failed message is sent to the dead-letter target
+	DeadLettered bool
 }
 
 type ErrorMsg struct {
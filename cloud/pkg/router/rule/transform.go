@@ -0,0 +1,119 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	routerv1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/utils"
+)
+
+// ruleTarget applies the filter and transform of rule to the messages before they go to target
+type ruleTarget struct {
+	provider.Target
+	ruleKey     string
+	namespace   string
+	filter      *utils.MessageFilter
+	transformer *utils.Transformer
+}
+
+// newRuleTarget wraps target with the filter and transform of rule, the target is
+// returned as is if rule defines neither of them
+func newRuleTarget(rule *routerv1.Rule, target provider.Target) (provider.Target, error) {
+	if rule.Spec.Filter == "" && rule.Spec.Transform == nil {
+		return target, nil
+	}
+	t := &ruleTarget{
+		Target:    target,
+		ruleKey:   getKey(rule.Namespace, rule.Name),
+		namespace: rule.Namespace,
+	}
+	var err error
+	if rule.Spec.Filter != "" {
+		if t.filter, err = utils.NewMessageFilter(rule.Spec.Filter); err != nil {
+			return nil, fmt.Errorf("rule %s: %v", t.ruleKey, err)
+		}
+	}
+	if rule.Spec.Transform != nil {
+		if t.transformer, err = utils.NewTransformer(rule.Spec.Transform); err != nil {
+			return nil, fmt.Errorf("rule %s: %v", t.ruleKey, err)
+		}
+	}
+	return t, nil
+}
+
+func (t *ruleTarget) GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error) {
+	if _, streamed := data["body"]; streamed {
+		return nil, errors.New("filter and transform are not supported for the streamed body")
+	}
+	payload, ok := data["data"].([]byte)
+	if !ok {
+		return nil, errors.New("input data does not exist valid value \"data\"")
+	}
+	// use zero value if not found
+	messageID, _ := data["messageID"].(string)
+	nodeName, _ := data["nodeName"].(string)
+	topic, _ := data["topic"].(string)
+
+	if t.filter != nil {
+		matched, err := t.filter.Match(&utils.Message{
+			Topic:    topic,
+			NodeName: nodeName,
+			Headers:  flattenHeader(data["header"]),
+			Payload:  payload,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			klog.V(4).Infof("message %s is dropped by the filter of rule %s", messageID, t.ruleKey)
+			return nil, nil
+		}
+	}
+
+	if t.transformer != nil {
+		param, _ := data["param"].(string)
+		transformed, err := t.transformer.Transform(payload, utils.TemplateData{
+			MessageID: messageID,
+			NodeName:  nodeName,
+			Namespace: t.namespace,
+			Param:     param,
+			Topic:     topic,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// the data may be used by the source after target returns, so it is not modified
+		copied := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			copied[k] = v
+		}
+		copied["data"] = transformed
+		data = copied
+	}
+	return t.Target.GoToTarget(data, stop)
+}
+
+// flattenHeader returns the first value of every header
+func flattenHeader(v interface{}) map[string]string {
+	var header map[string][]string
+	switch h := v.(type) {
+	case http.Header:
+		header = h
+	case map[string][]string:
+		header = h
+	default:
+		return nil
+	}
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+	return headers
+}
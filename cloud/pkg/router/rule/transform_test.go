@@ -0,0 +1,89 @@
+package rule
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	routerv1 "github.com/kubeedge/api/apis/rules/v1"
+)
+
+type recordTarget struct {
+	data []map[string]interface{}
+}
+
+func (*recordTarget) Name() string { return "record" }
+
+func (r *recordTarget) GoToTarget(data map[string]interface{}, _ chan struct{}) (interface{}, error) {
+	r.data = append(r.data, data)
+	return nil, nil
+}
+
+func newRule(filter string, transform *routerv1.RuleTransform) *routerv1.Rule {
+	return &routerv1.Rule{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "default"},
+		Spec:       routerv1.RuleSpec{Filter: filter, Transform: transform},
+	}
+}
+
+func TestNewRuleTarget(t *testing.T) {
+	target := &recordTarget{}
+	wrapped, err := newRuleTarget(newRule("", nil), target)
+	require.NoError(t, err)
+	assert.Same(t, target, wrapped)
+
+	_, err = newRuleTarget(newRule("payload >", nil), target)
+	assert.Error(t, err)
+	_, err = newRuleTarget(newRule("", &routerv1.RuleTransform{Extract: "a["}), target)
+	assert.Error(t, err)
+}
+
+func TestRuleTargetGoToTarget(t *testing.T) {
+	target := &recordTarget{}
+	wrapped, err := newRuleTarget(newRule(`payload.temperature > 30 && headers["Unit"] == "C"`,
+		&routerv1.RuleTransform{
+			Extract:  "$.temperature",
+			Envelope: &routerv1.RuleEnvelope{Fields: map[string]string{"node": "{{.NodeName}}", "topic": "{{.Topic}}"}},
+		}), target)
+	require.NoError(t, err)
+	assert.Equal(t, "record", wrapped.Name())
+
+	header := http.Header{}
+	header.Set("Unit", "C")
+	data := map[string]interface{}{
+		"messageID": "id-1",
+		"nodeName":  "node-1",
+		"topic":     "sensors",
+		"header":    header,
+		"data":      []byte(`{"temperature": 35}`),
+	}
+	_, err = wrapped.GoToTarget(data, nil)
+	require.NoError(t, err)
+	require.Len(t, target.data, 1)
+	assert.JSONEq(t, `{"data":35,"node":"node-1","topic":"sensors"}`, string(target.data[0]["data"].([]byte)))
+	assert.Equal(t, "id-1", target.data[0]["messageID"])
+	// the input data is not modified
+	assert.Equal(t, []byte(`{"temperature": 35}`), data["data"])
+
+	// dropped by filter
+	_, err = wrapped.GoToTarget(map[string]interface{}{"header": header, "data": []byte(`{"temperature": 20}`)}, nil)
+	require.NoError(t, err)
+	assert.Len(t, target.data, 1)
+
+	// the filter fails to evaluate without the header
+	_, err = wrapped.GoToTarget(map[string]interface{}{"data": []byte(`{"temperature": 35}`)}, nil)
+	assert.Error(t, err)
+	assert.Len(t, target.data, 1)
+
+	_, err = wrapped.GoToTarget(map[string]interface{}{}, nil)
+	assert.Error(t, err)
+
+	// the streamed body can not be filtered or transformed
+	_, err = wrapped.GoToTarget(map[string]interface{}{"header": header, "body": strings.NewReader(`{"temperature": 35}`)}, nil)
+	assert.Error(t, err)
+	assert.Len(t, target.data, 1)
+}
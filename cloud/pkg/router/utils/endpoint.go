@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// The properties of RuleEndpoint shared by the streaming providers.
+const (
+	// PropertyTLS enables TLS when its value is "true"
+	PropertyTLS = "tls"
+	// PropertyTLSCA is the PEM encoded CA certificates to verify the servers
+	PropertyTLSCA = "tls_ca"
+	// PropertyTLSInsecureSkipVerify disables the verification of server certificates
+	PropertyTLSInsecureSkipVerify = "tls_insecure_skip_verify"
+	// PropertyCredentialsSecret is the name of the secret in the namespace of RuleEndpoint
+	// which holds the credentials to connect the servers
+	PropertyCredentialsSecret = "credentials_secret"
+
+	// HeaderPrefix is the prefix of target resource keys which define the message headers
+	HeaderPrefix = "header."
+)
+
+// The keys of the credentials secret referenced by PropertyCredentialsSecret
+const (
+	CredentialsUsername = "username"
+	CredentialsPassword = "password"
+	CredentialsToken    = "token"
+)
+
+// TemplateData is the data which the templates of target resource are rendered with
+type TemplateData struct {
+	// MessageID is the id of the forwarded message
+	MessageID string
+	// NodeName is the edge node where the message comes from
+	NodeName string
+	// Namespace is the namespace of the rule
+	Namespace string
+	// Param is the rest of the path or topic after the one defined in source resource
+	Param string
+	// Topic is the topic, subject or path where the message comes from
+	Topic string
+}
+
+// TLSConfigFromProperties builds the TLS config from the properties of RuleEndpoint,
+// it returns nil if TLS is not enabled.
+func TLSConfigFromProperties(properties map[string]string) (*tls.Config, error) {
+	enabled, err := boolProperty(properties, PropertyTLS)
+	if err != nil {
+		return nil, err
+	}
+	ca := properties[PropertyTLSCA]
+	if !enabled && ca == "" {
+		return nil, nil
+	}
+	insecure, err := boolProperty(properties, PropertyTLSInsecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// #nosec G402 -- skipping verification is opted in explicitly by user
+		InsecureSkipVerify: insecure,
+	}
+	if ca != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca)) {
+			return nil, fmt.Errorf("property %q does not contain valid PEM certificates", PropertyTLSCA)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// Credentials are the credentials in the secret referenced by RuleEndpoint
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+	// ResourceVersion is the version of the secret, the clients are recreated when it is changed
+	ResourceVersion string
+}
+
+// GetCredentials reads the credentials from the secret referenced by the properties of RuleEndpoint,
+// it returns empty credentials if no secret is referenced.
+func GetCredentials(kubeClient kubernetes.Interface, namespace string, properties map[string]string) (Credentials, error) {
+	name := properties[PropertyCredentialsSecret]
+	if name == "" {
+		return Credentials{}, nil
+	}
+	if kubeClient == nil {
+		return Credentials{}, errors.New("kube client is not initialized")
+	}
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to get credentials secret %s/%s: %v", namespace, name, err)
+	}
+	return Credentials{
+		Username:        string(secret.Data[CredentialsUsername]),
+		Password:        string(secret.Data[CredentialsPassword]),
+		Token:           string(secret.Data[CredentialsToken]),
+		ResourceVersion: secret.ResourceVersion,
+	}, nil
+}
+
+func boolProperty(properties map[string]string, key string) (bool, error) {
+	value, ok := properties[key]
+	if !ok || value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("property %q should be a boolean: %v", key, err)
+	}
+	return b, nil
+}
+
+// ParseTemplate parses the value of target resource as a text template,
+// the value without actions is returned as is when it is rendered.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+// RenderTemplate renders the template with data, nil template renders empty string
+func RenderTemplate(tmpl *template.Template, data TemplateData) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ParseHeaderTemplates parses the target resource keys with HeaderPrefix as header templates
+func ParseHeaderTemplates(targetResource map[string]string) (map[string]*template.Template, error) {
+	headers := make(map[string]*template.Template)
+	for key, value := range targetResource {
+		if !strings.HasPrefix(key, HeaderPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, HeaderPrefix)
+		if name == "" {
+			return nil, fmt.Errorf("header name of %q can not be empty", key)
+		}
+		tmpl, err := ParseTemplate(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template of %q: %v", key, err)
+		}
+		headers[name] = tmpl
+	}
+	return headers, nil
+}
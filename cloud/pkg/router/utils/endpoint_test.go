@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTLSConfigFromProperties(t *testing.T) {
+	config, err := TLSConfigFromProperties(map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = TLSConfigFromProperties(map[string]string{PropertyTLS: "true", PropertyTLSInsecureSkipVerify: "true"})
+	require.NoError(t, err)
+	assert.True(t, config.InsecureSkipVerify)
+
+	_, err = TLSConfigFromProperties(map[string]string{PropertyTLS: "yes"})
+	assert.Error(t, err)
+	_, err = TLSConfigFromProperties(map[string]string{PropertyTLSCA: "not a certificate"})
+	assert.Error(t, err)
+}
+
+func TestTemplates(t *testing.T) {
+	data := TemplateData{MessageID: "id", NodeName: "node-1", Namespace: "default", Param: "a/b"}
+
+	tmpl, err := ParseTemplate("topic", "{{.Namespace}}.{{.NodeName}}")
+	require.NoError(t, err)
+	out, err := RenderTemplate(tmpl, data)
+	require.NoError(t, err)
+	assert.Equal(t, "default.node-1", out)
+
+	out, err = RenderTemplate(nil, data)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	_, err = ParseTemplate("topic", "{{.NodeName")
+	assert.Error(t, err)
+
+	headers, err := ParseHeaderTemplates(map[string]string{"topic": "t", "header.node": "{{.NodeName}}"})
+	require.NoError(t, err)
+	require.Len(t, headers, 1)
+	out, err = RenderTemplate(headers["node"], data)
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", out)
+
+	_, err = ParseHeaderTemplates(map[string]string{"header.": "x"})
+	assert.Error(t, err)
+}
+
+func TestGetCredentials(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-credentials", Namespace: "default", ResourceVersion: "1"},
+		Data:       map[string][]byte{CredentialsUsername: []byte("user"), CredentialsPassword: []byte("secret")},
+	})
+
+	creds, err := GetCredentials(kubeClient, "default", map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{}, creds)
+
+	creds, err = GetCredentials(kubeClient, "default", map[string]string{PropertyCredentialsSecret: "kafka-credentials"})
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "user", Password: "secret", ResourceVersion: "1"}, creds)
+
+	_, err = GetCredentials(kubeClient, "other", map[string]string{PropertyCredentialsSecret: "kafka-credentials"})
+	assert.Error(t, err)
+	_, err = GetCredentials(nil, "default", map[string]string{PropertyCredentialsSecret: "kafka-credentials"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// The properties of servicebus RuleEndpoint.
+const (
+	// PropertyScheme is the scheme of the edge services, http or https
+	PropertyScheme = "scheme"
+	// PropertyAllowedTargets is the comma separated edge services which the rules can reach,
+	// in the format of <name>.<namespace> or <name>.<namespace>:<port>
+	PropertyAllowedTargets = "allowed_targets"
+	// PropertyMaxBodySize is the max size of the request and response body streamed between
+	// cloud and edge, in bytes or quantity like 512Mi
+	PropertyMaxBodySize = "max_body_size"
+)
+
+// DefaultMaxBodySize is the default value of PropertyMaxBodySize
+const DefaultMaxBodySize int64 = 1 << 30
+
+// ServiceTarget is an edge service which servicebus sends requests to
+type ServiceTarget struct {
+	Name      string
+	Namespace string
+	// Port is empty if any port of the service is allowed
+	Port string
+}
+
+func (t ServiceTarget) String() string {
+	s := t.Name + "." + t.Namespace
+	if t.Port != "" {
+		s += ":" + t.Port
+	}
+	return s
+}
+
+// ParseServiceTarget parses the edge service in the format of <name>.<namespace>[:<port>]
+func ParseServiceTarget(s string) (ServiceTarget, error) {
+	target := ServiceTarget{}
+	service, port, hasPort := strings.Cut(strings.TrimSpace(s), ":")
+	if hasPort {
+		if err := ValidatePort(port); err != nil {
+			return target, fmt.Errorf("invalid service %q: %v", s, err)
+		}
+		target.Port = port
+	}
+	name, namespace, ok := strings.Cut(service, ".")
+	if !ok {
+		return target, fmt.Errorf("invalid service %q, it should be <name>.<namespace>", s)
+	}
+	if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+		return target, fmt.Errorf("invalid service name %q: %s", name, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return target, fmt.Errorf("invalid service namespace %q: %s", namespace, strings.Join(errs, ", "))
+	}
+	target.Name, target.Namespace = name, namespace
+	return target, nil
+}
+
+// ParseAllowedTargets parses the value of PropertyAllowedTargets
+func ParseAllowedTargets(value string) ([]ServiceTarget, error) {
+	var targets []ServiceTarget
+	for _, s := range strings.Split(value, ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		target, err := ParseServiceTarget(s)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// IsServiceAllowed reports whether the port of edge service is in the allowed targets
+func IsServiceAllowed(allowed []ServiceTarget, service ServiceTarget) bool {
+	for _, a := range allowed {
+		if a.Name == service.Name && a.Namespace == service.Namespace &&
+			(a.Port == "" || a.Port == service.Port) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePort checks the port is an integer in range 1-65535
+func ValidatePort(port string) error {
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("port should be integer")
+	}
+	if p < 1 || p > 65535 {
+		return fmt.Errorf("port must be in range 1-65535")
+	}
+	return nil
+}
+
+// ParseMaxBodySize parses the value of PropertyMaxBodySize, DefaultMaxBodySize is returned if it is empty
+func ParseMaxBodySize(value string) (int64, error) {
+	if value == "" {
+		return DefaultMaxBodySize, nil
+	}
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max body size %q: %v", value, err)
+	}
+	size, ok := q.AsInt64()
+	if !ok || size <= 0 {
+		return 0, fmt.Errorf("max body size %q should be a positive integer", value)
+	}
+	return size, nil
+}
+
+// ServiceAccount is the service account of edge pods which can call cloud services by servicebus
+type ServiceAccount struct {
+	Namespace string
+	// Name is * if all service accounts in Namespace are allowed
+	Name string
+}
+
+// ParseServiceAccounts parses the comma separated service accounts in the format of <namespace>/<name>,
+// and <namespace>/* for all service accounts in namespace
+func ParseServiceAccounts(value string) ([]ServiceAccount, error) {
+	var accounts []ServiceAccount
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		namespace, name, ok := strings.Cut(s, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid service account %q, it should be <namespace>/<name>", s)
+		}
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid service account namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+		if name != "*" {
+			if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid service account name %q: %s", name, strings.Join(errs, ", "))
+			}
+		}
+		accounts = append(accounts, ServiceAccount{Namespace: namespace, Name: name})
+	}
+	return accounts, nil
+}
+
+// IsServiceAccountAllowed reports whether the service account is in the allowed service accounts
+func IsServiceAccountAllowed(allowed []ServiceAccount, namespace, name string) bool {
+	for _, a := range allowed {
+		if a.Namespace == namespace && (a.Name == "*" || a.Name == name) {
+			return true
+		}
+	}
+	return false
+}
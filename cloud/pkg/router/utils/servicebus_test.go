@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServiceTarget(t *testing.T) {
+	target, err := ParseServiceTarget("web.default:8080")
+	require.NoError(t, err)
+	assert.Equal(t, ServiceTarget{Name: "web", Namespace: "default", Port: "8080"}, target)
+	assert.Equal(t, "web.default:8080", target.String())
+
+	target, err = ParseServiceTarget(" web.default ")
+	require.NoError(t, err)
+	assert.Equal(t, ServiceTarget{Name: "web", Namespace: "default"}, target)
+
+	for _, invalid := range []string{"web", "web.default:0", "web.default:http", "Web.default", "web.default.svc", ".default"} {
+		_, err := ParseServiceTarget(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestIsServiceAllowed(t *testing.T) {
+	allowed, err := ParseAllowedTargets("web.default, api.prod:443,")
+	require.NoError(t, err)
+	require.Len(t, allowed, 2)
+
+	assert.True(t, IsServiceAllowed(allowed, ServiceTarget{Name: "web", Namespace: "default", Port: "80"}))
+	assert.True(t, IsServiceAllowed(allowed, ServiceTarget{Name: "api", Namespace: "prod", Port: "443"}))
+	assert.False(t, IsServiceAllowed(allowed, ServiceTarget{Name: "api", Namespace: "prod", Port: "80"}))
+	assert.False(t, IsServiceAllowed(allowed, ServiceTarget{Name: "web", Namespace: "prod", Port: "80"}))
+	assert.False(t, IsServiceAllowed(nil, ServiceTarget{Name: "web", Namespace: "default", Port: "80"}))
+
+	_, err = ParseAllowedTargets("web.default,invalid")
+	assert.Error(t, err)
+}
+
+func TestParseMaxBodySize(t *testing.T) {
+	size, err := ParseMaxBodySize("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMaxBodySize, size)
+
+	size, err = ParseMaxBodySize("512Mi")
+	require.NoError(t, err)
+	assert.Equal(t, int64(512<<20), size)
+
+	size, err = ParseMaxBodySize("1048576")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<20), size)
+
+	for _, invalid := range []string{"0", "-1", "1.5", "large"} {
+		_, err := ParseMaxBodySize(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestIsServiceAccountAllowed(t *testing.T) {
+	allowed, err := ParseServiceAccounts("default/app, monitoring/*,")
+	require.NoError(t, err)
+	require.Len(t, allowed, 2)
+
+	assert.True(t, IsServiceAccountAllowed(allowed, "default", "app"))
+	assert.True(t, IsServiceAccountAllowed(allowed, "monitoring", "agent"))
+	assert.False(t, IsServiceAccountAllowed(allowed, "default", "other"))
+	assert.False(t, IsServiceAccountAllowed(nil, "default", "app"))
+
+	for _, invalid := range []string{"app", "default/", "Default/app", "default/App"} {
+		_, err := ParseServiceAccounts(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
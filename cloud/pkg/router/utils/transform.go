@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/google/cel-go/cel"
+
+	rulesv1 "github.com/kubeedge/api/apis/rules/v1"
+)
+
+const (
+	defaultEnvelopePayloadField = "data"
+	// filterCostLimit limits the cost of evaluating a filter expression for one message
+	filterCostLimit = 100000
+)
+
+// Message is the view of a forwarded message used by filters and transforms
+type Message struct {
+	Topic    string
+	NodeName string
+	Headers  map[string]string
+	Payload  []byte
+}
+
+// MessageFilter is a compiled CEL filter expression of rule
+type MessageFilter struct {
+	program cel.Program
+}
+
+var filterEnv *cel.Env
+
+func init() {
+	var err error
+	filterEnv, err = cel.NewEnv(
+		cel.Variable("topic", cel.StringType),
+		cel.Variable("nodeName", cel.StringType),
+		cel.Variable("headers", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("payload", cel.DynType),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create CEL environment of rule filter: %v", err))
+	}
+}
+
+// NewMessageFilter compiles the CEL expression, which must evaluate to a bool
+func NewMessageFilter(expression string) (*MessageFilter, error) {
+	ast, issues := filterEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid filter expression: %v", issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("filter expression should return bool, got %v", ast.OutputType())
+	}
+	program, err := filterEnv.Program(ast, cel.CostLimit(filterCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %v", err)
+	}
+	return &MessageFilter{program: program}, nil
+}
+
+// Match reports whether the message passes the filter
+func (f *MessageFilter) Match(msg *Message) (bool, error) {
+	headers := msg.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	out, _, err := f.program.Eval(map[string]interface{}{
+		"topic":    msg.Topic,
+		"nodeName": msg.NodeName,
+		"headers":  headers,
+		"payload":  decodePayload(msg.Payload),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate filter: %v", err)
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("filter returns %T instead of bool", out.Value())
+	}
+	return matched, nil
+}
+
+// decodePayload returns the parsed JSON value of payload, or the raw string if it is not JSON
+func decodePayload(payload []byte) interface{} {
+	var v interface{}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return string(payload)
+	}
+	return v
+}
+
+// Transformer reshapes the JSON payload of messages as defined by RuleTransform
+type Transformer struct {
+	extract      []pathElement
+	mapping      map[string][]pathElement
+	envelope     bool
+	payloadField string
+	fields       map[string]*template.Template
+}
+
+// NewTransformer validates and compiles the transform of rule
+func NewTransformer(transform *rulesv1.RuleTransform) (*Transformer, error) {
+	t := &Transformer{}
+	var err error
+	if transform.Extract != "" {
+		if t.extract, err = parseJSONPath(transform.Extract); err != nil {
+			return nil, fmt.Errorf("invalid extract path %q: %v", transform.Extract, err)
+		}
+	}
+	if len(transform.Mapping) > 0 {
+		t.mapping = make(map[string][]pathElement, len(transform.Mapping))
+		for field, path := range transform.Mapping {
+			if field == "" {
+				return nil, errors.New("field name of mapping can not be empty")
+			}
+			if t.mapping[field], err = parseJSONPath(path); err != nil {
+				return nil, fmt.Errorf("invalid mapping path %q of field %s: %v", path, field, err)
+			}
+		}
+	}
+	if envelope := transform.Envelope; envelope != nil {
+		t.envelope = true
+		t.payloadField = envelope.PayloadField
+		if t.payloadField == "" {
+			t.payloadField = defaultEnvelopePayloadField
+		}
+		t.fields = make(map[string]*template.Template, len(envelope.Fields))
+		for field, value := range envelope.Fields {
+			if field == t.payloadField {
+				return nil, fmt.Errorf("envelope field %s conflicts with payload field", field)
+			}
+			if t.fields[field], err = ParseTemplate(field, value); err != nil {
+				return nil, fmt.Errorf("invalid template of envelope field %s: %v", field, err)
+			}
+		}
+	}
+	return t, nil
+}
+
+// Transform returns the reshaped payload. The payload must be JSON if extract or
+// mapping is defined, otherwise it is wrapped into envelope as a string.
+func (t *Transformer) Transform(payload []byte, data TemplateData) ([]byte, error) {
+	var value interface{}
+	if t.extract != nil || t.mapping != nil {
+		if err := json.Unmarshal(payload, &value); err != nil {
+			return nil, fmt.Errorf("payload is not JSON: %v", err)
+		}
+	} else {
+		value = decodePayload(payload)
+	}
+
+	var err error
+	if t.extract != nil {
+		if value, err = lookupJSONPath(value, t.extract); err != nil {
+			return nil, fmt.Errorf("failed to extract payload: %v", err)
+		}
+	}
+	if t.mapping != nil {
+		mapped := make(map[string]interface{}, len(t.mapping))
+		for field, path := range t.mapping {
+			// the missing fields are omitted
+			if v, err := lookupJSONPath(value, path); err == nil {
+				mapped[field] = v
+			}
+		}
+		value = mapped
+	}
+	if t.envelope {
+		envelope := make(map[string]interface{}, len(t.fields)+1)
+		for field, tmpl := range t.fields {
+			if envelope[field], err = RenderTemplate(tmpl, data); err != nil {
+				return nil, fmt.Errorf("failed to render envelope field %s: %v", field, err)
+			}
+		}
+		envelope[t.payloadField] = value
+		value = envelope
+	}
+	return json.Marshal(value)
+}
+
+// pathElement is a key of object or an index of array in JSON path
+type pathElement struct {
+	key   string
+	index int
+	isKey bool
+}
+
+// parseJSONPath parses the JSON path in dot notation, like $.a.b[0].c or a.b[0].c
+func parseJSONPath(path string) ([]pathElement, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	elements := []pathElement{}
+	if path == "" {
+		return elements, nil
+	}
+	for _, part := range strings.Split(path, ".") {
+		key, rest, hasIndex := strings.Cut(part, "[")
+		if key == "" && !hasIndex {
+			return nil, errors.New("empty key")
+		}
+		if hasIndex && rest == "" {
+			return nil, errors.New("missing ]")
+		}
+		if key != "" {
+			elements = append(elements, pathElement{key: key, isKey: true})
+		}
+		for rest != "" {
+			indexStr, after, found := strings.Cut(rest, "]")
+			if !found {
+				return nil, errors.New("missing ]")
+			}
+			index, err := strconv.Atoi(indexStr)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q", indexStr)
+			}
+			elements = append(elements, pathElement{index: index})
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("unexpected %q after index", after)
+			}
+			rest = after[1:]
+		}
+	}
+	return elements, nil
+}
+
+func lookupJSONPath(value interface{}, path []pathElement) (interface{}, error) {
+	for _, e := range path {
+		if e.isKey {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("value of %q is not an object", e.key)
+			}
+			if value, ok = obj[e.key]; !ok {
+				return nil, fmt.Errorf("key %q does not exist", e.key)
+			}
+			continue
+		}
+		arr, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("value of index %d is not an array", e.index)
+		}
+		if e.index >= len(arr) {
+			return nil, fmt.Errorf("index %d out of range", e.index)
+		}
+		value = arr[e.index]
+	}
+	return value, nil
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rulesv1 "github.com/kubeedge/api/apis/rules/v1"
+)
+
+func TestMessageFilter(t *testing.T) {
+	msg := &Message{
+		Topic:    "sensors/temperature",
+		NodeName: "node-1",
+		Headers:  map[string]string{"Content-Type": "application/json"},
+		Payload:  []byte(`{"temperature": 32.5, "tags": ["a", "b"]}`),
+	}
+	cases := []struct {
+		expression string
+		matched    bool
+	}{
+		{`payload.temperature > 30`, true},
+		{`payload.temperature > 40`, false},
+		{`topic.startsWith("sensors/") && nodeName == "node-1"`, true},
+		{`headers["Content-Type"] == "application/json"`, true},
+		{`"b" in payload.tags`, true},
+	}
+	for _, tc := range cases {
+		filter, err := NewMessageFilter(tc.expression)
+		require.NoError(t, err, tc.expression)
+		matched, err := filter.Match(msg)
+		require.NoError(t, err, tc.expression)
+		assert.Equal(t, tc.matched, matched, tc.expression)
+	}
+
+	// the payload which is not JSON is a string
+	filter, err := NewMessageFilter(`payload == "on"`)
+	require.NoError(t, err)
+	matched, err := filter.Match(&Message{Payload: []byte("on")})
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	// missing fields are errors at runtime
+	filter, err = NewMessageFilter(`payload.humidity > 1`)
+	require.NoError(t, err)
+	_, err = filter.Match(msg)
+	assert.Error(t, err)
+
+	_, err = NewMessageFilter(`payload.temperature >`)
+	assert.Error(t, err)
+	_, err = NewMessageFilter(`topic + "x"`)
+	assert.Error(t, err)
+	_, err = NewMessageFilter(`unknown == 1`)
+	assert.Error(t, err)
+}
+
+func TestTransformer(t *testing.T) {
+	payload := []byte(`{"device": {"name": "thermometer"}, "data": {"items": [{"temperature": 25, "unit": "C"}]}}`)
+	data := TemplateData{MessageID: "id-1", NodeName: "node-1", Namespace: "default", Topic: "sensors"}
+
+	cases := []struct {
+		name      string
+		transform *rulesv1.RuleTransform
+		payload   []byte
+		expect    string
+	}{
+		{
+			name:      "extract",
+			transform: &rulesv1.RuleTransform{Extract: "$.data.items[0]"},
+			payload:   payload,
+			expect:    `{"temperature":25,"unit":"C"}`,
+		},
+		{
+			name: "extract and mapping",
+			transform: &rulesv1.RuleTransform{
+				Extract: "data.items[0]",
+				Mapping: map[string]string{"temp": "$.temperature", "missing": "$.humidity"},
+			},
+			payload: payload,
+			expect:  `{"temp":25}`,
+		},
+		{
+			name: "mapping and envelope",
+			transform: &rulesv1.RuleTransform{
+				Mapping: map[string]string{"device": "device.name", "temp": "data.items[0].temperature"},
+				Envelope: &rulesv1.RuleEnvelope{
+					PayloadField: "reading",
+					Fields:       map[string]string{"node": "{{.NodeName}}", "id": "{{.MessageID}}"},
+				},
+			},
+			payload: payload,
+			expect:  `{"id":"id-1","node":"node-1","reading":{"device":"thermometer","temp":25}}`,
+		},
+		{
+			name:      "envelope of non JSON payload",
+			transform: &rulesv1.RuleTransform{Envelope: &rulesv1.RuleEnvelope{}},
+			payload:   []byte("on"),
+			expect:    `{"data":"on"}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			transformer, err := NewTransformer(tc.transform)
+			require.NoError(t, err)
+			out, err := transformer.Transform(tc.payload, data)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expect, string(out))
+		})
+	}
+
+	transformer, err := NewTransformer(&rulesv1.RuleTransform{Extract: "$.data.items[3]"})
+	require.NoError(t, err)
+	_, err = transformer.Transform(payload, data)
+	assert.Error(t, err)
+	_, err = transformer.Transform([]byte("not json"), data)
+	assert.Error(t, err)
+
+	invalid := []*rulesv1.RuleTransform{
+		{Extract: "$.a[x]"},
+		{Extract: "a..b"},
+		{Extract: "a["},
+		{Mapping: map[string]string{"": "a"}},
+		{Envelope: &rulesv1.RuleEnvelope{Fields: map[string]string{"data": "x"}}},
+		{Envelope: &rulesv1.RuleEnvelope{Fields: map[string]string{"node": "{{.NodeName"}}},
+	}
+	for _, transform := range invalid {
+		_, err := NewTransformer(transform)
+		assert.Error(t, err, "%+v", transform)
+	}
+}
@@ -249,6 +249,11 @@ func BuildObjectSyncName(nodeName, UID string) string {
 	return nodeName + "." + UID
 }
 
+// NodeNameOfObjectSync returns the name of the node which objectSync/clusterObjectSync belongs to
+func NodeNameOfObjectSync(syncName string) string {
+	return getNodeName(syncName)
+}
+
 func getNodeName(syncName string) string {
 	tmps := strings.Split(syncName, ".")
 	return strings.Join(tmps[:len(tmps)-1], ".")
@@ -56,6 +56,16 @@ const (
 	ResourceTypePersistentVolumeClaim = "persistentvolumeclaim"
 	ResourceTypeVolumeAttachment      = "volumeattachment"
 
+	// ResourceTypeObjectResync is the resource of the request from edge to resend all the
+	// objects of the node, it is sent after the edge database is rebuilt
+	ResourceTypeObjectResync = "objectresync"
+	// ResourceTypeObjectDigest is the resource of the request from edge to compare the digest
+	// of its objects with the cloud on reconnect, only the missing or changed ones are resent
+	ResourceTypeObjectDigest = "objectdigest"
+	// ResourceTypeBandwidthUsage is the resource of the report from edge with the bytes sent
+	// to the cloud since the last report, which are counted into the bandwidth budget of the node
+	ResourceTypeBandwidthUsage = "bandwidthusage"
+
 	CSIResourceTypeVolume                     = "volume"
 	CSIOperationTypeCreateVolume              = "createvolume"
 	CSIOperationTypeDeleteVolume              = "deletevolume"
@@ -8,6 +8,22 @@ type HTTPRequest struct {
 	Body   []byte      `json:"body"`
 	Method string      `json:"method"`
 	URL    string      `json:"url"`
+	// Service is the edge service <name>.<namespace> which servicebus sends the request to,
+	// the request is sent to 127.0.0.1 if it is empty
+	Service string `json:"service,omitempty"`
+	// Scheme is the scheme of the edge service, http or https, default is http
+	Scheme string `json:"scheme,omitempty"`
+	// CA is the PEM encoded CA certificates to verify the https edge service
+	CA []byte `json:"ca,omitempty"`
+	// InsecureSkipVerify disables the verification of https edge service
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// Stream indicates the body is not carried by the request, it follows the request
+	// in HTTPStreamChunk messages
+	Stream bool `json:"stream,omitempty"`
+	// StreamResponse indicates the sender accepts the response body streamed in HTTPStreamChunk messages
+	StreamResponse bool `json:"streamResponse,omitempty"`
+	// MaxBodySize is the max size of streamed request and response body, no limit if it is 0
+	MaxBodySize int64 `json:"maxBodySize,omitempty"`
 }
 
 // HTTPResponse is HTTP request's response structure used to send response to cloud
@@ -15,8 +31,29 @@ type HTTPResponse struct {
 	Header     http.Header `json:"header"`
 	StatusCode int         `json:"status_code"`
 	Body       []byte      `json:"body"`
+	// Stream indicates the body is not carried by the response, it follows the response
+	// in HTTPStreamChunk messages
+	Stream bool `json:"stream,omitempty"`
 }
 
+// HTTPStreamChunk is a part of streamed http body, the chunks of a body are sent in order
+// of Seq and the last one is marked with EOF. A chunk with Error aborts the stream, it is
+// also sent by the receiver to cancel the stream.
+type HTTPStreamChunk struct {
+	// ID is the ID of request message which the stream belongs to
+	ID    string `json:"id"`
+	Seq   int64  `json:"seq"`
+	Data  []byte `json:"data,omitempty"`
+	EOF   bool   `json:"eof,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Consumed is set in the window update sent by the receiver, it is the size of data
+	// read from the stream. The sender does not send the data beyond the window after it.
+	Consumed int64 `json:"consumed,omitempty"`
+}
+
+// ResourceStreamChunk is the resource of messages carrying HTTPStreamChunk
+const ResourceStreamChunk = "stream_chunk"
+
 const (
 	HeaderAuthorization = "Authorization"
 	HeaderNodeName      = "NodeName"
@@ -129,3 +129,34 @@ type ExecResponse struct {
 	RunOutMessages []string `json:"runOutMessages,omitempty"`
 	RunErrMessages []string `json:"runErrMessages,omitempty"`
 }
+
+// ObjectDigest is Message.Content of the object digest request which comes from edge
+type ObjectDigest struct {
+	// Digests are the digests of the objects stored in edge, keyed by GroupVersionResource
+	Digests map[string]ResourceDigest `json:"digests,omitempty"`
+	// Versions are the resource versions of the objects stored in edge, keyed by
+	// GroupVersionResource and then the key of object. They are only sent for the
+	// resources whose digests mismatch with the cloud.
+	Versions map[string]map[string]string `json:"versions,omitempty"`
+}
+
+// ResourceDigest is the digest of the objects of one resource
+type ResourceDigest struct {
+	Count int    `json:"count"`
+	Hash  string `json:"hash"`
+}
+
+// ObjectDigestResponse is the response of the object digest request
+type ObjectDigestResponse struct {
+	// Mismatched are the resources whose digests mismatch, edge sends their versions then
+	Mismatched []string `json:"mismatched,omitempty"`
+	// Resynced is the number of objects whose versions sent to edge are lowered to the
+	// versions in edge, so that they are resent
+	Resynced int `json:"resynced"`
+}
+
+// BandwidthUsage is Message.Content of the bandwidth usage report which comes from edge
+type BandwidthUsage struct {
+	// UpstreamBytes is the bytes sent to the cloud since the last report
+	UpstreamBytes int64 `json:"upstreamBytes"`
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/eventbus"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/encryption"
 	"github.com/kubeedge/kubeedge/edge/pkg/servicebus"
 	"github.com/kubeedge/kubeedge/edge/pkg/taskmanager"
 	"github.com/kubeedge/kubeedge/edge/test"
@@ -201,12 +202,15 @@ func environmentCheck(skipCheck bool) error {
 // registerModules register all the modules started in edgecore
 func registerModules(c *v1alpha2.EdgeCoreConfig) {
 	dao.Init(
-		c.DataBase.DataSource,
+		c.DataBase,
 		c.Modules.DeviceTwin,
 		c.Modules.EventBus,
 		c.Modules.MetaManager,
 		c.Modules.ServiceBus,
 	)
+	if err := encryption.Init(dao.GetStorage(), c.DataBase.Encryption); err != nil {
+		klog.Exitf("failed to init encryption at rest of database: %v", err)
+	}
 	// register all modules
 	devicetwin.Register(c.Modules.DeviceTwin, c.Modules.Edged.HostnameOverride)
 	edged.Register(c.Modules.Edged)
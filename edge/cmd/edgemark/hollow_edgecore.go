@@ -121,7 +121,7 @@ func run(config *hollowEdgeNodeConfig) {
 	metamanager.Register(c.Modules.MetaManager)
 
 	dao.Init(
-		c.DataBase.DataSource,
+		c.DataBase,
 		c.Modules.MetaManager,
 	)
 	// start all modules
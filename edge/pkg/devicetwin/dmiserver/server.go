@@ -175,6 +175,26 @@ func (s *server) ReportDeviceStates(_ctx context.Context, in *pb.ReportDeviceSta
 	return &pb.ReportDeviceStatesResponse{}, nil
 }
 
+func (s *server) ReportDiscoveredDevices(_ctx context.Context, in *pb.ReportDiscoveredDevicesRequest,
+) (*pb.ReportDiscoveredDevicesResponse, error) {
+	if in == nil || in.MapperName == "" || in.Protocol == "" || len(in.Devices) == 0 {
+		return &pb.ReportDiscoveredDevicesResponse{}, fmt.Errorf("ReportDiscoveredDevicesRequest is invalid data")
+	}
+
+	if !s.limiter.Allow() {
+		return nil, fmt.Errorf("fail to report discovered devices because of too many request: %s", in.MapperName)
+	}
+
+	msg, err := CreateMessageDiscoveredDevices(in)
+	if err != nil {
+		klog.Errorf("fail to create discovered devices message data of mapper %s with err: %v", in.MapperName, err)
+		return nil, err
+	}
+	handleDiscoveredDevices(msg)
+
+	return &pb.ReportDiscoveredDevicesResponse{}, nil
+}
+
 func handleDeviceTwin(in *pb.ReportDeviceStatusRequest, payload []byte) {
 	deviceID := util.GetResourceID(in.DeviceNamespace, in.DeviceName)
 	topic := dtcommon.DeviceETPrefix + deviceID + dtcommon.TwinETUpdateSuffix
@@ -199,6 +219,15 @@ func handleDeviceState(in *pb.ReportDeviceStatesRequest, payload []byte) {
 	beehiveContext.SendToGroup(target, *message)
 }
 
+func handleDiscoveredDevices(payload []byte) {
+	// discovered devices are sent to devicecontroller in cloud directly,
+	// they are not managed by devicetwin until they are provisioned as devices.
+	message := beehiveModel.NewMessage("").BuildRouter(modules.TwinGroup, deviceconst.GroupResource,
+		deviceconst.ResourceTypeDiscoveredDevice, beehiveModel.UpdateOperation).FillBody(string(payload))
+
+	beehiveContext.SendToGroup(modules.HubGroup, *message)
+}
+
 // CreateMessageTwinUpdate create twin update message.
 func CreateMessageTwinUpdate(twin *pb.Twin) ([]byte, error) {
 	var updateMsg DeviceTwinUpdate
@@ -222,6 +251,32 @@ func CreateMessageStateUpdate(in *pb.ReportDeviceStatesRequest) ([]byte, error)
 	return msg, err
 }
 
+// CreateMessageDiscoveredDevices create discovered devices message.
+func CreateMessageDiscoveredDevices(in *pb.ReportDiscoveredDevicesRequest) ([]byte, error) {
+	var report types.DiscoveredDeviceReport
+	report.BaseMessage.Timestamp = getTimestamp()
+	report.MapperName = in.MapperName
+	report.Protocol = in.Protocol
+	for _, device := range in.Devices {
+		if device == nil || device.Id == "" {
+			return nil, fmt.Errorf("discovered device id cannot be empty")
+		}
+		protocol, err := dtcommon.ConvertProtocolConfig(device.Protocol)
+		if err != nil {
+			return nil, fmt.Errorf("fail to convert protocol of discovered device %s: %v", device.Id, err)
+		}
+		if protocol.ProtocolName == "" {
+			protocol.ProtocolName = in.Protocol
+		}
+		report.Devices = append(report.Devices, types.DiscoveredDevice{
+			ID:         device.Id,
+			Protocol:   protocol,
+			Attributes: device.Attributes,
+		})
+	}
+	return json.Marshal(report)
+}
+
 func StartDMIServer(cache *dmicache.DMICache) error {
 	socketPath := deviceconfig.Get().DeviceTwin.DMISockPath
 	// Compatibility with older configurations.
@@ -181,6 +181,54 @@ func ConvertDeviceModel(model *v1beta1.DeviceModel) (*pb.DeviceModel, error) {
 	edgeDeviceModel.Namespace = model.Namespace
 	return &edgeDeviceModel, nil
 }
+
+// ConvertProtocolConfig converts the protocol config reported by mapper to the protocol config of device
+func ConvertProtocolConfig(protocol *pb.ProtocolConfig) (v1beta1.ProtocolConfig, error) {
+	var config v1beta1.ProtocolConfig
+	if protocol == nil {
+		return config, errors.New("protocol cannot be nil")
+	}
+	config.ProtocolName = protocol.ProtocolName
+	if protocol.ConfigData != nil && protocol.ConfigData.Data != nil {
+		data := make(map[string]interface{}, len(protocol.ConfigData.Data))
+		for k, v := range protocol.ConfigData.Data {
+			value, err := anyToData(v)
+			if err != nil {
+				return config, fmt.Errorf("failed to convert protocol config data %s: %w", k, err)
+			}
+			data[k] = value
+		}
+		config.ConfigData = &v1beta1.CustomizedValue{Data: data}
+	}
+	return config, nil
+}
+
+func anyToData(v *anypb.Any) (interface{}, error) {
+	if v == nil {
+		return nil, errors.New("value cannot be nil")
+	}
+	m, err := v.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	switch w := m.(type) {
+	case *wrapperspb.StringValue:
+		return w.GetValue(), nil
+	case *wrapperspb.Int32Value:
+		return w.GetValue(), nil
+	case *wrapperspb.Int64Value:
+		return w.GetValue(), nil
+	case *wrapperspb.FloatValue:
+		return w.GetValue(), nil
+	case *wrapperspb.DoubleValue:
+		return w.GetValue(), nil
+	case *wrapperspb.BoolValue:
+		return w.GetValue(), nil
+	default:
+		return nil, fmt.Errorf("%s does not support converting from any", v.GetTypeUrl())
+	}
+}
+
 func dataToAny(v interface{}) (*anypb.Any, error) {
 	switch m := v.(type) {
 	case string:
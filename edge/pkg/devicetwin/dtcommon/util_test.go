@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubeedge/api/apis/devices/v1beta1"
+	pb "github.com/kubeedge/api/apis/dmi/v1beta1"
 )
 
 // TestValidateValue is function to test ValidateValue
@@ -643,3 +644,73 @@ func TestConvertDeviceProperty(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertProtocolConfig(t *testing.T) {
+	strAny, _ := anypb.New(wrapperspb.String("/dev/ttyS0"))
+	intAny, _ := anypb.New(wrapperspb.Int32(9600))
+	boolAny, _ := anypb.New(wrapperspb.Bool(true))
+	unsupportedAny, _ := anypb.New(&pb.DiscoveredDevice{})
+
+	cases := []struct {
+		name    string
+		input   *pb.ProtocolConfig
+		want    v1beta1.ProtocolConfig
+		wantErr bool
+	}{
+		{
+			name:    "nil protocol",
+			input:   nil,
+			wantErr: true,
+		},
+		{
+			name:  "protocol without config data",
+			input: &pb.ProtocolConfig{ProtocolName: "modbus"},
+			want:  v1beta1.ProtocolConfig{ProtocolName: "modbus"},
+		},
+		{
+			name: "protocol with config data",
+			input: &pb.ProtocolConfig{
+				ProtocolName: "modbus",
+				ConfigData: &pb.CustomizedValue{
+					Data: map[string]*anypb.Any{
+						"serialPort": strAny,
+						"baudRate":   intAny,
+						"rtu":        boolAny,
+					},
+				},
+			},
+			want: v1beta1.ProtocolConfig{
+				ProtocolName: "modbus",
+				ConfigData: &v1beta1.CustomizedValue{
+					Data: map[string]interface{}{
+						"serialPort": "/dev/ttyS0",
+						"baudRate":   int32(9600),
+						"rtu":        true,
+					},
+				},
+			},
+		},
+		{
+			name: "unsupported config data",
+			input: &pb.ProtocolConfig{
+				ProtocolName: "modbus",
+				ConfigData: &pb.CustomizedValue{
+					Data: map[string]*anypb.Any{"unknown": unsupportedAny},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertProtocolConfig(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
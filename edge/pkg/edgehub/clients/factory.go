@@ -7,6 +7,7 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients/quicclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients/wsclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 )
 
 // GetClient returns an Adapter object with new web socket
@@ -23,6 +24,7 @@ func GetClient() (Adapter, error) {
 			WriteDeadline:    time.Duration(config.WebSocket.WriteDeadline) * time.Second,
 			ProjectID:        config.ProjectID,
 			NodeID:           config.NodeName,
+			Batch:            batchOptions(),
 		}
 		return wsclient.NewWebSocketClient(&websocketConf), nil
 	case config.Quic.Enable:
@@ -34,11 +36,26 @@ func GetClient() (Adapter, error) {
 			HandshakeTimeout: time.Duration(config.Quic.HandshakeTimeout) * time.Second,
 			ReadDeadline:     time.Duration(config.Quic.ReadDeadline) * time.Second,
 			WriteDeadline:    time.Duration(config.Quic.WriteDeadline) * time.Second,
+			IdleTimeout:      time.Duration(config.Quic.IdleTimeout) * time.Second,
 			ProjectID:        config.ProjectID,
 			NodeID:           config.NodeName,
+			Batch:            batchOptions(),
 		}
 		return quicclient.NewQuicClient(&quicConfig), nil
 	}
 
 	return nil, fmt.Errorf("Websocket and Quic are both disabled")
 }
+
+// batchOptions returns how the messages sent to cloud are batched, nil if disabled
+func batchOptions() *conn.BatchOptions {
+	batch := config.Config.MessageBatch
+	if batch == nil || !batch.Enable {
+		return nil
+	}
+	return &conn.BatchOptions{
+		Window:      time.Duration(batch.WindowMilliseconds) * time.Millisecond,
+		MaxMessages: int(batch.MaxMessages),
+		MaxBytes:    int(batch.MaxBytes),
+	}
+}
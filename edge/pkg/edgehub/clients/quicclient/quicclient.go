@@ -31,8 +31,11 @@ type QuicConfig struct {
 	HandshakeTimeout time.Duration
 	ReadDeadline     time.Duration
 	WriteDeadline    time.Duration
+	IdleTimeout      time.Duration
 	NodeID           string
 	ProjectID        string
+	// Batch indicates how the messages are batched if the server is able to unpack them, nil if disabled
+	Batch *conn.BatchOptions
 }
 
 // NewQuicClient initializes a new quic client instance
@@ -67,8 +70,9 @@ func (qcc *QuicClient) Init() error {
 		TLSConfig:        tlsConfig,
 		Type:             api.ProtocolTypeQuic,
 		Addr:             qcc.config.Addr,
+		Batch:            qcc.config.Batch,
 	}
-	exOpts := api.QuicClientOption{Header: make(http.Header)}
+	exOpts := api.QuicClientOption{Header: make(http.Header), IdleTimeout: qcc.config.IdleTimeout}
 	exOpts.Header.Set("node_id", qcc.config.NodeID)
 	exOpts.Header.Set("project_id", qcc.config.ProjectID)
 	client := qclient.NewQuicClient(option, exOpts)
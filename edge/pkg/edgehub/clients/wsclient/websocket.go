@@ -39,6 +39,8 @@ type WebSocketConfig struct {
 	WriteDeadline    time.Duration
 	NodeID           string
 	ProjectID        string
+	// Batch indicates how the messages are batched if the server is able to unpack them, nil if disabled
+	Batch *conn.BatchOptions
 }
 
 // NewWebSocketClient initializes a new websocket client instance
@@ -78,6 +80,7 @@ func (wsc *WebSocketClient) Init() error {
 		Addr:             wsc.config.URL,
 		AutoRoute:        false,
 		ConnUse:          api.UseTypeMessage,
+		Batch:            wsc.config.Batch,
 	}
 	exOpts := api.WSClientOption{Header: make(http.Header)}
 	exOpts.Header.Set("node_id", wsc.config.NodeID)
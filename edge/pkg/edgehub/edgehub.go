@@ -2,6 +2,7 @@ package edgehub
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"k8s.io/client-go/util/flowcontrol"
@@ -26,6 +27,8 @@ type EdgeHub struct {
 	rateLimiter   flowcontrol.RateLimiter
 	keeperLock    sync.RWMutex
 	enable        bool
+	// upstreamBytes is the bytes sent to cloud which are not reported yet
+	upstreamBytes atomic.Int64
 }
 
 var _ core.Module = (*EdgeHub)(nil)
@@ -9,12 +9,15 @@ import (
 
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
 	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
 	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
 	msghandler "github.com/kubeedge/kubeedge/edge/pkg/edgehub/messagehandler"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/translator"
 )
 
 var (
@@ -68,6 +71,9 @@ func (eh *EdgeHub) sendToCloud(message model.Message) error {
 	if err != nil {
 		return fmt.Errorf("failed to send message, error: %v", err)
 	}
+	if config.Config.ReportBandwidthUsage {
+		eh.upstreamBytes.Add(int64(translator.Size(&message)))
+	}
 
 	return nil
 }
@@ -123,10 +129,36 @@ func (eh *EdgeHub) keepalive() {
 			return
 		}
 
+		if config.Config.ReportBandwidthUsage {
+			if err := eh.reportBandwidthUsage(); err != nil {
+				klog.Errorf("failed to report bandwidth usage: %v", err)
+				eh.reconnectChan <- struct{}{}
+				return
+			}
+		}
+
 		time.Sleep(time.Duration(config.Config.Heartbeat) * time.Second)
 	}
 }
 
+// reportBandwidthUsage reports the bytes sent to cloud since the last report, which are
+// counted into the bandwidth budget of the node by CloudHub
+func (eh *EdgeHub) reportBandwidthUsage() error {
+	bytes := eh.upstreamBytes.Swap(0)
+	if bytes == 0 {
+		return nil
+	}
+	msg := model.NewMessage("").
+		BuildRouter(modules.EdgeHubModuleName, "resource", constants.ResourceTypeBandwidthUsage, model.UpdateOperation).
+		FillBody(types.BandwidthUsage{UpstreamBytes: bytes})
+	if err := eh.sendToCloud(*msg); err != nil {
+		// report them next time
+		eh.upstreamBytes.Add(bytes)
+		return err
+	}
+	return nil
+}
+
 var pubGroups = []string{modules.TwinGroup, modules.MetaGroup, modules.BusGroup, modules.TaskManagerGroup}
 
 func (eh *EdgeHub) pubConnectInfo(isConnected bool) {
@@ -31,6 +31,8 @@ import (
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
 	cloudmodules "github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/mocks/edgehub"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
@@ -377,3 +379,35 @@ func TestKeepalive(t *testing.T) {
 		})
 	}
 }
+
+// TestReportBandwidthUsage() tests the bytes sent to the cloud are reported and reset
+func TestReportBandwidthUsage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAdapter := edgehub.NewMockAdapter(mockCtrl)
+	reportBandwidthUsage := config.Config.ReportBandwidthUsage
+	defer func() { config.Config.ReportBandwidthUsage = reportBandwidthUsage }()
+	config.Config.ReportBandwidthUsage = true
+
+	hub := &EdgeHub{chClient: mockAdapter}
+	// nothing is reported if no bytes are sent
+	require.NoError(t, hub.reportBandwidthUsage())
+
+	mockAdapter.EXPECT().Send(gomock.Any()).Return(nil).Times(1)
+	require.NoError(t, hub.sendToCloud(*model.NewMessage("").FillBody("ping")))
+	sent := hub.upstreamBytes.Load()
+	require.Positive(t, sent)
+
+	mockAdapter.EXPECT().Send(gomock.Any()).Return(errors.New("Connection Refused")).Times(1)
+	require.Error(t, hub.reportBandwidthUsage())
+	require.Equal(t, sent, hub.upstreamBytes.Load(), "bytes should be kept if the report fails")
+
+	mockAdapter.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg model.Message) error {
+		require.Equal(t, constants.ResourceTypeBandwidthUsage, msg.GetResource())
+		require.Equal(t, types.BandwidthUsage{UpstreamBytes: sent}, msg.GetContent())
+		return nil
+	}).Times(1)
+	require.NoError(t, hub.reportBandwidthUsage())
+	// the report itself is counted into the next report
+	require.Positive(t, hub.upstreamBytes.Load())
+}
@@ -2,6 +2,7 @@ package eventbus
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/beehive/pkg/core"
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commonconst "github.com/kubeedge/kubeedge/common/constants"
 	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
@@ -75,10 +78,16 @@ func (eb *eventbus) Start() {
 			PubClientID: eventconfig.Config.MqttPubClientID,
 			Username:    eventconfig.Config.MqttUsername,
 			Password:    eventconfig.Config.MqttPassword,
+
+			ProtocolVersion: eventconfig.Config.MqttProtocolVersion,
 		}
 		mqttBus.MQTTHub = hub
-		hub.InitSubClient()
-		hub.InitPubClient()
+		if hub.ProtocolVersion == v1alpha2.MqttProtocolVersion5 {
+			hub.InitClient5()
+		} else {
+			hub.InitSubClient()
+			hub.InitPubClient()
+		}
 		klog.Infof("Init Sub And Pub Client for external mqtt broker %v successfully", eventconfig.Config.MqttServerExternal)
 	}
 
@@ -89,6 +98,9 @@ func (eb *eventbus) Start() {
 			eventconfig.Config.MqttServerInternal,
 			eventconfig.Config.MqttRetain,
 			int(eventconfig.Config.MqttQOS))
+		mqttServer.SetAuth(eventconfig.Config.MqttAuth)
+		mqttServer.SetPersistence(eventconfig.Config.MqttPersistence)
+		mqttServer.SetBridge(eventconfig.Config.MqttBridge)
 		mqttServer.InitInternalTopics()
 		err := mqttServer.Run()
 		if err != nil {
@@ -101,13 +113,28 @@ func (eb *eventbus) Start() {
 	eb.pubCloudMsgToEdge()
 }
 
-func pubMQTT(topic string, payload []byte) {
-	token := mqttBus.MQTTHub.PubCli.Publish(topic, 1, false, payload)
-	if token.WaitTimeout(util.TokenWaitTime) && token.Error() != nil {
-		klog.Errorf("Error in pubMQTT with topic: %s, %v", topic, token.Error())
-	} else {
+func pubMQTT(topic string, payload []byte, properties map[string]string) error {
+	if mqttBus.MQTTHub.ProtocolVersion == v1alpha2.MqttProtocolVersion5 {
+		if err := mqttBus.MQTTHub.Publish5(topic, payload, properties); err != nil {
+			klog.Errorf("Error in pubMQTT with topic: %s, %v", topic, err)
+			return err
+		}
 		klog.Infof("Success in pubMQTT with topic: %s", topic)
+		return nil
+	}
+
+	token := mqttBus.MQTTHub.PubCli.Publish(topic, 1, false, payload)
+	if !token.WaitTimeout(util.TokenWaitTime) {
+		err := fmt.Errorf("publish to topic %s timeout", topic)
+		klog.Errorf("Error in pubMQTT with topic: %s, %v", topic, err)
+		return err
+	}
+	if err := token.Error(); err != nil {
+		klog.Errorf("Error in pubMQTT with topic: %s, %v", topic, err)
+		return err
 	}
+	klog.Infof("Success in pubMQTT with topic: %s", topic)
+	return nil
 }
 
 func (eb *eventbus) pubCloudMsgToEdge() {
@@ -153,7 +180,7 @@ func (eb *eventbus) pubCloudMsgToEdge() {
 				klog.Errorf("marshal message %v error: %v", topic, err)
 				continue
 			}
-			eb.publish(topic, payload)
+			eb.publish(topic, payload, nil)
 		case messagepkg.OperationPublish:
 			topic := resource
 			// cloud and edge will send different type of content, need to check
@@ -166,7 +193,18 @@ func (eb *eventbus) pubCloudMsgToEdge() {
 				}
 				payload = []byte(content)
 			}
-			eb.publish(topic, payload)
+			// the message expiry is counted from the time the message was created in cloud
+			properties, expired := mqttBus.RemainingExpiry(accessInfo.GetProperties(), accessInfo.GetTimestamp())
+			if expired {
+				err = fmt.Errorf("message to topic %s expired", topic)
+				klog.Warning(err.Error())
+			} else {
+				err = eb.publish(topic, payload, properties)
+			}
+			// the sync messages come from the rules in AtLeastOnce mode, which wait for the ack
+			if accessInfo.IsSync() {
+				ackPublish(&accessInfo, err)
+			}
 		case messagepkg.OperationGetResult:
 			if resource != "auth_info" {
 				klog.Info("Skip none auth_info get_result message")
@@ -174,23 +212,40 @@ func (eb *eventbus) pubCloudMsgToEdge() {
 			}
 			topic := fmt.Sprintf("$hw/events/node/%s/authInfo/get/result", eventconfig.Config.NodeName)
 			payload, _ := json.Marshal(accessInfo.GetContent())
-			eb.publish(topic, payload)
+			eb.publish(topic, payload, nil)
 		default:
 			klog.Warningf("Action not found")
 		}
 	}
 }
 
-func (eb *eventbus) publish(topic string, payload []byte) {
+func (eb *eventbus) publish(topic string, payload []byte, properties map[string]string) error {
+	var errs []error
 	if eventconfig.Config.MqttMode >= v1alpha2.MqttModeBoth {
 		// pub msg to external mqtt broker.
-		pubMQTT(topic, payload)
+		if err := pubMQTT(topic, payload, properties); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	if eventconfig.Config.MqttMode <= v1alpha2.MqttModeBoth {
 		// pub msg to internal mqtt broker.
-		mqttServer.Publish(topic, payload)
+		if err := mqttServer.Publish(topic, payload, properties); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ackPublish sends the result of publishing msg back to cloud
+func ackPublish(msg *model.Message, err error) {
+	content := commonconst.MessageSuccessfulContent
+	if err != nil {
+		content = err.Error()
 	}
+	ack := model.NewMessage(msg.GetID()).SetRoute(modules.EventBusModuleName, messagepkg.UserGroupName).
+		SetResourceOperation("", model.UploadOperation).FillBody(content)
+	beehiveContext.SendToGroup(modules.HubGroup, *ack)
 }
 
 func (eb *eventbus) subscribe(topic string) {
@@ -201,10 +256,17 @@ func (eb *eventbus) subscribe(topic string) {
 
 	if eventconfig.Config.MqttMode >= v1alpha2.MqttModeBoth {
 		// subscribe topic to external mqtt broker.
-		token := mqttBus.MQTTHub.SubCli.Subscribe(topic, 1, mqttBus.OnSubMessageReceived)
-		if rs, err := util.CheckClientToken(token); !rs {
-			klog.Errorf("Edge-hub-cli subscribe topic: %s, %v", topic, err)
-			return
+		if mqttBus.MQTTHub.ProtocolVersion == v1alpha2.MqttProtocolVersion5 {
+			if err := mqttBus.MQTTHub.Subscribe5(topic); err != nil {
+				klog.Errorf("Edge-hub-cli subscribe topic: %s, %v", topic, err)
+				return
+			}
+		} else {
+			token := mqttBus.MQTTHub.SubCli.Subscribe(topic, 1, mqttBus.OnSubMessageReceived)
+			if rs, err := util.CheckClientToken(token); !rs {
+				klog.Errorf("Edge-hub-cli subscribe topic: %s, %v", topic, err)
+				return
+			}
 		}
 	}
 
@@ -220,10 +282,17 @@ func (eb *eventbus) unsubscribe(topic string) {
 	}
 
 	if eventconfig.Config.MqttMode >= v1alpha2.MqttModeBoth {
-		token := mqttBus.MQTTHub.SubCli.Unsubscribe(topic)
-		if rs, err := util.CheckClientToken(token); !rs {
-			klog.Errorf("Edge-hub-cli unsubscribe topic: %s, %v", topic, err)
-			return
+		if mqttBus.MQTTHub.ProtocolVersion == v1alpha2.MqttProtocolVersion5 {
+			if err := mqttBus.MQTTHub.Unsubscribe5(topic); err != nil {
+				klog.Errorf("Edge-hub-cli unsubscribe topic: %s, %v", topic, err)
+				return
+			}
+		} else {
+			token := mqttBus.MQTTHub.SubCli.Unsubscribe(topic)
+			if rs, err := util.CheckClientToken(token); !rs {
+				klog.Errorf("Edge-hub-cli unsubscribe topic: %s, %v", topic, err)
+				return
+			}
 		}
 	}
 
@@ -0,0 +1,328 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/api/apis/devices/v1beta1"
+	pb "github.com/kubeedge/api/apis/dmi/v1beta1"
+	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcommon"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/auth"
+	"github.com/kubeedge/kubeedge/pkg/util"
+)
+
+const (
+	// mapperACLRefreshPeriod is the period to reload the mappers and devices from db
+	mapperACLRefreshPeriod = 10 * time.Second
+	// deviceDataETPrefix is the topic prefix of the device data reported by mappers
+	deviceDataETPrefix = "$ke/events/device/"
+)
+
+var (
+	// newTokenAuthenticator is a function variable that can be mocked in tests
+	newTokenAuthenticator = auth.NewServiceAccountTokenAuthenticator
+
+	// queryMetas is a function variable that can be mocked in tests
+	queryMetas = func(resourceType string) (*[]string, error) {
+		return dbclient.NewMetaService().QueryMeta("type", resourceType)
+	}
+)
+
+// authHook authenticates the clients of internal broker and checks the topics they access
+// against the ACLs. The username of client is replaced with the identity authenticated, which
+// is the common name of client certificate, the configured user or the service account.
+type authHook struct {
+	mqttserver.HookBase
+	config    *v1alpha2.EventBusMqttAuth
+	users     map[string]string
+	tokenAuth authenticator.Token
+	mappers   *mapperACL
+}
+
+func newAuthHook(config *v1alpha2.EventBusMqttAuth) (*authHook, error) {
+	h := &authHook{
+		config: config,
+		users:  make(map[string]string, len(config.Users)),
+	}
+	for _, u := range config.Users {
+		h.users[u.Username] = u.Password
+	}
+	if config.ServiceAccountToken {
+		tokenAuth, err := newTokenAuthenticator()
+		if err != nil {
+			return nil, err
+		}
+		h.tokenAuth = tokenAuth
+	}
+	if config.MapperACL {
+		h.mappers = &mapperACL{}
+	}
+	return h, nil
+}
+
+func (h *authHook) ID() string {
+	return "kubeedge-auth"
+}
+
+func (h *authHook) Provides(b byte) bool {
+	return b == mqttserver.OnConnectAuthenticate || b == mqttserver.OnACLCheck
+}
+
+func (h *authHook) OnConnectAuthenticate(cl *mqttserver.Client, pk packets.Packet) bool {
+	username, err := h.authenticate(cl, pk)
+	if err != nil {
+		klog.Warningf("reject mqtt client %s from %s: %v", cl.ID, cl.Net.Remote, err)
+		return false
+	}
+	cl.Properties.Username = []byte(username)
+	return true
+}
+
+func (h *authHook) authenticate(cl *mqttserver.Client, pk packets.Packet) (string, error) {
+	username := string(pk.Connect.Username)
+	if h.config.TLSClientCAFile != "" {
+		conn, ok := cl.Net.Conn.(*tls.Conn)
+		if !ok || len(conn.ConnectionState().PeerCertificates) == 0 {
+			return "", errors.New("no client certificate")
+		}
+		commonName := conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		if username != "" && username != commonName {
+			return "", fmt.Errorf("username %s mismatches the common name %s of certificate", username, commonName)
+		}
+		return commonName, nil
+	}
+
+	if username == "" {
+		return "", errors.New("no username")
+	}
+	if password, ok := h.users[username]; ok {
+		if subtle.ConstantTimeCompare([]byte(password), pk.Connect.Password) != 1 {
+			return "", fmt.Errorf("invalid password of user %s", username)
+		}
+		return username, nil
+	}
+	if h.tokenAuth != nil && strings.HasPrefix(username, serviceaccount.ServiceAccountUsernamePrefix) {
+		resp, ok, err := h.tokenAuth.AuthenticateToken(context.Background(), string(pk.Connect.Password))
+		if err != nil {
+			return "", fmt.Errorf("invalid token of %s: %v", username, err)
+		}
+		if !ok || resp.User.GetName() != username {
+			return "", fmt.Errorf("invalid token of %s", username)
+		}
+		return username, nil
+	}
+	return "", fmt.Errorf("unknown user %s", username)
+}
+
+func (h *authHook) OnACLCheck(cl *mqttserver.Client, topic string, write bool) bool {
+	username := string(cl.Properties.Username)
+	filter := trimSharePrefix(topic)
+	for _, acl := range h.config.ACLs {
+		if !matchUsername(acl.Usernames, username) {
+			continue
+		}
+		allowed := acl.Subscribe
+		if write {
+			allowed = acl.Publish
+		}
+		for _, f := range allowed {
+			f, ok := expandFilter(f, username, cl.ID)
+			if ok && filterCovers(f, filter) {
+				return true
+			}
+		}
+	}
+	if h.mappers != nil && h.mappers.allowed(username, filter) {
+		return true
+	}
+	klog.V(4).Infof("mqtt client %s of user %s is not allowed to access topic %s", cl.ID, username, topic)
+	return false
+}
+
+// expandFilter replaces "%u" and "%c" in the allowed filter with the username and client id.
+// It returns false if the filter uses an identity which is not a plain topic level, otherwise
+// a client id like "#" would widen the filter to other topics.
+func expandFilter(filter, username, clientID string) (string, bool) {
+	if strings.Contains(filter, "%u") && !isTopicLevel(username) ||
+		strings.Contains(filter, "%c") && !isTopicLevel(clientID) {
+		return "", false
+	}
+	return strings.NewReplacer("%u", username, "%c", clientID).Replace(filter), true
+}
+
+func isTopicLevel(s string) bool {
+	return s != "" && !strings.ContainsAny(s, "+#/")
+}
+
+func matchUsername(patterns []string, username string) bool {
+	for _, p := range patterns {
+		if matched, _ := path.Match(p, username); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// trimSharePrefix returns the topic filter of shared subscription "$share/<group>/<filter>"
+func trimSharePrefix(filter string) string {
+	if !strings.HasPrefix(filter, "$share/") {
+		return filter
+	}
+	parts := strings.SplitN(filter, "/", 3)
+	if len(parts) < 3 {
+		return filter
+	}
+	return parts[2]
+}
+
+// filterCovers returns whether all topics matched by the requested filter are matched by the
+// allowed filter, the requested one is a topic name when publishing or receiving messages
+func filterCovers(allowed, requested string) bool {
+	a := strings.Split(allowed, "/")
+	r := strings.Split(requested, "/")
+	for i, level := range a {
+		if level == "#" {
+			return true
+		}
+		if i >= len(r) {
+			return false
+		}
+		switch level {
+		case "+":
+			if r[i] == "#" {
+				return false
+			}
+		default:
+			if level != r[i] {
+				return false
+			}
+		}
+	}
+	return len(a) == len(r)
+}
+
+// mapperACL allows the registered mappers to access the topics of devices using their protocols
+type mapperACL struct {
+	sync.Mutex
+	updated time.Time
+	// filters are the allowed topic filters of mappers
+	filters map[string][]string
+}
+
+func (m *mapperACL) allowed(mapper, filter string) bool {
+	m.Lock()
+	defer m.Unlock()
+	if time.Since(m.updated) > mapperACLRefreshPeriod {
+		if err := m.refresh(); err != nil {
+			klog.Errorf("failed to load mapper ACLs: %v", err)
+		}
+	}
+	for _, f := range m.filters[mapper] {
+		if filterCovers(f, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mapperACL) refresh() error {
+	mapperMetas, err := queryMetas(deviceconst.ResourceTypeDeviceMapper)
+	if err != nil {
+		return err
+	}
+	deviceMetas, err := queryMetas(deviceconst.ResourceTypeDevice)
+	if err != nil {
+		return err
+	}
+
+	protocols := make(map[string][]string)
+	for _, meta := range *deviceMetas {
+		device := v1beta1.Device{}
+		if err := json.Unmarshal([]byte(meta), &device); err != nil {
+			klog.Errorf("failed to unmarshal device: %v", err)
+			continue
+		}
+		// the device ID in the topics is namespaced
+		deviceID := util.GetResourceID(device.Namespace, device.Name)
+		protocol := device.Spec.Protocol.ProtocolName
+		protocols[protocol] = append(protocols[protocol],
+			dtcommon.DeviceETPrefix+deviceID+"/#", deviceDataETPrefix+deviceID+"/#")
+	}
+
+	filters := make(map[string][]string)
+	for _, meta := range *mapperMetas {
+		mapper := pb.MapperInfo{}
+		if err := json.Unmarshal([]byte(meta), &mapper); err != nil {
+			klog.Errorf("failed to unmarshal device mapper: %v", err)
+			continue
+		}
+		filters[mapper.Name] = protocols[mapper.Protocol]
+	}
+	m.filters = filters
+	m.updated = time.Now()
+	return nil
+}
+
+// serverTLSConfig returns the TLS configuration of internal broker, it is nil if the broker
+// listens without TLS
+func serverTLSConfig(config *v1alpha2.EventBusMqttAuth) (*tls.Config, error) {
+	if config == nil || !config.Enable || config.TLSCertFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load x509 key pair: %v", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if config.TLSClientCAFile != "" {
+		caCert, err := os.ReadFile(config.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLSClientCAFile: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM(caCert); !ok {
+			return nil, errors.New("cannot parse the client ca certificates")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestFilterCovers(t *testing.T) {
+	cases := []struct {
+		allowed   string
+		requested string
+		want      bool
+	}{
+		{"$hw/events/device/+/twin/+", "$hw/events/device/dev1/twin/update", true},
+		{"$hw/events/device/+/twin/+", "$hw/events/device/+/twin/+", true},
+		{"$hw/events/device/+/twin/+", "$hw/events/device/#", false},
+		{"$hw/events/device/dev1/#", "$hw/events/device/dev1", true},
+		{"$hw/events/device/dev1/#", "$hw/events/device/dev1/twin/+", true},
+		{"$hw/events/device/dev1/#", "$hw/events/device/dev2/twin/update", false},
+		{"a/b", "a/b/c", false},
+		{"a/b/c", "a/b", false},
+		{"#", "$hw/events/upload/x", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, filterCovers(c.allowed, c.requested), "%s covers %s", c.allowed, c.requested)
+	}
+}
+
+func TestTrimSharePrefix(t *testing.T) {
+	assert.Equal(t, "devices/+/command", trimSharePrefix("$share/group/devices/+/command"))
+	assert.Equal(t, "$share/group", trimSharePrefix("$share/group"))
+	assert.Equal(t, "devices/+/command", trimSharePrefix("devices/+/command"))
+}
+
+func TestAuthACLPlaceholders(t *testing.T) {
+	h, err := newAuthHook(&v1alpha2.EventBusMqttAuth{
+		Enable: true,
+		ACLs: []v1alpha2.EventBusMqttACL{
+			{
+				Usernames: []string{"*"},
+				Subscribe: []string{"clients/%c/#", "users/%u"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		clientID string
+		username string
+		topic    string
+		want     bool
+	}{
+		{"c1", "app", "clients/c1/status", true},
+		{"c1", "app", "clients/c2/status", false},
+		{"c1", "app", "users/app", true},
+		{"#", "app", "clients/c2/status", false},
+		{"+", "app", "clients/c2/status", false},
+		{"c1/c2", "app", "clients/c1/c2/status", false},
+		{"c1", "a/b", "users/a/b", false},
+		{"c1", "#", "users/app", false},
+	}
+	for _, c := range cases {
+		cl := &mqttserver.Client{ID: c.clientID}
+		cl.Properties.Username = []byte(c.username)
+		assert.Equal(t, c.want, h.OnACLCheck(cl, c.topic, false), "client %s of user %s subscribes %s", c.clientID, c.username, c.topic)
+	}
+}
+
+func newTestAuthServer(t *testing.T, config *v1alpha2.EventBusMqttAuth) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	server := NewMqttServer(10, "tcp://"+addr, false, 1)
+	server.SetAuth(config)
+	require.NoError(t, server.Run())
+	t.Cleanup(func() { server.server.Close() })
+	return addr
+}
+
+func connectTest(t *testing.T, addr, username, password string, tlsConfig *tls.Config) (*paho.Client, error) {
+	var conn net.Conn
+	var err error
+	if tlsConfig != nil {
+		conn, err = tls.Dial("tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.Dial("tcp", addr)
+	}
+	require.NoError(t, err)
+	c := paho.NewClient(paho.ClientConfig{Conn: conn})
+	t.Cleanup(func() { _ = c.Disconnect(&paho.Disconnect{}) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cp := &paho.Connect{
+		ClientID:     "test-client",
+		KeepAlive:    30,
+		CleanStart:   true,
+		Username:     username,
+		UsernameFlag: username != "",
+		Password:     []byte(password),
+		PasswordFlag: password != "",
+	}
+	_, err = c.Connect(ctx, cp)
+	return c, err
+}
+
+func subscribeTest(c *paho.Client, filter string) byte {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, _ := c.Subscribe(ctx, &paho.Subscribe{Subscriptions: []paho.SubscribeOptions{{Topic: filter, QoS: 1}}})
+	if resp == nil || len(resp.Reasons) == 0 {
+		return 0xff
+	}
+	return resp.Reasons[0]
+}
+
+func publishTest(c *paho.Client, topic string) byte {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, _ := c.Publish(ctx, &paho.Publish{QoS: 1, Topic: topic, Payload: []byte("{}")})
+	if resp == nil {
+		return 0xff
+	}
+	return resp.ReasonCode
+}
+
+func TestAuthUsers(t *testing.T) {
+	addr := newTestAuthServer(t, &v1alpha2.EventBusMqttAuth{
+		Enable: true,
+		Users:  []v1alpha2.EventBusMqttUser{{Username: "app", Password: "secret"}},
+		ACLs: []v1alpha2.EventBusMqttACL{
+			{
+				Usernames: []string{"app"},
+				Publish:   []string{"apps/%u/#"},
+				Subscribe: []string{"$hw/events/device/+/twin/+"},
+			},
+		},
+	})
+
+	_, err := connectTest(t, addr, "app", "wrong", nil)
+	assert.Error(t, err)
+	_, err = connectTest(t, addr, "", "", nil)
+	assert.Error(t, err)
+
+	c, err := connectTest(t, addr, "app", "secret", nil)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), subscribeTest(c, "$hw/events/device/+/twin/+"))
+	assert.Equal(t, byte(1), subscribeTest(c, "$share/g1/$hw/events/device/+/twin/+"))
+	assert.Equal(t, packets.ErrNotAuthorized.Code, subscribeTest(c, "$hw/events/#"))
+	assert.Equal(t, byte(0), publishTest(c, "apps/app/status"))
+	assert.Equal(t, packets.ErrNotAuthorized.Code, publishTest(c, "$hw/events/device/dev1/twin/update"))
+}
+
+func TestAuthServiceAccountToken(t *testing.T) {
+	original := newTokenAuthenticator
+	defer func() { newTokenAuthenticator = original }()
+	newTokenAuthenticator = func() (authenticator.Token, error) {
+		return authenticator.TokenFunc(func(_ context.Context, token string) (*authenticator.Response, bool, error) {
+			if token != "sa-token" {
+				return nil, false, nil
+			}
+			return &authenticator.Response{User: &user.DefaultInfo{Name: "system:serviceaccount:default:app"}}, true, nil
+		}), nil
+	}
+
+	addr := newTestAuthServer(t, &v1alpha2.EventBusMqttAuth{
+		Enable:              true,
+		ServiceAccountToken: true,
+		ACLs: []v1alpha2.EventBusMqttACL{
+			{
+				Usernames: []string{"system:serviceaccount:default:*"},
+				Subscribe: []string{"apps/#"},
+			},
+		},
+	})
+
+	_, err := connectTest(t, addr, "system:serviceaccount:default:other", "sa-token", nil)
+	assert.Error(t, err)
+	_, err = connectTest(t, addr, "system:serviceaccount:default:app", "invalid", nil)
+	assert.Error(t, err)
+
+	c, err := connectTest(t, addr, "system:serviceaccount:default:app", "sa-token", nil)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), subscribeTest(c, "apps/+/status"))
+	assert.Equal(t, packets.ErrNotAuthorized.Code, subscribeTest(c, "$hw/events/upload/#"))
+}
+
+func TestAuthMapperACL(t *testing.T) {
+	original := queryMetas
+	defer func() { queryMetas = original }()
+	queryMetas = func(resourceType string) (*[]string, error) {
+		metas := map[string][]string{
+			"devicemapper": {`{"name":"modbus-mapper","protocol":"modbus"}`},
+			"device": {
+				`{"metadata":{"name":"dev1","namespace":"default"},"spec":{"protocol":{"protocolName":"modbus"}}}`,
+				`{"metadata":{"name":"dev2","namespace":"default"},"spec":{"protocol":{"protocolName":"opcua"}}}`,
+				// a device named after a namespace
+				`{"metadata":{"name":"factory","namespace":"default"},"spec":{"protocol":{"protocolName":"modbus"}}}`,
+			},
+		}[resourceType]
+		return &metas, nil
+	}
+
+	addr := newTestAuthServer(t, &v1alpha2.EventBusMqttAuth{
+		Enable:    true,
+		MapperACL: true,
+		Users:     []v1alpha2.EventBusMqttUser{{Username: "modbus-mapper", Password: "secret"}},
+	})
+
+	c, err := connectTest(t, addr, "modbus-mapper", "secret", nil)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), publishTest(c, "$hw/events/device/default/dev1/twin/update"))
+	assert.Equal(t, byte(0), publishTest(c, "$ke/events/device/default/dev1/data/update"))
+	assert.Equal(t, packets.ErrNotAuthorized.Code, publishTest(c, "$hw/events/device/default/dev2/twin/update"))
+	// the devices of the same name in other namespaces are denied
+	assert.Equal(t, packets.ErrNotAuthorized.Code, publishTest(c, "$hw/events/device/other/dev1/twin/update"))
+	assert.Equal(t, packets.ErrNotAuthorized.Code, publishTest(c, "$ke/events/device/other/dev1/data/update"))
+	// and so are the devices in a namespace named after a device
+	assert.Equal(t, packets.ErrNotAuthorized.Code, publishTest(c, "$hw/events/device/factory/dev3/twin/update"))
+	assert.Equal(t, byte(1), subscribeTest(c, "$hw/events/device/default/dev1/twin/update/delta"))
+}
+
+func TestAuthClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey := newTestCert(t, "ca", nil, nil)
+	writePEM(t, filepath.Join(dir, "ca.crt"), "CERTIFICATE", caCert.Raw)
+	serverCert, serverKey := newTestCert(t, "127.0.0.1", caCert, caKey)
+	writePEM(t, filepath.Join(dir, "server.crt"), "CERTIFICATE", serverCert.Raw)
+	keyDER, err := x509.MarshalECPrivateKey(serverKey)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "server.key"), "EC PRIVATE KEY", keyDER)
+
+	addr := newTestAuthServer(t, &v1alpha2.EventBusMqttAuth{
+		Enable:            true,
+		TLSCertFile:       filepath.Join(dir, "server.crt"),
+		TLSPrivateKeyFile: filepath.Join(dir, "server.key"),
+		TLSClientCAFile:   filepath.Join(dir, "ca.crt"),
+		ACLs: []v1alpha2.EventBusMqttACL{
+			{
+				Usernames: []string{"mapper-*"},
+				Publish:   []string{"$hw/events/device/+/state/update"},
+			},
+		},
+	})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	clientCert, clientKey := newTestCert(t, "mapper-1", caCert, caKey)
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		ServerName: "127.0.0.1",
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{clientCert.Raw},
+			PrivateKey:  clientKey,
+		}},
+	}
+
+	_, err = connectTest(t, addr, "mapper-2", "", tlsConfig)
+	assert.Error(t, err)
+
+	c, err := connectTest(t, addr, "", "", tlsConfig)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), publishTest(c, "$hw/events/device/dev1/state/update"))
+	assert.Equal(t, packets.ErrNotAuthorized.Code, publishTest(c, "$hw/events/device/dev1/twin/update"))
+}
+
+func newTestCert(t *testing.T, commonName string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+	if ip := net.ParseIP(commonName); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
@@ -0,0 +1,436 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
+	eventconfig "github.com/kubeedge/kubeedge/edge/pkg/eventbus/config"
+)
+
+const (
+	// bridgeRetryPeriod is the period to retry forwarding the buffered message to remote broker
+	bridgeRetryPeriod = time.Second
+	// bridgeBufferType is the type of buffered messages in the mqtt store
+	bridgeBufferType = "bridge"
+)
+
+// bridgeMessage is the message buffered by the bridge, seq keeps the order of messages
+// restored from the mqtt store
+type bridgeMessage struct {
+	Seq uint64 `json:"seq"`
+	*paho.Publish
+}
+
+func (m *bridgeMessage) key() string {
+	return fmt.Sprintf("%s_%020d", bridgeBufferType, m.Seq)
+}
+
+// bridgeRule remaps the topics between internal broker and remote broker
+type bridgeRule struct {
+	v1alpha2.EventBusMqttBridgeTopic
+}
+
+func (r bridgeRule) out() bool {
+	return r.Direction == "" || r.Direction == v1alpha2.MqttBridgeDirectionOut ||
+		r.Direction == v1alpha2.MqttBridgeDirectionBoth
+}
+
+func (r bridgeRule) in() bool {
+	return r.Direction == v1alpha2.MqttBridgeDirectionIn || r.Direction == v1alpha2.MqttBridgeDirectionBoth
+}
+
+// toRemote returns the topic of remote broker if the topic of internal broker is forwarded out
+func (r bridgeRule) toRemote(topic string) (string, bool) {
+	if !r.out() || !filterCovers(r.LocalPrefix+r.Topic, topic) {
+		return "", false
+	}
+	return r.RemotePrefix + strings.TrimPrefix(topic, r.LocalPrefix), true
+}
+
+// toLocal returns the topic of internal broker if the topic of remote broker is forwarded in
+func (r bridgeRule) toLocal(topic string) (string, bool) {
+	if !r.in() || !filterCovers(r.RemotePrefix+r.Topic, topic) {
+		return "", false
+	}
+	return r.LocalPrefix + strings.TrimPrefix(topic, r.RemotePrefix), true
+}
+
+// Bridge forwards the messages between internal broker and a remote broker. The messages
+// forwarded out are buffered while the remote broker is unreachable, and the oldest
+// ones are dropped when the buffer is full. The buffer is kept in memory only unless
+// the persistence of internal broker is enabled, then the buffered QoS 1/2 messages are
+// persisted and forwarded after edgecore restarts.
+type Bridge struct {
+	config *v1alpha2.EventBusMqttBridge
+	rules  []bridgeRule
+	server *Server
+
+	// client is the inline client publishing the messages of remote broker to internal broker
+	client *mqttserver.Client
+	conn   *autopaho.ConnectionManager
+
+	sync.Mutex
+	buffer []*bridgeMessage
+	seq    uint64
+	notify chan struct{}
+
+	// store persists the buffered messages, it is nil if the persistence is disabled
+	store          MqttStore
+	maxMessageSize int32
+}
+
+func newBridge(server *Server, config *v1alpha2.EventBusMqttBridge) *Bridge {
+	b := &Bridge{
+		config: config,
+		server: server,
+		notify: make(chan struct{}, 1),
+	}
+	for _, t := range config.Topics {
+		b.rules = append(b.rules, bridgeRule{t})
+	}
+	return b
+}
+
+// restore loads the buffered messages persisted in store, the later messages are persisted too
+func (b *Bridge) restore(store MqttStore, maxMessageSize int32) error {
+	values, err := store.List(bridgeBufferType)
+	if err != nil {
+		return fmt.Errorf("failed to load buffered messages of mqtt bridge: %v", err)
+	}
+	var messages []*bridgeMessage
+	for _, value := range values {
+		m := &bridgeMessage{}
+		if err := json.Unmarshal(value, m); err != nil || m.Publish == nil {
+			klog.Errorf("failed to decode buffered message of mqtt bridge: %v", err)
+			continue
+		}
+		messages = append(messages, m)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Seq < messages[j].Seq })
+
+	b.Lock()
+	defer b.Unlock()
+	b.store, b.maxMessageSize = store, maxMessageSize
+	for _, m := range messages {
+		b.push(m)
+	}
+	klog.Infof("mqtt bridge restored %d buffered messages", len(b.buffer))
+	return nil
+}
+
+// Start connects to the remote broker and forwards the buffered messages
+func (b *Bridge) Start(ctx context.Context) error {
+	serverURL, err := url.Parse(b.config.RemoteServer)
+	if err != nil {
+		return fmt.Errorf("failed to parse remote mqtt url %s: %v", b.config.RemoteServer, err)
+	}
+	tlsConfig, err := bridgeTLSConfig(b.config)
+	if err != nil {
+		return err
+	}
+	clientID := b.config.ClientID
+	if clientID == "" {
+		clientID = "kubeedge-bridge-" + eventconfig.Config.NodeName
+	}
+	b.client = b.server.server.NewClient(nil, "local", clientID, true)
+
+	cfg := autopaho.ClientConfig{
+		ServerUrls:        []*url.URL{serverURL},
+		TlsCfg:            tlsConfig,
+		KeepAlive:         30,
+		ConnectRetryDelay: util.LoopConnectPeriord,
+		OnConnectionUp:    b.onConnectionUp,
+		OnConnectError: func(err error) {
+			klog.Errorf("mqtt bridge %s connect to remote mqtt broker failed: %v", clientID, err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: clientID,
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+				b.onPublishReceived,
+			},
+			OnServerDisconnect: func(d *paho.Disconnect) {
+				klog.Errorf("remote mqtt broker disconnected the bridge with reason code %d", d.ReasonCode)
+			},
+			OnClientError: func(err error) {
+				klog.Errorf("mqtt bridge %s error: %v", clientID, err)
+			},
+		},
+	}
+	if b.config.Username != "" {
+		cfg.ConnectUsername = b.config.Username
+		cfg.ConnectPassword = []byte(b.config.Password)
+	}
+	conn, err := autopaho.NewConnection(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create connection to remote mqtt broker: %v", err)
+	}
+	b.conn = conn
+	go b.forward(ctx)
+	klog.Infof("mqtt bridge %s to remote mqtt broker %s started", clientID, b.config.RemoteServer)
+	return nil
+}
+
+func (b *Bridge) onConnectionUp(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+	klog.Infof("mqtt bridge connected to remote mqtt broker %s", b.config.RemoteServer)
+	var subscriptions []paho.SubscribeOptions
+	for _, r := range b.rules {
+		if !r.in() {
+			continue
+		}
+		// NoLocal prevents the messages forwarded out from being forwarded in again
+		subscriptions = append(subscriptions, paho.SubscribeOptions{
+			Topic:   r.RemotePrefix + r.Topic,
+			QoS:     r.QoS,
+			NoLocal: true,
+		})
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), util.TokenWaitTime)
+	defer cancel()
+	resp, err := cm.Subscribe(ctx, &paho.Subscribe{Subscriptions: subscriptions})
+	if err != nil {
+		klog.Errorf("mqtt bridge subscribe remote topics failed: %v", err)
+		return
+	}
+	for i, code := range resp.Reasons {
+		if code >= reasonCodeFailure && i < len(subscriptions) {
+			klog.Errorf("mqtt bridge subscribe remote topic %s failed with reason code %d", subscriptions[i].Topic, code)
+		}
+	}
+}
+
+// onPublishReceived publishes the messages of remote broker to internal broker
+func (b *Bridge) onPublishReceived(pr paho.PublishReceived) (bool, error) {
+	for _, r := range b.rules {
+		topic, ok := r.toLocal(pr.Packet.Topic)
+		if !ok {
+			continue
+		}
+		pk := packets.Packet{
+			FixedHeader: packets.FixedHeader{
+				Type:   packets.Publish,
+				Qos:    r.QoS,
+				Retain: pr.Packet.Retain,
+			},
+			TopicName:  topic,
+			Payload:    pr.Packet.Payload,
+			Properties: toPacketProperties(fromPahoProperties(pr.Packet.Properties)),
+		}
+		if err := b.server.injectPublish(b.client, pk); err != nil {
+			klog.Errorf("mqtt bridge publish topic %s to internal mqtt broker failed: %v", topic, err)
+			return false, err
+		}
+		klog.V(4).Infof("mqtt bridge forward topic %s in as %s", pr.Packet.Topic, topic)
+		return true, nil
+	}
+	return true, nil
+}
+
+// onPublished buffers the messages of internal broker forwarded to remote broker
+func (b *Bridge) onPublished(cl *mqttserver.Client, pk packets.Packet) {
+	// the messages come from remote broker are not forwarded back
+	if cl == b.client {
+		return
+	}
+	for _, r := range b.rules {
+		topic, ok := r.toRemote(pk.TopicName)
+		if !ok {
+			continue
+		}
+		pk.ProtocolVersion = 5
+		b.enqueue(&paho.Publish{
+			QoS:        r.QoS,
+			Retain:     pk.FixedHeader.Retain,
+			Topic:      topic,
+			Payload:    pk.Payload,
+			Properties: toPahoProperties(fromPacketProperties(pk)),
+		})
+		return
+	}
+}
+
+func (b *Bridge) enqueue(p *paho.Publish) {
+	b.Lock()
+	m := &bridgeMessage{Seq: b.seq + 1, Publish: p}
+	if b.store != nil && p.QoS > 0 && len(p.Payload) <= int(b.maxMessageSize) {
+		if data, err := json.Marshal(m); err != nil {
+			klog.Errorf("failed to marshal buffered message of topic %s: %v", p.Topic, err)
+		} else if err := b.store.Set(m.key(), bridgeBufferType, data); err != nil {
+			klog.Errorf("failed to persist buffered message of topic %s: %v", p.Topic, err)
+		}
+	}
+	b.push(m)
+	b.Unlock()
+
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
+
+// push appends the message to buffer, the oldest one is dropped if the buffer is full
+func (b *Bridge) push(m *bridgeMessage) {
+	if len(b.buffer) >= int(b.config.BufferSize) {
+		klog.Warningf("mqtt bridge buffer is full, drop the message of topic %s", b.buffer[0].Topic)
+		b.remove(b.buffer[0])
+		b.buffer = b.buffer[1:]
+	}
+	b.buffer = append(b.buffer, m)
+	b.seq = m.Seq
+}
+
+// remove deletes the message forwarded or dropped from the store
+func (b *Bridge) remove(m *bridgeMessage) {
+	if b.store == nil {
+		return
+	}
+	if err := b.store.Delete(m.key()); err != nil {
+		klog.Errorf("failed to delete buffered message of topic %s: %v", m.Topic, err)
+	}
+}
+
+// forward publishes the buffered messages to remote broker in order, the message is
+// retried until it is published or dropped for the buffer is full. The messages restored
+// from the store are forwarded at once, without waiting for a new message.
+func (b *Bridge) forward(ctx context.Context) {
+	for {
+		for {
+			b.Lock()
+			if len(b.buffer) == 0 {
+				b.Unlock()
+				break
+			}
+			p := b.buffer[0]
+			b.Unlock()
+
+			if err := b.publish(ctx, p.Publish); err != nil {
+				klog.Errorf("mqtt bridge forward topic %s out failed: %v", p.Topic, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(bridgeRetryPeriod):
+				}
+				continue
+			}
+
+			b.Lock()
+			if len(b.buffer) > 0 && b.buffer[0] == p {
+				b.remove(p)
+				b.buffer = b.buffer[1:]
+			}
+			b.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.notify:
+		}
+	}
+}
+
+func (b *Bridge) publish(ctx context.Context, p *paho.Publish) error {
+	if err := b.conn.AwaitConnection(ctx); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, util.TokenWaitTime)
+	defer cancel()
+	resp, err := b.conn.Publish(ctx, p)
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.ReasonCode >= reasonCodeFailure {
+		return fmt.Errorf("reason code %d", resp.ReasonCode)
+	}
+	return nil
+}
+
+// bridgeHook passes the messages published to internal broker to the bridge
+type bridgeHook struct {
+	mqttserver.HookBase
+	bridge *Bridge
+}
+
+func (h *bridgeHook) ID() string {
+	return "kubeedge-bridge"
+}
+
+func (h *bridgeHook) Provides(b byte) bool {
+	return b == mqttserver.OnPublished
+}
+
+func (h *bridgeHook) OnPublished(cl *mqttserver.Client, pk packets.Packet) {
+	h.bridge.onPublished(cl, pk)
+}
+
+// bridgeTLSConfig returns the TLS configuration of bridge, it is nil if the remote broker
+// is connected without TLS
+func bridgeTLSConfig(config *v1alpha2.EventBusMqttBridge) (*tls.Config, error) {
+	u, err := url.Parse(config.RemoteServer)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "ssl", "tls", "wss":
+	default:
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if config.TLSCAFile != "" {
+		caCert, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLSCAFile: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM(caCert); !ok {
+			return nil, errors.New("cannot parse the ca certificates of remote mqtt broker")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load x509 key pair: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func newTestBridgeConfig(remote string) *v1alpha2.EventBusMqttBridge {
+	return &v1alpha2.EventBusMqttBridge{
+		Enable:       true,
+		RemoteServer: "tcp://" + remote,
+		ClientID:     "bridge-test",
+		BufferSize:   2,
+		Topics: []v1alpha2.EventBusMqttBridgeTopic{
+			{Topic: "devices/+/telemetry", QoS: 1, RemotePrefix: "edge/node1/"},
+			{Topic: "devices/+/command", Direction: v1alpha2.MqttBridgeDirectionIn, QoS: 1, RemotePrefix: "edge/node1/"},
+		},
+	}
+}
+
+func TestBridgeRule(t *testing.T) {
+	cases := []struct {
+		name       string
+		rule       v1alpha2.EventBusMqttBridgeTopic
+		topic      string
+		wantRemote string
+		wantLocal  string
+	}{
+		{
+			name:       "out with remote prefix",
+			rule:       v1alpha2.EventBusMqttBridgeTopic{Topic: "devices/#", RemotePrefix: "edge/node1/"},
+			topic:      "devices/dev1/telemetry",
+			wantRemote: "edge/node1/devices/dev1/telemetry",
+		},
+		{
+			name:      "in with both prefixes",
+			rule:      v1alpha2.EventBusMqttBridgeTopic{Topic: "+/command", Direction: v1alpha2.MqttBridgeDirectionIn, LocalPrefix: "local/", RemotePrefix: "remote/"},
+			topic:     "remote/dev1/command",
+			wantLocal: "local/dev1/command",
+		},
+		{
+			name:       "both",
+			rule:       v1alpha2.EventBusMqttBridgeTopic{Topic: "status", Direction: v1alpha2.MqttBridgeDirectionBoth, LocalPrefix: "a/", RemotePrefix: "b/"},
+			topic:      "a/status",
+			wantRemote: "b/status",
+		},
+		{
+			name:  "not matched",
+			rule:  v1alpha2.EventBusMqttBridgeTopic{Topic: "devices/+/telemetry"},
+			topic: "devices/dev1/command",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := bridgeRule{c.rule}
+			remote, _ := r.toRemote(c.topic)
+			assert.Equal(t, c.wantRemote, remote)
+			local, _ := r.toLocal(c.topic)
+			assert.Equal(t, c.wantLocal, local)
+		})
+	}
+}
+
+func TestBridgeBuffer(t *testing.T) {
+	b := newBridge(nil, newTestBridgeConfig("127.0.0.1:1883"))
+	for _, topic := range []string{"t1", "t2", "t3"} {
+		b.enqueue(&paho.Publish{Topic: topic})
+	}
+	require.Len(t, b.buffer, 2)
+	assert.Equal(t, "t2", b.buffer[0].Topic)
+	assert.Equal(t, "t3", b.buffer[1].Topic)
+}
+
+func TestBridgeBufferPersistence(t *testing.T) {
+	store := newFakeMqttStore()
+	b := newBridge(nil, newTestBridgeConfig("127.0.0.1:1883"))
+	require.NoError(t, b.restore(store, 16))
+	b.enqueue(&paho.Publish{Topic: "t1", QoS: 1})
+	b.enqueue(&paho.Publish{Topic: "t2"})
+	b.enqueue(&paho.Publish{Topic: "t3", QoS: 1, Payload: []byte("too large to be persisted")})
+	b.enqueue(&paho.Publish{Topic: "t4", QoS: 1})
+	// t1 is dropped for the buffer is full, t2 and t3 are not persisted
+	assert.Equal(t, 1, store.count(bridgeBufferType))
+
+	restored := newBridge(nil, newTestBridgeConfig("127.0.0.1:1883"))
+	require.NoError(t, restored.restore(store, 16))
+	require.Len(t, restored.buffer, 1)
+	assert.Equal(t, "t4", restored.buffer[0].Topic)
+	assert.Equal(t, byte(1), restored.buffer[0].QoS)
+
+	restored.enqueue(&paho.Publish{Topic: "t5", QoS: 1})
+	assert.Equal(t, uint64(5), restored.buffer[1].Seq)
+	restored.remove(restored.buffer[0])
+	values, err := store.List(bridgeBufferType)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	assert.Contains(t, string(values[0]), `"Topic":"t5"`)
+}
+
+func TestBridgeForward(t *testing.T) {
+	_, remoteAddr := newTestServer(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	localAddr := l.Addr().String()
+	require.NoError(t, l.Close())
+	local := NewMqttServer(10, "tcp://"+localAddr, false, 1)
+	local.SetBridge(newTestBridgeConfig(remoteAddr))
+	require.NoError(t, local.Run())
+	t.Cleanup(func() {
+		_ = local.bridge.conn.Disconnect(context.Background())
+		local.server.Close()
+	})
+
+	remoteReceived := make(chan *paho.Publish, 1)
+	remoteClient := newTestClient5(t, remoteAddr, remoteReceived)
+	localReceived := make(chan *paho.Publish, 1)
+	localClient := newTestClient5(t, localAddr, localReceived)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = remoteClient.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: "edge/node1/devices/+/telemetry", QoS: 1}},
+	})
+	require.NoError(t, err)
+	_, err = localClient.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: "devices/+/command", QoS: 1}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, local.bridge.conn.AwaitConnection(ctx))
+
+	// out: internal broker -> remote broker
+	require.NoError(t, local.Publish("devices/dev1/telemetry", []byte("21.5"), nil))
+	select {
+	case pk := <-remoteReceived:
+		assert.Equal(t, "edge/node1/devices/dev1/telemetry", pk.Topic)
+		assert.Equal(t, []byte("21.5"), pk.Payload)
+	case <-time.After(10 * time.Second):
+		t.Fatal("message is not forwarded to remote broker")
+	}
+
+	// in: remote broker -> internal broker
+	assert.Eventually(t, func() bool {
+		_, err := remoteClient.Publish(ctx, &paho.Publish{
+			QoS:     1,
+			Topic:   "edge/node1/devices/dev1/command",
+			Payload: []byte("on"),
+		})
+		require.NoError(t, err)
+		select {
+		case pk := <-localReceived:
+			assert.Equal(t, "devices/dev1/command", pk.Topic)
+			assert.Equal(t, []byte("on"), pk.Payload)
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestBridgeForwardRestored(t *testing.T) {
+	_, remoteAddr := newTestServer(t)
+	remoteReceived := make(chan *paho.Publish, 1)
+	remoteClient := newTestClient5(t, remoteAddr, remoteReceived)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := remoteClient.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: "edge/node1/devices/+/telemetry", QoS: 1}},
+	})
+	require.NoError(t, err)
+
+	// the message buffered before the restart
+	store := newFakeMqttStore()
+	factory := MqttStoreFactory
+	MqttStoreFactory = func() MqttStore { return store }
+	t.Cleanup(func() { MqttStoreFactory = factory })
+	buffered := newBridge(nil, newTestBridgeConfig(remoteAddr))
+	require.NoError(t, buffered.restore(store, 16))
+	buffered.enqueue(&paho.Publish{QoS: 1, Topic: "edge/node1/devices/dev1/telemetry", Payload: []byte("21.5")})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	localAddr := l.Addr().String()
+	require.NoError(t, l.Close())
+	local := NewMqttServer(10, "tcp://"+localAddr, false, 1)
+	local.SetPersistence(newTestPersistence())
+	local.SetBridge(newTestBridgeConfig(remoteAddr))
+	require.NoError(t, local.Run())
+	t.Cleanup(func() {
+		_ = local.bridge.conn.Disconnect(context.Background())
+		local.server.Close()
+	})
+
+	// the restored message is forwarded without any new message published
+	select {
+	case pk := <-remoteReceived:
+		assert.Equal(t, "edge/node1/devices/dev1/telemetry", pk.Topic)
+		assert.Equal(t, []byte("21.5"), pk.Payload)
+	case <-time.After(10 * time.Second):
+		t.Fatal("restored message is not forwarded to remote broker")
+	}
+	assert.Eventually(t, func() bool {
+		return store.count(bridgeBufferType) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
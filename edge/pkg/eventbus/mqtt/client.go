@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/eclipse/paho.golang/autopaho"
 	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"k8s.io/klog/v2"
 
@@ -68,6 +69,10 @@ type Client struct {
 	Password    string
 	PubCli      MQTT.Client
 	SubCli      MQTT.Client
+	// ProtocolVersion is the MQTT protocol version used to connect to the external broker
+	ProtocolVersion uint8
+	// Conn is the connection to the external broker when MQTT 5 is used
+	Conn *autopaho.ConnectionManager
 }
 
 // AccessInfo that deliver between edge-hub and cloud-hub
@@ -120,7 +125,7 @@ func onSubConnect(client MQTT.Client) {
 func OnSubMessageReceived(_ MQTT.Client, msg MQTT.Message) {
 	klog.Infof("OnSubMessageReceived receive msg from topic: %s", msg.Topic())
 
-	NewMessageMux().Dispatch(msg.Topic(), msg.Payload())
+	NewMessageMux().Dispatch(msg.Topic(), msg.Payload(), nil)
 }
 
 // InitSubClient init sub client
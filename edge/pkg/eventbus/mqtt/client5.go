@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/eventbus/common/util"
+)
+
+// reasonCodeFailure is the lowest reason code of MQTT 5 that indicates a failure
+const reasonCodeFailure byte = 0x80
+
+// InitClient5 init the MQTT 5 client connecting to the external broker.
+// Unlike MQTT 3.1.1, a single connection is used for both publishing and subscribing,
+// and the connection is re-established automatically after it is lost.
+func (mq *Client) InitClient5() {
+	serverURL, err := url.Parse(mq.MQTTUrl)
+	if err != nil {
+		klog.Errorf("failed to parse mqtt url %s: %v", mq.MQTTUrl, err)
+		return
+	}
+
+	// if SubClientID is NOT set, we need to generate it by ourselves.
+	if mq.SubClientID == "" {
+		timeStr := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
+		right := len(timeStr)
+		if right > 10 {
+			right = 10
+		}
+		mq.SubClientID = fmt.Sprintf("hub-client-%s", timeStr[0:right])
+	}
+
+	tlsConfig, err := util.ClientTLSConfig()
+	if err != nil {
+		klog.Errorf("failed to set TLS configuration for MQTT client: %v", err)
+		return
+	}
+
+	cfg := autopaho.ClientConfig{
+		ServerUrls:                    []*url.URL{serverURL},
+		TlsCfg:                        tlsConfig,
+		KeepAlive:                     30,
+		CleanStartOnInitialConnection: true,
+		ConnectRetryDelay:             util.LoopConnectPeriord,
+		OnConnectionUp:                onConnectionUp,
+		OnConnectError: func(err error) {
+			klog.Errorf("client %s connect to external mqtt broker failed: %v", mq.SubClientID, err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: mq.SubClientID,
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+				onPublishReceived,
+			},
+			OnServerDisconnect: func(d *paho.Disconnect) {
+				klog.Errorf("external mqtt broker disconnected with reason code %d", d.ReasonCode)
+			},
+			OnClientError: func(err error) {
+				klog.Errorf("mqtt client %s error: %v", mq.SubClientID, err)
+			},
+		},
+	}
+	if mq.Username != "" {
+		cfg.ConnectUsername = mq.Username
+		cfg.ConnectPassword = []byte(mq.Password)
+	}
+
+	conn, err := autopaho.NewConnection(context.Background(), cfg)
+	if err != nil {
+		klog.Errorf("failed to create connection to external mqtt broker: %v", err)
+		return
+	}
+	mq.Conn = conn
+	if err := conn.AwaitConnection(context.Background()); err != nil {
+		klog.Errorf("client %s connect to external mqtt broker failed: %v", mq.SubClientID, err)
+		return
+	}
+	klog.Info("finish hub-client with MQTT 5")
+}
+
+func onConnectionUp(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+	topics := SubTopics
+	dbTopics, err := EventBusServiceFactory().QueryAllTopics()
+	if err != nil {
+		klog.Errorf("list edge-hub-cli-topics failed: %v", err)
+	} else if dbTopics != nil {
+		topics = append(append([]string{}, SubTopics...), *dbTopics...)
+	}
+	for _, t := range topics {
+		if err := subscribe5(cm, t); err != nil {
+			klog.Errorf("edge-hub-cli subscribe topic: %s, %v", t, err)
+			return
+		}
+		klog.Infof("edge-hub-cli subscribe topic to %s", t)
+	}
+}
+
+func onPublishReceived(pr paho.PublishReceived) (bool, error) {
+	klog.Infof("OnPublishReceived receive msg from topic: %s", pr.Packet.Topic)
+
+	NewMessageMux().Dispatch(pr.Packet.Topic, pr.Packet.Payload, fromPahoProperties(pr.Packet.Properties))
+	return true, nil
+}
+
+// Publish5 publishes the message with the properties to the external broker
+func (mq *Client) Publish5(topic string, payload []byte, properties map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), util.TokenWaitTime)
+	defer cancel()
+
+	resp, err := mq.Conn.Publish(ctx, &paho.Publish{
+		QoS:        1,
+		Topic:      topic,
+		Payload:    payload,
+		Properties: toPahoProperties(properties),
+	})
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("publish to topic %s failed with reason code %d: %v", topic, resp.ReasonCode, err)
+		}
+		return fmt.Errorf("publish to topic %s failed: %v", topic, err)
+	}
+	return nil
+}
+
+// Subscribe5 subscribes the topic from the external broker
+func (mq *Client) Subscribe5(topic string) error {
+	return subscribe5(mq.Conn, topic)
+}
+
+// Unsubscribe5 unsubscribes the topic from the external broker
+func (mq *Client) Unsubscribe5(topic string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), util.TokenWaitTime)
+	defer cancel()
+
+	resp, err := mq.Conn.Unsubscribe(ctx, &paho.Unsubscribe{Topics: []string{topic}})
+	if err != nil {
+		if resp != nil && len(resp.Reasons) > 0 {
+			return fmt.Errorf("unsubscribe topic %s failed with reason code %d: %v", topic, resp.Reasons[0], err)
+		}
+		return fmt.Errorf("unsubscribe topic %s failed: %v", topic, err)
+	}
+	return nil
+}
+
+func subscribe5(cm *autopaho.ConnectionManager, topic string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), util.TokenWaitTime)
+	defer cancel()
+
+	resp, err := cm.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: topic, QoS: 1}},
+	})
+	if resp != nil && len(resp.Reasons) > 0 && resp.Reasons[0] >= reasonCodeFailure {
+		return fmt.Errorf("subscribe topic %s failed with reason code %d", topic, resp.Reasons[0])
+	}
+	return err
+}
@@ -260,7 +260,7 @@ func TestOnSubMessageReceived(t *testing.T) {
 		payload: []byte("test payload"),
 	}
 
-	patchHandler := gomonkey.ApplyFunc(handleUploadTopic, func(topic string, payload []byte) {
+	patchHandler := gomonkey.ApplyFunc(handleUploadTopic, func(topic string, payload []byte, properties map[string]string) {
 		assert.Equal(t, "SYS/dis/upload_records", topic)
 		assert.Equal(t, []byte("test payload"), payload)
 	})
@@ -278,7 +278,7 @@ func TestOnSubMessageReceivedDeviceTwin(t *testing.T) {
 		payload: []byte("test twin payload"),
 	}
 
-	patchHandler := gomonkey.ApplyFunc(handleDeviceTwin, func(topic string, payload []byte) {
+	patchHandler := gomonkey.ApplyFunc(handleDeviceTwin, func(topic string, payload []byte, properties map[string]string) {
 		assert.Equal(t, "$hw/events/device/test-device/twin/update", topic)
 		assert.Equal(t, []byte("test twin payload"), payload)
 	})
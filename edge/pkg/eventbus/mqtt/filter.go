@@ -9,7 +9,9 @@ import (
 	"k8s.io/klog/v2"
 )
 
-type HandlerFunc func(topic string, payload []byte)
+// HandlerFunc handles the message of topic, the properties are the user properties and
+// publish properties of MQTT 5 message, they are nil for MQTT 3.1.1.
+type HandlerFunc func(topic string, payload []byte, properties map[string]string)
 
 // MessageMuxEntry message mux entry
 type MessageMuxEntry struct {
@@ -115,7 +117,7 @@ func NewPattern(resource string) *MessagePattern {
 }
 
 // NewEntry new entry
-func NewEntry(pattern *MessagePattern, handle func(topic string, payload []byte)) *MessageMuxEntry {
+func NewEntry(pattern *MessagePattern, handle HandlerFunc) *MessageMuxEntry {
 	return &MessageMuxEntry{
 		pattern:     pattern,
 		handlerFunc: handle,
@@ -129,22 +131,23 @@ func (pattern *MessagePattern) Match(topic string) bool {
 
 // Entry mux := NewMessageMux(ctx, module)
 // mux.Entry(NewPattern(res).Op(opr), handle))
-func (mux *MessageMux) Entry(pattern *MessagePattern, handle func(topic string, payload []byte)) *MessageMux {
+func (mux *MessageMux) Entry(pattern *MessagePattern, handle HandlerFunc) *MessageMux {
 	entry := NewEntry(pattern, handle)
 	mux.muxEntry = append(mux.muxEntry, entry)
 	return mux
 }
 
-func (mux *MessageMux) Dispatch(topic string, payload []byte) {
+// Dispatch dispatches the message of topic to the handler whose pattern is matched
+func (mux *MessageMux) Dispatch(topic string, payload []byte, properties map[string]string) {
 	for _, entry := range mux.muxEntry {
 		matched := entry.pattern.Match(topic)
 		if !matched {
 			continue
 		}
-		entry.handlerFunc(topic, payload)
+		entry.handlerFunc(topic, payload, properties)
 		return
 	}
-	handleUploadTopic(topic, payload)
+	handleUploadTopic(topic, payload, properties)
 }
 
 // RegisterMsgHandler register handler for message if topic is matched in pattern
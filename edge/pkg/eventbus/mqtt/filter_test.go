@@ -3,7 +3,7 @@ package mqtt
 import (
 	"testing"
 
-	"github.com/256dpi/gomqtt/packet"
+	"github.com/mochi-mqtt/server/v2/packets"
 
 	"github.com/kubeedge/beehive/pkg/common"
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
@@ -24,13 +24,11 @@ func init() {
 }
 
 func TestDispatch(t *testing.T) {
-	msg := packet.Message{
-		Topic:   "$hw/events/device/sampledevice/twin/test",
-		Payload: []byte("device sample"),
-		QOS:     0,
-		Retain:  false,
+	msg := packets.Packet{
+		TopicName: "$hw/events/device/sampledevice/twin/test",
+		Payload:   []byte("device sample"),
 	}
-	NewMessageMux().Dispatch(msg.Topic, msg.Payload)
+	NewMessageMux().Dispatch(msg.TopicName, msg.Payload, nil)
 	message, _ := beehiveContext.Receive(modules.DeviceTwinModuleName)
 
 	t.Run("SuccessDispatchDeviceTwinMsg", func(t *testing.T) {
@@ -50,7 +50,7 @@ func TestHandleDeviceTwin(t *testing.T) {
 			messagepkg.OperationResponse).
 		FillBody(string(payload))
 
-	handleDeviceTwin(topic, payload)
+	handleDeviceTwin(topic, payload, nil)
 
 	received, err := beehiveContext.Receive(modules.TwinGroup)
 	assert.NoError(err)
@@ -83,7 +83,7 @@ func TestHandleUploadTopic(t *testing.T) {
 			beehiveModel.UploadOperation).
 		FillBody(string(payload))
 
-	handleUploadTopic(topic, payload)
+	handleUploadTopic(topic, payload, nil)
 
 	received, err := beehiveContext.Receive(modules.HubGroup)
 	assert.NoError(err)
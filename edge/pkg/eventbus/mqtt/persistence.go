@@ -0,0 +1,354 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"bytes"
+	"sync"
+
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/storage"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+)
+
+// MqttStore is the store of sessions, subscriptions, retained and inflight messages of internal broker
+type MqttStore interface {
+	Set(key, typ string, value []byte) error
+	Delete(key string) error
+	DeleteByPrefix(prefix string) error
+	List(typ string) ([][]byte, error)
+}
+
+// MqttStoreFactory is a function variable that can be mocked in tests
+var MqttStoreFactory = func() MqttStore {
+	return dbclient.NewMqttStoreService()
+}
+
+func clientKey(clientID string) string {
+	return storage.ClientKey + "_" + clientID
+}
+
+func subscriptionKey(clientID, filter string) string {
+	return storage.SubscriptionKey + "_" + clientID + ":" + filter
+}
+
+func retainedKey(topic string) string {
+	return storage.RetainedKey + "_" + topic
+}
+
+func inflightKey(clientID string, pk packets.Packet) string {
+	return storage.InflightKey + "_" + clientID + ":" + pk.FormatID()
+}
+
+// persistenceHook persists the sessions, subscriptions, retained and inflight QoS 1/2 messages
+// of internal broker, which are restored by the broker when it is started again.
+type persistenceHook struct {
+	mqttserver.HookBase
+	config *v1alpha2.EventBusMqttPersistence
+	store  MqttStore
+
+	sync.Mutex
+	// retained is the topics of retained messages persisted
+	retained map[string]struct{}
+}
+
+func newPersistenceHook(config *v1alpha2.EventBusMqttPersistence) *persistenceHook {
+	return &persistenceHook{
+		config:   config,
+		store:    MqttStoreFactory(),
+		retained: make(map[string]struct{}),
+	}
+}
+
+func (h *persistenceHook) ID() string {
+	return "kubeedge-persistence"
+}
+
+func (h *persistenceHook) Provides(b byte) bool {
+	return bytes.Contains([]byte{
+		mqttserver.OnSessionEstablished,
+		mqttserver.OnDisconnect,
+		mqttserver.OnSubscribed,
+		mqttserver.OnUnsubscribed,
+		mqttserver.OnRetainMessage,
+		mqttserver.OnWillSent,
+		mqttserver.OnQosPublish,
+		mqttserver.OnQosComplete,
+		mqttserver.OnQosDropped,
+		mqttserver.OnClientExpired,
+		mqttserver.OnRetainedExpired,
+		mqttserver.StoredClients,
+		mqttserver.StoredSubscriptions,
+		mqttserver.StoredInflightMessages,
+		mqttserver.StoredRetainedMessages,
+	}, []byte{b})
+}
+
+func (h *persistenceHook) OnSessionEstablished(cl *mqttserver.Client, _ packets.Packet) {
+	h.updateClient(cl)
+}
+
+func (h *persistenceHook) OnWillSent(cl *mqttserver.Client, _ packets.Packet) {
+	h.updateClient(cl)
+}
+
+func (h *persistenceHook) updateClient(cl *mqttserver.Client) {
+	props := cl.Properties.Props.Copy(false)
+	in := &storage.Client{
+		ID:              cl.ID,
+		T:               storage.ClientKey,
+		Remote:          cl.Net.Remote,
+		Listener:        cl.Net.Listener,
+		Username:        cl.Properties.Username,
+		Clean:           cl.Properties.Clean,
+		ProtocolVersion: cl.Properties.ProtocolVersion,
+		Properties: storage.ClientProperties{
+			SessionExpiryInterval:     props.SessionExpiryInterval,
+			SessionExpiryIntervalFlag: props.SessionExpiryIntervalFlag,
+			AuthenticationMethod:      props.AuthenticationMethod,
+			AuthenticationData:        props.AuthenticationData,
+			RequestProblemInfo:        props.RequestProblemInfo,
+			RequestProblemInfoFlag:    props.RequestProblemInfoFlag,
+			RequestResponseInfo:       props.RequestResponseInfo,
+			ReceiveMaximum:            props.ReceiveMaximum,
+			TopicAliasMaximum:         props.TopicAliasMaximum,
+			User:                      props.User,
+			MaximumPacketSize:         props.MaximumPacketSize,
+		},
+		Will: storage.ClientWill(cl.Properties.Will),
+	}
+	h.set(clientKey(cl.ID), storage.ClientKey, in)
+}
+
+func (h *persistenceHook) OnDisconnect(cl *mqttserver.Client, _ error, expire bool) {
+	// the session is kept until it expires, and it is taken over by the new connection
+	// of the same client id
+	if !expire || cl.StopCause() == packets.ErrSessionTakenOver {
+		return
+	}
+	h.deleteClient(cl.ID)
+}
+
+func (h *persistenceHook) OnClientExpired(cl *mqttserver.Client) {
+	h.deleteClient(cl.ID)
+}
+
+// deleteClient deletes the session of client with its subscriptions and inflight messages
+func (h *persistenceHook) deleteClient(clientID string) {
+	h.delete(clientKey(clientID))
+	if err := h.store.DeleteByPrefix(storage.SubscriptionKey + "_" + clientID + ":"); err != nil {
+		klog.Errorf("failed to delete mqtt subscriptions of client %s: %v", clientID, err)
+	}
+	if err := h.store.DeleteByPrefix(storage.InflightKey + "_" + clientID + ":"); err != nil {
+		klog.Errorf("failed to delete mqtt inflight messages of client %s: %v", clientID, err)
+	}
+}
+
+func (h *persistenceHook) OnSubscribed(cl *mqttserver.Client, pk packets.Packet, reasonCodes []byte) {
+	for i, f := range pk.Filters {
+		if i >= len(reasonCodes) || reasonCodes[i] >= packets.ErrUnspecifiedError.Code {
+			continue
+		}
+		in := &storage.Subscription{
+			ID:                subscriptionKey(cl.ID, f.Filter),
+			T:                 storage.SubscriptionKey,
+			Client:            cl.ID,
+			Qos:               reasonCodes[i],
+			Filter:            f.Filter,
+			Identifier:        f.Identifier,
+			NoLocal:           f.NoLocal,
+			RetainHandling:    f.RetainHandling,
+			RetainAsPublished: f.RetainAsPublished,
+		}
+		h.set(in.ID, storage.SubscriptionKey, in)
+	}
+}
+
+func (h *persistenceHook) OnUnsubscribed(cl *mqttserver.Client, pk packets.Packet) {
+	for _, f := range pk.Filters {
+		h.delete(subscriptionKey(cl.ID, f.Filter))
+	}
+}
+
+func (h *persistenceHook) OnRetainMessage(cl *mqttserver.Client, pk packets.Packet, r int64) {
+	h.Lock()
+	defer h.Unlock()
+	if r == -1 {
+		h.delete(retainedKey(pk.TopicName))
+		delete(h.retained, pk.TopicName)
+		return
+	}
+	if _, ok := h.retained[pk.TopicName]; !ok && len(h.retained) >= int(h.config.MaxRetainedMessages) {
+		klog.Warningf("retained message of topic %s is not persisted for the limit %d", pk.TopicName, h.config.MaxRetainedMessages)
+		return
+	}
+	if h.tooLarge(pk) {
+		return
+	}
+	h.set(retainedKey(pk.TopicName), storage.RetainedKey, toStorageMessage(retainedKey(pk.TopicName), storage.RetainedKey, cl, pk, 0))
+	h.retained[pk.TopicName] = struct{}{}
+}
+
+func (h *persistenceHook) OnRetainedExpired(topic string) {
+	h.Lock()
+	defer h.Unlock()
+	h.delete(retainedKey(topic))
+	delete(h.retained, topic)
+}
+
+func (h *persistenceHook) OnQosPublish(cl *mqttserver.Client, pk packets.Packet, sent int64, _ int) {
+	if h.tooLarge(pk) {
+		return
+	}
+	key := inflightKey(cl.ID, pk)
+	h.set(key, storage.InflightKey, toStorageMessage(key, storage.InflightKey, cl, pk, sent))
+}
+
+func (h *persistenceHook) OnQosComplete(cl *mqttserver.Client, pk packets.Packet) {
+	h.delete(inflightKey(cl.ID, pk))
+}
+
+func (h *persistenceHook) OnQosDropped(cl *mqttserver.Client, pk packets.Packet) {
+	h.OnQosComplete(cl, pk)
+}
+
+func (h *persistenceHook) StoredClients() ([]storage.Client, error) {
+	var v []storage.Client
+	err := h.list(storage.ClientKey, func(value []byte) error {
+		obj := storage.Client{}
+		if err := obj.UnmarshalBinary(value); err != nil {
+			return err
+		}
+		v = append(v, obj)
+		return nil
+	})
+	return v, err
+}
+
+func (h *persistenceHook) StoredSubscriptions() ([]storage.Subscription, error) {
+	var v []storage.Subscription
+	err := h.list(storage.SubscriptionKey, func(value []byte) error {
+		obj := storage.Subscription{}
+		if err := obj.UnmarshalBinary(value); err != nil {
+			return err
+		}
+		v = append(v, obj)
+		return nil
+	})
+	return v, err
+}
+
+func (h *persistenceHook) StoredInflightMessages() ([]storage.Message, error) {
+	var v []storage.Message
+	err := h.list(storage.InflightKey, func(value []byte) error {
+		obj := storage.Message{}
+		if err := obj.UnmarshalBinary(value); err != nil {
+			return err
+		}
+		v = append(v, obj)
+		return nil
+	})
+	return v, err
+}
+
+func (h *persistenceHook) StoredRetainedMessages() ([]storage.Message, error) {
+	h.Lock()
+	defer h.Unlock()
+	var v []storage.Message
+	err := h.list(storage.RetainedKey, func(value []byte) error {
+		obj := storage.Message{}
+		if err := obj.UnmarshalBinary(value); err != nil {
+			return err
+		}
+		v = append(v, obj)
+		h.retained[obj.TopicName] = struct{}{}
+		return nil
+	})
+	return v, err
+}
+
+// tooLarge returns whether the payload of message exceeds the size limit of persistence
+func (h *persistenceHook) tooLarge(pk packets.Packet) bool {
+	if len(pk.Payload) <= int(h.config.MaxMessageSize) {
+		return false
+	}
+	klog.Warningf("message of topic %s is not persisted for the payload size %d exceeds the limit %d",
+		pk.TopicName, len(pk.Payload), h.config.MaxMessageSize)
+	return true
+}
+
+func (h *persistenceHook) set(key, typ string, v storage.Serializable) {
+	data, err := v.MarshalBinary()
+	if err != nil {
+		klog.Errorf("failed to marshal mqtt store %s: %v", key, err)
+		return
+	}
+	if err := h.store.Set(key, typ, data); err != nil {
+		klog.Errorf("failed to persist mqtt store %s: %v", key, err)
+	}
+}
+
+func (h *persistenceHook) delete(key string) {
+	if err := h.store.Delete(key); err != nil {
+		klog.Errorf("failed to delete mqtt store %s: %v", key, err)
+	}
+}
+
+// list visits the values of the type, the values that cannot be decoded are skipped
+func (h *persistenceHook) list(typ string, visit func([]byte) error) error {
+	values, err := h.store.List(typ)
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		if err := visit(value); err != nil {
+			klog.Errorf("failed to decode mqtt store of type %s: %v", typ, err)
+		}
+	}
+	return nil
+}
+
+func toStorageMessage(key, typ string, cl *mqttserver.Client, pk packets.Packet, sent int64) *storage.Message {
+	props := pk.Properties.Copy(false)
+	return &storage.Message{
+		ID:          key,
+		T:           typ,
+		Client:      cl.ID,
+		Origin:      pk.Origin,
+		FixedHeader: pk.FixedHeader,
+		PacketID:    pk.PacketID,
+		TopicName:   pk.TopicName,
+		Payload:     pk.Payload,
+		Sent:        sent,
+		Created:     pk.Created,
+		Properties: storage.MessageProperties{
+			PayloadFormat:          props.PayloadFormat,
+			PayloadFormatFlag:      props.PayloadFormatFlag,
+			MessageExpiryInterval:  props.MessageExpiryInterval,
+			ContentType:            props.ContentType,
+			ResponseTopic:          props.ResponseTopic,
+			CorrelationData:        props.CorrelationData,
+			SubscriptionIdentifier: props.SubscriptionIdentifier,
+			TopicAlias:             props.TopicAlias,
+			User:                   props.User,
+		},
+	}
+}
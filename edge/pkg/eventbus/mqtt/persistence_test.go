@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/mochi-mqtt/server/v2/hooks/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// fakeMqttStore keeps the values in memory instead of edge database
+type fakeMqttStore struct {
+	sync.Mutex
+	types  map[string]string
+	values map[string][]byte
+}
+
+func newFakeMqttStore() *fakeMqttStore {
+	return &fakeMqttStore{types: make(map[string]string), values: make(map[string][]byte)}
+}
+
+func (s *fakeMqttStore) Set(key, typ string, value []byte) error {
+	s.Lock()
+	defer s.Unlock()
+	s.types[key] = typ
+	s.values[key] = value
+	return nil
+}
+
+func (s *fakeMqttStore) Delete(key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.types, key)
+	delete(s.values, key)
+	return nil
+}
+
+func (s *fakeMqttStore) DeleteByPrefix(prefix string) error {
+	s.Lock()
+	defer s.Unlock()
+	for key := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.types, key)
+			delete(s.values, key)
+		}
+	}
+	return nil
+}
+
+func (s *fakeMqttStore) List(typ string) ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	var values [][]byte
+	for key, t := range s.types {
+		if t == typ {
+			values = append(values, s.values[key])
+		}
+	}
+	return values, nil
+}
+
+func (s *fakeMqttStore) count(typ string) int {
+	values, _ := s.List(typ)
+	return len(values)
+}
+
+func newTestPersistence() *v1alpha2.EventBusMqttPersistence {
+	return &v1alpha2.EventBusMqttPersistence{
+		Enable:                true,
+		MaxInflightMessages:   16,
+		MaxRetainedMessages:   1,
+		MaxMessageSize:        16,
+		SessionExpiryInterval: 3600,
+	}
+}
+
+func startPersistentServer(t *testing.T, addr string, config *v1alpha2.EventBusMqttPersistence) *Server {
+	server := NewMqttServer(10, "tcp://"+addr, true, 1)
+	server.SetPersistence(config)
+	require.NoError(t, server.Run())
+	return server
+}
+
+// connectPersistent connects a client which keeps its session after disconnected
+func connectPersistent(t *testing.T, addr, clientID string, received chan *paho.Publish) (*paho.Client, *paho.Connack) {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	c := paho.NewClient(paho.ClientConfig{
+		ClientID: clientID,
+		Conn:     conn,
+		OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+			func(pr paho.PublishReceived) (bool, error) {
+				received <- pr.Packet
+				return true, nil
+			},
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	expiry := uint32(3600)
+	ca, err := c.Connect(ctx, &paho.Connect{
+		ClientID:   clientID,
+		KeepAlive:  30,
+		CleanStart: false,
+		Properties: &paho.ConnectProperties{SessionExpiryInterval: &expiry},
+	})
+	require.NoError(t, err)
+	return c, ca
+}
+
+func TestPersistenceRestart(t *testing.T) {
+	store := newFakeMqttStore()
+	factory := MqttStoreFactory
+	MqttStoreFactory = func() MqttStore { return store }
+	t.Cleanup(func() { MqttStoreFactory = factory })
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	server := startPersistentServer(t, addr, newTestPersistence())
+	c, _ := connectPersistent(t, addr, "mapper-1", make(chan *paho.Publish, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = c.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: "devices/+/command", QoS: 1}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Disconnect(&paho.Disconnect{}))
+	assert.Eventually(t, func() bool {
+		cl, ok := server.server.Clients.Get("mapper-1")
+		return ok && cl.Closed()
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// the command is queued for the offline client
+	require.NoError(t, server.Publish("devices/dev1/command", []byte("on"), nil))
+	// the message exceeding the size limit is not persisted
+	require.NoError(t, server.Publish("devices/dev2/command", []byte(strings.Repeat("x", 32)), nil))
+	// only one retained message is persisted
+	require.NoError(t, server.Publish("devices/dev1/state", []byte("on"), nil))
+	require.NoError(t, server.Publish("devices/dev2/state", []byte("off"), nil))
+	assert.Eventually(t, func() bool {
+		return store.count(storage.InflightKey) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, store.count(storage.ClientKey))
+	assert.Equal(t, 1, store.count(storage.SubscriptionKey))
+	assert.Equal(t, 1, store.count(storage.RetainedKey))
+	require.NoError(t, server.server.Close())
+
+	server = startPersistentServer(t, addr, newTestPersistence())
+	t.Cleanup(func() { server.server.Close() })
+	received := make(chan *paho.Publish, 1)
+	c, ca := connectPersistent(t, addr, "mapper-1", received)
+	defer func() { _ = c.Disconnect(&paho.Disconnect{}) }()
+	assert.True(t, ca.SessionPresent)
+
+	select {
+	case pk := <-received:
+		assert.Equal(t, "devices/dev1/command", pk.Topic)
+		assert.Equal(t, []byte("on"), pk.Payload)
+	case <-time.After(10 * time.Second):
+		t.Fatal("persisted message is not received after restart")
+	}
+	assert.Eventually(t, func() bool {
+		return store.count(storage.InflightKey) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
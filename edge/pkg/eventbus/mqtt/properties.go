@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"k8s.io/klog/v2"
+)
+
+// The properties of beehive message header which carry the MQTT 5 publish properties, the
+// other properties of header are the user properties of MQTT 5 message. The messages of
+// MQTT 3.1.1 have no properties.
+const (
+	// PropertyResponseTopic is the topic which the response of a request message is published to
+	PropertyResponseTopic = "mqtt-response-topic"
+	// PropertyCorrelationData is the base64 encoded data to correlate the response with its request
+	PropertyCorrelationData = "mqtt-correlation-data"
+	// PropertyContentType is the content type of payload
+	PropertyContentType = "mqtt-content-type"
+	// PropertyMessageExpiry is the lifetime of message in seconds
+	PropertyMessageExpiry = "mqtt-message-expiry"
+
+	propertyPrefix = "mqtt-"
+)
+
+// fromPacketProperties returns the header properties of message published to internal broker
+func fromPacketProperties(pk packets.Packet) map[string]string {
+	if pk.ProtocolVersion != 5 {
+		return nil
+	}
+	p := pk.Properties
+	user := make([][2]string, 0, len(p.User))
+	for _, u := range p.User {
+		user = append(user, [2]string{u.Key, u.Val})
+	}
+	return buildProperties(p.ResponseTopic, p.CorrelationData, p.ContentType, p.MessageExpiryInterval, user)
+}
+
+// toPacketProperties returns the publish properties of message published to internal broker
+func toPacketProperties(properties map[string]string) packets.Properties {
+	var p packets.Properties
+	if len(properties) == 0 {
+		return p
+	}
+	p.ResponseTopic = properties[PropertyResponseTopic]
+	p.CorrelationData = correlationData(properties)
+	p.ContentType = properties[PropertyContentType]
+	p.MessageExpiryInterval = messageExpiry(properties)
+	for k, v := range properties {
+		if !strings.HasPrefix(k, propertyPrefix) {
+			p.User = append(p.User, packets.UserProperty{Key: k, Val: v})
+		}
+	}
+	return p
+}
+
+// fromPahoProperties returns the header properties of message received from external broker
+func fromPahoProperties(p *paho.PublishProperties) map[string]string {
+	if p == nil {
+		return nil
+	}
+	var expiry uint32
+	if p.MessageExpiry != nil {
+		expiry = *p.MessageExpiry
+	}
+	user := make([][2]string, 0, len(p.User))
+	for _, u := range p.User {
+		user = append(user, [2]string{u.Key, u.Value})
+	}
+	return buildProperties(p.ResponseTopic, p.CorrelationData, p.ContentType, expiry, user)
+}
+
+// toPahoProperties returns the publish properties of message published to external broker
+func toPahoProperties(properties map[string]string) *paho.PublishProperties {
+	if len(properties) == 0 {
+		return nil
+	}
+	p := &paho.PublishProperties{
+		ResponseTopic:   properties[PropertyResponseTopic],
+		CorrelationData: correlationData(properties),
+		ContentType:     properties[PropertyContentType],
+	}
+	if expiry := messageExpiry(properties); expiry > 0 {
+		p.MessageExpiry = &expiry
+	}
+	for k, v := range properties {
+		if !strings.HasPrefix(k, propertyPrefix) {
+			p.User.Add(k, v)
+		}
+	}
+	return p
+}
+
+// buildProperties returns the header properties of MQTT 5 message. The user properties with
+// the same key are merged into the last one, and the ones with the reserved prefix are dropped.
+func buildProperties(responseTopic string, correlation []byte, contentType string, expiry uint32, user [][2]string) map[string]string {
+	properties := make(map[string]string, len(user)+4)
+	for _, u := range user {
+		if strings.HasPrefix(u[0], propertyPrefix) {
+			klog.V(4).Infof("user property %s is dropped for the reserved prefix %s", u[0], propertyPrefix)
+			continue
+		}
+		properties[u[0]] = u[1]
+	}
+	if responseTopic != "" {
+		properties[PropertyResponseTopic] = responseTopic
+	}
+	if len(correlation) > 0 {
+		properties[PropertyCorrelationData] = base64.StdEncoding.EncodeToString(correlation)
+	}
+	if contentType != "" {
+		properties[PropertyContentType] = contentType
+	}
+	if expiry > 0 {
+		properties[PropertyMessageExpiry] = strconv.FormatUint(uint64(expiry), 10)
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
+
+func correlationData(properties map[string]string) []byte {
+	v, ok := properties[PropertyCorrelationData]
+	if !ok {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		klog.Warningf("invalid property %s: %v", PropertyCorrelationData, err)
+		return nil
+	}
+	return data
+}
+
+func messageExpiry(properties map[string]string) uint32 {
+	v, ok := properties[PropertyMessageExpiry]
+	if !ok {
+		return 0
+	}
+	expiry, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		klog.Warningf("invalid property %s: %v", PropertyMessageExpiry, err)
+		return 0
+	}
+	return uint32(expiry)
+}
+
+// RemainingExpiry returns the properties whose message expiry is reduced by the time elapsed
+// since the message was created at timestamp in milliseconds, and whether the message is
+// expired. The properties are returned as is if the message has no expiry.
+func RemainingExpiry(properties map[string]string, timestamp int64) (map[string]string, bool) {
+	expiry := messageExpiry(properties)
+	if expiry == 0 || timestamp <= 0 {
+		return properties, false
+	}
+	elapsed := time.Since(time.UnixMilli(timestamp)) / time.Second
+	if elapsed >= time.Duration(expiry) {
+		return properties, true
+	}
+	if elapsed <= 0 {
+		return properties, false
+	}
+	remaining := make(map[string]string, len(properties))
+	for k, v := range properties {
+		remaining[k] = v
+	}
+	remaining[PropertyMessageExpiry] = strconv.FormatUint(uint64(expiry)-uint64(elapsed), 10)
+	return remaining, false
+}
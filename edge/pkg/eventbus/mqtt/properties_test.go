@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacketProperties(t *testing.T) {
+	properties := map[string]string{
+		PropertyResponseTopic:   "devices/response",
+		PropertyCorrelationData: "cmVxLTE=",
+		PropertyContentType:     "application/json",
+		PropertyMessageExpiry:   "60",
+		"tenant":                "t1",
+	}
+
+	pk := packets.Packet{
+		ProtocolVersion: 5,
+		Properties:      toPacketProperties(properties),
+	}
+	assert.Equal(t, []byte("req-1"), pk.Properties.CorrelationData)
+	assert.Equal(t, uint32(60), pk.Properties.MessageExpiryInterval)
+	assert.Equal(t, []packets.UserProperty{{Key: "tenant", Val: "t1"}}, pk.Properties.User)
+	assert.Equal(t, properties, fromPacketProperties(pk))
+
+	pk.ProtocolVersion = 4
+	assert.Nil(t, fromPacketProperties(pk))
+}
+
+func TestPahoProperties(t *testing.T) {
+	properties := map[string]string{
+		PropertyResponseTopic: "devices/response",
+		PropertyMessageExpiry: "30",
+		"tenant":              "t1",
+	}
+
+	p := toPahoProperties(properties)
+	assert.Equal(t, uint32(30), *p.MessageExpiry)
+	assert.Equal(t, "t1", p.User.Get("tenant"))
+	assert.Equal(t, properties, fromPahoProperties(p))
+
+	assert.Nil(t, toPahoProperties(nil))
+	assert.Nil(t, fromPahoProperties(nil))
+}
+
+func TestBuildPropertiesReservedPrefix(t *testing.T) {
+	properties := buildProperties("", nil, "", 0, [][2]string{
+		{"mqtt-response-topic", "hijacked"},
+		{"tenant", "t1"},
+	})
+	assert.Equal(t, map[string]string{"tenant": "t1"}, properties)
+
+	assert.Nil(t, buildProperties("", nil, "", 0, nil))
+}
+
+func TestRemainingExpiry(t *testing.T) {
+	cases := []struct {
+		name        string
+		properties  map[string]string
+		timestamp   int64
+		wantExpiry  string
+		wantExpired bool
+	}{
+		{
+			name:       "no expiry",
+			properties: map[string]string{"tenant": "t1"},
+			timestamp:  time.Now().Add(-time.Hour).UnixMilli(),
+		},
+		{
+			name:       "not elapsed",
+			properties: map[string]string{PropertyMessageExpiry: "60"},
+			timestamp:  time.Now().UnixMilli(),
+			wantExpiry: "60",
+		},
+		{
+			name:       "partly elapsed",
+			properties: map[string]string{PropertyMessageExpiry: "60"},
+			timestamp:  time.Now().Add(-20 * time.Second).UnixMilli(),
+			wantExpiry: "40",
+		},
+		{
+			name:        "expired",
+			properties:  map[string]string{PropertyMessageExpiry: "60"},
+			timestamp:   time.Now().Add(-time.Minute).UnixMilli(),
+			wantExpiry:  "60",
+			wantExpired: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			properties, expired := RemainingExpiry(c.properties, c.timestamp)
+			assert.Equal(t, c.wantExpired, expired)
+			assert.Equal(t, c.wantExpiry, properties[PropertyMessageExpiry])
+		})
+	}
+	// the properties of caller are not modified
+	assert.Equal(t, "60", cases[2].properties[PropertyMessageExpiry])
+}
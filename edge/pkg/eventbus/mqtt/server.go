@@ -17,28 +17,41 @@ limitations under the License.
 package mqtt
 
 import (
-	"github.com/256dpi/gomqtt/broker"
-	"github.com/256dpi/gomqtt/packet"
-	"github.com/256dpi/gomqtt/topic"
-	"github.com/256dpi/gomqtt/transport"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/go-logr/logr"
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
 )
 
-// Server serve as an internal mqtt broker.
+// internalSubscriber is the subscriber of the topics whose messages are dispatched to the
+// edge modules and cloud
+const internalSubscriber = "kubeedge-eventbus"
+
+// Server serve as an internal mqtt broker, it accepts both MQTT 3.1.1 and MQTT 5 clients.
 type Server struct {
 	// Internal mqtt url
 	url string
 
-	// Used to save and match topic, it is thread-safe tree.
-	tree *topic.Tree
+	// Used to save and match topic, it is thread-safe index.
+	topics *mqttserver.TopicsIndex
 
 	// A server accepts incoming connections.
-	server transport.Server
+	server *mqttserver.Server
 
-	// A MemoryBackend stores all in memory.
-	backend *broker.MemoryBackend
+	// The inline client publishes the messages from the edge modules and cloud.
+	inline *mqttserver.Client
 
 	// Qos has three types: QOSAtMostOnce, QOSAtLeastOnce, QOSExactlyOnce.
 	// now we use QOSAtMostOnce as default.
@@ -50,8 +63,20 @@ type Server struct {
 	// If set retain to false, then will do nothing.
 	retain bool
 
-	// A sessionQueueSize will default to 100
+	// A sessionQueueSize will default to 100, it is the max number of messages
+	// pending to be written to a client.
 	sessionQueueSize int
+
+	// auth is the authentication and topic ACLs of clients, all clients are allowed
+	// if it is not enabled.
+	auth *v1alpha2.EventBusMqttAuth
+
+	// persistence is the persistence of sessions, retained and inflight messages in edge
+	// database, they are kept in memory only if it is not enabled.
+	persistence *v1alpha2.EventBusMqttPersistence
+
+	// bridge forwards the messages between internal broker and a remote broker if it is enabled.
+	bridge *Bridge
 }
 
 // NewMqttServer create an internal mqtt server.
@@ -59,49 +84,157 @@ func NewMqttServer(sqz int, url string, retain bool, qos int) *Server {
 	return &Server{
 		sessionQueueSize: sqz,
 		url:              url,
-		tree:             topic.NewTree(),
+		topics:           mqttserver.NewTopicsIndex(),
 		retain:           retain,
 		qos:              qos,
 	}
 }
 
+// SetAuth sets the authentication and topic ACLs of clients, it must be called before Run.
+func (m *Server) SetAuth(config *v1alpha2.EventBusMqttAuth) {
+	m.auth = config
+}
+
+// SetPersistence sets the persistence of sessions and messages, it must be called before Run.
+func (m *Server) SetPersistence(config *v1alpha2.EventBusMqttPersistence) {
+	m.persistence = config
+}
+
+// SetBridge sets the bridge to a remote broker, it must be called before Run.
+func (m *Server) SetBridge(config *v1alpha2.EventBusMqttBridge) {
+	if config != nil && config.Enable {
+		m.bridge = newBridge(m, config)
+	}
+}
+
 // Run launch a server and accept connections.
 func (m *Server) Run() error {
-	var err error
-
-	m.server, err = transport.Launch(m.url)
+	tlsConfig, err := serverTLSConfig(m.auth)
+	if err != nil {
+		klog.Errorf("Launch transport failed %v", err)
+		return err
+	}
+	listener, err := newListener(m.url, tlsConfig)
 	if err != nil {
 		klog.Errorf("Launch transport failed %v", err)
 		return err
 	}
 
-	m.backend = broker.NewMemoryBackend()
-	m.backend.SessionQueueSize = m.sessionQueueSize
-
-	m.backend.Logger = func(event broker.LogEvent, client *broker.Client, pkt packet.Generic, msg *packet.Message, err error) {
-		if event == broker.MessagePublished {
-			if len(m.tree.Match(msg.Topic)) > 0 {
-				m.onSubscribe(msg)
+	capabilities := mqttserver.NewDefaultServerCapabilities()
+	if m.sessionQueueSize > 0 {
+		capabilities.MaximumClientWritesPending = int32(m.sessionQueueSize)
+	}
+	if m.persistence != nil && m.persistence.Enable {
+		capabilities.MaximumInflight = uint16(m.persistence.MaxInflightMessages)
+		capabilities.MaximumSessionExpiryInterval = uint32(m.persistence.SessionExpiryInterval)
+	}
+	m.server = mqttserver.New(&mqttserver.Options{
+		Capabilities: capabilities,
+		InlineClient: true,
+		Logger:       slog.New(logr.ToSlogHandler(klog.Background().WithName("mqtt"))),
+	})
+	m.inline, _ = m.server.Clients.Get(mqttserver.InlineClientId)
+	if m.auth != nil && m.auth.Enable {
+		authHook, err := newAuthHook(m.auth)
+		if err != nil {
+			return err
+		}
+		if err := m.server.AddHook(authHook, nil); err != nil {
+			return err
+		}
+	} else if err := m.server.AddHook(new(auth.AllowHook), nil); err != nil {
+		return err
+	}
+	if m.persistence != nil && m.persistence.Enable {
+		if err := m.server.AddHook(newPersistenceHook(m.persistence), nil); err != nil {
+			return err
+		}
+	}
+	if err := m.server.AddHook(&dispatchHook{server: m}, nil); err != nil {
+		return err
+	}
+	if m.bridge != nil {
+		if m.persistence != nil && m.persistence.Enable {
+			if err := m.bridge.restore(MqttStoreFactory(), m.persistence.MaxMessageSize); err != nil {
+				return err
 			}
 		}
+		if err := m.server.AddHook(&bridgeHook{bridge: m.bridge}, nil); err != nil {
+			return err
+		}
 	}
+	if err := m.server.AddListener(listener); err != nil {
+		klog.Errorf("Launch transport failed %v", err)
+		return err
+	}
+	if err := m.server.Serve(); err != nil {
+		return err
+	}
+	if m.bridge != nil {
+		return m.bridge.Start(context.Background())
+	}
+	return nil
+}
 
-	engine := broker.NewEngine(m.backend)
-	engine.Accept(m.server)
+// newListener returns the listener of url, the schemes tcp, mqtt and ws are supported.
+func newListener(rawURL string, tlsConfig *tls.Config) (listeners.Listener, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	config := listeners.Config{ID: "internal", Address: u.Host, TLSConfig: tlsConfig}
+	switch strings.ToLower(u.Scheme) {
+	case "tcp", "mqtt":
+		config.Type = listeners.TypeTCP
+		return listeners.NewTCP(config), nil
+	case "ws":
+		// the websocket listener hides the tls connection, the client certificate can't be
+		// used as the identity of client
+		if tlsConfig != nil && tlsConfig.ClientCAs != nil {
+			return nil, fmt.Errorf("client certificate authentication is not supported by mqtt url %s, use tcp instead", rawURL)
+		}
+		config.Type = listeners.TypeWS
+		return listeners.NewWebsocket(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q of mqtt url %s", u.Scheme, rawURL)
+	}
+}
 
-	return nil
+// dispatchHook dispatches the messages published by clients to the edge modules and cloud if
+// their topics are subscribed internally.
+type dispatchHook struct {
+	mqttserver.HookBase
+	server *Server
+}
+
+func (h *dispatchHook) ID() string {
+	return "kubeedge-dispatch"
 }
 
-// onSubscribe will be called if the topic is matched in topic tree.
-func (m *Server) onSubscribe(msg *packet.Message) {
-	klog.Infof("OnSubscribe receive msg from topic: %s", msg.Topic)
-	NewMessageMux().Dispatch(msg.Topic, msg.Payload)
+func (h *dispatchHook) Provides(b byte) bool {
+	return b == mqttserver.OnPublished
+}
+
+func (h *dispatchHook) OnPublished(cl *mqttserver.Client, pk packets.Packet) {
+	// the messages published by inline client come from the edge modules and cloud
+	if cl == h.server.inline {
+		return
+	}
+	if len(h.server.topics.Subscribers(pk.TopicName).Subscriptions) > 0 {
+		h.server.onSubscribe(pk)
+	}
+}
+
+// onSubscribe will be called if the topic is matched in topic index.
+func (m *Server) onSubscribe(pk packets.Packet) {
+	klog.Infof("OnSubscribe receive msg from topic: %s", pk.TopicName)
+	NewMessageMux().Dispatch(pk.TopicName, pk.Payload, fromPacketProperties(pk))
 }
 
 // InitInternalTopics sets internal topics to server by default.
 func (m *Server) InitInternalTopics() {
 	for _, v := range SubTopics {
-		m.tree.Set(v, packet.Subscription{Topic: v, QOS: packet.QOS(m.qos)})
+		m.SetTopic(v)
 		klog.Infof("Subscribe internal topic to %s", v)
 	}
 	topics, err := dbclient.NewEventBusService().QueryAllTopics()
@@ -114,33 +247,50 @@ func (m *Server) InitInternalTopics() {
 		return
 	}
 	for _, t := range *topics {
-		m.tree.Set(t, packet.Subscription{Topic: t, QOS: packet.QOS(m.qos)})
+		m.SetTopic(t)
 		klog.Infof("Subscribe internal topic to %s", t)
 	}
 }
 
 // SetTopic set the topic to internal mqtt broker.
 func (m *Server) SetTopic(topic string) {
-	m.tree.Set(topic, packet.Subscription{Topic: topic, QOS: packet.QOSAtMostOnce})
+	m.topics.Subscribe(internalSubscriber, packets.Subscription{Filter: topic, Qos: byte(m.qos)})
 }
 
 // RemoveTopic remove the topic from internal mqtt broker.
 func (m *Server) RemoveTopic(topic string) {
-	m.tree.Remove(topic, packet.Subscription{Topic: topic, QOS: packet.QOSAtMostOnce})
+	m.topics.Unsubscribe(topic, internalSubscriber)
 }
 
-// Publish will dispatch topic msg to its subscribers directly.
-func (m *Server) Publish(topic string, payload []byte) {
-	client := &broker.Client{}
-
-	msg := &packet.Message{
-		Topic:   topic,
-		Retain:  m.retain,
-		Payload: payload,
-		QOS:     packet.QOS(m.qos),
+// Publish will dispatch topic msg to its subscribers directly, the properties are carried
+// to MQTT 5 subscribers.
+func (m *Server) Publish(topic string, payload []byte, properties map[string]string) error {
+	pk := packets.Packet{
+		FixedHeader: packets.FixedHeader{
+			Type:   packets.Publish,
+			Qos:    byte(m.qos),
+			Retain: m.retain,
+		},
+		TopicName:  topic,
+		Payload:    payload,
+		Properties: toPacketProperties(properties),
 	}
-	if err := m.backend.Publish(client, msg, nil); err != nil {
-		// TODO: handle error
+	if err := m.injectPublish(m.inline, pk); err != nil {
 		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// injectPublish publishes the packet of an inline client to the subscribers, the packets
+// of QoS > 0 are given a packet id of the client as those from the network clients.
+func (m *Server) injectPublish(cl *mqttserver.Client, pk packets.Packet) error {
+	if pk.FixedHeader.Qos > 0 {
+		id, err := cl.NextPacketID()
+		if err != nil {
+			return fmt.Errorf("failed to allocate packet id for client %s: %v", cl.ID, err)
+		}
+		pk.PacketID = uint16(id)
 	}
+	return m.server.InjectPacket(cl, pk)
 }
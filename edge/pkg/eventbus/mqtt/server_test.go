@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	server := NewMqttServer(10, "tcp://"+addr, false, 1)
+	require.NoError(t, server.Run())
+	t.Cleanup(func() { server.server.Close() })
+	return server, addr
+}
+
+func newTestClient5(t *testing.T, addr string, received chan *paho.Publish) *autopaho.ConnectionManager {
+	u, err := url.Parse("mqtt://" + addr)
+	require.NoError(t, err)
+	cfg := autopaho.ClientConfig{
+		ServerUrls:                    []*url.URL{u},
+		KeepAlive:                     30,
+		CleanStartOnInitialConnection: true,
+		ClientConfig: paho.ClientConfig{
+			ClientID: fmt.Sprintf("test-%d", time.Now().UnixNano()),
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+				func(pr paho.PublishReceived) (bool, error) {
+					received <- pr.Packet
+					return true, nil
+				},
+			},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cm, err := autopaho.NewConnection(context.Background(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, cm.AwaitConnection(ctx))
+	t.Cleanup(func() { _ = cm.Disconnect(context.Background()) })
+	return cm
+}
+
+func TestServerDispatchProperties(t *testing.T) {
+	server, addr := newTestServer(t)
+	topic := "$hw/events/device/dev1/twin/update"
+	server.SetTopic(topic)
+	cm := newTestClient5(t, addr, make(chan *paho.Publish, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	expiry := uint32(60)
+	_, err := cm.Publish(ctx, &paho.Publish{
+		QoS:     1,
+		Topic:   topic,
+		Payload: []byte("{}"),
+		Properties: &paho.PublishProperties{
+			ResponseTopic: "dev1/response",
+			MessageExpiry: &expiry,
+			User:          paho.UserProperties{{Key: "tenant", Value: "t1"}},
+		},
+	})
+	require.NoError(t, err)
+
+	message, err := beehiveContext.Receive(modules.DeviceTwinModuleName)
+	require.NoError(t, err)
+	properties := message.GetProperties()
+	assert.Equal(t, "dev1/response", properties[PropertyResponseTopic])
+	assert.Equal(t, "t1", properties["tenant"])
+	assert.NotEmpty(t, properties[PropertyMessageExpiry])
+}
+
+func TestServerPublishProperties(t *testing.T) {
+	server, addr := newTestServer(t)
+	received := make(chan *paho.Publish, 1)
+	cm := newTestClient5(t, addr, received)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := cm.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: "$share/group/devices/+/command", QoS: 1}},
+	})
+	require.NoError(t, err)
+
+	err = server.Publish("devices/dev1/command", []byte("on"), map[string]string{
+		PropertyCorrelationData: "cmVxLTE=",
+		"tenant":                "t1",
+	})
+	require.NoError(t, err)
+
+	select {
+	case pk := <-received:
+		assert.Equal(t, []byte("on"), pk.Payload)
+		assert.Equal(t, []byte("req-1"), pk.Properties.CorrelationData)
+		assert.Equal(t, "t1", pk.Properties.User.Get("tenant"))
+	case <-time.After(10 * time.Second):
+		t.Fatal("message is not received by the shared subscription")
+	}
+}
+
+// packetIDHook records the packet ids of the published messages
+type packetIDHook struct {
+	mqttserver.HookBase
+	ids chan uint16
+}
+
+func (*packetIDHook) ID() string { return "packet-id" }
+
+func (*packetIDHook) Provides(b byte) bool { return b == mqttserver.OnPublish }
+
+func (h *packetIDHook) OnPublish(_ *mqttserver.Client, pk packets.Packet) (packets.Packet, error) {
+	h.ids <- pk.PacketID
+	return pk, nil
+}
+
+func TestServerPublishPacketID(t *testing.T) {
+	server, _ := newTestServer(t)
+	hook := &packetIDHook{ids: make(chan uint16, 3)}
+	require.NoError(t, server.server.AddHook(hook, nil))
+
+	// the messages of QoS 1 are given distinct packet ids
+	require.NoError(t, server.Publish("devices/dev1/command", []byte("on"), nil))
+	require.NoError(t, server.Publish("devices/dev1/command", []byte("off"), nil))
+	first, second := <-hook.ids, <-hook.ids
+	assert.NotZero(t, first)
+	assert.NotZero(t, second)
+	assert.NotEqual(t, first, second)
+
+	// the messages of QoS 0 do not have packet id
+	server.qos = 0
+	require.NoError(t, server.Publish("devices/dev1/command", []byte("on"), nil))
+	assert.Zero(t, <-hook.ids)
+}
+
+func TestNewListenerClientCertificate(t *testing.T) {
+	mtls := &tls.Config{ClientCAs: x509.NewCertPool(), ClientAuth: tls.RequireAndVerifyClientCert}
+
+	_, err := newListener("ws://127.0.0.1:1884", mtls)
+	assert.Error(t, err)
+	_, err = newListener("tcp://127.0.0.1:1884", mtls)
+	assert.NoError(t, err)
+	_, err = newListener("ws://127.0.0.1:1884", &tls.Config{})
+	assert.NoError(t, err)
+}
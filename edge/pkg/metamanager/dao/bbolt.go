@@ -0,0 +1,529 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"gorm.io/gorm/schema"
+)
+
+// boltStorage is the Storage of bbolt. Each table is a bucket, whose keys are the primary
+// keys of records and values are the records in json. The records are filtered in memory,
+// except that the records are iterated in the order of primary key, so that the queries
+// ordered by the primary key, e.g. the pagination of meta_v2, stop early.
+type boltStorage struct {
+	db *bolt.DB
+	// mu guards db, which is reopened after compacted
+	mu *sync.RWMutex
+	// tx is set if the storage is used in a transaction
+	tx      *bolt.Tx
+	schemas *sync.Map
+}
+
+func newBoltStorage(dataSource string) (Storage, error) {
+	db, err := openBolt(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	return &boltStorage{db: db, mu: &sync.RWMutex{}, schemas: &sync.Map{}}, nil
+}
+
+func openBolt(dataSource string) (*bolt.DB, error) {
+	db, err := bolt.Open(dataSource, 0600, &bolt.Options{
+		// fail fast if the database is locked by other process, e.g. edgecore is running
+		Timeout: 10 * time.Second,
+		// the freelist is rebuilt when the database is opened instead of being synced to
+		// disk in each write, which reduces the writes to flash
+		NoFreelistSync: true,
+		FreelistType:   bolt.FreelistMapType,
+	})
+	if errors.Is(err, bolt.ErrInvalid) || errors.Is(err, bolt.ErrChecksum) || errors.Is(err, bolt.ErrVersionMismatch) {
+		return nil, fmt.Errorf("%w: failed to open bbolt database %s: %v", ErrCorrupted, dataSource, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bbolt database %s: %v", dataSource, err)
+	}
+	return db, nil
+}
+
+func (s *boltStorage) view(fn func(tx *bolt.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.View(fn)
+}
+
+func (s *boltStorage) update(fn func(tx *bolt.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.Update(fn)
+}
+
+func (s *boltStorage) schema(model interface{}) (*schema.Schema, error) {
+	return schema.Parse(newModel(model), s.schemas, schema.NamingStrategy{})
+}
+
+func (s *boltStorage) bucket(tx *bolt.Tx, sch *schema.Schema) (*bolt.Bucket, error) {
+	b := tx.Bucket([]byte(sch.Table))
+	if b == nil {
+		return nil, fmt.Errorf("table %s does not exist", sch.Table)
+	}
+	return b, nil
+}
+
+func (s *boltStorage) Migrate(models ...interface{}) error {
+	return s.update(func(tx *bolt.Tx) error {
+		for _, m := range models {
+			sch, err := s.schema(m)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucketIfNotExists([]byte(sch.Table)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStorage) HasTable(model interface{}) bool {
+	sch, err := s.schema(model)
+	if err != nil {
+		return false
+	}
+	exists := false
+	_ = s.view(func(tx *bolt.Tx) error {
+		exists = tx.Bucket([]byte(sch.Table)) != nil
+		return nil
+	})
+	return exists
+}
+
+func (s *boltStorage) Create(record interface{}) error {
+	return s.put(record, false)
+}
+
+func (s *boltStorage) Save(record interface{}) error {
+	return s.put(record, true)
+}
+
+func (s *boltStorage) put(record interface{}, replace bool) error {
+	sch, err := s.schema(record)
+	if err != nil {
+		return err
+	}
+	rv := reflect.Indirect(reflect.ValueOf(record))
+	return s.update(func(tx *bolt.Tx) error {
+		b, err := s.bucket(tx, sch)
+		if err != nil {
+			return err
+		}
+		if f := sch.PrioritizedPrimaryField; f != nil && f.AutoIncrement {
+			v, zero := f.ValueOf(context.Background(), rv)
+			if zero {
+				id, err := b.NextSequence()
+				if err != nil {
+					return err
+				}
+				if err := f.Set(context.Background(), rv, id); err != nil {
+					return err
+				}
+			} else if id, ok := toFloat(v); ok && uint64(id) > b.Sequence() {
+				// keep the sequence after the ids set explicitly, e.g. the copied records
+				if err := b.SetSequence(uint64(id)); err != nil {
+					return err
+				}
+			}
+		}
+		key := primaryKey(sch, rv)
+		if !replace && b.Get(key) != nil {
+			return ErrDuplicatedKey
+		}
+		data, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return err
+		}
+		return b.Put(key, data)
+	})
+}
+
+func (s *boltStorage) Update(model interface{}, values map[string]interface{}, conds ...Condition) (int64, error) {
+	sch, err := s.schema(model)
+	if err != nil {
+		return 0, err
+	}
+	var affected int64
+	err = s.update(func(tx *bolt.Tx) error {
+		b, err := s.bucket(tx, sch)
+		if err != nil {
+			return err
+		}
+		records, keys, err := s.scan(b, sch, Query{Conditions: conds})
+		if err != nil {
+			return err
+		}
+		for i := 0; i < records.Len(); i++ {
+			rv := records.Index(i)
+			for column, value := range values {
+				f := sch.LookUpField(column)
+				if f == nil {
+					return fmt.Errorf("column %s does not exist in table %s", column, sch.Table)
+				}
+				if err := f.Set(context.Background(), rv, value); err != nil {
+					return err
+				}
+			}
+			data, err := json.Marshal(rv.Interface())
+			if err != nil {
+				return err
+			}
+			// the primary key may be updated
+			if err := b.Delete(keys[i]); err != nil {
+				return err
+			}
+			if err := b.Put(primaryKey(sch, rv), data); err != nil {
+				return err
+			}
+		}
+		affected = int64(records.Len())
+		return nil
+	})
+	return affected, err
+}
+
+func (s *boltStorage) Delete(model interface{}, conds ...Condition) (int64, error) {
+	sch, err := s.schema(model)
+	if err != nil {
+		return 0, err
+	}
+	var affected int64
+	err = s.update(func(tx *bolt.Tx) error {
+		b, err := s.bucket(tx, sch)
+		if err != nil {
+			return err
+		}
+		_, keys, err := s.scan(b, sch, Query{Conditions: conds})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+		}
+		affected = int64(len(keys))
+		return nil
+	})
+	return affected, err
+}
+
+func (s *boltStorage) Find(dest interface{}, query Query) error {
+	sch, err := s.schema(dest)
+	if err != nil {
+		return err
+	}
+	return s.view(func(tx *bolt.Tx) error {
+		b, err := s.bucket(tx, sch)
+		if err != nil {
+			return err
+		}
+		records, _, err := s.scan(b, sch, query)
+		if err != nil {
+			return err
+		}
+		reflect.ValueOf(dest).Elem().Set(records)
+		return nil
+	})
+}
+
+func (s *boltStorage) First(dest interface{}, query Query) error {
+	query.Limit = 1
+	records := reflect.New(reflect.SliceOf(reflect.TypeOf(dest).Elem()))
+	if err := s.Find(records.Interface(), query); err != nil {
+		return err
+	}
+	if records.Elem().Len() == 0 {
+		return ErrRecordNotFound
+	}
+	reflect.ValueOf(dest).Elem().Set(records.Elem().Index(0))
+	return nil
+}
+
+func (s *boltStorage) Count(model interface{}, conds ...Condition) (int64, error) {
+	sch, err := s.schema(model)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = s.view(func(tx *bolt.Tx) error {
+		b, err := s.bucket(tx, sch)
+		if err != nil {
+			return err
+		}
+		_, keys, err := s.scan(b, sch, Query{Conditions: conds})
+		count = int64(len(keys))
+		return err
+	})
+	return count, err
+}
+
+func (s *boltStorage) Transaction(fn func(tx Storage) error) error {
+	return s.update(func(tx *bolt.Tx) error {
+		if s.tx != nil {
+			return fn(s)
+		}
+		return fn(&boltStorage{db: s.db, mu: s.mu, tx: tx, schemas: s.schemas})
+	})
+}
+
+func (s *boltStorage) Check() error {
+	return s.view(func(tx *bolt.Tx) error {
+		var errs []string
+		for err := range tx.Check() {
+			errs = append(errs, err.Error())
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%w: %s", ErrCorrupted, strings.Join(errs, "; "))
+		}
+		return nil
+	})
+}
+
+// Compact copies the database into a new file, which replaces the database then.
+// The storage is blocked during the compaction.
+func (s *boltStorage) Compact() error {
+	if s.tx != nil {
+		return errors.New("database can not be compacted in a transaction")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.db.Path()
+	compacted := path + ".compact"
+	if err := os.RemoveAll(compacted); err != nil {
+		return err
+	}
+	dst, err := openBolt(compacted)
+	if err != nil {
+		return err
+	}
+	// commit every 64MiB to bound the memory used
+	if err := bolt.Compact(dst, s.db, 64<<20); err != nil {
+		dst.Close()
+		os.Remove(compacted)
+		return fmt.Errorf("failed to compact bbolt database %s: %v", path, err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(compacted)
+		return err
+	}
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	renameErr := os.Rename(compacted, path)
+	// the database is reopened even if the compacted one fails to replace it
+	db, err := openBolt(path)
+	if err != nil {
+		return err
+	}
+	s.db = db
+	return renameErr
+}
+
+func (s *boltStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+// scan returns the records matched by query in a slice of model and the keys of them
+func (s *boltStorage) scan(b *bolt.Bucket, sch *schema.Schema, query Query) (reflect.Value, [][]byte, error) {
+	records := reflect.MakeSlice(reflect.SliceOf(sch.ModelType), 0, 0)
+	var keys [][]byte
+
+	// the records are iterated in the order of primary key, so the scan stops
+	// once the limit is reached if the records are ordered by primary key
+	orderByKey := query.OrderBy == "" ||
+		(len(sch.PrimaryFields) == 1 && sch.LookUpField(query.OrderBy) == sch.PrimaryFields[0])
+	c := b.Cursor()
+	next, k, v := c.Next, []byte(nil), []byte(nil)
+	if orderByKey && query.Desc {
+		next = c.Prev
+		k, v = c.Last()
+	} else {
+		k, v = c.First()
+	}
+	for ; k != nil; k, v = next() {
+		rv := reflect.New(sch.ModelType).Elem()
+		if err := json.Unmarshal(v, rv.Addr().Interface()); err != nil {
+			return records, nil, fmt.Errorf("failed to decode record %s of table %s: %v", k, sch.Table, err)
+		}
+		matched, err := match(sch, rv, query.Conditions)
+		if err != nil {
+			return records, nil, err
+		}
+		if !matched {
+			continue
+		}
+		records = reflect.Append(records, rv)
+		keys = append(keys, append([]byte(nil), k...))
+		if orderByKey && query.Limit > 0 && records.Len() == query.Limit {
+			break
+		}
+	}
+	if orderByKey {
+		return records, keys, nil
+	}
+
+	f := sch.LookUpField(query.OrderBy)
+	if f == nil {
+		return records, nil, fmt.Errorf("column %s does not exist in table %s", query.OrderBy, sch.Table)
+	}
+	index := make([]int, records.Len())
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(i, j int) bool {
+		a, _ := f.ValueOf(context.Background(), records.Index(index[i]))
+		b, _ := f.ValueOf(context.Background(), records.Index(index[j]))
+		if query.Desc {
+			return compare(b, a) < 0
+		}
+		return compare(a, b) < 0
+	})
+	if query.Limit > 0 && len(index) > query.Limit {
+		index = index[:query.Limit]
+	}
+	sorted := reflect.MakeSlice(records.Type(), 0, len(index))
+	sortedKeys := make([][]byte, 0, len(index))
+	for _, i := range index {
+		sorted = reflect.Append(sorted, records.Index(i))
+		sortedKeys = append(sortedKeys, keys[i])
+	}
+	return sorted, sortedKeys, nil
+}
+
+// primaryKey encodes the primary key of record, the integers are encoded in big endian
+// so that the keys are sorted by bbolt in the same order as the values
+func primaryKey(sch *schema.Schema, rv reflect.Value) []byte {
+	var parts [][]byte
+	for _, f := range sch.PrimaryFields {
+		v, _ := f.ValueOf(context.Background(), rv)
+		switch n := reflect.ValueOf(v); n.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			parts = append(parts, binary.BigEndian.AppendUint64(nil, uint64(n.Int())))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			parts = append(parts, binary.BigEndian.AppendUint64(nil, n.Uint()))
+		default:
+			parts = append(parts, []byte(fmt.Sprint(v)))
+		}
+	}
+	return bytes.Join(parts, []byte{0})
+}
+
+func match(sch *schema.Schema, rv reflect.Value, conds []Condition) (bool, error) {
+	for _, c := range conds {
+		f := sch.LookUpField(c.Column)
+		if f == nil {
+			return false, fmt.Errorf("column %s does not exist in table %s", c.Column, sch.Table)
+		}
+		v, _ := f.ValueOf(context.Background(), rv)
+		var matched bool
+		switch c.Operator {
+		case Equal:
+			matched = compare(v, c.Value) == 0
+		case NotEqual:
+			matched = compare(v, c.Value) != 0
+		case GreaterThan:
+			matched = compare(v, c.Value) > 0
+		case In, NotIn:
+			values := reflect.ValueOf(c.Value)
+			for i := 0; i < values.Len() && !matched; i++ {
+				matched = compare(v, values.Index(i).Interface()) == 0
+			}
+			if c.Operator == NotIn {
+				matched = !matched
+			}
+		case HasPrefix:
+			matched = strings.HasPrefix(fmt.Sprint(v), fmt.Sprint(c.Value))
+		case Contains:
+			matched = strings.Contains(fmt.Sprint(v), fmt.Sprint(c.Value))
+		default:
+			return false, fmt.Errorf("unsupported operator %s", c.Operator)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compare compares the numbers by value and the others by their strings
+func compare(a, b interface{}) int {
+	x, xok := toFloat(a)
+	y, yok := toFloat(b)
+	if xok && yok {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(toString(a), toString(b))
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func toString(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package dbclient
 
 import (
-	"gorm.io/gorm"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
@@ -25,45 +24,45 @@ import (
 )
 
 type DeviceService struct {
-	db *gorm.DB
+	db dao.Storage
 }
 
 func NewDeviceService() *DeviceService {
-	return &DeviceService{db: dao.GetDB()}
+	return &DeviceService{db: dao.GetStorage()}
 }
 
 // SaveDevice saves a device in a transaction
 func (s *DeviceService) SaveDevice(doc *models.Device) error {
-	tx := s.db.Begin()
-	if err := tx.Create(doc).Error; err != nil {
-		tx.Rollback()
-		klog.Errorf("Failed to insert Device data: %v", err)
-		return err
-	}
-	return tx.Commit().Error
+	return s.db.Transaction(func(tx dao.Storage) error {
+		if err := tx.Create(doc); err != nil {
+			klog.Errorf("Failed to insert Device data: %v", err)
+			return err
+		}
+		return nil
+	})
 }
 
 // DeleteDeviceByID deletes a device by ID in a transaction
 func (s *DeviceService) DeleteDeviceByID(id string) error {
-	tx := s.db.Begin()
-	if err := tx.Delete(&models.Device{}, "id = ?", id).Error; err != nil {
-		tx.Rollback()
-		klog.Errorf("Failed to delete Device data: %v", err)
-		return err
-	}
-	return tx.Commit().Error
+	return s.db.Transaction(func(tx dao.Storage) error {
+		if _, err := tx.Delete(&models.Device{}, dao.Eq("id", id)); err != nil {
+			klog.Errorf("Failed to delete Device data: %v", err)
+			return err
+		}
+		return nil
+	})
 }
 
 // UpdateDeviceField updates a single column
 func (s *DeviceService) UpdateDeviceField(deviceID string, col string, value interface{}) error {
-	err := s.db.Model(&models.Device{}).Where("id = ?", deviceID).Update(col, value).Error
+	_, err := s.db.Update(&models.Device{}, map[string]interface{}{col: value}, dao.Eq("id", deviceID))
 	klog.V(4).Infof("Update affected for field %s: %v", col, err)
 	return err
 }
 
 // UpdateDeviceFields updates multiple columns
 func (s *DeviceService) UpdateDeviceFields(deviceID string, cols map[string]interface{}) error {
-	err := s.db.Model(&models.Device{}).Where("id = ?", deviceID).Updates(cols).Error
+	_, err := s.db.Update(&models.Device{}, cols, dao.Eq("id", deviceID))
 	klog.V(4).Infof("Update affected multiple fields: %v", err)
 	return err
 }
@@ -71,7 +70,7 @@ func (s *DeviceService) UpdateDeviceFields(deviceID string, cols map[string]inte
 // QueryDevice queries devices with a condition
 func (s *DeviceService) QueryDevice(key string, condition string) ([]models.Device, error) {
 	var devices []models.Device
-	err := s.db.Where(key+" = ?", condition).Find(&devices).Error
+	err := s.db.Find(&devices, dao.Query{Conditions: []dao.Condition{dao.Eq(key, condition)}})
 	if err != nil {
 		klog.Errorf("Failed to query device: %v", err)
 		return nil, err
@@ -82,7 +81,7 @@ func (s *DeviceService) QueryDevice(key string, condition string) ([]models.Devi
 // QueryDeviceAll returns all devices
 func (s *DeviceService) QueryDeviceAll() ([]models.Device, error) {
 	var devices []models.Device
-	err := s.db.Find(&devices).Error
+	err := s.db.Find(&devices, dao.Query{})
 	if err != nil {
 		klog.Errorf("Failed to query all devices: %v", err)
 		return nil, err
@@ -102,64 +101,56 @@ func (s *DeviceService) UpdateDeviceMulti(updates []models.DeviceUpdate) error {
 
 // AddDeviceTrans handles transactional creation of devices, attributes, and twins
 func (s *DeviceService) AddDeviceTrans(adds []models.Device, addAttrs []models.DeviceAttr, addTwins []models.DeviceTwin) error {
-	tx := s.db.Begin()
-
-	for _, device := range adds {
-		if err := tx.Create(&device).Error; err != nil {
-			tx.Rollback()
-			klog.Errorf("Failed to save device: %v", err)
-			return err
+	return s.db.Transaction(func(tx dao.Storage) error {
+		for _, device := range adds {
+			if err := tx.Create(&device); err != nil {
+				klog.Errorf("Failed to save device: %v", err)
+				return err
+			}
 		}
-	}
 
-	for _, attr := range addAttrs {
-		if err := tx.Create(&attr).Error; err != nil {
-			tx.Rollback()
-			klog.Errorf("Failed to save device attr: %v", err)
-			return err
+		for _, attr := range addAttrs {
+			if err := tx.Create(&attr); err != nil {
+				klog.Errorf("Failed to save device attr: %v", err)
+				return err
+			}
 		}
-	}
 
-	for _, twin := range addTwins {
-		if err := tx.Create(&twin).Error; err != nil {
-			tx.Rollback()
-			klog.Errorf("Failed to save device twin: %v", err)
-			return err
+		for _, twin := range addTwins {
+			if err := tx.Create(&twin); err != nil {
+				klog.Errorf("Failed to save device twin: %v", err)
+				return err
+			}
 		}
-	}
-
-	return tx.Commit().Error
+		return nil
+	})
 }
 
 // DeleteDeviceTrans handles transactional deletion of devices, attributes, and twins
 func (s *DeviceService) DeleteDeviceTrans(deletes []string) error {
-	tx := s.db.Begin()
-
-	for _, id := range deletes {
-		if err := tx.Delete(&models.Device{}, "id = ?", id).Error; err != nil {
-			tx.Rollback()
-			return err
-		}
+	return s.db.Transaction(func(tx dao.Storage) error {
+		for _, id := range deletes {
+			if _, err := tx.Delete(&models.Device{}, dao.Eq("id", id)); err != nil {
+				return err
+			}
 
-		if err := tx.Where("deviceid = ?", id).Delete(&models.DeviceAttr{}).Error; err != nil {
-			tx.Rollback()
-			klog.Errorf("Failed to delete DeviceAttr by deviceID: %v", err)
-			return err
-		}
+			if _, err := tx.Delete(&models.DeviceAttr{}, dao.Eq("deviceid", id)); err != nil {
+				klog.Errorf("Failed to delete DeviceAttr by deviceID: %v", err)
+				return err
+			}
 
-		if err := tx.Where("deviceid = ?", id).Delete(&models.DeviceTwin{}).Error; err != nil {
-			tx.Rollback()
-			klog.Errorf("Failed to delete DeviceTwin by deviceID: %v", err)
-			return err
+			if _, err := tx.Delete(&models.DeviceTwin{}, dao.Eq("deviceid", id)); err != nil {
+				klog.Errorf("Failed to delete DeviceTwin by deviceID: %v", err)
+				return err
+			}
 		}
-	}
-
-	return tx.Commit().Error
+		return nil
+	})
 }
 
 func (s *DeviceService) SaveDeviceAttr(doc *models.DeviceAttr) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(doc).Error; err != nil {
+	return s.db.Transaction(func(tx dao.Storage) error {
+		if err := tx.Create(doc); err != nil {
 			klog.Errorf("Failed to insert DeviceAttr: %v", err)
 			return err
 		}
@@ -169,8 +160,8 @@ func (s *DeviceService) SaveDeviceAttr(doc *models.DeviceAttr) error {
 }
 
 func (s *DeviceService) DeleteDeviceAttr(deviceID string, name string) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("deviceid = ? AND name = ?", deviceID, name).Delete(&models.DeviceAttr{}).Error; err != nil {
+	return s.db.Transaction(func(tx dao.Storage) error {
+		if _, err := tx.Delete(&models.DeviceAttr{}, deviceNameConds(deviceID, name)...); err != nil {
 			klog.Errorf("Failed to delete DeviceAttr by deviceID and name: %v", err)
 			return err
 		}
@@ -179,20 +170,20 @@ func (s *DeviceService) DeleteDeviceAttr(deviceID string, name string) error {
 }
 
 func (s *DeviceService) UpdateDeviceAttrField(deviceID, name, col string, value interface{}) error {
-	result := s.db.Model(&models.DeviceAttr{}).Where("deviceid = ? AND name = ?", deviceID, name).Update(col, value)
-	klog.V(4).Infof("Update affected rows: %d, error: %v", result.RowsAffected, result.Error)
-	return result.Error
+	rows, err := s.db.Update(&models.DeviceAttr{}, map[string]interface{}{col: value}, deviceNameConds(deviceID, name)...)
+	klog.V(4).Infof("Update affected rows: %d, error: %v", rows, err)
+	return err
 }
 
 func (s *DeviceService) UpdateDeviceAttrFields(deviceID, name string, cols map[string]interface{}) error {
-	result := s.db.Model(&models.DeviceAttr{}).Where("deviceid = ? AND name = ?", deviceID, name).Updates(cols)
-	klog.V(4).Infof("Update affected rows: %d, error: %v", result.RowsAffected, result.Error)
-	return result.Error
+	rows, err := s.db.Update(&models.DeviceAttr{}, cols, deviceNameConds(deviceID, name)...)
+	klog.V(4).Infof("Update affected rows: %d, error: %v", rows, err)
+	return err
 }
 
 func (s *DeviceService) QueryDeviceAttr(key, condition string) (*[]models.DeviceAttr, error) {
 	var attrs []models.DeviceAttr
-	if err := s.db.Where(key+" = ?", condition).Find(&attrs).Error; err != nil {
+	if err := s.db.Find(&attrs, dao.Query{Conditions: []dao.Condition{dao.Eq(key, condition)}}); err != nil {
 		return nil, err
 	}
 	return &attrs, nil
@@ -208,21 +199,19 @@ func (s *DeviceService) UpdateDeviceAttrMulti(updates []models.DeviceAttrUpdate)
 }
 
 func (s *DeviceService) DeviceAttrTrans(adds []models.DeviceAttr, deletes []models.DeviceDelete, updates []models.DeviceAttrUpdate) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	return s.db.Transaction(func(tx dao.Storage) error {
 		for _, add := range adds {
-			if err := tx.Create(&add).Error; err != nil {
+			if err := tx.Create(&add); err != nil {
 				return err
 			}
 		}
 		for _, del := range deletes {
-			if err := tx.Where("deviceid = ? AND name = ?", del.DeviceID, del.Name).Delete(&models.DeviceAttr{}).Error; err != nil {
+			if _, err := tx.Delete(&models.DeviceAttr{}, deviceNameConds(del.DeviceID, del.Name)...); err != nil {
 				return err
 			}
 		}
 		for _, upd := range updates {
-			if err := tx.Model(&models.DeviceAttr{}).
-				Where("deviceid = ? AND name = ?", upd.DeviceID, upd.Name).
-				Updates(upd.Cols).Error; err != nil {
+			if _, err := tx.Update(&models.DeviceAttr{}, upd.Cols, deviceNameConds(upd.DeviceID, upd.Name)...); err != nil {
 				return err
 			}
 		}
@@ -231,8 +220,8 @@ func (s *DeviceService) DeviceAttrTrans(adds []models.DeviceAttr, deletes []mode
 }
 
 func (s *DeviceService) SaveDeviceTwin(doc *models.DeviceTwin) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(doc).Error; err != nil {
+	return s.db.Transaction(func(tx dao.Storage) error {
+		if err := tx.Create(doc); err != nil {
 			klog.Errorf("Failed to insert DeviceTwin: %v", err)
 			return err
 		}
@@ -242,8 +231,8 @@ func (s *DeviceService) SaveDeviceTwin(doc *models.DeviceTwin) error {
 }
 
 func (s *DeviceService) DeleteDeviceTwin(deviceID, name string) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("deviceid = ? AND name = ?", deviceID, name).Delete(&models.DeviceTwin{}).Error; err != nil {
+	return s.db.Transaction(func(tx dao.Storage) error {
+		if _, err := tx.Delete(&models.DeviceTwin{}, deviceNameConds(deviceID, name)...); err != nil {
 			klog.Errorf("Failed to delete DeviceTwin: %v", err)
 			return err
 		}
@@ -252,20 +241,20 @@ func (s *DeviceService) DeleteDeviceTwin(deviceID, name string) error {
 }
 
 func (s *DeviceService) UpdateDeviceTwinField(deviceID, name, col string, value interface{}) error {
-	result := s.db.Model(&models.DeviceTwin{}).Where("deviceid = ? AND name = ?", deviceID, name).Update(col, value)
-	klog.V(4).Infof("Update affected rows: %d, error: %v", result.RowsAffected, result.Error)
-	return result.Error
+	rows, err := s.db.Update(&models.DeviceTwin{}, map[string]interface{}{col: value}, deviceNameConds(deviceID, name)...)
+	klog.V(4).Infof("Update affected rows: %d, error: %v", rows, err)
+	return err
 }
 
 func (s *DeviceService) UpdateDeviceTwinFields(deviceID, name string, cols map[string]interface{}) error {
-	result := s.db.Model(&models.DeviceTwin{}).Where("deviceid = ? AND name = ?", deviceID, name).Updates(cols)
-	klog.V(4).Infof("Update affected rows: %d, error: %v", result.RowsAffected, result.Error)
-	return result.Error
+	rows, err := s.db.Update(&models.DeviceTwin{}, cols, deviceNameConds(deviceID, name)...)
+	klog.V(4).Infof("Update affected rows: %d, error: %v", rows, err)
+	return err
 }
 
 func (s *DeviceService) QueryDeviceTwin(key, condition string) (*[]models.DeviceTwin, error) {
 	var twins []models.DeviceTwin
-	if err := s.db.Where(key+" = ?", condition).Find(&twins).Error; err != nil {
+	if err := s.db.Find(&twins, dao.Query{Conditions: []dao.Condition{dao.Eq(key, condition)}}); err != nil {
 		return nil, err
 	}
 	return &twins, nil
@@ -281,24 +270,27 @@ func (s *DeviceService) UpdateDeviceTwinMulti(updates []models.DeviceTwinUpdate)
 }
 
 func (s *DeviceService) DeviceTwinTrans(adds []models.DeviceTwin, deletes []models.DeviceDelete, updates []models.DeviceTwinUpdate) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	return s.db.Transaction(func(tx dao.Storage) error {
 		for _, add := range adds {
-			if err := tx.Create(&add).Error; err != nil {
+			if err := tx.Create(&add); err != nil {
 				return err
 			}
 		}
 		for _, del := range deletes {
-			if err := tx.Where("deviceid = ? AND name = ?", del.DeviceID, del.Name).Delete(&models.DeviceTwin{}).Error; err != nil {
+			if _, err := tx.Delete(&models.DeviceTwin{}, deviceNameConds(del.DeviceID, del.Name)...); err != nil {
 				return err
 			}
 		}
 		for _, upd := range updates {
-			if err := tx.Model(&models.DeviceTwin{}).
-				Where("deviceid = ? AND name = ?", upd.DeviceID, upd.Name).
-				Updates(upd.Cols).Error; err != nil {
+			if _, err := tx.Update(&models.DeviceTwin{}, upd.Cols, deviceNameConds(upd.DeviceID, upd.Name)...); err != nil {
 				return err
 			}
 		}
 		return nil
 	})
 }
+
+// deviceNameConds matches the attr or twin of device by name
+func deviceNameConds(deviceID, name string) []dao.Condition {
+	return []dao.Condition{dao.Eq("deviceid", deviceID), dao.Eq("name", name)}
+}
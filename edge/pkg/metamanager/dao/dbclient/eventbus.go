@@ -19,7 +19,6 @@ package dbclient
 import (
 	"errors"
 
-	"gorm.io/gorm"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
@@ -27,17 +26,16 @@ import (
 )
 
 type EventBusService struct {
-	db *gorm.DB
+	db dao.Storage
 }
 
 func NewEventBusService() *EventBusService {
-	return &EventBusService{db: dao.GetDB()}
+	return &EventBusService{db: dao.GetStorage()}
 }
 
 // InsertTopics insert or replace topic into sub_topics
 func (s *EventBusService) InsertTopics(topic string) error {
-	// GORM upsert using SQLite syntax: INSERT OR REPLACE
-	err := s.db.Exec("INSERT OR REPLACE INTO sub_topics (topic) VALUES (?)", topic).Error
+	err := s.db.Save(&models.SubTopics{Topic: topic})
 	if err != nil {
 		klog.Errorf("Failed to insert or replace topic: %v", err)
 	}
@@ -46,19 +44,19 @@ func (s *EventBusService) InsertTopics(topic string) error {
 
 // DeleteTopicsByKey deletes a topic from sub_topics
 func (s *EventBusService) DeleteTopicsByKey(key string) error {
-	result := s.db.Delete(&models.SubTopics{}, "topic = ?", key)
-	if result.Error != nil {
-		klog.Errorf("Failed to delete topic: %v", result.Error)
-		return result.Error
+	rows, err := s.db.Delete(&models.SubTopics{}, dao.Eq("topic", key))
+	if err != nil {
+		klog.Errorf("Failed to delete topic: %v", err)
+		return err
 	}
-	klog.V(4).Infof("Delete affected Num: %d", result.RowsAffected)
+	klog.V(4).Infof("Delete affected Num: %d", rows)
 	return nil
 }
 
 // QueryAllTopics retrieves all topics from sub_topics
 func (s *EventBusService) QueryAllTopics() (*[]string, error) {
 	var entries []models.SubTopics
-	if err := s.db.Find(&entries).Error; err != nil {
+	if err := s.db.Find(&entries, dao.Query{}); err != nil {
 		klog.Errorf("Failed to query topics: %v", err)
 		return nil, err
 	}
@@ -71,3 +69,52 @@ func (s *EventBusService) QueryAllTopics() (*[]string, error) {
 	}
 	return &result, nil
 }
+
+type MqttStoreService struct {
+	db dao.Storage
+}
+
+func NewMqttStoreService() *MqttStoreService {
+	return &MqttStoreService{db: dao.GetStorage()}
+}
+
+// Set inserts or replaces the value of key into mqtt_store
+func (s *MqttStoreService) Set(key, typ string, value []byte) error {
+	err := s.db.Save(&models.MqttStore{Key: key, Type: typ, Value: value})
+	if err != nil {
+		klog.Errorf("Failed to insert or replace mqtt store %s: %v", key, err)
+	}
+	return err
+}
+
+// Delete deletes the value of key from mqtt_store
+func (s *MqttStoreService) Delete(key string) error {
+	if _, err := s.db.Delete(&models.MqttStore{}, dao.Eq("key", key)); err != nil {
+		klog.Errorf("Failed to delete mqtt store %s: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteByPrefix deletes the values whose keys start with the prefix from mqtt_store
+func (s *MqttStoreService) DeleteByPrefix(prefix string) error {
+	if _, err := s.db.Delete(&models.MqttStore{}, dao.Condition{Column: "key", Operator: dao.HasPrefix, Value: prefix}); err != nil {
+		klog.Errorf("Failed to delete mqtt store with prefix %s: %v", prefix, err)
+		return err
+	}
+	return nil
+}
+
+// List retrieves all values of the type from mqtt_store
+func (s *MqttStoreService) List(typ string) ([][]byte, error) {
+	var entries []models.MqttStore
+	if err := s.db.Find(&entries, dao.Query{Conditions: []dao.Condition{dao.Eq("type", typ)}}); err != nil {
+		klog.Errorf("Failed to query mqtt store of type %s: %v", typ, err)
+		return nil, err
+	}
+	result := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Value)
+	}
+	return result, nil
+}
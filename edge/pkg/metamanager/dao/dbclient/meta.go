@@ -17,27 +17,31 @@ limitations under the License.
 package dbclient
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 
-	"gorm.io/gorm"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/encryption"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
 )
 
 type MetaService struct {
-	db *gorm.DB
+	db dao.Storage
 }
 
 func NewMetaService() *MetaService {
-	return &MetaService{db: dao.GetDB()}
+	return &MetaService{db: dao.GetStorage()}
 }
 
 // SaveMeta saves meta to db
 func (s *MetaService) SaveMeta(meta *models.Meta) error {
-	err := s.db.Create(meta).Error
+	sealed, err := sealMeta(meta)
+	if err != nil {
+		return err
+	}
+	err = s.db.Create(sealed)
 	if err == nil || IsNonUniqueNameError(err) {
 		return nil
 	}
@@ -49,6 +53,9 @@ func IsNonUniqueNameError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, dao.ErrDuplicatedKey) {
+		return true
+	}
 	str := err.Error()
 	return strings.HasSuffix(str, "are not unique") ||
 		strings.Contains(str, "UNIQUE constraint failed") ||
@@ -57,51 +64,79 @@ func IsNonUniqueNameError(err error) bool {
 
 // DeleteMetaByKey deletes meta by key
 func (s *MetaService) DeleteMetaByKey(key string) error {
-	return s.db.Where("key = ?", key).Delete(&models.Meta{}).Error
+	_, err := s.db.Delete(&models.Meta{}, dao.Eq("key", key))
+	return err
 }
 
 // DeleteMetaByKeyAndPodUID deletes meta by key and podUID from value field
 func (s *MetaService) DeleteMetaByKeyAndPodUID(key, podUID string) (int64, error) {
-	result := s.db.Where("key = ? AND value LIKE ?", key, "%"+podUID+"%").Delete(&models.Meta{})
-	if result.Error != nil {
-		klog.Errorf("delete pod by key %s and podUID %s failed, err: %v", key, podUID, result.Error)
-		return 0, result.Error
+	rows, err := s.db.Delete(&models.Meta{}, dao.Eq("key", key),
+		dao.Condition{Column: "value", Operator: dao.Contains, Value: podUID})
+	if err != nil {
+		klog.Errorf("delete pod by key %s and podUID %s failed, err: %v", key, podUID, err)
+		return 0, err
 	}
-	return result.RowsAffected, nil
+	return rows, nil
 }
 
 // UpdateMeta updates a meta entry (all fields)
 func (s *MetaService) UpdateMeta(meta *models.Meta) error {
-	return s.db.Save(meta).Error
+	sealed, err := sealMeta(meta)
+	if err != nil {
+		return err
+	}
+	return s.db.Save(sealed)
 }
 
 // InsertOrUpdate inserts or replaces a meta entry
 func (s *MetaService) InsertOrUpdate(meta *models.Meta) error {
-	// SQLite syntax
-	sql := "INSERT OR REPLACE INTO meta (key, type, value) VALUES (?, ?, ?)"
-	return s.db.Exec(sql, meta.Key, meta.Type, meta.Value).Error
+	sealed, err := sealMeta(meta)
+	if err != nil {
+		return err
+	}
+	return s.db.Save(sealed)
 }
 
 // UpdateMetaField updates one field
 func (s *MetaService) UpdateMetaField(key string, col string, value interface{}) error {
-	return s.db.Model(&models.Meta{}).Where("key = ?", key).Update(col, value).Error
+	if col == models.VALUE {
+		sealed, err := s.sealMetaValue(key, nil, value)
+		if err != nil {
+			return err
+		}
+		value = sealed
+	}
+	_, err := s.db.Update(&models.Meta{}, map[string]interface{}{col: value}, dao.Eq("key", key))
+	return err
 }
 
 // UpdateMetaFields updates multiple fields
 func (s *MetaService) UpdateMetaFields(key string, cols map[string]interface{}) error {
-	return s.db.Model(&models.Meta{}).Where("key = ?", key).Updates(cols).Error
+	if value, ok := cols[models.VALUE]; ok {
+		sealed, err := s.sealMetaValue(key, cols[models.TYPE], value)
+		if err != nil {
+			return err
+		}
+		cols[models.VALUE] = sealed
+	}
+	_, err := s.db.Update(&models.Meta{}, cols, dao.Eq("key", key))
+	return err
 }
 
 // QueryMeta returns only meta values for given key and condition
 func (s *MetaService) QueryMeta(key string, condition string) (*[]string, error) {
 	var metas []models.Meta
-	err := s.db.Where(fmt.Sprintf("%s = ?", key), condition).Find(&metas).Error
+	err := s.db.Find(&metas, dao.Query{Conditions: []dao.Condition{dao.Eq(key, condition)}})
 	if err != nil {
 		return nil, err
 	}
 	var result []string
 	for _, v := range metas {
-		result = append(result, v.Value)
+		value, err := encryption.Decrypt(v.Key, v.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
 	}
 	return &result, nil
 }
@@ -109,9 +144,50 @@ func (s *MetaService) QueryMeta(key string, condition string) (*[]string, error)
 // QueryAllMeta returns all metas for given key and condition
 func (s *MetaService) QueryAllMeta(key string, condition string) (*[]models.Meta, error) {
 	var metas []models.Meta
-	err := s.db.Where(fmt.Sprintf("%s = ?", key), condition).Find(&metas).Error
+	err := s.db.Find(&metas, dao.Query{Conditions: []dao.Condition{dao.Eq(key, condition)}})
 	if err != nil {
 		return nil, err
 	}
+	for i := range metas {
+		if metas[i].Value, err = encryption.Decrypt(metas[i].Key, metas[i].Value); err != nil {
+			return nil, err
+		}
+	}
 	return &metas, nil
 }
+
+// sealMeta returns a copy of meta whose value is encrypted if it is a secret or token
+func sealMeta(meta *models.Meta) (*models.Meta, error) {
+	if !encryption.IsSensitiveMeta(meta.Type) {
+		return meta, nil
+	}
+	value, err := encryption.Encrypt(meta.Key, meta.Value)
+	if err != nil {
+		return nil, err
+	}
+	sealed := *meta
+	sealed.Value = value
+	return &sealed, nil
+}
+
+// sealMetaValue encrypts the value to be updated of meta key, the type of meta
+// is queried from db if it is not updated together
+func (s *MetaService) sealMetaValue(key string, typ, value interface{}) (interface{}, error) {
+	v, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	t, ok := typ.(string)
+	if !ok {
+		var meta models.Meta
+		err := s.db.First(&meta, dao.Query{Conditions: []dao.Condition{dao.Eq("key", key)}})
+		if err != nil && !errors.Is(err, dao.ErrRecordNotFound) {
+			return nil, err
+		}
+		t = meta.Type
+	}
+	if !encryption.IsSensitiveMeta(t) {
+		return value, nil
+	}
+	return encryption.Encrypt(key, v)
+}
@@ -20,58 +20,223 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
-	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/klog/v2"
 
 	commontypes "github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/encryption"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+	metaserverutil "github.com/kubeedge/kubeedge/pkg/metaserver/util"
 )
 
 type MetaV2Service struct {
-	db *gorm.DB
+	db dao.Storage
 }
 
 func NewMetaV2Service() *MetaV2Service {
-	return &MetaV2Service{db: dao.GetDB()}
+	return &MetaV2Service{db: dao.GetStorage()}
 }
 
 func (s *MetaV2Service) RawMetaByGVRNN(gvr schema.GroupVersionResource, namespace string, name string) (*[]models.MetaV2, error) {
 	var objs []models.MetaV2
-	tx := s.db.Model(&models.MetaV2{})
-
-	if !gvr.Empty() {
-		tx = tx.Where(models.GVR+" = ?", gvr.String())
+	if err := s.db.Find(&objs, dao.Query{Conditions: gvrnnConditions(gvr, namespace, name)}); err != nil {
+		return nil, err
 	}
+	if err := openMetaV2s(objs); err != nil {
+		return nil, err
+	}
+	return &objs, nil
+}
 
-	if namespace != models.NullNamespace && namespace != "" {
-		tx = tx.Where(models.NS+" = ?", namespace)
+// ListResourceVersions returns the resource versions of all the objects, keyed by
+// GroupVersionResource and then the digest key of object
+func (s *MetaV2Service) ListResourceVersions() (map[string]map[string]string, error) {
+	var objs []models.MetaV2
+	if err := s.db.Find(&objs, dao.Query{}); err != nil {
+		return nil, err
+	}
+	versions := make(map[string]map[string]string)
+	for _, obj := range objs {
+		if versions[obj.GroupVersionResource] == nil {
+			versions[obj.GroupVersionResource] = make(map[string]string)
+		}
+		versions[obj.GroupVersionResource][metaserverutil.DigestKey(obj.Namespace, obj.Name)] =
+			strconv.FormatUint(obj.ResourceVersion, 10)
 	}
+	return versions, nil
+}
 
-	if name != models.NullName && name != "" {
-		tx = tx.Where(models.NAME+" = ?", name)
+// MetaV2ListOptions is the options to list meta_v2, the selectors are pushed down
+// to the query as far as possible, the requirements that can not be pushed down are
+// ignored here and must be checked by the caller
+type MetaV2ListOptions struct {
+	Label labels.Selector
+	Field fields.Selector
+	// Continue is the key of the last object listed, only the objects after it are listed
+	Continue string
+	// Limit is the max number of objects listed, 0 means no limit
+	Limit int64
+}
+
+// ListMetaV2 lists the objects of gvr/namespace/name ordered by key
+func (s *MetaV2Service) ListMetaV2(gvr schema.GroupVersionResource, namespace string, name string, opts MetaV2ListOptions) (*[]models.MetaV2, error) {
+	var objs []models.MetaV2
+	conds := gvrnnConditions(gvr, namespace, name)
+	labelConds, err := s.labelSelectorConditions(opts.Label)
+	if err != nil {
+		return nil, err
+	}
+	conds = append(conds, labelConds...)
+	conds = append(conds, fieldSelectorConditions(opts.Field)...)
+	if opts.Continue != "" {
+		conds = append(conds, dao.Condition{Column: models.KEY, Operator: dao.GreaterThan, Value: opts.Continue})
 	}
 
-	if err := tx.Find(&objs).Error; err != nil {
+	query := dao.Query{Conditions: conds, OrderBy: models.KEY, Limit: int(opts.Limit)}
+	if err := s.db.Find(&objs, query); err != nil {
+		return nil, err
+	}
+	if err := openMetaV2s(objs); err != nil {
 		return nil, err
 	}
 	return &objs, nil
 }
 
+// gvrnnConditions matches the objects of gvr/namespace/name, the empty ones match all
+func gvrnnConditions(gvr schema.GroupVersionResource, namespace string, name string) []dao.Condition {
+	var conds []dao.Condition
+	if !gvr.Empty() {
+		conds = append(conds, dao.Eq(models.GVR, gvr.String()))
+	}
+	if namespace != models.NullNamespace && namespace != "" {
+		conds = append(conds, dao.Eq(models.NS, namespace))
+	}
+	if name != models.NullName && name != "" {
+		conds = append(conds, dao.Eq(models.NAME, name))
+	}
+	return conds
+}
+
+// labelSelectorConditions pushes the label requirements down to the keys queried from
+// table meta_v2_label, the numeric comparisons are not pushed down
+func (s *MetaV2Service) labelSelectorConditions(selector labels.Selector) ([]dao.Condition, error) {
+	if selector == nil {
+		return nil, nil
+	}
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		return nil, nil
+	}
+	var conds []dao.Condition
+	for _, r := range requirements {
+		labelConds := []dao.Condition{dao.Eq(models.LabelName, r.Key())}
+		operator := dao.In
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			labelConds = append(labelConds, dao.Condition{Column: models.LabelValue, Operator: dao.In, Value: r.Values().List()})
+		case selection.NotEquals, selection.NotIn:
+			labelConds = append(labelConds, dao.Condition{Column: models.LabelValue, Operator: dao.In, Value: r.Values().List()})
+			operator = dao.NotIn
+		case selection.Exists:
+		case selection.DoesNotExist:
+			operator = dao.NotIn
+		default:
+			continue
+		}
+		var rows []models.MetaV2Label
+		if err := s.db.Find(&rows, dao.Query{Conditions: labelConds}); err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(rows))
+		for _, row := range rows {
+			keys = append(keys, row.Key)
+		}
+		conds = append(conds, dao.Condition{Column: models.KEY, Operator: operator, Value: keys})
+	}
+	return conds, nil
+}
+
+// fieldSelectorConditions pushes the requirements of metadata.name and metadata.namespace
+// down to the columns of table meta_v2
+func fieldSelectorConditions(selector fields.Selector) []dao.Condition {
+	if selector == nil {
+		return nil
+	}
+	var conds []dao.Condition
+	for _, r := range selector.Requirements() {
+		var column string
+		switch r.Field {
+		case "metadata.name":
+			column = models.NAME
+		case "metadata.namespace":
+			column = models.NS
+		default:
+			continue
+		}
+		switch r.Operator {
+		case selection.Equals, selection.DoubleEquals:
+			conds = append(conds, dao.Eq(column, r.Value))
+		case selection.NotEquals:
+			conds = append(conds, dao.Condition{Column: column, Operator: dao.NotEqual, Value: r.Value})
+		}
+	}
+	return conds
+}
+
 func (s *MetaV2Service) GetLatestMetaV2() (models.MetaV2, error) {
 	var meta models.MetaV2
-	err := s.db.Model(&models.MetaV2{}).Order(clause.OrderByColumn{Column: clause.Column{Name: models.RV}, Desc: true}).Limit(1).Find(&meta).Error
+	err := s.db.First(&meta, dao.Query{OrderBy: models.RV, Desc: true})
+	if errors.Is(err, dao.ErrRecordNotFound) {
+		return meta, nil
+	}
+	if err != nil {
+		return meta, err
+	}
+	meta.Value, err = encryption.Decrypt(meta.Key, meta.Value)
 	return meta, err
 }
 
 func (s *MetaV2Service) InsertOrReplaceMetaV2(m *models.MetaV2) error {
-	return s.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "key"}},
-		UpdateAll: true,
-	}).Create(m).Error
+	return s.db.Transaction(func(tx dao.Storage) error {
+		sealed, err := sealMetaV2(m)
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(sealed); err != nil {
+			return err
+		}
+		return replaceMetaV2Labels(tx, m.Key, m.Labels)
+	})
+}
+
+// ReplaceMetaV2Labels replaces the labels of the object of key
+func (s *MetaV2Service) ReplaceMetaV2Labels(key string, labelSet map[string]string) error {
+	return s.db.Transaction(func(tx dao.Storage) error {
+		return replaceMetaV2Labels(tx, key, labelSet)
+	})
+}
+
+// CountMetaV2Labels returns the number of labels saved
+func (s *MetaV2Service) CountMetaV2Labels() (int64, error) {
+	return s.db.Count(&models.MetaV2Label{})
+}
+
+func replaceMetaV2Labels(tx dao.Storage, key string, labelSet map[string]string) error {
+	if _, err := tx.Delete(&models.MetaV2Label{}, dao.Eq(models.KEY, key)); err != nil {
+		return err
+	}
+	for name, value := range labelSet {
+		if err := tx.Create(&models.MetaV2Label{Key: key, Name: name, Value: value}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *MetaV2Service) RetryInsertOrReplaceMetaV2(m *models.MetaV2, maxRetries int) error {
@@ -91,15 +256,79 @@ func (s *MetaV2Service) RetryInsertOrReplaceMetaV2(m *models.MetaV2, maxRetries
 
 func (s *MetaV2Service) GetByKey(key string) (*models.MetaV2, error) {
 	var result models.MetaV2
-	err := s.db.Where(models.KEY+" = ?", key).First(&result).Error
+	err := s.db.First(&result, dao.Query{Conditions: []dao.Condition{dao.Eq(models.KEY, key)}})
 	if err != nil {
 		return nil, err
 	}
+	if result.Value, err = encryption.Decrypt(result.Key, result.Value); err != nil {
+		return nil, err
+	}
 	return &result, nil
 }
 
+// sealMetaV2 returns a copy of m whose value is encrypted if it is a secret
+func sealMetaV2(m *models.MetaV2) (*models.MetaV2, error) {
+	if !encryption.IsSensitiveMetaV2(m.GroupVersionResource) {
+		return m, nil
+	}
+	value, err := encryption.Encrypt(m.Key, m.Value)
+	if err != nil {
+		return nil, err
+	}
+	sealed := *m
+	sealed.Value = value
+	return &sealed, nil
+}
+
+// openMetaV2s decrypts the encrypted values of objs in place
+func openMetaV2s(objs []models.MetaV2) error {
+	for i := range objs {
+		value, err := encryption.Decrypt(objs[i].Key, objs[i].Value)
+		if err != nil {
+			return err
+		}
+		objs[i].Value = value
+	}
+	return nil
+}
+
 func (s *MetaV2Service) DeleteByKey(key string) error {
-	return s.db.Where(models.KEY+" = ?", key).Delete(&models.MetaV2{}).Error
+	return s.db.Transaction(func(tx dao.Storage) error {
+		if _, err := tx.Delete(&models.MetaV2{}, dao.Eq(models.KEY, key)); err != nil {
+			return err
+		}
+		_, err := tx.Delete(&models.MetaV2Label{}, dao.Eq(models.KEY, key))
+		return err
+	})
+}
+
+// AddPendingWrite queues the write served offline
+func (s *MetaV2Service) AddPendingWrite(w *models.MetaV2PendingWrite) error {
+	return s.db.Create(w)
+}
+
+// ListPendingWrites lists the writes served offline in the order they are served
+func (s *MetaV2Service) ListPendingWrites() ([]models.MetaV2PendingWrite, error) {
+	var writes []models.MetaV2PendingWrite
+	err := s.db.Find(&writes, dao.Query{OrderBy: "id"})
+	return writes, err
+}
+
+// ListPendingWritesByKey lists the writes served offline to the object of key
+func (s *MetaV2Service) ListPendingWritesByKey(key string) ([]models.MetaV2PendingWrite, error) {
+	var writes []models.MetaV2PendingWrite
+	err := s.db.Find(&writes, dao.Query{Conditions: []dao.Condition{dao.Eq(models.KEY, key)}, OrderBy: "id"})
+	return writes, err
+}
+
+// CountPendingWrites returns the number of writes waiting to be reconciled
+func (s *MetaV2Service) CountPendingWrites() (int64, error) {
+	return s.db.Count(&models.MetaV2PendingWrite{})
+}
+
+func (s *MetaV2Service) DeletePendingWrite(id uint64) error {
+	_, err := s.db.Delete(&models.MetaV2PendingWrite{}, dao.Eq("id", id))
+	return err
 }
 
 // upgrade_db
@@ -117,33 +346,31 @@ func (s *MetaV2Service) SaveNodeUpgradeJobRequestToMetaV2(nodeUpgradeJobReq comm
 		Value: string(nodeUpgradeJobReqJSON),
 	}
 
-	var count int64
-	if err := db.Model(&models.MetaV2{}).
-		Where(models.NAME+" = ?", models.NodeUpgradeJobRequestName).
-		Count(&count).Error; err != nil {
+	count, err := db.Count(&models.MetaV2{}, dao.Eq(models.NAME, models.NodeUpgradeJobRequestName))
+	if err != nil {
 		return err
 	}
 
 	if count > 0 {
 		klog.Info("NodeUpgradeJobRequest exists, updating...")
-		return db.Model(&models.MetaV2{}).
-			Where(models.NAME+" = ?", models.NodeUpgradeJobRequestName).
-			Updates(meta).Error
+		_, err := db.Update(&models.MetaV2{}, nodeRequestValues(meta), dao.Eq(models.NAME, models.NodeUpgradeJobRequestName))
+		return err
 	}
 
 	klog.Info("NodeUpgradeJobRequest not found, inserting...")
-	return db.Create(&meta).Error
+	return db.Create(&meta)
 }
 
 func (s *MetaV2Service) DeleteNodeUpgradeJobRequestFromMetaV2() error {
-	return s.db.Where(models.NAME+" = ?", models.NodeUpgradeJobRequestName).Delete(&models.MetaV2{}).Error
+	_, err := s.db.Delete(&models.MetaV2{}, dao.Eq(models.NAME, models.NodeUpgradeJobRequestName))
+	return err
 }
 
 func (s *MetaV2Service) QueryNodeUpgradeJobRequestFromMetaV2() (commontypes.NodeUpgradeJobRequest, error) {
 	var nodeUpgradeReq commontypes.NodeUpgradeJobRequest
 	var meta models.MetaV2
 
-	err := s.db.Where(models.NAME+" = ?", models.NodeUpgradeJobRequestName).First(&meta).Error
+	err := s.db.First(&meta, dao.Query{Conditions: []dao.Condition{dao.Eq(models.NAME, models.NodeUpgradeJobRequestName)}})
 	if err != nil {
 		return nodeUpgradeReq, err
 	}
@@ -156,8 +383,7 @@ func (s *MetaV2Service) QueryNodeUpgradeJobRequestFromMetaV2() (commontypes.Node
 }
 
 func (s *MetaV2Service) checkIfNodeTaskRequestExists() bool {
-	var count int64
-	s.db.Model(&models.MetaV2{}).Where(models.NAME+" = ?", models.NodeTaskRequestName).Count(&count)
+	count, _ := s.db.Count(&models.MetaV2{}, dao.Eq(models.NAME, models.NodeTaskRequestName))
 	return count > 0
 }
 
@@ -176,22 +402,33 @@ func (s *MetaV2Service) SaveNodeTaskRequestToMetaV2(nodeTaskReq commontypes.Node
 
 	if s.checkIfNodeTaskRequestExists() {
 		klog.Info("NodeTaskRequest exists, updating...")
-		return db.Model(&models.MetaV2{}).Where(models.NAME+" = ?", models.NodeTaskRequestName).Updates(meta).Error
+		_, err := db.Update(&models.MetaV2{}, nodeRequestValues(meta), dao.Eq(models.NAME, models.NodeTaskRequestName))
+		return err
 	} else {
 		klog.Info("NodeTaskRequest not found, inserting...")
-		return db.Create(&meta).Error
+		return db.Create(&meta)
+	}
+}
+
+// nodeRequestValues returns the columns of the node request to update
+func nodeRequestValues(meta models.MetaV2) map[string]interface{} {
+	return map[string]interface{}{
+		models.KEY:   meta.Key,
+		models.NAME:  meta.Name,
+		models.VALUE: meta.Value,
 	}
 }
 
 func (s *MetaV2Service) DeleteNodeTaskRequestFromMetaV2() error {
-	return s.db.Where(models.NAME+" = ?", models.NodeTaskRequestName).Delete(&models.MetaV2{}).Error
+	_, err := s.db.Delete(&models.MetaV2{}, dao.Eq(models.NAME, models.NodeTaskRequestName))
+	return err
 }
 
 func (s *MetaV2Service) QueryNodeTaskRequestFromMetaV2() (commontypes.NodeTaskRequest, error) {
 	var nodeTaskReq commontypes.NodeTaskRequest
 	var meta models.MetaV2
 
-	err := s.db.Where(models.NAME+" = ?", models.NodeTaskRequestName).First(&meta).Error
+	err := s.db.First(&meta, dao.Query{Conditions: []dao.Condition{dao.Eq(models.NAME, models.NodeTaskRequestName)}})
 	if err != nil {
 		return nodeTaskReq, err
 	}
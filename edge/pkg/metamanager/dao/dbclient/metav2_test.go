@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dbclient
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+)
+
+func newTestMetaV2Service(t *testing.T, driverName string) *MetaV2Service {
+	db, err := dao.NewStorage(driverName, filepath.Join(t.TempDir(), "edgecore.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Migrate(&models.MetaV2{}, &models.MetaV2Label{}))
+	return &MetaV2Service{db: db}
+}
+
+func TestListMetaV2(t *testing.T) {
+	for _, driverName := range []string{v1alpha2.DataBaseDriverName, v1alpha2.DataBaseDriverBbolt} {
+		t.Run(driverName, func(t *testing.T) {
+			testListMetaV2(t, newTestMetaV2Service(t, driverName))
+		})
+	}
+}
+
+func testListMetaV2(t *testing.T, s *MetaV2Service) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	objs := []struct {
+		namespace string
+		name      string
+		labels    map[string]string
+	}{
+		{"default", "cm1", map[string]string{"app": "web", "tier": "frontend"}},
+		{"default", "cm2", map[string]string{"app": "web", "tier": "backend"}},
+		{"default", "cm3", map[string]string{"app": "db"}},
+		{"kube-system", "cm4", nil},
+	}
+	for i, o := range objs {
+		require.NoError(t, s.InsertOrReplaceMetaV2(&models.MetaV2{
+			Key:                  fmt.Sprintf("/core/v1/configmaps/%s/%s", o.namespace, o.name),
+			GroupVersionResource: gvr.String(),
+			Namespace:            o.namespace,
+			Name:                 o.name,
+			ResourceVersion:      uint64(i + 1),
+			Labels:               o.labels,
+		}))
+	}
+	// the labels are replaced when the object is updated
+	require.NoError(t, s.InsertOrReplaceMetaV2(&models.MetaV2{
+		Key:                  "/core/v1/configmaps/default/cm3",
+		GroupVersionResource: gvr.String(),
+		Namespace:            "default",
+		Name:                 "cm3",
+		ResourceVersion:      5,
+		Labels:               map[string]string{"app": "cache"},
+	}))
+
+	cases := []struct {
+		name      string
+		namespace string
+		opts      MetaV2ListOptions
+		want      []string
+	}{
+		{
+			name: "all",
+			want: []string{"cm1", "cm2", "cm3", "cm4"},
+		},
+		{
+			name:      "namespace",
+			namespace: "kube-system",
+			want:      []string{"cm4"},
+		},
+		{
+			name: "label equals",
+			opts: MetaV2ListOptions{Label: labels.SelectorFromSet(labels.Set{"app": "web"})},
+			want: []string{"cm1", "cm2"},
+		},
+		{
+			name: "label updated",
+			opts: MetaV2ListOptions{Label: labels.SelectorFromSet(labels.Set{"app": "db"})},
+		},
+		{
+			name: "label set based",
+			opts: MetaV2ListOptions{Label: mustSelector(t, "app in (web,cache),tier!=frontend")},
+			want: []string{"cm2", "cm3"},
+		},
+		{
+			name: "label does not exist",
+			opts: MetaV2ListOptions{Label: mustSelector(t, "!app")},
+			want: []string{"cm4"},
+		},
+		{
+			name: "field selector",
+			opts: MetaV2ListOptions{Field: fields.ParseSelectorOrDie("metadata.namespace=default,metadata.name!=cm1")},
+			want: []string{"cm2", "cm3"},
+		},
+		{
+			name: "limit",
+			opts: MetaV2ListOptions{Limit: 2},
+			want: []string{"cm1", "cm2"},
+		},
+		{
+			name: "continue",
+			opts: MetaV2ListOptions{Limit: 2, Continue: "/core/v1/configmaps/default/cm2"},
+			want: []string{"cm3", "cm4"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			metas, err := s.ListMetaV2(gvr, c.namespace, "", c.opts)
+			require.NoError(t, err)
+			var names []string
+			for _, m := range *metas {
+				names = append(names, m.Name)
+			}
+			assert.Equal(t, c.want, names)
+		})
+	}
+
+	require.NoError(t, s.DeleteByKey("/core/v1/configmaps/default/cm1"))
+	count, err := s.CountMetaV2Labels()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestListResourceVersions(t *testing.T) {
+	s := newTestMetaV2Service(t, v1alpha2.DataBaseDriverName)
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	nodes := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	for _, m := range []models.MetaV2{
+		{Key: "/core/v1/pods/default/p1", GroupVersionResource: pods.String(), Namespace: "default", Name: "p1", ResourceVersion: 10},
+		{Key: "/core/v1/pods/kube-system/p2", GroupVersionResource: pods.String(), Namespace: "kube-system", Name: "p2", ResourceVersion: 11},
+		{Key: "/core/v1/nodes/null/n1", GroupVersionResource: nodes.String(), Name: "n1", ResourceVersion: 12},
+	} {
+		require.NoError(t, s.InsertOrReplaceMetaV2(&m))
+	}
+
+	versions, err := s.ListResourceVersions()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		pods.String():  {"default/p1": "10", "kube-system/p2": "11"},
+		nodes.String(): {"n1": "12"},
+	}, versions)
+}
+
+func mustSelector(t *testing.T, selector string) labels.Selector {
+	s, err := labels.Parse(selector)
+	require.NoError(t, err)
+	return s
+}
@@ -19,7 +19,6 @@ package dbclient
 import (
 	"errors"
 
-	"gorm.io/gorm"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
@@ -27,17 +26,16 @@ import (
 )
 
 type ServiceBusService struct {
-	db *gorm.DB
+	db dao.Storage
 }
 
 func NewServiceBusService() *ServiceBusService {
-	return &ServiceBusService{db: dao.GetDB()}
+	return &ServiceBusService{db: dao.GetStorage()}
 }
 
 // InsertUrls insert or replace url into target_urls
 func (s *ServiceBusService) InsertUrls(url string) error {
-	// Raw SQL query to perform INSERT OR REPLACE for SQLite
-	err := s.db.Exec("INSERT OR REPLACE INTO target_urls (url) VALUES (?)", url).Error
+	err := s.db.Save(&models.TargetUrls{URL: url})
 	if err != nil {
 		klog.Errorf("Failed to insert or replace URL: %v", err)
 	}
@@ -46,20 +44,19 @@ func (s *ServiceBusService) InsertUrls(url string) error {
 
 // DeleteUrlsByKey delete target_urls by URL
 func (s *ServiceBusService) DeleteUrlsByKey(key string) error {
-	result := s.db.Where("url = ?", key).Delete(&models.TargetUrls{})
-	if result.Error != nil {
-		klog.Errorf("Failed to delete URL %s: %v", key, result.Error)
-		return result.Error
+	rows, err := s.db.Delete(&models.TargetUrls{}, dao.Eq("url", key))
+	if err != nil {
+		klog.Errorf("Failed to delete URL %s: %v", key, err)
+		return err
 	}
 
-	klog.V(4).Infof("Delete affected Num: %d", result.RowsAffected)
+	klog.V(4).Infof("Delete affected Num: %d", rows)
 	return nil
 }
 
 // IsTableEmpty returns true if no records in target_urls
 func (s *ServiceBusService) IsTableEmpty() bool {
-	var count int64
-	err := s.db.Model(&models.TargetUrls{}).Count(&count).Error
+	count, err := s.db.Count(&models.TargetUrls{})
 	if err != nil {
 		klog.Errorf("Failed to count target_urls: %v", err)
 		return true
@@ -70,9 +67,9 @@ func (s *ServiceBusService) IsTableEmpty() bool {
 // GetUrlsByKey gets one record from target_urls by URL
 func (s *ServiceBusService) GetUrlsByKey(key string) (*models.TargetUrls, error) {
 	var target models.TargetUrls
-	err := s.db.Where("url = ?", key).First(&target).Error
+	err := s.db.First(&target, dao.Query{Conditions: []dao.Condition{dao.Eq("url", key)}})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, dao.ErrRecordNotFound) {
 			klog.V(4).Infof("URL not found: %s", key)
 			return nil, nil
 		}
@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"strings"
 
-	"gorm.io/gorm"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	operationv1alpha1 "github.com/kubeedge/api/apis/operations/v1alpha1"
@@ -51,11 +50,11 @@ var (
 // It's used to store information needed to continue upgrading after the upgrade is confirmed.
 // Whether complete information about node jobs needs to be stored is another matter.
 type Upgrade struct {
-	db *gorm.DB
+	db dao.Storage
 }
 
 func NewUpgrade() *Upgrade {
-	return &Upgrade{db: dao.GetDB()}
+	return &Upgrade{db: dao.GetStorage()}
 }
 
 // Generates the key for meta_v2 table. The format is:
@@ -132,10 +131,10 @@ func (dao *Upgrade) Delete() error {
 }
 
 // onlyOneUpgradeRowByGVR returns the first row of data that query by GVR.
-func onlyOneUpgradeRowByGVR(db *gorm.DB, gvr string) (*models.MetaV2, error) {
+func onlyOneUpgradeRowByGVR(db dao.Storage, gvr string) (*models.MetaV2, error) {
 	var row models.MetaV2
-	err := db.Where(models.GVR+" = ?", gvr).Limit(1).Find(&row).Error
-	if errors.Is(err, gorm.ErrRecordNotFound) {
+	err := db.First(&row, dao.Query{Conditions: []dao.Condition{dao.Eq(models.GVR, gvr)}})
+	if errors.Is(err, dao.ErrRecordNotFound) {
 		return nil, nil
 	}
 	if err != nil {
@@ -151,12 +150,12 @@ func onlyOneUpgradeRowByGVR(db *gorm.DB, gvr string) (*models.MetaV2, error) {
 // It's used to store information needed to continue upgrading after the upgrade is confirmed.
 // Deprecated: For compatibility with v1alpha1 version, It will be removed in v1.23
 type UpgradeV1alpha1 struct {
-	db *gorm.DB
+	db dao.Storage
 }
 
 func NewUpgradeV1alpha1() *UpgradeV1alpha1 {
 	return &UpgradeV1alpha1{
-		db: dao.GetDB(),
+		db: dao.GetStorage(),
 	}
 }
 
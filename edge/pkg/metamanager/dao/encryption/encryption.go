@@ -0,0 +1,273 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption encrypts the secrets and service account tokens saved in the
+// edge database. The values are encrypted by a data encryption key with AES-GCM, and
+// the data encryption key is saved in database after wrapped by a KeyProvider.
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+)
+
+// encryptedPrefix marks the values encrypted by the data encryption key,
+// the values without it are in plain text
+const encryptedPrefix = "k8s:enc:edge:aesgcm:v1:"
+
+var (
+	// sensitiveMetaTypes are the types of meta whose values are encrypted
+	sensitiveMetaTypes = []string{model.ResourceTypeSecret, model.ResourceTypeServiceAccountToken}
+	// sensitiveGVRs are the resources in meta_v2 whose values are encrypted
+	sensitiveGVRs = []string{schema.GroupVersionResource{Version: "v1", Resource: "secrets"}.String()}
+
+	lock        sync.RWMutex
+	transformer value.Transformer
+)
+
+// Init loads the data encryption key of db, the key is generated if it does not exist.
+// The sensitive values saved in plain text are encrypted if the encryption is enabled,
+// otherwise the encrypted values are decrypted back and the key is deleted.
+func Init(db dao.Storage, cfg *v1alpha2.DataBaseEncryption) error {
+	if !db.HasTable(&models.DataEncryptionKey{}) {
+		return nil
+	}
+	var keys []models.DataEncryptionKey
+	if err := db.Find(&keys, dao.Query{}); err != nil {
+		return fmt.Errorf("failed to load data encryption key: %v", err)
+	}
+	enabled := cfg != nil && cfg.Enable
+	if !enabled && len(keys) == 0 {
+		return nil
+	}
+	if cfg == nil {
+		return errors.New("database is encrypted, the encryption config is required to decrypt it")
+	}
+
+	ctx := context.Background()
+	provider, err := newKeyProvider(ctx, cfg, true)
+	if err != nil {
+		return err
+	}
+	var dek []byte
+	if len(keys) == 0 {
+		if dek, err = aestransformer.GenerateKey(keySize); err != nil {
+			return err
+		}
+		key, err := provider.WrapKey(ctx, dek)
+		if err != nil {
+			return err
+		}
+		if err := db.Create(key); err != nil {
+			return fmt.Errorf("failed to save data encryption key: %v", err)
+		}
+	} else if dek, err = unwrapKey(ctx, provider, &keys[0]); err != nil {
+		return err
+	}
+	t, err := newTransformer(dek)
+	if err != nil {
+		return err
+	}
+	setTransformer(t)
+
+	if enabled {
+		klog.Infof("encryption at rest of database is enabled, key provider: %s", provider.Name())
+		return db.Transaction(func(tx dao.Storage) error {
+			return migrate(tx, Encrypt)
+		})
+	}
+
+	klog.Infof("encryption at rest of database is disabled, decrypt the encrypted values")
+	err = db.Transaction(func(tx dao.Storage) error {
+		if err := migrate(tx, Decrypt); err != nil {
+			return err
+		}
+		_, err := tx.Delete(&models.DataEncryptionKey{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	setTransformer(nil)
+	return nil
+}
+
+// Load loads the data encryption key of db without changing db, so that the tools other
+// than edgecore can read the encrypted values. Nothing is loaded if db is not encrypted.
+func Load(db dao.Storage, cfg *v1alpha2.DataBaseEncryption) error {
+	if !db.HasTable(&models.DataEncryptionKey{}) {
+		return nil
+	}
+	var keys []models.DataEncryptionKey
+	if err := db.Find(&keys, dao.Query{}); err != nil {
+		return fmt.Errorf("failed to load data encryption key: %v", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if cfg == nil {
+		return errors.New("database is encrypted, the encryption config is required to decrypt it")
+	}
+
+	ctx := context.Background()
+	provider, err := newKeyProvider(ctx, cfg, false)
+	if err != nil {
+		return err
+	}
+	dek, err := unwrapKey(ctx, provider, &keys[0])
+	if err != nil {
+		return err
+	}
+	t, err := newTransformer(dek)
+	if err != nil {
+		return err
+	}
+	setTransformer(t)
+	return nil
+}
+
+// unwrapKey returns the data encryption key wrapped in key by provider
+func unwrapKey(ctx context.Context, provider KeyProvider, key *models.DataEncryptionKey) ([]byte, error) {
+	if key.Provider != provider.Name() {
+		return nil, fmt.Errorf("database is encrypted by key provider %s, but %s is configured", key.Provider, provider.Name())
+	}
+	dek, err := provider.UnwrapKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data encryption key: %v", err)
+	}
+	return dek, nil
+}
+
+func setTransformer(t value.Transformer) {
+	lock.Lock()
+	defer lock.Unlock()
+	transformer = t
+}
+
+func getTransformer() value.Transformer {
+	lock.RLock()
+	defer lock.RUnlock()
+	return transformer
+}
+
+// migrate converts the sensitive values in meta and meta_v2 by convert
+func migrate(tx dao.Storage, convert func(key, value string) (string, error)) error {
+	var metas []models.Meta
+	query := dao.Query{Conditions: []dao.Condition{{Column: models.TYPE, Operator: dao.In, Value: sensitiveMetaTypes}}}
+	if err := tx.Find(&metas, query); err != nil {
+		return err
+	}
+	for _, m := range metas {
+		v, err := convert(m.Key, m.Value)
+		if err != nil {
+			return fmt.Errorf("failed to convert meta %s: %v", m.Key, err)
+		}
+		if v == m.Value {
+			continue
+		}
+		if _, err := tx.Update(&models.Meta{}, map[string]interface{}{models.VALUE: v}, dao.Eq(models.KEY, m.Key)); err != nil {
+			return err
+		}
+	}
+
+	var metaV2s []models.MetaV2
+	query = dao.Query{Conditions: []dao.Condition{{Column: models.GVR, Operator: dao.In, Value: sensitiveGVRs}}}
+	if err := tx.Find(&metaV2s, query); err != nil {
+		return err
+	}
+	for _, m := range metaV2s {
+		v, err := convert(m.Key, m.Value)
+		if err != nil {
+			return fmt.Errorf("failed to convert meta_v2 %s: %v", m.Key, err)
+		}
+		if v == m.Value {
+			continue
+		}
+		if _, err := tx.Update(&models.MetaV2{}, map[string]interface{}{models.VALUE: v}, dao.Eq(models.KEY, m.Key)); err != nil {
+			return err
+		}
+	}
+	klog.Infof("converted %d meta and %d meta_v2 sensitive values", len(metas), len(metaV2s))
+	return nil
+}
+
+// IsSensitiveMeta returns whether the value of meta of typ is encrypted
+func IsSensitiveMeta(typ string) bool {
+	for _, t := range sensitiveMetaTypes {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveMetaV2 returns whether the value of meta_v2 of gvr is encrypted
+func IsSensitiveMetaV2(gvr string) bool {
+	for _, r := range sensitiveGVRs {
+		if gvr == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Encrypt encrypts the value of key if the encryption is enabled, the key is
+// authenticated so that the value can not be copied to other keys
+func Encrypt(key, v string) (string, error) {
+	t := getTransformer()
+	if t == nil || strings.HasPrefix(v, encryptedPrefix) {
+		return v, nil
+	}
+	data, err := t.TransformToStorage(context.Background(), []byte(v), value.DefaultContext(key))
+	if err != nil {
+		return "", err
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt decrypts the value of key if it is encrypted, the values in plain text are returned as they are
+func Decrypt(key, v string) (string, error) {
+	if !strings.HasPrefix(v, encryptedPrefix) {
+		return v, nil
+	}
+	t := getTransformer()
+	if t == nil {
+		return "", fmt.Errorf("value of %s is encrypted, but the data encryption key is not loaded", key)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	plain, _, err := t.TransformFromStorage(context.Background(), data, value.DefaultContext(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value of %s: %v", key, err)
+	}
+	return string(plain), nil
+}
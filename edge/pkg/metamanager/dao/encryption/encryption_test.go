@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+)
+
+const (
+	secretValue = `{"data":{"password":"cGFzc3dvcmQ="}}`
+	podValue    = `{"metadata":{"uid":"pod-uid"}}`
+)
+
+func newTestDB(t *testing.T) dao.Storage {
+	db, err := dao.NewStorage(v1alpha2.DataBaseDriverName, filepath.Join(t.TempDir(), "edgecore.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Migrate(&models.Meta{}, &models.MetaV2{}, &models.DataEncryptionKey{}))
+	require.NoError(t, db.Create(&models.Meta{Key: "default/secret/s1", Type: "secret", Value: secretValue}))
+	require.NoError(t, db.Create(&models.Meta{Key: "default/pod/p1", Type: "pod", Value: podValue}))
+	require.NoError(t, db.Create(&models.MetaV2{
+		Key:                  "/core/v1/secrets/default/s1",
+		GroupVersionResource: "/v1, Resource=secrets",
+		Value:                secretValue,
+	}))
+	t.Cleanup(func() { setTransformer(nil) })
+	return db
+}
+
+func rawValue(t *testing.T, db dao.Storage, key string) string {
+	var meta models.Meta
+	require.NoError(t, db.First(&meta, dao.Query{Conditions: []dao.Condition{dao.Eq("key", key)}}))
+	return meta.Value
+}
+
+func TestInit(t *testing.T) {
+	db := newTestDB(t)
+	keyFile := filepath.Join(t.TempDir(), "db", "encryption.key")
+	cfg := &v1alpha2.DataBaseEncryption{
+		Enable:      true,
+		KeyProvider: v1alpha2.DataBaseKeyProviderFile,
+		KeyFile:     keyFile,
+	}
+
+	// the key is generated and the existing secrets are encrypted
+	require.NoError(t, Init(db, cfg))
+	assert.FileExists(t, keyFile)
+	sealed := rawValue(t, db, "default/secret/s1")
+	assert.True(t, strings.HasPrefix(sealed, encryptedPrefix))
+	assert.NotContains(t, sealed, "password")
+	assert.Equal(t, podValue, rawValue(t, db, "default/pod/p1"))
+	var metaV2 models.MetaV2
+	require.NoError(t, db.First(&metaV2, dao.Query{}))
+	assert.True(t, strings.HasPrefix(metaV2.Value, encryptedPrefix))
+
+	plain, err := Decrypt("default/secret/s1", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, secretValue, plain)
+	// the value can not be decrypted as the one of other key
+	_, err = Decrypt("default/secret/s2", sealed)
+	assert.Error(t, err)
+
+	// the key is loaded after restarted, and the encrypted values are not encrypted again
+	setTransformer(nil)
+	require.NoError(t, Init(db, cfg))
+	assert.Equal(t, sealed, rawValue(t, db, "default/secret/s1"))
+	count, err := db.Count(&models.DataEncryptionKey{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// the key file is replaced
+	setTransformer(nil)
+	require.NoError(t, os.WriteFile(keyFile, []byte("MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="), 0600))
+	assert.Error(t, Init(db, &v1alpha2.DataBaseEncryption{Enable: true, KeyProvider: v1alpha2.DataBaseKeyProviderTPM, KeyFile: keyFile}))
+	assert.Error(t, Init(db, cfg))
+}
+
+func TestInitDisable(t *testing.T) {
+	db := newTestDB(t)
+	cfg := &v1alpha2.DataBaseEncryption{
+		Enable:      true,
+		KeyProvider: v1alpha2.DataBaseKeyProviderFile,
+		KeyFile:     filepath.Join(t.TempDir(), "encryption.key"),
+	}
+	require.NoError(t, Init(db, cfg))
+
+	// the encrypted values are decrypted back and the key is deleted
+	cfg.Enable = false
+	require.NoError(t, Init(db, cfg))
+	assert.Equal(t, secretValue, rawValue(t, db, "default/secret/s1"))
+	count, err := db.Count(&models.DataEncryptionKey{})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	v, err := Encrypt("default/secret/s1", secretValue)
+	require.NoError(t, err)
+	assert.Equal(t, secretValue, v)
+}
+
+func TestLoad(t *testing.T) {
+	db := newTestDB(t)
+	keyFile := filepath.Join(t.TempDir(), "encryption.key")
+	cfg := &v1alpha2.DataBaseEncryption{
+		Enable:      true,
+		KeyProvider: v1alpha2.DataBaseKeyProviderFile,
+		KeyFile:     keyFile,
+	}
+
+	// nothing is loaded from the database which is not encrypted
+	require.NoError(t, Load(db, nil))
+	require.NoError(t, Init(db, cfg))
+	sealed := rawValue(t, db, "default/secret/s1")
+
+	setTransformer(nil)
+	assert.Error(t, Load(db, nil))
+	require.NoError(t, Load(db, cfg))
+	plain, err := Decrypt("default/secret/s1", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, secretValue, plain)
+
+	// the missing key file is not generated
+	setTransformer(nil)
+	missing := filepath.Join(t.TempDir(), "encryption.key")
+	assert.Error(t, Load(db, &v1alpha2.DataBaseEncryption{Enable: true, KeyProvider: v1alpha2.DataBaseKeyProviderFile, KeyFile: missing}))
+	assert.NoFileExists(t, missing)
+}
+
+func TestTPMKeyProvider(t *testing.T) {
+	dir := t.TempDir()
+	machineID := filepath.Join(dir, "machine-id")
+	require.NoError(t, os.WriteFile(machineID, []byte("node-a\n"), 0600))
+	origin := machineIDFiles
+	machineIDFiles = []string{machineID}
+	defer func() { machineIDFiles = origin }()
+
+	db := newTestDB(t)
+	cfg := &v1alpha2.DataBaseEncryption{
+		Enable:      true,
+		KeyProvider: v1alpha2.DataBaseKeyProviderTPM,
+		KeyFile:     filepath.Join(dir, "encryption.key"),
+	}
+	require.NoError(t, Init(db, cfg))
+	assert.True(t, strings.HasPrefix(rawValue(t, db, "default/secret/s1"), encryptedPrefix))
+
+	// the sealed key can not be unsealed on other nodes
+	setTransformer(nil)
+	require.NoError(t, os.WriteFile(machineID, []byte("node-b\n"), 0600))
+	assert.Error(t, Init(db, cfg))
+}
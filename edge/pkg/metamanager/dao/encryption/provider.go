@@ -0,0 +1,261 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apiserver/pkg/storage/value"
+	aestransformer "k8s.io/apiserver/pkg/storage/value/encrypt/aes"
+	"k8s.io/apiserver/pkg/storage/value/encrypt/envelope/kmsv2"
+	"k8s.io/klog/v2"
+	kmsservice "k8s.io/kms/pkg/service"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+)
+
+const (
+	keySize = 32
+	// kmsProviderName is the name of edgecore in the metrics of KMS v2 plugin calls
+	kmsProviderName = "edgecore"
+)
+
+// machineIDFiles are the files which identify the node, the sealed key can only be
+// unsealed on the node where it is sealed
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// KeyProvider wraps the data encryption key by the key encryption key before it is
+// saved in database, and unwraps it when edgecore starts
+type KeyProvider interface {
+	// Name returns the name of provider, it is saved with the wrapped key
+	Name() string
+	// WrapKey wraps dek and returns the key to be saved
+	WrapKey(ctx context.Context, dek []byte) (*models.DataEncryptionKey, error)
+	// UnwrapKey unwraps the saved key and returns the data encryption key
+	UnwrapKey(ctx context.Context, key *models.DataEncryptionKey) ([]byte, error)
+}
+
+// newKeyProvider returns the key provider of cfg, the key encryption key of file and TPM
+// providers is generated if it does not exist and generate is true
+func newKeyProvider(ctx context.Context, cfg *v1alpha2.DataBaseEncryption, generate bool) (KeyProvider, error) {
+	switch cfg.KeyProvider {
+	case v1alpha2.DataBaseKeyProviderFile, "":
+		var encode func([]byte) ([]byte, error)
+		if generate {
+			encode = func(kek []byte) ([]byte, error) {
+				return []byte(base64.StdEncoding.EncodeToString(kek)), nil
+			}
+		}
+		kek, err := loadOrGenerateKey(cfg.KeyFile, func(data []byte) ([]byte, error) {
+			return base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+		}, encode)
+		if err != nil {
+			return nil, err
+		}
+		return &localKeyProvider{name: string(v1alpha2.DataBaseKeyProviderFile), kek: kek}, nil
+	case v1alpha2.DataBaseKeyProviderTPM:
+		var encode func([]byte) ([]byte, error)
+		if generate {
+			encode = sealKey
+		}
+		kek, err := loadOrGenerateKey(cfg.KeyFile, unsealKey, encode)
+		if err != nil {
+			return nil, err
+		}
+		return &localKeyProvider{name: string(v1alpha2.DataBaseKeyProviderTPM), kek: kek}, nil
+	case v1alpha2.DataBaseKeyProviderKMS:
+		timeout := time.Duration(cfg.KMSTimeout) * time.Second
+		service, err := kmsv2.NewGRPCService(ctx, cfg.KMSEndpoint, kmsProviderName, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to KMS plugin %s: %v", cfg.KMSEndpoint, err)
+		}
+		return &kmsKeyProvider{service: service}, nil
+	default:
+		return nil, fmt.Errorf("unknown key provider %s", cfg.KeyProvider)
+	}
+}
+
+// loadOrGenerateKey loads the key encryption key from file, a new key is generated
+// and saved into file if it does not exist, unless encode is nil
+func loadOrGenerateKey(file string, decode, encode func([]byte) ([]byte, error)) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err == nil {
+		key, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key file %s: %v", file, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("the key in %s must be %d bytes, got %d", file, keySize, len(key))
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) || encode == nil {
+		return nil, fmt.Errorf("failed to read key file %s: %v", file, err)
+	}
+
+	klog.Infof("key file %s does not exist, generate a new key", file)
+	key, err := aestransformer.GenerateKey(keySize)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = encode(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create dir of key file %s: %v", file, err)
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write key file %s: %v", file, err)
+	}
+	return key, nil
+}
+
+// sealKey is a stand-in of sealing the key by TPM, the key is encrypted by the key
+// derived from machine id, so that the sealed blob can not be used on other nodes
+func sealKey(key []byte) ([]byte, error) {
+	t, err := machineTransformer()
+	if err != nil {
+		return nil, err
+	}
+	return t.TransformToStorage(context.Background(), key, value.DefaultContext("sealed-key"))
+}
+
+// unsealKey is a stand-in of unsealing the key by TPM
+func unsealKey(blob []byte) ([]byte, error) {
+	t, err := machineTransformer()
+	if err != nil {
+		return nil, err
+	}
+	key, _, err := t.TransformFromStorage(context.Background(), blob, value.DefaultContext("sealed-key"))
+	if err != nil {
+		return nil, fmt.Errorf("the key is not sealed on this node: %v", err)
+	}
+	return key, nil
+}
+
+func machineTransformer() (value.Transformer, error) {
+	for _, file := range machineIDFiles {
+		id, err := os.ReadFile(file)
+		if err != nil || len(bytes.TrimSpace(id)) == 0 {
+			continue
+		}
+		seed := sha256.Sum256(append([]byte("kubeedge-db-seal:"), bytes.TrimSpace(id)...))
+		return newTransformer(seed[:])
+	}
+	return nil, errors.New("machine id is not found to seal the key")
+}
+
+func newTransformer(key []byte) (value.Transformer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return aestransformer.NewGCMTransformer(block)
+}
+
+// localKeyProvider wraps the data encryption key by the key encryption key at local
+type localKeyProvider struct {
+	name string
+	kek  []byte
+}
+
+func (p *localKeyProvider) Name() string {
+	return p.name
+}
+
+// keyID identifies the key encryption key without revealing it
+func (p *localKeyProvider) keyID() string {
+	sum := sha256.Sum256(p.kek)
+	return hex.EncodeToString(sum[:8])
+}
+
+func (p *localKeyProvider) WrapKey(ctx context.Context, dek []byte) (*models.DataEncryptionKey, error) {
+	t, err := newTransformer(p.kek)
+	if err != nil {
+		return nil, err
+	}
+	keyID := p.keyID()
+	wrapped, err := t.TransformToStorage(ctx, dek, value.DefaultContext(keyID))
+	if err != nil {
+		return nil, err
+	}
+	return &models.DataEncryptionKey{Provider: p.name, KeyID: keyID, WrappedKey: wrapped}, nil
+}
+
+func (p *localKeyProvider) UnwrapKey(ctx context.Context, key *models.DataEncryptionKey) ([]byte, error) {
+	if key.KeyID != p.keyID() {
+		return nil, fmt.Errorf("the database is encrypted by key %s, but key %s is provided", key.KeyID, p.keyID())
+	}
+	t, err := newTransformer(p.kek)
+	if err != nil {
+		return nil, err
+	}
+	dek, _, err := t.TransformFromStorage(ctx, key.WrappedKey, value.DefaultContext(key.KeyID))
+	return dek, err
+}
+
+// kmsKeyProvider wraps the data encryption key by KMS v2 plugin
+type kmsKeyProvider struct {
+	service kmsservice.Service
+}
+
+func (p *kmsKeyProvider) Name() string {
+	return string(v1alpha2.DataBaseKeyProviderKMS)
+}
+
+func (p *kmsKeyProvider) WrapKey(ctx context.Context, dek []byte) (*models.DataEncryptionKey, error) {
+	resp, err := p.service.Encrypt(ctx, kmsProviderName, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap key by KMS plugin: %v", err)
+	}
+	annotations, err := json.Marshal(resp.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DataEncryptionKey{
+		Provider:    p.Name(),
+		KeyID:       resp.KeyID,
+		WrappedKey:  resp.Ciphertext,
+		Annotations: string(annotations),
+	}, nil
+}
+
+func (p *kmsKeyProvider) UnwrapKey(ctx context.Context, key *models.DataEncryptionKey) ([]byte, error) {
+	req := &kmsservice.DecryptRequest{Ciphertext: key.WrappedKey, KeyID: key.KeyID}
+	if key.Annotations != "" {
+		if err := json.Unmarshal([]byte(key.Annotations), &req.Annotations); err != nil {
+			return nil, err
+		}
+	}
+	dek, err := p.service.Decrypt(ctx, kmsProviderName, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap key by KMS plugin: %v", err)
+	}
+	return dek, nil
+}
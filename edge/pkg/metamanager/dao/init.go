@@ -17,26 +17,27 @@ limitations under the License.
 package dao
 
 import (
-	"log"
+	"fmt"
 	"sync"
 
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
 )
 
-var dbInstance *gorm.DB
+var storageInstance Storage
+var dbConfig *v1alpha2.DataBase
 var once sync.Once
 
-func Init(dataSource string, modules ...interface{}) {
+// Init opens the storage of db and migrates the tables of enabled modules
+func Init(db *v1alpha2.DataBase, modules ...interface{}) {
 	once.Do(func() {
 		var err error
-		dbInstance, err = gorm.Open(sqlite.Open(dataSource), &gorm.Config{})
+		dbConfig = db
+		storageInstance, err = openStorage(db)
 		if err != nil {
-			log.Fatalf("Failed to connect to DB: %v", err)
+			klog.Exitf("Failed to connect to DB: %v", err)
 		}
 	})
 
@@ -47,64 +48,98 @@ func Init(dataSource string, modules ...interface{}) {
 // MigrateTables only migrates tables for enabled modules
 func migrateTables(modules ...interface{}) {
 	for _, m := range modules {
-		switch module := m.(type) {
-		case *v1alpha2.DeviceTwin:
-			if !module.Enable {
-				klog.Info("DeviceTwin module is disabled, skipping DB migration")
-				continue
-			}
-			klog.Info("Migrating DB tables for DeviceTwin module")
-			if err := dbInstance.AutoMigrate(
-				&models.Device{},
-				&models.DeviceAttr{},
-				&models.DeviceTwin{},
-			); err != nil {
-				klog.Fatalf("Failed to migrate DeviceTwin tables: %v", err)
-			}
+		name, enabled, tables := ModuleModels(m)
+		if name == "" {
+			klog.Warningf("Unknown module type: %T", m)
+			continue
+		}
+		if !enabled {
+			klog.Infof("%s module is disabled, skipping DB migration", name)
+			continue
+		}
+		klog.Infof("Migrating DB tables for %s module", name)
+		if err := storageInstance.Migrate(tables...); err != nil {
+			klog.Fatalf("Failed to migrate %s tables: %v", name, err)
+		}
+	}
+}
 
-		case *v1alpha2.EventBus:
-			if !module.Enable {
-				klog.Info("EventBus module is disabled, skipping DB migration")
-				continue
-			}
-			klog.Info("Migrating DB tables for EventBus module")
-			if err := dbInstance.AutoMigrate(
-				&models.SubTopics{},
-			); err != nil {
-				klog.Fatalf("Failed to migrate EventBus tables: %v", err)
-			}
+// ModuleModels returns the name, whether it is enabled and the models saved of module,
+// the name is empty if the module does not save anything in db
+func ModuleModels(module interface{}) (string, bool, []interface{}) {
+	switch module := module.(type) {
+	case *v1alpha2.DeviceTwin:
+		return "DeviceTwin", module.Enable, []interface{}{
+			&models.Device{},
+			&models.DeviceAttr{},
+			&models.DeviceTwin{},
+		}
+	case *v1alpha2.EventBus:
+		return "EventBus", module.Enable, []interface{}{
+			&models.SubTopics{},
+			&models.MqttStore{},
+		}
+	case *v1alpha2.MetaManager:
+		return "MetaManager", module.Enable, []interface{}{
+			&models.Meta{},
+			&models.MetaV2{},
+			&models.MetaV2Label{},
+			&models.MetaV2PendingWrite{},
+			&models.DataEncryptionKey{},
+		}
+	case *v1alpha2.ServiceBus:
+		return "ServiceBus", module.Enable, []interface{}{
+			&models.TargetUrls{},
+		}
+	default:
+		return "", false, nil
+	}
+}
 
-		case *v1alpha2.MetaManager:
-			if !module.Enable {
-				klog.Info("MetaManager module is disabled, skipping DB migration")
-				continue
-			}
-			klog.Info("Migrating DB tables for MetaManager module")
-			if err := dbInstance.AutoMigrate(
-				&models.Meta{},
-				&models.MetaV2{},
-			); err != nil {
-				klog.Fatalf("Failed to migrate MetaManager tables: %v", err)
-			}
+// AllModels returns the models of all modules
+func AllModels() []interface{} {
+	var all []interface{}
+	for _, m := range []interface{}{
+		&v1alpha2.DeviceTwin{},
+		&v1alpha2.EventBus{},
+		&v1alpha2.MetaManager{},
+		&v1alpha2.ServiceBus{},
+	} {
+		_, _, tables := ModuleModels(m)
+		all = append(all, tables...)
+	}
+	return all
+}
 
-		case *v1alpha2.ServiceBus:
-			if !module.Enable {
-				klog.Info("ServiceBus module is disabled, skipping DB migration")
+// Copy copies the records of models from src to dst in one transaction, the tables
+// which do not exist in src are skipped. It returns the number of records copied.
+func Copy(src, dst Storage, models ...interface{}) (int, error) {
+	if err := dst.Migrate(models...); err != nil {
+		return 0, err
+	}
+	copied := 0
+	err := dst.Transaction(func(tx Storage) error {
+		for _, m := range models {
+			if !src.HasTable(m) {
 				continue
 			}
-			klog.Info("Migrating DB tables for ServiceBus module")
-			if err := dbInstance.AutoMigrate(
-				&models.TargetUrls{},
-			); err != nil {
-				klog.Fatalf("Failed to migrate ServiceBus tables: %v", err)
+			records := reflectSliceOf(m)
+			if err := src.Find(records.Interface(), Query{}); err != nil {
+				return fmt.Errorf("failed to read %T: %v", m, err)
+			}
+			for i := 0; i < records.Elem().Len(); i++ {
+				if err := tx.Save(records.Elem().Index(i).Addr().Interface()); err != nil {
+					return fmt.Errorf("failed to write %T: %v", m, err)
+				}
+				copied++
 			}
-
-		default:
-			klog.Warningf("Unknown module type: %T", m)
 		}
-	}
+		return nil
+	})
+	return copied, err
 }
 
-func GetDB() *gorm.DB {
-	return dbInstance
+// GetStorage returns the storage opened by Init
+func GetStorage() Storage {
+	return storageInstance
 }
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+const (
+	// resyncMarkerSuffix is the suffix of the file which marks the database is rebuilt
+	// and waits for the objects resent by the cloud
+	resyncMarkerSuffix = ".resync"
+	// quarantineSuffix is the suffix of the corrupted database files moved aside
+	quarantineSuffix = ".corrupt-"
+)
+
+// sqliteSideFiles are the files of SQLite besides the database file, which are
+// quarantined together with the corrupted database
+var sqliteSideFiles = []string{"-wal", "-shm", "-journal"}
+
+// openStorage opens the storage of db and checks its integrity if it is enabled.
+// The corrupted database is quarantined and a new one is created, which is marked
+// to be resynced with the cloud.
+func openStorage(db *v1alpha2.DataBase) (Storage, error) {
+	s, err := NewStorage(db.DriverName, db.DataSource)
+	if err == nil && db.Maintenance != nil && db.Maintenance.IntegrityCheck {
+		start := time.Now()
+		if err = s.Check(); err != nil {
+			s.Close()
+		} else {
+			klog.Infof("integrity check of database %s passed in %v", db.DataSource, time.Since(start))
+		}
+	}
+	if err == nil {
+		return s, nil
+	}
+	if !errors.Is(err, ErrCorrupted) {
+		return nil, err
+	}
+
+	klog.Errorf("database %s is corrupted, it will be rebuilt by resyncing with the cloud: %v", db.DataSource, err)
+	quarantined, err := Quarantine(db.DataSource)
+	if err != nil {
+		return nil, err
+	}
+	klog.Warningf("corrupted database is moved to %s", quarantined)
+	if err := MarkResync(db.DataSource); err != nil {
+		return nil, err
+	}
+	return NewStorage(db.DriverName, db.DataSource)
+}
+
+// Quarantine moves the database files at dataSource aside, and returns the path of
+// the database file moved
+func Quarantine(dataSource string) (string, error) {
+	quarantined := dataSource + quarantineSuffix + time.Now().Format("20060102T150405")
+	if err := os.Rename(dataSource, quarantined); err != nil {
+		return "", fmt.Errorf("failed to quarantine database %s: %v", dataSource, err)
+	}
+	for _, suffix := range sqliteSideFiles {
+		if err := os.Rename(dataSource+suffix, quarantined+suffix); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to quarantine database %s: %v", dataSource+suffix, err)
+		}
+	}
+	return quarantined, nil
+}
+
+// MarkResync marks the database at dataSource to be resynced with the cloud
+func MarkResync(dataSource string) error {
+	return os.WriteFile(dataSource+resyncMarkerSuffix, []byte(time.Now().Format(time.RFC3339)), 0600)
+}
+
+// NeedResync returns whether the database opened by Init is rebuilt and waits for
+// the objects resent by the cloud
+func NeedResync() bool {
+	if dbConfig == nil {
+		return false
+	}
+	_, err := os.Stat(dbConfig.DataSource + resyncMarkerSuffix)
+	return err == nil
+}
+
+// ResyncDone clears the mark after the cloud accepts to resend the objects
+func ResyncDone() error {
+	if dbConfig == nil {
+		return nil
+	}
+	err := os.Remove(dbConfig.DataSource + resyncMarkerSuffix)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// RunCompaction compacts the database opened by Init periodically in the compaction window
+func RunCompaction(stopCh <-chan struct{}) {
+	if dbConfig == nil || dbConfig.Maintenance == nil || dbConfig.Maintenance.CompactionInterval <= 0 {
+		return
+	}
+	m := dbConfig.Maintenance
+	interval := time.Duration(m.CompactionInterval) * time.Hour
+	// the database is compacted in the first window after edgecore starts
+	var last time.Time
+	wait.Until(func() {
+		now := time.Now()
+		if now.Sub(last) < interval || !inCompactionWindow(m, now) {
+			return
+		}
+		if err := storageInstance.Compact(); err != nil {
+			klog.Errorf("failed to compact database %s: %v", dbConfig.DataSource, err)
+			return
+		}
+		last = now
+		klog.Infof("database %s is compacted in %v", dbConfig.DataSource, time.Since(now))
+	}, time.Minute, stopCh)
+}
+
+// inCompactionWindow returns whether now is in the compaction window of m
+func inCompactionWindow(m *v1alpha2.DataBaseMaintenance, now time.Time) bool {
+	if m.CompactionWindow == "" {
+		return true
+	}
+	start, end, err := m.ParseCompactionWindow()
+	if err != nil {
+		return false
+	}
+	offset := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	if start <= end {
+		return offset >= start && offset < end
+	}
+	return offset >= start || offset < end
+}
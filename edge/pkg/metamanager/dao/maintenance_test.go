@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+)
+
+func TestInCompactionWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		window string
+		now    time.Time
+		want   bool
+	}{
+		{window: "", now: at(12, 0), want: true},
+		{window: "02:00-05:00", now: at(2, 0), want: true},
+		{window: "02:00-05:00", now: at(4, 59), want: true},
+		{window: "02:00-05:00", now: at(5, 0), want: false},
+		{window: "02:00-05:00", now: at(1, 59), want: false},
+		{window: "23:00-01:00", now: at(23, 30), want: true},
+		{window: "23:00-01:00", now: at(0, 30), want: true},
+		{window: "23:00-01:00", now: at(12, 0), want: false},
+		{window: "invalid", now: at(12, 0), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s at %s", tt.window, tt.now.Format("15:04")), func(t *testing.T) {
+			m := &v1alpha2.DataBaseMaintenance{CompactionWindow: tt.window}
+			assert.Equal(t, tt.want, inCompactionWindow(m, tt.now))
+		})
+	}
+}
+
+func TestCheckAndCompact(t *testing.T) {
+	for _, driverName := range drivers {
+		t.Run(driverName, func(t *testing.T) {
+			s := newTestStorage(t, driverName)
+			for i := 0; i < 100; i++ {
+				require.NoError(t, s.Create(&models.Meta{Key: fmt.Sprintf("default/pod/p%d", i), Type: "pod",
+					Value: string(bytes.Repeat([]byte("v"), 1024))}))
+			}
+			_, err := s.Delete(&models.Meta{}, Condition{Column: "key", Operator: NotEqual, Value: "default/pod/p0"})
+			require.NoError(t, err)
+
+			require.NoError(t, s.Check())
+			require.NoError(t, s.Compact())
+			require.NoError(t, s.Check())
+
+			count, err := s.Count(&models.Meta{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+			require.NoError(t, s.Create(&models.Meta{Key: "default/pod/p1", Type: "pod"}))
+		})
+	}
+}
+
+func TestOpenStorageCorrupted(t *testing.T) {
+	for _, driverName := range drivers {
+		t.Run(driverName, func(t *testing.T) {
+			db := &v1alpha2.DataBase{
+				DriverName: driverName,
+				DataSource: filepath.Join(t.TempDir(), "edgecore.db"),
+				Maintenance: &v1alpha2.DataBaseMaintenance{
+					IntegrityCheck: true,
+				},
+			}
+			require.NoError(t, os.WriteFile(db.DataSource, bytes.Repeat([]byte("corrupted"), 1024), 0600))
+			dbConfig = db
+			t.Cleanup(func() { dbConfig = nil })
+			assert.False(t, NeedResync())
+
+			s, err := openStorage(db)
+			require.NoError(t, err)
+			defer s.Close()
+			require.NoError(t, s.Migrate(&models.Meta{}))
+			require.NoError(t, s.Check())
+
+			quarantined, err := filepath.Glob(db.DataSource + quarantineSuffix + "*")
+			require.NoError(t, err)
+			assert.Len(t, quarantined, 1)
+			assert.True(t, NeedResync())
+			require.NoError(t, ResyncDone())
+			assert.False(t, NeedResync())
+		})
+	}
+}
@@ -22,19 +22,30 @@ const (
 	DeviceTwinTableName = "device_twin"
 
 	SubTopicsName = "sub_topics"
+	MqttStoreName = "mqtt_store"
 
 	TargetUrlsName = "target_urls"
 
-	MetaTableName    = "meta"
-	NewMetaTableName = "meta_v2"
+	MetaTableName        = "meta"
+	NewMetaTableName     = "meta_v2"
+	MetaV2LabelTableName = "meta_v2_label"
+	// MetaV2PendingWriteTableName is the table of writes served by MetaServer offline
+	MetaV2PendingWriteTableName = "meta_v2_pending_write"
+	// DataEncryptionKeyTableName is the table of key to encrypt the values at rest
+	DataEncryptionKeyTableName = "data_encryption_key"
 )
 
 const (
-	KEY  = "key"
-	GVR  = "group_version_resource"
-	NS   = "namespace"
-	NAME = "name"
-	RV   = "resource_version"
+	KEY   = "key"
+	GVR   = "group_version_resource"
+	NS    = "namespace"
+	NAME  = "name"
+	RV    = "resource_version"
+	TYPE  = "type"
+	VALUE = "value"
+
+	LabelName  = "name"
+	LabelValue = "value"
 
 	NullNamespace = "null"
 	GroupCore     = "core"
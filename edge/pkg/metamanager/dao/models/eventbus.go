@@ -24,3 +24,15 @@ type SubTopics struct {
 func (SubTopics) TableName() string {
 	return SubTopicsName
 }
+
+// MqttStore stores the sessions, subscriptions, retained and inflight messages of internal mqtt broker
+type MqttStore struct {
+	Key   string `gorm:"column:key;type:text;primaryKey"`
+	Type  string `gorm:"column:type;type:text;index"`
+	Value []byte `gorm:"column:value;type:blob"`
+}
+
+// TableName returns the name of the table in the DB
+func (MqttStore) TableName() string {
+	return MqttStoreName
+}
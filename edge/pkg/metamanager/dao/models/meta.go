@@ -31,14 +31,65 @@ func (Meta) TableName() string {
 // MetaV2 record k8s api object
 type MetaV2 struct {
 	Key                  string `gorm:"column:key;primaryKey;size:256"`
-	GroupVersionResource string `gorm:"column:group_version_resource;size:256"`
-	Namespace            string `gorm:"column:namespace;size:256"`
+	GroupVersionResource string `gorm:"column:group_version_resource;size:256;index:idx_meta_v2_gvr_ns"`
+	Namespace            string `gorm:"column:namespace;size:256;index:idx_meta_v2_gvr_ns"`
 	Name                 string `gorm:"column:name;size:256"`
 	ResourceVersion      uint64 `gorm:"column:resource_version"`
 	Value                string `gorm:"column:value;type:text"`
+
+	// Labels are the labels of the object, they are saved into table meta_v2_label
+	Labels map[string]string `gorm:"-"`
 }
 
 // TableName sets the insert table name for this struct type
 func (MetaV2) TableName() string {
 	return NewMetaTableName
 }
+
+// MetaV2Label record the labels of k8s api object in meta_v2, the label selectors
+// of list/watch are pushed down to the query of this table
+type MetaV2Label struct {
+	Key   string `gorm:"column:key;primaryKey;size:256"`
+	Name  string `gorm:"column:name;primaryKey;size:320;index:idx_meta_v2_label_name_value"`
+	Value string `gorm:"column:value;size:64;index:idx_meta_v2_label_name_value"`
+}
+
+// TableName sets the insert table name for this struct type
+func (MetaV2Label) TableName() string {
+	return MetaV2LabelTableName
+}
+
+// MetaV2PendingWrite record the write served by MetaServer while EdgeCore is disconnected
+// from CloudCore, it is replayed to cloud in the order of ID after reconnected
+type MetaV2PendingWrite struct {
+	ID          uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	Key         string `gorm:"column:key;size:256;index"`
+	Verb        string `gorm:"column:verb;size:32"`
+	RequestInfo string `gorm:"column:request_info;type:text"`
+	User        string `gorm:"column:user;size:256"`
+	Option      string `gorm:"column:option;type:text"`
+	ReqBody     string `gorm:"column:req_body;type:text"`
+	// BaseResourceVersion is the resource version of the object in cloud which the write is based on
+	BaseResourceVersion string `gorm:"column:base_resource_version;size:64"`
+}
+
+// TableName sets the insert table name for this struct type
+func (MetaV2PendingWrite) TableName() string {
+	return MetaV2PendingWriteTableName
+}
+
+// DataEncryptionKey record the key which encrypts the secrets and service account tokens
+// in meta and meta_v2, it is saved after wrapped by the key encryption key of Provider
+type DataEncryptionKey struct {
+	ID         uint64 `gorm:"column:id;primaryKey"`
+	Provider   string `gorm:"column:provider;size:16"`
+	KeyID      string `gorm:"column:key_id;size:256"`
+	WrappedKey []byte `gorm:"column:wrapped_key"`
+	// Annotations are the annotations returned by KMS plugin when the key is wrapped, in json
+	Annotations string `gorm:"column:annotations;type:text"`
+}
+
+// TableName sets the insert table name for this struct type
+func (DataEncryptionKey) TableName() string {
+	return DataEncryptionKeyTableName
+}
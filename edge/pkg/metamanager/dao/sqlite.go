@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sqliteStorage is the Storage of SQLite, it is the default storage
+type sqliteStorage struct {
+	db *gorm.DB
+}
+
+func newSQLiteStorage(dataSource string) (Storage, error) {
+	db, err := gorm.Open(sqlite.Open(dataSource), &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, sqliteError(err)
+	}
+	return &sqliteStorage{db: db}, nil
+}
+
+func (s *sqliteStorage) Migrate(models ...interface{}) error {
+	return s.db.AutoMigrate(models...)
+}
+
+func (s *sqliteStorage) HasTable(model interface{}) bool {
+	return s.db.Migrator().HasTable(model)
+}
+
+func (s *sqliteStorage) Create(record interface{}) error {
+	return s.db.Create(record).Error
+}
+
+func (s *sqliteStorage) Save(record interface{}) error {
+	return s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(record).Error
+}
+
+func (s *sqliteStorage) Update(model interface{}, values map[string]interface{}, conds ...Condition) (int64, error) {
+	tx := where(s.db.Model(model), conds)
+	result := tx.Updates(values)
+	return result.RowsAffected, result.Error
+}
+
+func (s *sqliteStorage) Delete(model interface{}, conds ...Condition) (int64, error) {
+	tx := where(s.db, conds)
+	result := tx.Delete(newModel(model))
+	return result.RowsAffected, result.Error
+}
+
+func (s *sqliteStorage) Find(dest interface{}, query Query) error {
+	return s.query(query).Find(dest).Error
+}
+
+func (s *sqliteStorage) First(dest interface{}, query Query) error {
+	query.Limit = 1
+	tx := s.query(query).Find(dest)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *sqliteStorage) Count(model interface{}, conds ...Condition) (int64, error) {
+	var count int64
+	err := where(s.db.Model(model), conds).Count(&count).Error
+	return count, err
+}
+
+func (s *sqliteStorage) Transaction(fn func(tx Storage) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&sqliteStorage{db: tx})
+	})
+}
+
+func (s *sqliteStorage) Check() error {
+	rows, err := s.db.Raw("PRAGMA integrity_check").Rows()
+	if err != nil {
+		return sqliteError(err)
+	}
+	defer rows.Close()
+	var results []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return sqliteError(err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return sqliteError(err)
+	}
+	if len(results) == 1 && results[0] == "ok" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrCorrupted, strings.Join(results, "; "))
+}
+
+func (s *sqliteStorage) Compact() error {
+	return sqliteError(s.db.Exec("VACUUM").Error)
+}
+
+func (s *sqliteStorage) Close() error {
+	db, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+func (s *sqliteStorage) query(query Query) *gorm.DB {
+	tx := where(s.db, query.Conditions)
+	if query.OrderBy != "" {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: query.OrderBy}, Desc: query.Desc})
+	}
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+	return tx
+}
+
+// sqliteError wraps ErrCorrupted into err if it is caused by the corrupted database file
+func sqliteError(err error) error {
+	var e sqlite3.Error
+	if errors.As(err, &e) && (e.Code == sqlite3.ErrCorrupt || e.Code == sqlite3.ErrNotADB) {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return err
+}
+
+// where adds the conditions to tx, all records are matched if conds is empty
+func where(tx *gorm.DB, conds []Condition) *gorm.DB {
+	if len(conds) == 0 {
+		return tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+	}
+	for _, c := range conds {
+		column := c.Column
+		switch c.Operator {
+		case Equal, NotEqual, GreaterThan:
+			tx = tx.Where(column+" "+string(c.Operator)+" ?", c.Value)
+		case In:
+			tx = tx.Where(column+" IN ?", c.Value)
+		case NotIn:
+			// NOT IN an empty set is NULL in SQL, which matches nothing
+			if reflect.ValueOf(c.Value).Len() > 0 {
+				tx = tx.Where(column+" NOT IN ?", c.Value)
+			}
+		case HasPrefix:
+			tx = tx.Where("instr("+column+", ?) = 1", c.Value)
+		case Contains:
+			tx = tx.Where("instr("+column+", ?) > 0", c.Value)
+		}
+	}
+	return tx
+}
+
+// newModel returns a pointer to a new zero value of model
+func newModel(model interface{}) interface{} {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return reflect.New(t).Interface()
+}
+
+// reflectSliceOf returns a pointer to a new empty slice of model
+func reflectSliceOf(model interface{}) reflect.Value {
+	return reflect.New(reflect.SliceOf(reflect.TypeOf(newModel(model)).Elem()))
+}
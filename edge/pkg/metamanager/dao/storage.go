@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+var (
+	// ErrRecordNotFound is returned by First if no record is matched
+	ErrRecordNotFound = gorm.ErrRecordNotFound
+	// ErrDuplicatedKey is returned by Create if the primary key exists
+	ErrDuplicatedKey = gorm.ErrDuplicatedKey
+	// ErrCorrupted is returned if the database file is corrupted
+	ErrCorrupted = errors.New("database is corrupted")
+)
+
+// Operator is the operator of Condition
+type Operator string
+
+const (
+	Equal       Operator = "="
+	NotEqual    Operator = "!="
+	GreaterThan Operator = ">"
+	In          Operator = "IN"
+	NotIn       Operator = "NOT IN"
+	// HasPrefix matches the strings starting with the value of condition
+	HasPrefix Operator = "PREFIX"
+	// Contains matches the strings containing the value of condition
+	Contains Operator = "CONTAINS"
+)
+
+// Condition filters the records by the value of column
+type Condition struct {
+	Column   string
+	Operator Operator
+	// Value is a slice for In and NotIn
+	Value interface{}
+}
+
+// Eq returns the condition that the value of column equals to value
+func Eq(column string, value interface{}) Condition {
+	return Condition{Column: column, Operator: Equal, Value: value}
+}
+
+// Query selects the records to find
+type Query struct {
+	Conditions []Condition
+	// OrderBy is the column which the records are sorted by, the order is undefined if it is empty
+	OrderBy string
+	Desc    bool
+	// Limit is the max number of records, 0 means no limit
+	Limit int
+}
+
+// Storage is the backend which the edge metadata is saved in. The records are the models
+// defined in package models, they are identified by the primary keys and the columns are
+// referred by the column names of gorm tags.
+type Storage interface {
+	// Migrate creates the tables of the models if they do not exist
+	Migrate(models ...interface{}) error
+	// HasTable returns whether the table of model exists
+	HasTable(model interface{}) bool
+	// Create inserts record, which is a pointer to model. ErrDuplicatedKey is returned if the
+	// primary key exists, and the auto increment primary key is set into record.
+	Create(record interface{}) error
+	// Save inserts or replaces record, which is a pointer to model
+	Save(record interface{}) error
+	// Update updates the columns of the records of model matched by conds
+	Update(model interface{}, values map[string]interface{}, conds ...Condition) (int64, error)
+	// Delete deletes the records of model matched by conds, all records are deleted if conds is empty
+	Delete(model interface{}, conds ...Condition) (int64, error)
+	// Find finds the records matched by query into dest, which is a pointer to slice of model
+	Find(dest interface{}, query Query) error
+	// First finds the first record matched by query into dest, which is a pointer to model.
+	// ErrRecordNotFound is returned if no record is matched.
+	First(dest interface{}, query Query) error
+	// Count returns the number of records of model matched by conds
+	Count(model interface{}, conds ...Condition) (int64, error)
+	// Transaction runs fn in a transaction, which is rolled back if fn returns error
+	Transaction(fn func(tx Storage) error) error
+	// Check checks the integrity of the database, ErrCorrupted is wrapped in the returned
+	// error if the database is corrupted
+	Check() error
+	// Compact rebuilds the database file to reclaim the free pages
+	Compact() error
+	// Close closes the storage
+	Close() error
+}
+
+// NewStorage opens the storage of driver at dataSource
+func NewStorage(driverName, dataSource string) (Storage, error) {
+	switch driverName {
+	case v1alpha2.DataBaseDriverName, "":
+		return newSQLiteStorage(dataSource)
+	case v1alpha2.DataBaseDriverBbolt:
+		return newBoltStorage(dataSource)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %s", driverName)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+)
+
+var drivers = []string{v1alpha2.DataBaseDriverName, v1alpha2.DataBaseDriverBbolt}
+
+func newTestStorage(t *testing.T, driverName string) Storage {
+	s, err := NewStorage(driverName, filepath.Join(t.TempDir(), "edgecore.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	require.NoError(t, s.Migrate(AllModels()...))
+	return s
+}
+
+func metaKeys(metas []models.Meta) []string {
+	var keys []string
+	for _, m := range metas {
+		keys = append(keys, m.Key)
+	}
+	return keys
+}
+
+func TestStorage(t *testing.T) {
+	for _, driverName := range drivers {
+		t.Run(driverName, func(t *testing.T) {
+			s := newTestStorage(t, driverName)
+			assert.True(t, s.HasTable(&models.Meta{}))
+
+			for _, m := range []models.Meta{
+				{Key: "default/pod/p1", Type: "pod", Value: "uid-1"},
+				{Key: "default/pod/p2", Type: "pod", Value: "uid-2"},
+				{Key: "default/configmap/c1", Type: "configmap", Value: "cm"},
+			} {
+				require.NoError(t, s.Create(&m))
+			}
+			err := s.Create(&models.Meta{Key: "default/pod/p1"})
+			assert.True(t, errors.Is(err, ErrDuplicatedKey))
+
+			var metas []models.Meta
+			require.NoError(t, s.Find(&metas, Query{OrderBy: "key"}))
+			assert.Equal(t, []string{"default/configmap/c1", "default/pod/p1", "default/pod/p2"}, metaKeys(metas))
+
+			metas = nil
+			require.NoError(t, s.Find(&metas, Query{Conditions: []Condition{Eq("type", "pod")}, OrderBy: "key", Desc: true, Limit: 1}))
+			assert.Equal(t, []string{"default/pod/p2"}, metaKeys(metas))
+
+			metas = nil
+			require.NoError(t, s.Find(&metas, Query{Conditions: []Condition{
+				{Column: "key", Operator: HasPrefix, Value: "default/pod/"},
+				{Column: "value", Operator: NotIn, Value: []string{"uid-2"}},
+			}}))
+			assert.Equal(t, []string{"default/pod/p1"}, metaKeys(metas))
+
+			rows, err := s.Update(&models.Meta{}, map[string]interface{}{"value": "uid-3"}, Eq("key", "default/pod/p2"))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), rows)
+			var meta models.Meta
+			require.NoError(t, s.First(&meta, Query{Conditions: []Condition{{Column: "value", Operator: Contains, Value: "3"}}}))
+			assert.Equal(t, "default/pod/p2", meta.Key)
+
+			require.NoError(t, s.Save(&models.Meta{Key: "default/pod/p2", Type: "pod", Value: "uid-4"}))
+			count, err := s.Count(&models.Meta{}, Eq("value", "uid-4"))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+
+			rows, err = s.Delete(&models.Meta{}, Eq("type", "pod"))
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), rows)
+			assert.True(t, errors.Is(s.First(&meta, Query{Conditions: []Condition{Eq("type", "pod")}}), ErrRecordNotFound))
+		})
+	}
+}
+
+func TestStorageAutoIncrement(t *testing.T) {
+	for _, driverName := range drivers {
+		t.Run(driverName, func(t *testing.T) {
+			s := newTestStorage(t, driverName)
+			for i := 0; i < 3; i++ {
+				require.NoError(t, s.Create(&models.MetaV2PendingWrite{Key: "k", Verb: "create"}))
+			}
+			var writes []models.MetaV2PendingWrite
+			require.NoError(t, s.Find(&writes, Query{OrderBy: "id", Desc: true}))
+			require.Len(t, writes, 3)
+			assert.Equal(t, uint64(3), writes[0].ID)
+			assert.Equal(t, uint64(1), writes[2].ID)
+
+			_, err := s.Delete(&models.MetaV2PendingWrite{}, Condition{Column: "id", Operator: GreaterThan, Value: 1})
+			require.NoError(t, err)
+			count, err := s.Count(&models.MetaV2PendingWrite{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+		})
+	}
+}
+
+func TestStorageTransaction(t *testing.T) {
+	for _, driverName := range drivers {
+		t.Run(driverName, func(t *testing.T) {
+			s := newTestStorage(t, driverName)
+			rollback := errors.New("rollback")
+			err := s.Transaction(func(tx Storage) error {
+				if err := tx.Create(&models.SubTopics{Topic: "a"}); err != nil {
+					return err
+				}
+				// the record is visible in the transaction
+				count, err := tx.Count(&models.SubTopics{})
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), count)
+				return rollback
+			})
+			assert.Equal(t, rollback, err)
+			count, err := s.Count(&models.SubTopics{})
+			require.NoError(t, err)
+			assert.Zero(t, count)
+		})
+	}
+}
+
+func TestCopy(t *testing.T) {
+	src := newTestStorage(t, v1alpha2.DataBaseDriverName)
+	require.NoError(t, src.Create(&models.Meta{Key: "default/pod/p1", Type: "pod", Value: "v"}))
+	require.NoError(t, src.Create(&models.MetaV2Label{Key: "/core/v1/pods/default/p1", Name: "app", Value: "web"}))
+	require.NoError(t, src.Create(&models.DeviceTwin{DeviceID: "d1", Name: "temperature"}))
+
+	dst, err := NewStorage(v1alpha2.DataBaseDriverBbolt, filepath.Join(t.TempDir(), "edgecore.bolt"))
+	require.NoError(t, err)
+	defer dst.Close()
+	copied, err := Copy(src, dst, AllModels()...)
+	require.NoError(t, err)
+	assert.Equal(t, 3, copied)
+
+	var twin models.DeviceTwin
+	require.NoError(t, dst.First(&twin, Query{Conditions: []Condition{Eq("deviceid", "d1")}}))
+	assert.Equal(t, int64(1), twin.ID)
+	// the sequence of auto increment continues after the copied records
+	require.NoError(t, dst.Create(&models.DeviceTwin{DeviceID: "d1", Name: "humidity"}))
+	count, err := dst.Count(&models.DeviceTwin{}, Eq("deviceid", "d1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
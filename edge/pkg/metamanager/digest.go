@@ -0,0 +1,98 @@
+package metamanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+	metaserverutil "github.com/kubeedge/kubeedge/pkg/metaserver/util"
+)
+
+// digestSyncing makes sure only one object digest sync runs at a time
+var digestSyncing atomic.Bool
+
+// syncObjectDigest compares the digest of the objects stored in edge with the cloud after
+// reconnection, so that the cloud only resends the objects missing or changed in edge.
+// The digests of the resources synced with ObjectSyncs are sent first, then the versions
+// of the objects of the resources whose digests mismatch.
+func syncObjectDigest() {
+	if !digestSyncing.CompareAndSwap(false, true) {
+		return
+	}
+	defer digestSyncing.Store(false)
+
+	// all the objects are resent if the database is rebuilt
+	if dao.NeedResync() {
+		return
+	}
+	versions, err := dbclient.NewMetaV2Service().ListResourceVersions()
+	if err != nil {
+		klog.Errorf("failed to list the resource versions of objects: %v", err)
+		return
+	}
+
+	digest := commontypes.ObjectDigest{Digests: make(map[string]commontypes.ResourceDigest, len(versions))}
+	for gvr, v := range versions {
+		if metaserverutil.IsDigestResource(gvr) {
+			digest.Digests[gvr] = metaserverutil.NewResourceDigest(v)
+		}
+	}
+	resp, err := sendObjectDigest(digest)
+	if err != nil {
+		klog.Errorf("failed to sync object digest: %v", err)
+		return
+	}
+	if len(resp.Mismatched) == 0 {
+		klog.Info("object digest matches with the cloud, no object needs to be resent")
+		return
+	}
+
+	mismatched := resp.Mismatched
+	digest = commontypes.ObjectDigest{Versions: make(map[string]map[string]string, len(mismatched))}
+	for _, gvr := range mismatched {
+		// the resources not in edge are sent with empty versions, so that all their objects are resent
+		v := versions[gvr]
+		if v == nil {
+			v = map[string]string{}
+		}
+		digest.Versions[gvr] = v
+	}
+	resp, err = sendObjectDigest(digest)
+	if err != nil {
+		klog.Errorf("failed to sync object versions of %v: %v", mismatched, err)
+		return
+	}
+	klog.Infof("object digest mismatches with the cloud in %v, %d objects are resynced", mismatched, resp.Resynced)
+}
+
+func sendObjectDigest(digest commontypes.ObjectDigest) (*commontypes.ObjectDigestResponse, error) {
+	msg := message.BuildMsg(modules.MetaGroup, "", modules.MetaManagerModuleName,
+		constants.ResourceTypeObjectDigest, model.UpdateOperation, digest)
+	resp, err := beehiveContext.SendSync(modules.EdgeHubModuleName, *msg, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	content, err := resp.GetContentData()
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetOperation() == model.ResponseErrorOperation {
+		return nil, fmt.Errorf("rejected by the cloud: %s", content)
+	}
+	var digestResp commontypes.ObjectDigestResponse
+	if err := json.Unmarshal(content, &digestResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the response: %v", err)
+	}
+	return &digestResp, nil
+}
@@ -20,11 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -80,6 +82,10 @@ func (a *Agent) Generate(ctx context.Context, verb metaserver.ApplicationVerb, o
 	if err != nil {
 		return nil, err
 	}
+	// the service account of edge client is authorized by cloud if the node is not allowed
+	if user, ok := apirequest.UserFrom(ctx); ok && strings.HasPrefix(user.GetName(), serviceaccount.ServiceAccountUsernamePrefix) {
+		app.ServiceAccount = user.GetName()
+	}
 	store, ok := a.Applications.LoadOrStore(app.Identifier(), app)
 	if ok {
 		app = store.(*metaserver.Application)
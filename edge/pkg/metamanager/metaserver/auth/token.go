@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"fmt"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/edged/kubeclientbridge"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/client"
+	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
+)
+
+// NewServiceAccountTokenAuthenticator returns the authenticator of service account tokens,
+// the tokens are verified with the issuers, audiences and public keys configured in MetaServer
+func NewServiceAccountTokenAuthenticator() (authenticator.Token, error) {
+	allPublicKeys := []interface{}{}
+	for _, keyfile := range metaserverconfig.Config.ServiceAccountKeyFiles {
+		publicKeys, err := keyutil.PublicKeysFromFile(keyfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load public key file %s: %v", keyfile, err)
+		}
+		allPublicKeys = append(allPublicKeys, publicKeys...)
+	}
+	return JWTTokenAuthenticator(nil,
+		metaserverconfig.Config.ServiceAccountIssuers, allPublicKeys, metaserverconfig.Config.APIAudiences,
+		NewValidator(client.NewGetterFromClient(kubeclientbridge.NewSimpleClientset(client.New())))), nil
+}
@@ -0,0 +1,647 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/agent"
+	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator/watchhook"
+	"github.com/kubeedge/kubeedge/pkg/metaserver"
+	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+)
+
+// OfflineWriteConflictReason is the reason of the event reported when an offline write is rejected by cloud
+const OfflineWriteConflictReason = "OfflineWriteConflict"
+
+// offlineLock serializes the offline writes, so that the resource version of the local
+// object is checked and bumped atomically
+var offlineLock sync.Mutex
+
+// offlineWritable returns whether the write failed for the connection lost can be
+// served at local, only the configured resources are written offline
+func offlineWritable(ctx context.Context, err error) bool {
+	if !stderrors.Is(err, connect.ErrConnectionLost) {
+		return false
+	}
+	c := metaserverconfig.Config.OfflineWrite
+	if c == nil || !c.Enable {
+		return false
+	}
+	info, ok := apirequest.RequestInfoFrom(ctx)
+	if !ok || !info.IsResourceRequest || (info.Subresource != "" && info.Subresource != "status") {
+		return false
+	}
+	gr := schema.GroupResource{Group: info.APIGroup, Resource: info.Resource}
+	for _, r := range c.Resources {
+		if schema.ParseGroupResource(r) == gr {
+			return true
+		}
+	}
+	return false
+}
+
+func requestGVK(info *apirequest.RequestInfo, obj *unstructured.Unstructured) schema.GroupVersionKind {
+	// the kind guessed from resource may be wrong in case, e.g. configmaps -> Configmap
+	if obj != nil && obj.GetKind() != "" {
+		return obj.GroupVersionKind()
+	}
+	return schema.GroupVersionKind{
+		Group:   info.APIGroup,
+		Version: info.APIVersion,
+		Kind:    util.UnsafeResourceToKind(info.Resource),
+	}
+}
+
+func getLocalObj(ctx context.Context, key string) (*unstructured.Unstructured, error) {
+	resp, err := imitator.DefaultV2Client.Get(ctx, key)
+	if err != nil {
+		gvr, _, name := metaserver.ParseKey(key)
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+	}
+	obj := new(unstructured.Unstructured)
+	if err := runtime.DecodeInto(unstructured.UnstructuredJSONScheme, []byte((*resp.Kvs)[0].Value), obj); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return obj, nil
+}
+
+// saveLocalObj bumps the resource version of obj and saves it, the watchers at local are notified
+func saveLocalObj(ctx context.Context, obj *unstructured.Unstructured, t watch.EventType) error {
+	obj.SetResourceVersion(strconv.FormatUint(imitator.DefaultV2Client.GetRevision()+1, 10))
+	if err := imitator.DefaultV2Client.InsertOrUpdateObj(ctx, obj); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	watchhook.Trigger(watch.Event{Type: t, Object: obj})
+	return nil
+}
+
+// checkPendingWrites returns an error if too many writes are waiting to be reconciled
+func checkPendingWrites() error {
+	count, err := dbclient.NewMetaV2Service().CountPendingWrites()
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if count >= int64(metaserverconfig.Config.OfflineWrite.MaxPendingWrites) {
+		return apierrors.NewTooManyRequests("too many offline writes are waiting to be reconciled with cloud", 10)
+	}
+	return nil
+}
+
+// baseResourceVersion returns the resource version in cloud of the object which is written offline
+func baseResourceVersion(key string, existing *unstructured.Unstructured) (string, error) {
+	writes, err := dbclient.NewMetaV2Service().ListPendingWritesByKey(key)
+	if err != nil {
+		return "", apierrors.NewInternalError(err)
+	}
+	if len(writes) > 0 {
+		return writes[0].BaseResourceVersion, nil
+	}
+	if existing == nil {
+		return "", nil
+	}
+	return existing.GetResourceVersion(), nil
+}
+
+// queueWrite saves the write served offline, it is replayed to cloud after reconnected
+func queueWrite(ctx context.Context, verb metaserver.ApplicationVerb, key, baseRV string, option interface{}, body interface{}) error {
+	info, _ := apirequest.RequestInfoFrom(ctx)
+	w := &models.MetaV2PendingWrite{
+		Key:                 key,
+		Verb:                string(verb),
+		RequestInfo:         string(metaserver.ToBytes(info)),
+		Option:              string(metaserver.ToBytes(option)),
+		ReqBody:             string(metaserver.ToBytes(body)),
+		BaseResourceVersion: baseRV,
+	}
+	if u, ok := apirequest.UserFrom(ctx); ok {
+		w.User = u.GetName()
+	}
+	if err := dbclient.NewMetaV2Service().AddPendingWrite(w); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	klog.Infof("[metaserver/offline] queue offline write %s %s", verb, key)
+	return nil
+}
+
+func (r *REST) createOffline(ctx context.Context, obj runtime.Object, options *metav1.CreateOptions) (runtime.Object, error) {
+	offlineLock.Lock()
+	defer offlineLock.Unlock()
+
+	info, _ := apirequest.RequestInfoFrom(ctx)
+	unstr, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("obj is not unstructured type"))
+	}
+	unstr.SetGroupVersionKind(requestGVK(info, unstr))
+	if unstr.GetNamespace() == "" {
+		unstr.SetNamespace(info.Namespace)
+	}
+	if unstr.GetName() == "" && unstr.GetGenerateName() != "" {
+		unstr.SetName(names.SimpleNameGenerator.GenerateName(unstr.GetGenerateName()))
+	}
+	key, err := metaserver.KeyFuncObj(unstr)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if _, err := getLocalObj(ctx, key); err == nil {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{Group: info.APIGroup, Resource: info.Resource}, unstr.GetName())
+	}
+	if err := checkPendingWrites(); err != nil {
+		return nil, err
+	}
+
+	body := unstr.DeepCopy()
+	unstr.SetUID(uuid.NewUUID())
+	unstr.SetCreationTimestamp(metav1.Now())
+	if err := saveLocalObj(ctx, unstr, watch.Added); err != nil {
+		return nil, err
+	}
+	if err := queueWrite(ctx, metaserver.Create, key, "", options, body); err != nil {
+		return nil, err
+	}
+	return unstr, nil
+}
+
+func (r *REST) updateOffline(ctx context.Context, obj runtime.Object, options *metav1.UpdateOptions) (runtime.Object, error) {
+	offlineLock.Lock()
+	defer offlineLock.Unlock()
+
+	info, _ := apirequest.RequestInfoFrom(ctx)
+	gr := schema.GroupResource{Group: info.APIGroup, Resource: info.Resource}
+	unstr, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("obj is not unstructured type"))
+	}
+	key, err := metaserver.KeyFuncReq(ctx, "")
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	existing, err := getLocalObj(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	// local optimistic concurrency, the update must be based on the latest local object
+	if rv := unstr.GetResourceVersion(); rv != "" && rv != existing.GetResourceVersion() {
+		return nil, apierrors.NewConflict(gr, info.Name,
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+	baseRV, err := baseResourceVersion(key, existing)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPendingWrites(); err != nil {
+		return nil, err
+	}
+
+	body := unstr.DeepCopy()
+	updated := unstr.DeepCopy()
+	verb := metaserver.Update
+	if info.Subresource == "status" {
+		verb = metaserver.UpdateStatus
+		updated = existing.DeepCopy()
+		if status, ok := unstr.Object["status"]; ok {
+			updated.Object["status"] = status
+		}
+	}
+	updated.SetGroupVersionKind(requestGVK(info, existing))
+	updated.SetUID(existing.GetUID())
+	updated.SetCreationTimestamp(existing.GetCreationTimestamp())
+	if err := saveLocalObj(ctx, updated, watch.Modified); err != nil {
+		return nil, err
+	}
+	if err := queueWrite(ctx, verb, key, baseRV, options, body); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *REST) patchOffline(ctx context.Context, pi metaserver.PatchInfo) (runtime.Object, error) {
+	offlineLock.Lock()
+	defer offlineLock.Unlock()
+
+	info, _ := apirequest.RequestInfoFrom(ctx)
+	key, err := metaserver.KeyFuncReq(ctx, "")
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	// server-side apply needs the managed fields resolved by cloud, it can't be served at local
+	if pi.PatchType == types.ApplyPatchType {
+		return nil, apierrors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "patch",
+			schema.GroupResource{Group: info.APIGroup, Resource: info.Resource}, info.Name,
+			"server-side apply is not supported while disconnected from cloud", 0, false)
+	}
+	existing, err := getLocalObj(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	baseRV, err := baseResourceVersion(key, existing)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPendingWrites(); err != nil {
+		return nil, err
+	}
+
+	gvk := existing.GroupVersionKind()
+	original, err := existing.MarshalJSON()
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	patched, err := applyPatch(original, pi, gvk)
+	if err != nil {
+		return nil, err
+	}
+	updated := new(unstructured.Unstructured)
+	if err := updated.UnmarshalJSON(patched); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if updated.GetKind() == "" {
+		updated.SetGroupVersionKind(gvk)
+	}
+	updated.SetUID(existing.GetUID())
+	updated.SetCreationTimestamp(existing.GetCreationTimestamp())
+	if err := saveLocalObj(ctx, updated, watch.Modified); err != nil {
+		return nil, err
+	}
+	if err := queueWrite(ctx, metaserver.Patch, key, baseRV, pi, nil); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// applyPatch applies the patch to the object at local, strategic merge patch is supported
+// for the built-in types only.
+func applyPatch(original []byte, pi metaserver.PatchInfo, gvk schema.GroupVersionKind) ([]byte, error) {
+	var (
+		patched []byte
+		err     error
+	)
+	switch pi.PatchType {
+	case types.JSONPatchType:
+		var patch jsonpatch.Patch
+		if patch, err = jsonpatch.DecodePatch(pi.Data); err == nil {
+			patched, err = patch.Apply(original)
+		}
+	case types.MergePatchType:
+		patched, err = jsonpatch.MergePatch(original, pi.Data)
+	case types.StrategicMergePatchType:
+		dataStruct, schemeErr := scheme.Scheme.New(gvk)
+		if schemeErr != nil {
+			return nil, apierrors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "patch", schema.GroupResource{Group: gvk.Group}, "",
+				fmt.Sprintf("strategic merge patch is not supported for %s", gvk.String()), 0, false)
+		}
+		patched, err = strategicpatch.StrategicMergePatch(original, pi.Data, dataStruct)
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unsupported patch type %s", pi.PatchType))
+	}
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	return patched, nil
+}
+
+func (r *REST) deleteOffline(ctx context.Context, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	offlineLock.Lock()
+	defer offlineLock.Unlock()
+
+	key, err := metaserver.KeyFuncReq(ctx, "")
+	if err != nil {
+		return nil, false, apierrors.NewBadRequest(err.Error())
+	}
+	existing, err := getLocalObj(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	baseRV, err := baseResourceVersion(key, existing)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := checkPendingWrites(); err != nil {
+		return nil, false, err
+	}
+	if err := imitator.DefaultV2Client.DeleteObj(ctx, existing); err != nil {
+		return nil, false, apierrors.NewInternalError(err)
+	}
+	watchhook.Trigger(watch.Event{Type: watch.Deleted, Object: existing})
+	if err := queueWrite(ctx, metaserver.Delete, key, baseRV, options, nil); err != nil {
+		return nil, false, err
+	}
+	return existing, true, nil
+}
+
+// ReconcileOfflineWrites replays the writes served offline to cloud periodically
+// after EdgeCore reconnects to CloudCore
+func ReconcileOfflineWrites(stopCh <-chan struct{}) {
+	c := metaserverconfig.Config.OfflineWrite
+	if c == nil || !c.Enable {
+		return
+	}
+	wait.Until(func() {
+		reconcileOfflineWrites(agent.DefaultAgent)
+	}, time.Duration(c.ReconcilePeriod)*time.Second, stopCh)
+}
+
+// reconcileOfflineWrites replays the writes in order. Each write is sent with the resource
+// version in cloud it is based on as precondition, so it never overwrites the writes made
+// in cloud meanwhile. The writes rejected by cloud are reported as events and the local
+// objects are replaced by the ones in cloud, the reconciliation stops at the first write
+// failed for other reasons and is retried later.
+func reconcileOfflineWrites(a *agent.Agent) {
+	if !connect.IsConnected() {
+		return
+	}
+	service := dbclient.NewMetaV2Service()
+	writes, err := service.ListPendingWrites()
+	if err != nil {
+		klog.Errorf("[metaserver/offline] failed to list offline writes: %v", err)
+		return
+	}
+	// latest records the resource versions in cloud of the objects replayed
+	latest := make(map[string]string)
+	for i := range writes {
+		w := &writes[i]
+		obj, err := replayWrite(a, w, latest)
+		switch {
+		case err == nil:
+			klog.Infof("[metaserver/offline] successfully reconcile offline write %s %s", w.Verb, w.Key)
+			if obj != nil {
+				latest[w.Key] = obj.GetResourceVersion()
+				if err := imitator.DefaultV2Client.InsertOrUpdateObj(context.TODO(), obj); err != nil {
+					klog.Warningf("[metaserver/offline] failed to save obj %s: %v", w.Key, err)
+				} else {
+					watchhook.Trigger(watch.Event{Type: watch.Modified, Object: obj})
+				}
+			}
+		case isRejected(err):
+			klog.Warningf("[metaserver/offline] offline write %s %s is rejected by cloud: %v", w.Verb, w.Key, err)
+			reportConflict(a, w, err)
+			// the later writes of the object are based on the one in cloud
+			if obj := refreshLocalObj(a, w.Key); obj != nil {
+				latest[w.Key] = obj.GetResourceVersion()
+			} else {
+				delete(latest, w.Key)
+			}
+		default:
+			klog.Errorf("[metaserver/offline] failed to reconcile offline write %s %s, retry later: %v", w.Verb, w.Key, err)
+			return
+		}
+		if err := service.DeletePendingWrite(w.ID); err != nil {
+			klog.Errorf("[metaserver/offline] failed to delete offline write %d: %v", w.ID, err)
+			return
+		}
+	}
+}
+
+// isRejected returns whether the write is rejected by cloud, it is not retried
+func isRejected(err error) bool {
+	var status apierrors.APIStatus
+	if !stderrors.As(err, &status) {
+		return false
+	}
+	return !apierrors.IsInternalError(err) && !apierrors.IsServerTimeout(err) && !apierrors.IsTimeout(err) &&
+		!apierrors.IsServiceUnavailable(err) && !apierrors.IsTooManyRequests(err)
+}
+
+func replayWrite(a *agent.Agent, w *models.MetaV2PendingWrite, latest map[string]string) (*unstructured.Unstructured, error) {
+	info := new(apirequest.RequestInfo)
+	if err := json.Unmarshal([]byte(w.RequestInfo), info); err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid request info: %v", err))
+	}
+	ctx := apirequest.WithRequestInfo(apirequest.WithNamespace(context.Background(), info.Namespace), info)
+	if w.User != "" {
+		ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: w.User})
+	}
+
+	verb := metaserver.ApplicationVerb(w.Verb)
+	// the write is based on the object in cloud rather than the one at local
+	rv, ok := latest[w.Key]
+	if !ok {
+		rv = w.BaseResourceVersion
+	}
+	var body runtime.Object
+	if w.ReqBody != "" {
+		obj := new(unstructured.Unstructured)
+		if err := obj.UnmarshalJSON([]byte(w.ReqBody)); err != nil {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid request body: %v", err))
+		}
+		if verb == metaserver.Update || verb == metaserver.UpdateStatus {
+			obj.SetResourceVersion(rv)
+		}
+		body = obj
+	}
+	var option interface{}
+	if w.Option != "" {
+		option = json.RawMessage(w.Option)
+	}
+	if rv != "" && (verb == metaserver.Patch || verb == metaserver.Delete) {
+		var err error
+		if option, err = withPrecondition(verb, w.Option, rv); err != nil {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid request option: %v", err))
+		}
+	}
+
+	app, err := a.Generate(ctx, verb, option, body)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+	if err := a.Apply(app); err != nil {
+		return nil, err
+	}
+	if verb == metaserver.Delete || len(app.RespBody) == 0 {
+		return nil, nil
+	}
+	obj := new(unstructured.Unstructured)
+	if err := obj.UnmarshalJSON(app.RespBody); err != nil {
+		return nil, nil
+	}
+	return obj, nil
+}
+
+// withPrecondition returns the option of patch or delete which requires the object in cloud
+// is still of the resource version rv
+func withPrecondition(verb metaserver.ApplicationVerb, option string, rv string) (interface{}, error) {
+	if verb == metaserver.Delete {
+		options := new(metav1.DeleteOptions)
+		if option != "" {
+			if err := json.Unmarshal([]byte(option), options); err != nil {
+				return nil, err
+			}
+		}
+		options.Preconditions = &metav1.Preconditions{ResourceVersion: &rv}
+		return options, nil
+	}
+
+	pi := new(metaserver.PatchInfo)
+	if err := json.Unmarshal([]byte(option), pi); err != nil {
+		return nil, err
+	}
+	if pi.PatchType == types.JSONPatchType {
+		var patch []interface{}
+		if err := json.Unmarshal(pi.Data, &patch); err != nil {
+			return nil, err
+		}
+		test := map[string]interface{}{"op": "test", "path": "/metadata/resourceVersion", "value": rv}
+		pi.Data = metaserver.ToBytes(append([]interface{}{test}, patch...))
+		return pi, nil
+	}
+	// the resource version in merge patch is checked by cloud before the patch is applied
+	patch := make(map[string]interface{})
+	if err := json.Unmarshal(pi.Data, &patch); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(patch, rv, "metadata", "resourceVersion"); err != nil {
+		return nil, err
+	}
+	pi.Data = metaserver.ToBytes(patch)
+	return pi, nil
+}
+
+// newRequestContext returns the context of the request to the object of key
+func newRequestContext(key string, verb string) context.Context {
+	gvr, ns, name := metaserver.ParseKey(key)
+	info := &apirequest.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              verb,
+		APIPrefix:         "apis",
+		APIGroup:          gvr.Group,
+		APIVersion:        gvr.Version,
+		Resource:          gvr.Resource,
+		Namespace:         ns,
+		Name:              name,
+	}
+	if gvr.Group == "" {
+		info.APIPrefix = "api"
+	}
+	return apirequest.WithRequestInfo(apirequest.WithNamespace(context.Background(), ns), info)
+}
+
+// refreshLocalObj replaces the object at local with the one in cloud, which is returned.
+// It returns nil if the object is not found in cloud or failed to be refreshed.
+func refreshLocalObj(a *agent.Agent, key string) *unstructured.Unstructured {
+	ctx := newRequestContext(key, "get")
+	app, err := a.Generate(ctx, metaserver.Get, metav1.GetOptions{}, nil)
+	if err != nil {
+		klog.Errorf("[metaserver/offline] failed to get %s from cloud: %v", key, err)
+		return nil
+	}
+	defer app.Close()
+	if err := a.Apply(app); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("[metaserver/offline] failed to get %s from cloud: %v", key, err)
+			return nil
+		}
+		if existing, err := getLocalObj(ctx, key); err == nil {
+			if err := imitator.DefaultV2Client.DeleteObj(ctx, existing); err == nil {
+				watchhook.Trigger(watch.Event{Type: watch.Deleted, Object: existing})
+			}
+		}
+		return nil
+	}
+	obj := new(unstructured.Unstructured)
+	if err := obj.UnmarshalJSON(app.RespBody); err != nil {
+		klog.Errorf("[metaserver/offline] failed to decode %s from cloud: %v", key, err)
+		return nil
+	}
+	if err := imitator.DefaultV2Client.InsertOrUpdateObj(ctx, obj); err != nil {
+		klog.Errorf("[metaserver/offline] failed to save obj %s: %v", key, err)
+		return nil
+	}
+	watchhook.Trigger(watch.Event{Type: watch.Modified, Object: obj})
+	return obj
+}
+
+// reportConflict records a warning event of the object whose offline write is rejected by cloud
+func reportConflict(a *agent.Agent, w *models.MetaV2PendingWrite, cause error) {
+	gvr, ns, name := metaserver.ParseKey(w.Key)
+	// do not report the conflicts of events themselves
+	if gvr.Group == "" && gvr.Resource == "events" {
+		return
+	}
+	if ns == "" {
+		ns = metav1.NamespaceDefault
+	}
+	now := metav1.Now()
+	event := &corev1.Event{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", name, now.UnixNano()),
+			Namespace: ns,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: gvr.GroupVersion().String(),
+			Kind:       util.UnsafeResourceToKind(gvr.Resource),
+			Namespace:  ns,
+			Name:       name,
+		},
+		Reason: OfflineWriteConflictReason,
+		Message: fmt.Sprintf("%s served offline by node %s is rejected by cloud: %v",
+			w.Verb, metaserverconfig.Config.NodeName, cause),
+		Source:         corev1.EventSource{Component: "metaserver", Host: metaserverconfig.Config.NodeName},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           corev1.EventTypeWarning,
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(event)
+	if err != nil {
+		klog.Errorf("[metaserver/offline] failed to convert event: %v", err)
+		return
+	}
+
+	ctx := newRequestContext(fmt.Sprintf("/%s/v1/events/%s/%s", models.GroupCore, ns, models.NullName), "create")
+	app, err := a.Generate(ctx, metaserver.Create, metav1.CreateOptions{}, &unstructured.Unstructured{Object: content})
+	if err != nil {
+		klog.Errorf("[metaserver/offline] failed to report conflict of %s: %v", w.Key, err)
+		return
+	}
+	defer app.Close()
+	if err := a.Apply(app); err != nil {
+		klog.Errorf("[metaserver/offline] failed to report conflict of %s: %v", w.Key, err)
+	}
+}
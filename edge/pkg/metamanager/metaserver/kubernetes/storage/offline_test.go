@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/agent"
+	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
+	"github.com/kubeedge/kubeedge/pkg/metaserver"
+)
+
+func newConfigMapContext(verb, name string) context.Context {
+	info := &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              verb,
+		APIPrefix:         "api",
+		APIVersion:        "v1",
+		Resource:          "configmaps",
+		Namespace:         "default",
+		Name:              name,
+	}
+	return request.WithRequestInfo(request.WithNamespace(context.TODO(), "default"), info)
+}
+
+func newConfigMap(name, rv string, data map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"data": data}}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetResourceVersion(rv)
+	return obj
+}
+
+func TestOfflineWrite(t *testing.T) {
+	dao.Init(&v1alpha2.DataBase{DataSource: filepath.Join(t.TempDir(), "edgecore.db")}, &v1alpha2.MetaManager{Enable: true})
+	offlineWrite := metaserverconfig.Config.OfflineWrite
+	metaserverconfig.Config.OfflineWrite = &v1alpha2.MetaServerOfflineWrite{
+		Enable:           true,
+		Resources:        []string{"configmaps"},
+		MaxPendingWrites: 3,
+		ReconcilePeriod:  1,
+	}
+	defer func() {
+		metaserverconfig.Config.OfflineWrite = offlineWrite
+		connect.SetConnected(false)
+	}()
+	connect.SetConnected(false)
+
+	// the cloud approves the writes except the update, and bumps the resource version
+	var (
+		cloudRV  = 100
+		received []*metaserver.Application
+	)
+	patches := gomonkey.ApplyFunc(beehiveContext.SendSync, func(_ string, msg model.Message, _ time.Duration) (model.Message, error) {
+		app := msg.GetContent().(*metaserver.Application)
+		received = append(received, app)
+		ret := metaserver.Application{Status: metaserver.Approved}
+		switch app.Verb {
+		case metaserver.Update:
+			ret.Status = metaserver.Rejected
+			ret.Error = *apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "cm1", nil)
+		case metaserver.Create, metaserver.Get, metaserver.Patch:
+			cloudRV++
+			obj := newConfigMap("cm1", strconv.Itoa(cloudRV), map[string]interface{}{"k": "cloud"})
+			ret.RespBody, _ = obj.MarshalJSON()
+		}
+		content, _ := json.Marshal(ret)
+		return model.Message{Content: content}, nil
+	})
+	defer patches.Reset()
+
+	r := &REST{Agent: agent.NewApplicationAgent()}
+
+	// writes are served at local while disconnected
+	created, err := r.Create(newConfigMapContext("create", ""), newConfigMap("cm1", "", map[string]interface{}{"k": "v1"}), nil, &metav1.CreateOptions{})
+	require.NoError(t, err)
+	createdRV := created.(*unstructured.Unstructured).GetResourceVersion()
+	assert.NotEmpty(t, createdRV)
+
+	_, _, err = r.Update(newConfigMapContext("update", "cm1"), "cm1",
+		fakeUpdatedObjectInfo{newConfigMap("cm1", "999", map[string]interface{}{"k": "v2"})}, nil, nil, false, &metav1.UpdateOptions{})
+	assert.True(t, apierrors.IsConflict(err), "stale update must be rejected, got %v", err)
+
+	_, _, err = r.Update(newConfigMapContext("update", "cm1"), "cm1",
+		fakeUpdatedObjectInfo{newConfigMap("cm1", createdRV, map[string]interface{}{"k": "v2"})}, nil, nil, false, &metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	patched, err := r.Patch(newConfigMapContext("patch", "cm1"), metaserver.PatchInfo{
+		Name:      "cm1",
+		PatchType: types.StrategicMergePatchType,
+		Data:      []byte(`{"data":{"k2":"v3"}}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"k": "v2", "k2": "v3"}, patched.(*unstructured.Unstructured).Object["data"])
+
+	_, err = r.Patch(newConfigMapContext("patch", "cm1"), metaserver.PatchInfo{
+		Name:      "cm1",
+		PatchType: types.ApplyPatchType,
+		Data:      []byte(`{"data":{"k3":"v4"}}`),
+	})
+	require.Error(t, err)
+	assert.Equal(t, int32(http.StatusUnsupportedMediaType), err.(apierrors.APIStatus).Status().Code,
+		"server-side apply must not be served offline, got %v", err)
+
+	_, err = r.Patch(newConfigMapContext("patch", "cm1"), metaserver.PatchInfo{
+		Name:      "cm1",
+		PatchType: types.MergePatchType,
+		Data:      []byte(`{"data":{"k3":"v4"}}`),
+	})
+	assert.True(t, apierrors.IsTooManyRequests(err), "pending writes must be limited, got %v", err)
+
+	local, err := getLocalObj(context.TODO(), "/core/v1/configmaps/default/cm1")
+	require.NoError(t, err)
+	assert.Equal(t, "v3", local.Object["data"].(map[string]interface{})["k2"])
+
+	// writes are replayed in order after reconnected, the rejected update is reported
+	// and the local object is replaced by the one in cloud
+	connect.SetConnected(true)
+	reconcileOfflineWrites(r.Agent)
+
+	var verbs []metaserver.ApplicationVerb
+	for _, app := range received {
+		verbs = append(verbs, app.Verb)
+	}
+	assert.Equal(t, []metaserver.ApplicationVerb{
+		metaserver.Create, metaserver.Update, metaserver.Create, metaserver.Get, metaserver.Patch,
+	}, verbs)
+	// the update is based on the resource version returned by cloud
+	var updated unstructured.Unstructured
+	require.NoError(t, updated.UnmarshalJSON(received[1].ReqBody))
+	assert.Equal(t, "101", updated.GetResourceVersion())
+	assert.Contains(t, received[2].Key, "/events/")
+	// the patch after the rejected update is based on the object refreshed from cloud
+	var pi metaserver.PatchInfo
+	require.NoError(t, json.Unmarshal(received[4].Option, &pi))
+	assert.JSONEq(t, `{"metadata":{"resourceVersion":"103"},"data":{"k2":"v3"}}`, string(pi.Data))
+
+	count, err := dbclient.NewMetaV2Service().CountPendingWrites()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	local, err = getLocalObj(context.TODO(), "/core/v1/configmaps/default/cm1")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(cloudRV), local.GetResourceVersion())
+}
+
+func TestWithPrecondition(t *testing.T) {
+	option, err := withPrecondition(metaserver.Delete, `{"gracePeriodSeconds":0}`, "5")
+	require.NoError(t, err)
+	options := option.(*metav1.DeleteOptions)
+	assert.Equal(t, "5", *options.Preconditions.ResourceVersion)
+	assert.Equal(t, int64(0), *options.GracePeriodSeconds)
+
+	option, err = withPrecondition(metaserver.Patch, string(metaserver.ToBytes(metaserver.PatchInfo{
+		PatchType: types.JSONPatchType,
+		Data:      []byte(`[{"op":"replace","path":"/data/k","value":"v"}]`),
+	})), "5")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"test","path":"/metadata/resourceVersion","value":"5"},{"op":"replace","path":"/data/k","value":"v"}]`,
+		string(option.(*metaserver.PatchInfo).Data))
+
+	option, err = withPrecondition(metaserver.Patch, string(metaserver.ToBytes(metaserver.PatchInfo{
+		PatchType: types.MergePatchType,
+		Data:      []byte(`{"metadata":{"labels":{"a":"b"}}}`),
+	})), "5")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"metadata":{"labels":{"a":"b"},"resourceVersion":"5"}}`, string(option.(*metaserver.PatchInfo).Data))
+}
+
+type fakeUpdatedObjectInfo struct {
+	obj *unstructured.Unstructured
+}
+
+func (i fakeUpdatedObjectInfo) Preconditions() *metav1.Preconditions {
+	return nil
+}
+
+func (i fakeUpdatedObjectInfo) UpdatedObject(context.Context, runtime.Object) (runtime.Object, error) {
+	return i.obj, nil
+}
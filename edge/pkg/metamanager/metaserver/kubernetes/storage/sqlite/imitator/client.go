@@ -6,6 +6,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/storage"
@@ -35,11 +36,14 @@ type Client interface {
 	SetRevision(version interface{})
 
 	// This set of functions for upper storage
-	List(ctx context.Context, key string) (Resp, error)
+	List(ctx context.Context, key string, opts ListOptions) (Resp, error)
 	Get(ctx context.Context, key string) (Resp, error)
 	Watch(ctx context.Context, key string, ResourceVersion uint64) <-chan watch.Event
 }
 
+// ListOptions is the options pushed down to the backend storage when listing objects
+type ListOptions = dbclient.MetaV2ListOptions
+
 type Resp struct {
 	//TODO: change to []*MetaV2
 	Kvs *[]models.MetaV2
@@ -63,4 +67,34 @@ func StorageInit() {
 
 	DefaultV2Client.SetRevision(meta.ResourceVersion)
 	klog.Infof("StorageInit set revision to: %d", meta.ResourceVersion)
+
+	utilruntime.Must(rebuildLabels())
+}
+
+// rebuildLabels saves the labels of the objects cached before table meta_v2_label is introduced
+func rebuildLabels() error {
+	service := dbclient.NewMetaV2Service()
+	count, err := service.CountMetaV2Labels()
+	if err != nil || count > 0 {
+		return err
+	}
+	metas, err := service.RawMetaByGVRNN(schema.GroupVersionResource{}, "", "")
+	if err != nil {
+		return err
+	}
+	for _, meta := range *metas {
+		if meta.GroupVersionResource == "" {
+			continue
+		}
+		var obj unstructured.Unstructured
+		if err := runtime.DecodeInto(unstructured.UnstructuredJSONScheme, []byte(meta.Value), &obj); err != nil {
+			klog.Warningf("failed to decode obj %s, skip saving its labels: %v", meta.Key, err)
+			continue
+		}
+		if err := service.ReplaceMetaV2Labels(meta.Key, obj.GetLabels()); err != nil {
+			return err
+		}
+	}
+	klog.Infof("StorageInit rebuild labels of %d objects", len(*metas))
+	return nil
 }
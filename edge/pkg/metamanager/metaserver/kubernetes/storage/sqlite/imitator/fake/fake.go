@@ -19,7 +19,7 @@ type Client struct {
 	GetPassThroughObjF            func(ctx context.Context, key string) ([]byte, error)
 	GetRevisionF                  func() uint64
 	SetRevisionF                  func(version interface{})
-	ListF                         func(ctx context.Context, key string) (imitator.Resp, error)
+	ListF                         func(ctx context.Context, key string, opts imitator.ListOptions) (imitator.Resp, error)
 	GetF                          func(ctx context.Context, key string) (imitator.Resp, error)
 	WatchF                        func(ctx context.Context, key string, ResourceVersion uint64) <-chan watch.Event
 }
@@ -60,8 +60,8 @@ func (c Client) SetRevision(version interface{}) {
 }
 
 // List fake
-func (c Client) List(ctx context.Context, key string) (imitator.Resp, error) {
-	return c.ListF(ctx, key)
+func (c Client) List(ctx context.Context, key string, opts imitator.ListOptions) (imitator.Resp, error) {
+	return c.ListF(ctx, key, opts)
 }
 
 // Get fake
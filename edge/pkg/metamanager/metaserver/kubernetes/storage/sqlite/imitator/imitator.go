@@ -82,6 +82,7 @@ func (s *imitator) InsertOrUpdateObj(_ context.Context, obj runtime.Object) erro
 		Name:                 name,
 		ResourceVersion:      objRv,
 		Value:                buf.String(),
+		Labels:               unstr.GetLabels(),
 	}
 	return s.insertOrReplaceMetaV2(m, objRv)
 }
@@ -160,7 +161,7 @@ func (s *imitator) Get(_ context.Context, key string) (Resp, error) {
 		return Resp{}, fmt.Errorf("the server could not find the requested resource")
 	}
 }
-func (s *imitator) List(_ context.Context, key string) (Resp, error) {
+func (s *imitator) List(_ context.Context, key string, opts ListOptions) (Resp, error) {
 	gvr, ns, name := metaserver.ParseKey(key)
 	//if name != NullName {
 	//	return Resp{}, fmt.Errorf("dao client list must not have resource name")
@@ -168,7 +169,7 @@ func (s *imitator) List(_ context.Context, key string) (Resp, error) {
 	klog.Infof("%v,%v,%v", gvr, ns, name)
 	var resp Resp
 	s.lock.RLock()
-	results, err := dbclient.NewMetaV2Service().RawMetaByGVRNN(gvr, ns, name)
+	results, err := dbclient.NewMetaV2Service().ListMetaV2(gvr, ns, name, opts)
 	resp.Revision = s.revision
 
 	s.lock.RUnlock()
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/conversion"
@@ -79,6 +80,9 @@ func (s *store) Get(ctx context.Context, key string, _ storage.GetOptions, objPt
 	return nil
 }
 
+// GetList lists the objects of key, the selectors and pagination are pushed down to the
+// storage, and the objects are still filtered here for the requirements which are not supported
+// by the storage. The objects are listed in batches until the limit is reached.
 func (s *store) GetList(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
 	klog.Infof("get a list req, key=%v", key)
 	listPtr, err := meta.GetItemsPtr(listObj)
@@ -90,43 +94,81 @@ func (s *store) GetList(ctx context.Context, key string, opts storage.ListOption
 		return fmt.Errorf("need ptr to slice: %v", err)
 	}
 
-	resp, err := s.client.List(context.TODO(), key)
-
-	if err != nil || len(*resp.Kvs) == 0 {
-		klog.Error(err)
-		return err
+	listOpts := imitator.ListOptions{
+		Label: opts.Predicate.Label,
+		Field: opts.Predicate.Field,
+		Limit: opts.Predicate.Limit,
 	}
+	if opts.Predicate.Continue != "" {
+		listOpts.Continue, _, err = storage.DecodeContinue(opts.Predicate.Continue, "/")
+		if err != nil {
+			return apierrors.NewBadRequest(fmt.Sprintf("invalid continue token: %v", err))
+		}
+	}
+
 	unstrList := listObj.(*unstructured.UnstructuredList)
-	for _, v := range *resp.Kvs {
-		var unstrObj unstructured.Unstructured
-		err := runtime.DecodeInto(s.codec, []byte(v.Value), &unstrObj)
+	var (
+		revision uint64
+		lastKey  string
+		hasMore  bool
+	)
+	for {
+		resp, err := s.client.List(context.TODO(), key, listOpts)
 		if err != nil {
+			klog.Error(err)
 			return err
 		}
-
-		if unstrObj.GetKind() == "Pod" {
-			if err = MergePatchedResource(ctx, &unstrObj, model.ResourceTypePodPatch); err != nil {
+		revision = resp.Revision
+		for i, v := range *resp.Kvs {
+			var unstrObj unstructured.Unstructured
+			err := runtime.DecodeInto(s.codec, []byte(v.Value), &unstrObj)
+			if err != nil {
 				return err
 			}
-		}
 
-		labelSet := labels.Set(unstrObj.GetLabels())
-		if !opts.Predicate.Label.Matches(labelSet) {
-			continue
-		}
+			if unstrObj.GetKind() == "Pod" {
+				if err = MergePatchedResource(ctx, &unstrObj, model.ResourceTypePodPatch); err != nil {
+					return err
+				}
+			}
+
+			labelSet := labels.Set(unstrObj.GetLabels())
+			if !opts.Predicate.Label.Matches(labelSet) {
+				continue
+			}
+
+			// only support metadata.name & metadata.namespace
+			fieldSet := fields.Set{
+				"metadata.name":      unstrObj.GetName(),
+				"metadata.namespace": unstrObj.GetNamespace(),
+			}
+			if !opts.Predicate.Field.Matches(fieldSet) {
+				continue
+			}
 
-		// only support metadata.name & metadata.namespace
-		fieldSet := fields.Set{
-			"metadata.name":      unstrObj.GetName(),
-			"metadata.namespace": unstrObj.GetNamespace(),
+			unstrList.Items = append(unstrList.Items, unstrObj)
+			lastKey = v.Key
+			if listOpts.Limit > 0 && int64(len(unstrList.Items)) == listOpts.Limit {
+				hasMore = i < len(*resp.Kvs)-1 || int64(len(*resp.Kvs)) == listOpts.Limit
+				break
+			}
 		}
-		if !opts.Predicate.Field.Matches(fieldSet) {
-			continue
+		// stop if all the objects are listed or the limit is reached
+		if listOpts.Limit <= 0 || int64(len(*resp.Kvs)) < listOpts.Limit ||
+			int64(len(unstrList.Items)) == listOpts.Limit {
+			break
 		}
+		listOpts.Continue = (*resp.Kvs)[len(*resp.Kvs)-1].Key
+	}
 
-		unstrList.Items = append(unstrList.Items, unstrObj)
+	if hasMore {
+		next, err := storage.EncodeContinue(lastKey, "/", int64(revision))
+		if err != nil {
+			return err
+		}
+		unstrList.SetContinue(next)
 	}
-	rv := strconv.FormatUint(resp.Revision, 10)
+	rv := strconv.FormatUint(revision, 10)
 	unstrList.SetResourceVersion(rv)
 	unstrList.SetSelfLink(key)
 	gvr, _, _ := metaserver.ParseKey(key)
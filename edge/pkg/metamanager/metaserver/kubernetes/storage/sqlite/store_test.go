@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/storage"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator/fake"
+)
+
+// newFakeListClient returns a client which pushes down only the pagination,
+// so that the objects must be filtered by store
+func newFakeListClient(names ...string) imitator.Client {
+	var metas []models.MetaV2
+	for _, name := range names {
+		metas = append(metas, models.MetaV2{
+			Key:   "/core/v1/configmaps/default/" + name,
+			Value: fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":%q,"namespace":"default"}}`, name),
+		})
+	}
+	sort.Slice(metas, func(i, j int) bool { return metas[i].Key < metas[j].Key })
+	return fake.Client{
+		ListF: func(_ context.Context, _ string, opts imitator.ListOptions) (imitator.Resp, error) {
+			var kvs []models.MetaV2
+			for _, m := range metas {
+				if m.Key <= opts.Continue {
+					continue
+				}
+				if opts.Limit > 0 && int64(len(kvs)) == opts.Limit {
+					break
+				}
+				kvs = append(kvs, m)
+			}
+			return imitator.Resp{Kvs: &kvs, Revision: 10}, nil
+		},
+	}
+}
+
+func TestGetListPagination(t *testing.T) {
+	s := newStore()
+	s.client = newFakeListClient("cm1", "cm2", "cm3", "cm4", "cm5")
+	// cm2 and cm4 are filtered out by store
+	selector := fields.ParseSelectorOrDie("metadata.name!=cm2,metadata.name!=cm4")
+
+	var names []string
+	continueToken := ""
+	for i := 0; i < 3; i++ {
+		list := &unstructured.UnstructuredList{}
+		err := s.GetList(context.TODO(), "/core/v1/configmaps/default/null", storage.ListOptions{
+			Predicate: storage.SelectionPredicate{
+				Label:    labels.Everything(),
+				Field:    selector,
+				Limit:    2,
+				Continue: continueToken,
+			},
+		}, list)
+		require.NoError(t, err)
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"cm1", "cm3", "cm5"}, names)
+	assert.Empty(t, continueToken)
+}
@@ -166,7 +166,11 @@ func (wc *watchChan) ResultChan() <-chan watch.Event {
 func (wc *watchChan) sync() error {
 	switch wc.recursive {
 	case true: /*list*/
-		resp, err := wc.watcher.client.List(context.TODO(), wc.key)
+		// the selectors are pushed down to storage, the events are still filtered when sent
+		resp, err := wc.watcher.client.List(context.TODO(), wc.key, imitator.ListOptions{
+			Label: wc.internalPred.Label,
+			Field: wc.internalPred.Field,
+		})
 		if err != nil {
 			return err
 		}
@@ -180,7 +184,7 @@ func (wc *watchChan) sync() error {
 		}
 		wc.initialRev = int64(resp.Revision)
 	case false: /*get*/
-		resp, err := wc.watcher.client.List(context.TODO(), wc.key)
+		resp, err := wc.watcher.client.List(context.TODO(), wc.key, imitator.ListOptions{})
 		if err != nil {
 			return err
 		}
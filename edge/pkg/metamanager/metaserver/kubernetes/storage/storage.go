@@ -193,7 +193,12 @@ func (r *REST) List(ctx context.Context, options *metainternalversion.ListOption
 		if err != nil {
 			return nil, err
 		}
-		// imitator.DefaultV2Client.InsertOrUpdateObj(context.TODO(), list)
+		// save the items to local so that they can be listed when cloud is unreachable, ignore error
+		for i := range list.Items {
+			if err := imitator.DefaultV2Client.InsertOrUpdateObj(context.TODO(), &list.Items[i]); err != nil {
+				klog.V(3).Infof("failed to save obj to metav2, err: %v", err)
+			}
+		}
 		klog.Infof("[metaserver/reststorage] successfully process list req (%v) through cloud", info.Path)
 		return list, nil
 	}()
@@ -243,7 +248,7 @@ func (r *REST) Watch(ctx context.Context, options *metainternalversion.ListOptio
 }
 
 func (r *REST) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
-	obj, err := func() (runtime.Object, error) {
+	retObj, err := func() (runtime.Object, error) {
 		app, err := r.Agent.Generate(ctx, metaserver.Create, *options, obj)
 		if err != nil {
 			klog.Errorf("[metaserver/reststorage] failed to generate application: %v", err)
@@ -263,18 +268,26 @@ func (r *REST) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateOb
 		return retObj, nil
 	}()
 
+	if err != nil && offlineWritable(ctx, err) {
+		klog.Infof("[metaserver/reststorage] cloud is disconnected, create (%v) at local", metaserver.KeyFunc(obj))
+		return r.createOffline(ctx, obj, options)
+	}
 	if err != nil {
 		klog.Errorf("[metaserver/reststorage] failed to create (%v)", metaserver.KeyFunc(obj))
 		return nil, err
 	}
 
-	klog.Infof("[metaserver/reststorage] successfully create (%v)", metaserver.KeyFunc(obj))
-	return obj, nil
+	klog.Infof("[metaserver/reststorage] successfully create (%v)", metaserver.KeyFunc(retObj))
+	return retObj, nil
 }
 
 func (r *REST) Delete(ctx context.Context, _ string, _ rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
 	key, _ := metaserver.KeyFuncReq(ctx, "")
 	app, err := r.Agent.Generate(ctx, metaserver.Delete, options, nil)
+	if err != nil && offlineWritable(ctx, err) {
+		klog.Infof("[metaserver/reststorage] cloud is disconnected, delete (%v) at local", key)
+		return r.deleteOffline(ctx, options)
+	}
 	if err != nil {
 		klog.Errorf("[metaserver/reststorage] failed to generate application: %v", err)
 		return nil, false, err
@@ -302,6 +315,11 @@ func (r *REST) Update(ctx context.Context, _ string, objInfo rest.UpdatedObjectI
 	} else {
 		app, err = r.Agent.Generate(ctx, metaserver.Update, options, obj)
 	}
+	if err != nil && offlineWritable(ctx, err) {
+		klog.Infof("[metaserver/reststorage] cloud is disconnected, update (%v) at local", metaserver.KeyFunc(obj))
+		retObj, err := r.updateOffline(ctx, obj, options)
+		return retObj, false, err
+	}
 	if err != nil {
 		klog.Errorf("[metaserver/reststorage] failed to generate application: %v", err)
 		return nil, false, err
@@ -319,6 +337,10 @@ func (r *REST) Update(ctx context.Context, _ string, objInfo rest.UpdatedObjectI
 
 func (r *REST) Patch(ctx context.Context, pi metaserver.PatchInfo) (runtime.Object, error) {
 	app, err := r.Agent.Generate(ctx, metaserver.Patch, pi, nil)
+	if err != nil && offlineWritable(ctx, err) {
+		klog.Infof("[metaserver/reststorage] cloud is disconnected, patch (%v) at local", pi.Name)
+		return r.patchOffline(ctx, pi)
+	}
 	if err != nil {
 		klog.Errorf("[metaserver/reststorage] failed to generate application: %v", err)
 		return nil, err
@@ -31,19 +31,18 @@ import (
 	"k8s.io/apiserver/pkg/server"
 	genericfilters "k8s.io/apiserver/pkg/server/filters"
 	certutil "k8s.io/client-go/util/cert"
-	"k8s.io/client-go/util/keyutil"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/api/legacyscheme"
 	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
 
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
-	"github.com/kubeedge/kubeedge/edge/pkg/edged/kubeclientbridge"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/client"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/auth"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/certificate"
 	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/handlerfactory"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/serializer"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage"
 	kefeatures "github.com/kubeedge/kubeedge/pkg/features"
 	passthrough "github.com/kubeedge/kubeedge/pkg/util/pass-through"
 )
@@ -90,18 +89,11 @@ func buildAuth() *metaServerAuth {
 		&client.ClusterRoleGetter{},
 		&client.ClusterRoleBindingLister{})
 
-	allPublicKeys := []interface{}{}
-	for _, keyfile := range metaserverconfig.Config.ServiceAccountKeyFiles {
-		publicKeys, err := keyutil.PublicKeysFromFile(keyfile)
-		if err != nil {
-			klog.Errorf("Failed to load public key file %s: %v", keyfile, err)
-			return nil
-		}
-		allPublicKeys = append(allPublicKeys, publicKeys...)
+	tokenAuthenticator, err := auth.NewServiceAccountTokenAuthenticator()
+	if err != nil {
+		klog.Error(err)
+		return nil
 	}
-	tokenAuthenticator := auth.JWTTokenAuthenticator(nil,
-		metaserverconfig.Config.ServiceAccountIssuers, allPublicKeys, metaserverconfig.Config.APIAudiences,
-		auth.NewValidator(client.NewGetterFromClient(kubeclientbridge.NewSimpleClientset(client.New()))))
 	bearerTokenAuthenticator := bearertoken.New(tokenAuthenticator)
 
 	// Use union authenticator with anonymous fallback for discovery paths
@@ -182,6 +174,7 @@ func (ls *MetaServer) startHTTPSServer(addr string, stopChan <-chan struct{}) {
 }
 
 func (ls *MetaServer) Start(stopChan <-chan struct{}) {
+	go storage.ReconcileOfflineWrites(stopChan)
 	if kefeatures.DefaultFeatureGate.Enabled(kefeatures.RequireAuthorization) {
 		err := ls.prepareServer()
 		if err != nil {
@@ -0,0 +1,52 @@
+package metamanager
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+)
+
+const resyncRetryPeriod = 10 * time.Second
+
+// requestObjectResync requests the cloud to resend all the objects of the node after
+// the database is rebuilt, it is retried until the cloud accepts the request
+func requestObjectResync(ctx context.Context) {
+	if !dao.NeedResync() {
+		return
+	}
+	klog.Info("database is rebuilt, request the cloud to resend all the objects")
+	err := wait.PollUntilContextCancel(ctx, resyncRetryPeriod, true, func(context.Context) (bool, error) {
+		if !connect.IsConnected() {
+			return false, nil
+		}
+		msg := message.BuildMsg(modules.MetaGroup, "", modules.MetaManagerModuleName,
+			constants.ResourceTypeObjectResync, model.UpdateOperation, nil)
+		resp, err := beehiveContext.SendSync(modules.EdgeHubModuleName, *msg, time.Minute)
+		if err != nil {
+			klog.Errorf("failed to request object resync: %v", err)
+			return false, nil
+		}
+		if resp.GetOperation() == model.ResponseErrorOperation {
+			klog.Errorf("object resync is rejected by the cloud: %v", resp.GetContent())
+			return false, nil
+		}
+		if err := dao.ResyncDone(); err != nil {
+			klog.Errorf("failed to clear the resync mark of database: %v", err)
+		}
+		klog.Infof("object resync is accepted by the cloud: %v", resp.GetContent())
+		return true, nil
+	})
+	if err != nil {
+		klog.Warningf("stop requesting object resync: %v", err)
+	}
+}
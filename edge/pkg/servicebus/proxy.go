@@ -0,0 +1,107 @@
+package servicebus
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+)
+
+const (
+	// proxySourceType is the source of http requests proxied to cloud services, it must be
+	// the same as the source listened by servicebus provider of router
+	proxySourceType = "servicebus_proxy"
+)
+
+var (
+	// lookupTargetURL returns the target url of rule which the path is proxied to, it is
+	// replaced in tests
+	lookupTargetURL = func(path string) string {
+		sbs := dbclient.NewServiceBusService()
+		// the target url of rule may be configured with or without the leading slash
+		for _, key := range []string{path, strings.TrimPrefix(path, "/")} {
+			if target, err := sbs.GetUrlsByKey(key); err == nil && target != nil {
+				return target.URL
+			}
+		}
+		return ""
+	}
+
+	// sendToCloud sends the message to cloud by edgehub and waits for the response, it is
+	// replaced in tests
+	sendToCloud = func(msg *beehiveModel.Message, timeout time.Duration) (beehiveModel.Message, error) {
+		return beehiveContext.SendSync(modules.EdgeHubModuleName, *msg, timeout)
+	}
+)
+
+// buildProxyHandler returns the handler of proxy server, which forwards the http request of
+// edge application to the cloud service configured by servicebus rule, the request path is
+// the target url of the rule, e.g. /api/v1/alerts. The request must carry the service account
+// token of pod, it is authorized by cloud.
+func buildProxyHandler(timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		targetURL := lookupTargetURL(req.URL.Path)
+		if targetURL == "" {
+			http.Error(w, "url is not allowed and please make a rule for this url in the cloud", http.StatusNotFound)
+			return
+		}
+		if req.Header.Get(commonType.HeaderAuthorization) == "" {
+			http.Error(w, "service account token is required", http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+		if err != nil {
+			http.Error(w, "can't read data from body of the http's request", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		request := commonType.HTTPRequest{
+			Header: req.Header.Clone(),
+			Method: req.Method,
+			URL:    req.URL.RawQuery,
+			Body:   body,
+		}
+		msg := beehiveModel.NewMessage("").BuildRouter(proxySourceType, modules.UserGroup,
+			targetURL, beehiveModel.UploadOperation).FillBody(request)
+		responseMessage, err := sendToCloud(msg, timeout)
+		if err != nil {
+			klog.Errorf("failed to proxy request %s to cloud: %v", msg.GetID(), err)
+			http.Error(w, err.Error(), http.StatusGatewayTimeout)
+			return
+		}
+		content, err := responseMessage.GetContentData()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		var response commonType.HTTPResponse
+		if err := json.Unmarshal(content, &response); err != nil {
+			klog.Errorf("response of request %s can not convert to HTTPResponse: %v", msg.GetID(), err)
+			http.Error(w, "invalid response from cloud", http.StatusBadGateway)
+			return
+		}
+
+		for k, values := range response.Header {
+			// the body is written completely, the length is set by server
+			if k == "Content-Length" || k == "Transfer-Encoding" {
+				continue
+			}
+			for _, v := range values {
+				w.Header().Add(k, v)
+			}
+		}
+		w.WriteHeader(response.StatusCode)
+		if _, err := w.Write(response.Body); err != nil {
+			klog.Errorf("failed to write response of request %s: %v", msg.GetID(), err)
+		}
+	})
+}
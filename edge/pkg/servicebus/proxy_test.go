@@ -0,0 +1,88 @@
+package servicebus
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+func stubProxy(t *testing.T, targets map[string]string, send func(*beehiveModel.Message) (beehiveModel.Message, error)) {
+	t.Helper()
+	origLookup, origSend := lookupTargetURL, sendToCloud
+	t.Cleanup(func() { lookupTargetURL, sendToCloud = origLookup, origSend })
+	lookupTargetURL = func(path string) string { return targets[path] }
+	sendToCloud = func(msg *beehiveModel.Message, _ time.Duration) (beehiveModel.Message, error) { return send(msg) }
+}
+
+func TestProxyHandler(t *testing.T) {
+	var sent *beehiveModel.Message
+	stubProxy(t, map[string]string{"/api/alerts": "/api/alerts"}, func(msg *beehiveModel.Message) (beehiveModel.Message, error) {
+		sent = msg
+		response := commonType.HTTPResponse{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{"X-Cloud": []string{"1"}, "Content-Length": []string{"100"}},
+			Body:       []byte("created"),
+		}
+		return *beehiveModel.NewMessage(msg.GetID()).FillBody(response), nil
+	})
+	h := buildProxyHandler(time.Second)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/alerts?level=1", strings.NewReader("alert"))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "created" || rec.Header().Get("X-Cloud") != "1" {
+		t.Fatalf("got response %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+	if sent.GetSource() != proxySourceType || sent.GetResource() != "/api/alerts" || sent.GetOperation() != beehiveModel.UploadOperation {
+		t.Errorf("got message route %+v", sent.Router)
+	}
+	content, _ := sent.GetContentData()
+	var request commonType.HTTPRequest
+	if err := json.Unmarshal(content, &request); err != nil {
+		t.Fatalf("message content can not convert to HTTPRequest: %v", err)
+	}
+	if request.Method != http.MethodPut || request.URL != "level=1" || string(request.Body) != "alert" ||
+		request.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("got request %+v", request)
+	}
+}
+
+func TestProxyHandlerErrors(t *testing.T) {
+	stubProxy(t, map[string]string{"/api": "/api"}, func(*beehiveModel.Message) (beehiveModel.Message, error) {
+		return beehiveModel.Message{}, errors.New("timeout")
+	})
+	h := buildProxyHandler(time.Second)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		want          int
+	}{
+		{"not allowed", "/other", "Bearer token", http.StatusNotFound},
+		{"no token", "/api", "", http.StatusUnauthorized},
+		{"cloud unavailable", "/api", "Bearer token", http.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
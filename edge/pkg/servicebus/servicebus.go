@@ -1,6 +1,7 @@
 package servicebus
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -181,6 +182,15 @@ func (sb *servicebus) Start() {
 
 		// build new message with required field & send message to servicebus
 		klog.V(4).Info("servicebus receive msg")
+		// the chunks are delivered in order, so they are handled in the loop, and the requests
+		// are registered before their chunks are received
+		if isStreamChunk(&msg) {
+			handleStreamChunk(&msg)
+			continue
+		}
+		if isHTTPRequest(&msg) {
+			registerStream(msg.GetID())
+		}
 		go processMessage(&msg)
 	}
 }
@@ -213,6 +223,8 @@ func processMessage(msg *beehiveModel.Message) {
 			c <- struct{}{}
 		}
 	default:
+		s := registerStream(msg.GetID())
+		defer unregisterStream(msg.GetID())
 		r := strings.Split(resource, ":")
 		if len(r) != 2 {
 			m := "the format of resource " + resource + " is incorrect"
@@ -247,8 +259,35 @@ func processMessage(msg *beehiveModel.Message) {
 
 		//send message with resource to the edge part
 		operation := httpRequest.Method
-		targetURL := "http://127.0.0.1:" + r[0] + r[1]
-		resp, err := uc.HTTPDo(operation, targetURL, httpRequest.Header, httpRequest.Body)
+		targetURL, err := buildTargetURL(&httpRequest, r[0], r[1])
+		if err != nil {
+			m := "error to resolve service: " + err.Error()
+			code := http.StatusServiceUnavailable
+			klog.Error(m)
+			if response, err := buildErrorResponse(msg.GetID(), m, code); err == nil {
+				beehiveContext.SendToGroup(modules.HubGroup, response)
+			}
+			return
+		}
+		client, err := getURLClient(&httpRequest)
+		if err != nil {
+			m := "error to build client: " + err.Error()
+			code := http.StatusBadRequest
+			klog.Error(m)
+			if response, err := buildErrorResponse(msg.GetID(), m, code); err == nil {
+				beehiveContext.SendToGroup(modules.HubGroup, response)
+			}
+			return
+		}
+		var body io.Reader = bytes.NewReader(httpRequest.Body)
+		if httpRequest.Stream {
+			body = s.body
+		}
+		if httpRequest.Stream || httpRequest.StreamResponse {
+			// the streamed body may take longer than the client timeout, the request is canceled by cloud
+			client = withoutTimeout(client)
+		}
+		resp, err := client.HTTPDoWithContext(s.ctx, operation, targetURL, httpRequest.Header, body)
 		if err != nil {
 			m := "error to call service"
 			code := http.StatusNotFound
@@ -259,7 +298,12 @@ func processMessage(msg *beehiveModel.Message) {
 			return
 		}
 		defer resp.Body.Close()
-		resBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		limit := int64(maxBodySize)
+		if httpRequest.StreamResponse {
+			// read one more byte to know whether the body fits in a message
+			limit++
+		}
+		resBody, err := io.ReadAll(io.LimitReader(resp.Body, limit))
 		if err != nil {
 			if err.Error() == "http: request body too large" {
 				err = fmt.Errorf("response body too large")
@@ -273,6 +317,11 @@ func processMessage(msg *beehiveModel.Message) {
 			return
 		}
 
+		if int64(len(resBody)) > maxBodySize {
+			sendStreamResponse(s, msg.GetID(), resp, io.MultiReader(bytes.NewReader(resBody), resp.Body), httpRequest.MaxBodySize)
+			return
+		}
+
 		response := commonType.HTTPResponse{Header: resp.Header, StatusCode: resp.StatusCode, Body: resBody}
 		responseMsg := beehiveModel.NewMessage(msg.GetID()).SetRoute(modules.ServiceBusModuleName, modules.UserGroup).
 			SetResourceOperation("", beehiveModel.UploadOperation).FillBody(response)
@@ -404,10 +453,17 @@ func server(stopChan <-chan struct{}, tlsOpts TLSOptions) {
 		timeout, _ = time.ParseDuration("10s")
 	}
 
-	h := buildBasicHandler(timeout)
 	s := http.Server{
 		Addr:    fmt.Sprintf("%s:%d", servicebusConfig.Config.Server, servicebusConfig.Config.Port),
-		Handler: h,
+		Handler: buildBasicHandler(timeout),
+	}
+	// the proxy is served on its own port, so any path of the basic server is left untouched
+	var ps *http.Server
+	if servicebusConfig.Config.ProxyPort != 0 {
+		ps = &http.Server{
+			Addr:    fmt.Sprintf("%s:%d", servicebusConfig.Config.Server, servicebusConfig.Config.ProxyPort),
+			Handler: buildProxyHandler(timeout),
+		}
 	}
 
 	if tlsOpts.TLSEnabled {
@@ -421,18 +477,33 @@ func server(stopChan <-chan struct{}, tlsOpts TLSOptions) {
 			return
 		}
 		s.TLSConfig = tlsCfg
+		if ps != nil {
+			ps.TLSConfig = tlsCfg
+		}
 	}
 
 	go func() {
 		<-stopChan
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
+		if ps != nil {
+			if err := ps.Shutdown(ctx); err != nil {
+				klog.Errorf("Proxy server shutdown failed: %s", err)
+			}
+		}
 		if err := s.Shutdown(ctx); err != nil {
 			klog.Errorf("Server shutdown failed: %s", err)
 		}
 		atomic.StoreInt32(&inited, 0)
 	}()
 
+	if ps != nil {
+		go listenAndServe(ps)
+	}
+	listenAndServe(&s)
+}
+
+func listenAndServe(s *http.Server) {
 	if s.TLSConfig != nil {
 		klog.Infof("[servicebus] starting HTTPS server at %v", s.Addr)
 		// cert and key are already loaded via GetCertificate; pass empty strings.
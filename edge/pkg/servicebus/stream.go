@@ -0,0 +1,121 @@
+package servicebus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/pkg/util/httpstream"
+)
+
+// streams holds the requests from cloud in processing by their message ID, the streamed
+// request body and the cancellation from cloud are delivered to them in chunk messages
+var streams sync.Map
+
+// stream is a request from cloud in processing
+type stream struct {
+	body *httpstream.Receiver
+	// window is the flow control window of the streamed response body
+	window *httpstream.Window
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func isStreamChunk(msg *beehiveModel.Message) bool {
+	return msg.GetSource() == sourceType && msg.GetResource() == commonType.ResourceStreamChunk
+}
+
+func isHTTPRequest(msg *beehiveModel.Message) bool {
+	if msg.GetSource() != sourceType || isStreamChunk(msg) {
+		return false
+	}
+	switch msg.GetOperation() {
+	case message.OperationStart, message.OperationStop:
+		return false
+	}
+	return true
+}
+
+// registerStream registers the request of message id, it must be called before the chunks
+// of request are received
+func registerStream(id string) *stream {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &stream{
+		body:   httpstream.NewReceiver(id, 0, func(update *commonType.HTTPStreamChunk) { sendStreamChunk(id, update) }),
+		window: httpstream.NewWindow(),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	v, loaded := streams.LoadOrStore(id, s)
+	if loaded {
+		cancel()
+	}
+	return v.(*stream)
+}
+
+// unregisterStream cancels the request of message id once it is processed
+func unregisterStream(id string) {
+	if v, ok := streams.LoadAndDelete(id); ok {
+		s := v.(*stream)
+		s.cancel()
+		s.body.Close()
+	}
+}
+
+// handleStreamChunk delivers the chunk from cloud to its request, it is called in the loop
+// of receiving messages so that the chunks are delivered in order
+func handleStreamChunk(msg *beehiveModel.Message) {
+	chunk, err := httpstream.ParseChunk(msg)
+	if err != nil {
+		klog.Errorf("failed to parse stream chunk: %v", err)
+		return
+	}
+	v, ok := streams.Load(chunk.ID)
+	if !ok {
+		klog.V(4).Infof("request %s is not in processing, chunk %d is dropped", chunk.ID, chunk.Seq)
+		return
+	}
+	s := v.(*stream)
+	if httpstream.IsWindowUpdate(chunk) {
+		s.window.Update(chunk)
+		return
+	}
+	if err := s.body.Push(chunk); err != nil {
+		klog.Warningf("request %s is canceled: %v", chunk.ID, err)
+		s.cancel()
+	}
+}
+
+// sendStreamChunk sends the chunk of request parentID to cloud, it is the chunk of response
+// body or the window update of request body
+func sendStreamChunk(parentID string, chunk *commonType.HTTPStreamChunk) {
+	chunkMsg := beehiveModel.NewMessage(parentID).SetRoute(modules.ServiceBusModuleName, modules.UserGroup).
+		SetResourceOperation(commonType.ResourceStreamChunk, beehiveModel.UploadOperation).FillBody(chunk)
+	beehiveContext.SendToGroup(modules.HubGroup, *chunkMsg)
+}
+
+// sendStreamResponse sends the response to cloud, and then its body in chunks as cloud reads it
+func sendStreamResponse(s *stream, parentID string, resp *http.Response, body io.Reader, maxSize int64) {
+	response := commonType.HTTPResponse{Header: resp.Header, StatusCode: resp.StatusCode, Stream: true}
+	responseMsg := beehiveModel.NewMessage(parentID).SetRoute(modules.ServiceBusModuleName, modules.UserGroup).
+		SetResourceOperation("", beehiveModel.UploadOperation).FillBody(response)
+	beehiveContext.SendToGroup(modules.HubGroup, *responseMsg)
+
+	sent, err := httpstream.Send(s.ctx, parentID, body, maxSize, s.window, func(chunk *commonType.HTTPStreamChunk) error {
+		sendStreamChunk(parentID, chunk)
+		return nil
+	})
+	if err != nil {
+		klog.Errorf("failed to stream response body of request %s, %d bytes sent: %v", parentID, sent, err)
+		return
+	}
+	klog.Infof("response body of request %s is streamed to cloud, %d bytes sent", parentID, sent)
+}
@@ -0,0 +1,89 @@
+package servicebus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/servicebus/util"
+)
+
+func chunkMessage(chunk commonType.HTTPStreamChunk) *beehiveModel.Message {
+	return beehiveModel.NewMessage("").SetRoute(sourceType, "user").
+		SetResourceOperation(commonType.ResourceStreamChunk, beehiveModel.UploadOperation).FillBody(chunk)
+}
+
+func TestIsHTTPRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *beehiveModel.Message
+		want bool
+	}{
+		{"request", beehiveModel.NewMessage("").SetRoute(sourceType, "user").SetResourceOperation("80:/a", http.MethodPost), true},
+		{"start", beehiveModel.NewMessage("").SetRoute(sourceType, "user").SetResourceOperation("80:/a", message.OperationStart), false},
+		{"chunk", chunkMessage(commonType.HTTPStreamChunk{}), false},
+		{"other source", beehiveModel.NewMessage("").SetRoute("router_eventbus", "user").SetResourceOperation("80:/a", http.MethodPost), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHTTPRequest(tt.msg); got != tt.want {
+				t.Errorf("isHTTPRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !isStreamChunk(chunkMessage(commonType.HTTPStreamChunk{})) {
+		t.Error("isStreamChunk() = false, want true")
+	}
+}
+
+func TestStreamedRequestBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer ts.Close()
+
+	s := registerStream("id")
+	defer unregisterStream("id")
+	if registerStream("id") != s {
+		t.Fatal("registerStream() registers the request twice")
+	}
+	// the chunks received out of order are delivered in order
+	handleStreamChunk(chunkMessage(commonType.HTTPStreamChunk{ID: "id", Seq: 1, Data: []byte("world"), EOF: true}))
+	handleStreamChunk(chunkMessage(commonType.HTTPStreamChunk{ID: "id", Seq: 0, Data: []byte("hello ")}))
+	// the chunk of unknown request is dropped
+	handleStreamChunk(chunkMessage(commonType.HTTPStreamChunk{ID: "unknown", Data: []byte("a")}))
+
+	client := withoutTimeout(&util.URLClient{Client: &http.Client{}})
+	resp, err := client.HTTPDoWithContext(s.ctx, http.MethodPost, ts.URL, nil, s.body)
+	if err != nil {
+		t.Fatalf("HTTPDoWithContext() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "hello world" {
+		t.Errorf("response body = %q, want %q", body, "hello world")
+	}
+}
+
+func TestStreamCanceledByCloud(t *testing.T) {
+	s := registerStream("id")
+	handleStreamChunk(chunkMessage(commonType.HTTPStreamChunk{ID: "id", Error: "canceled"}))
+	if !errors.Is(s.ctx.Err(), context.Canceled) {
+		t.Errorf("request context error = %v, want %v", s.ctx.Err(), context.Canceled)
+	}
+	if _, err := s.body.Read(make([]byte, 1)); err == nil || err.Error() != "canceled" {
+		t.Errorf("Read() error = %v, want canceled", err)
+	}
+
+	unregisterStream("id")
+	if _, ok := streams.Load("id"); ok {
+		t.Error("the request is not unregistered")
+	}
+}
@@ -0,0 +1,166 @@
+package servicebus
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	commonType "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/servicebus/util"
+)
+
+const (
+	schemeHTTP  = "http"
+	schemeHTTPS = "https"
+	loopback    = "127.0.0.1"
+)
+
+var (
+	servicesGVR  = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	endpointsGVR = schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}
+
+	// getCachedObject reads the object cached by metamanager, it is replaced in tests
+	getCachedObject = func(gvr schema.GroupVersionResource, namespace, name string, obj interface{}) error {
+		metas, err := dbclient.NewMetaV2Service().RawMetaByGVRNN(gvr, namespace, name)
+		if err != nil {
+			return err
+		}
+		if metas == nil || len(*metas) == 0 {
+			return errNotCached
+		}
+		return json.Unmarshal([]byte((*metas)[0].Value), obj)
+	}
+	errNotCached = errors.New("not cached in edge")
+
+	// tlsClients caches the https clients by their TLS options
+	tlsClients sync.Map
+)
+
+// buildTargetURL returns the URL of the edge service which the request from cloud is sent to
+func buildTargetURL(request *commonType.HTTPRequest, port, path string) (string, error) {
+	scheme := request.Scheme
+	switch scheme {
+	case "":
+		scheme = schemeHTTP
+	case schemeHTTP, schemeHTTPS:
+	default:
+		return "", fmt.Errorf("unsupported scheme %q", scheme)
+	}
+	host := net.JoinHostPort(loopback, port)
+	if request.Service != "" {
+		var err error
+		if host, err = resolveService(request.Service, port); err != nil {
+			return "", err
+		}
+	}
+	return scheme + "://" + host + path, nil
+}
+
+// resolveService resolves the port of edge service <name>.<namespace> to a ready endpoint
+// cached by metamanager, and falls back to the cluster IP of service
+func resolveService(service, port string) (string, error) {
+	name, namespace, ok := strings.Cut(service, ".")
+	if !ok {
+		return "", fmt.Errorf("invalid service %q", service)
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return "", fmt.Errorf("invalid port %q of service %s", port, service)
+	}
+
+	svc := &v1.Service{}
+	if err := getCachedObject(servicesGVR, namespace, name, svc); err != nil {
+		return "", fmt.Errorf("failed to get service %s: %v", service, err)
+	}
+	var servicePort *v1.ServicePort
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Port == int32(portNum) {
+			servicePort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		return "", fmt.Errorf("service %s does not have port %d", service, portNum)
+	}
+
+	endpoints := &v1.Endpoints{}
+	if err := getCachedObject(endpointsGVR, namespace, name, endpoints); err == nil {
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) == 0 {
+				continue
+			}
+			for _, p := range subset.Ports {
+				if p.Name == servicePort.Name {
+					return net.JoinHostPort(subset.Addresses[0].IP, strconv.Itoa(int(p.Port))), nil
+				}
+			}
+		}
+	}
+	if svc.Spec.ClusterIP != "" && svc.Spec.ClusterIP != v1.ClusterIPNone {
+		return net.JoinHostPort(svc.Spec.ClusterIP, port), nil
+	}
+	return "", fmt.Errorf("service %s does not have ready endpoints", service)
+}
+
+// getURLClient returns the client to call the edge service, the https clients are cached
+// by their CA, verification option and server name
+func getURLClient(request *commonType.HTTPRequest) (*util.URLClient, error) {
+	if request.Scheme != schemeHTTPS || (len(request.CA) == 0 && !request.InsecureSkipVerify && request.Service == "") {
+		return uc, nil
+	}
+	// the request is sent to the endpoint IP of the service, so the certificate is
+	// verified against the DNS name of the service instead
+	var serverName string
+	if request.Service != "" {
+		serverName = request.Service + ".svc"
+	}
+	sum := sha256.Sum256(request.CA)
+	key := hex.EncodeToString(sum[:]) + "/" + strconv.FormatBool(request.InsecureSkipVerify) + "/" + serverName
+	if v, ok := tlsClients.Load(key); ok {
+		return v.(*util.URLClient), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		// #nosec G402 -- skipping verification is opted in explicitly in ruleendpoint
+		InsecureSkipVerify: request.InsecureSkipVerify,
+	}
+	if len(request.CA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(request.CA) {
+			return nil, errors.New("the CA of service is not valid PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	client := &util.URLClient{
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		TLS: tlsConfig,
+	}
+	v, _ := tlsClients.LoadOrStore(key, client)
+	return v.(*util.URLClient), nil
+}
+
+// withoutTimeout returns the client sharing the transport of client without timeout
+func withoutTimeout(client *util.URLClient) *util.URLClient {
+	c := *client.Client
+	c.Timeout = 0
+	return &util.URLClient{Client: &c, TLS: client.TLS}
+}
@@ -0,0 +1,134 @@
+package servicebus
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+func stubCachedObjects(t *testing.T, objects map[string]interface{}) {
+	origin := getCachedObject
+	getCachedObject = func(gvr schema.GroupVersionResource, namespace, name string, obj interface{}) error {
+		v, ok := objects[gvr.Resource+"/"+namespace+"/"+name]
+		if !ok {
+			return errNotCached
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, obj)
+	}
+	t.Cleanup(func() { getCachedObject = origin })
+}
+
+func TestBuildTargetURL(t *testing.T) {
+	stubCachedObjects(t, map[string]interface{}{
+		"services/default/web": &v1.Service{Spec: v1.ServiceSpec{
+			ClusterIP: "10.96.0.10",
+			Ports:     []v1.ServicePort{{Name: "http", Port: 80}, {Name: "metrics", Port: 9090}},
+		}},
+		"endpoints/default/web": &v1.Endpoints{Subsets: []v1.EndpointSubset{{
+			Addresses: []v1.EndpointAddress{{IP: "172.17.0.5"}},
+			Ports:     []v1.EndpointPort{{Name: "http", Port: 8080}},
+		}}},
+		"services/default/headless": &v1.Service{Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Ports:     []v1.ServicePort{{Port: 80}},
+		}},
+	})
+
+	cases := []struct {
+		name    string
+		request commonType.HTTPRequest
+		port    string
+		expect  string
+		wantErr bool
+	}{
+		{name: "loopback", port: "8080", expect: "http://127.0.0.1:8080/a"},
+		{name: "https loopback", request: commonType.HTTPRequest{Scheme: "https"}, port: "443", expect: "https://127.0.0.1:443/a"},
+		{name: "endpoint", request: commonType.HTTPRequest{Service: "web.default"}, port: "80", expect: "http://172.17.0.5:8080/a"},
+		{name: "cluster ip", request: commonType.HTTPRequest{Service: "web.default"}, port: "9090", expect: "http://10.96.0.10:9090/a"},
+		{name: "unknown port", request: commonType.HTTPRequest{Service: "web.default"}, port: "81", wantErr: true},
+		{name: "no endpoints", request: commonType.HTTPRequest{Service: "headless.default"}, port: "80", wantErr: true},
+		{name: "not cached", request: commonType.HTTPRequest{Service: "db.default"}, port: "80", wantErr: true},
+		{name: "invalid scheme", request: commonType.HTTPRequest{Scheme: "ftp"}, port: "80", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			url, err := buildTargetURL(&tc.request, tc.port, "/a")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expect error, got url %s", url)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if url != tc.expect {
+				t.Fatalf("expect url %s, got %s", tc.expect, url)
+			}
+		})
+	}
+}
+
+func TestGetURLClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	client, err := getURLClient(&commonType.HTTPRequest{})
+	if err != nil || client != uc {
+		t.Fatalf("expect default client for http, got %v, %v", client, err)
+	}
+
+	request := &commonType.HTTPRequest{Scheme: "https", CA: ca}
+	client, err = getURLClient(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cached, _ := getURLClient(request)
+	if cached != client {
+		t.Fatalf("expect the client is cached")
+	}
+	resp, err := client.HTTPDo(http.MethodGet, server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to call https service: %v", err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "ok" {
+		t.Fatalf("unexpected response %s", body)
+	}
+
+	if _, err := getURLClient(&commonType.HTTPRequest{Scheme: "https", CA: []byte("invalid")}); err == nil {
+		t.Fatalf("expect error for invalid CA")
+	}
+
+	// the certificate of edge service is verified against the DNS name of the service,
+	// the test server only has a certificate for example.com
+	client, err = getURLClient(&commonType.HTTPRequest{Scheme: "https", CA: ca, Service: "web.default"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client == cached || client.TLS.ServerName != "web.default.svc" {
+		t.Fatalf("expect a client with server name web.default.svc, got %q", client.TLS.ServerName)
+	}
+	if resp, err := client.HTTPDo(http.MethodGet, server.URL, nil, nil); err == nil {
+		resp.Body.Close()
+		t.Fatalf("expect the certificate is not valid for web.default.svc")
+	}
+	if client, _ = getURLClient(&commonType.HTTPRequest{Scheme: "https", Service: "web.default"}); client == uc {
+		t.Fatalf("expect the server name is set without CA")
+	}
+}
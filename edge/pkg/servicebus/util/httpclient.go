@@ -2,8 +2,10 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -32,6 +34,12 @@ type URLClient struct {
 
 // HTTPDo is a method used for http connection
 func (client *URLClient) HTTPDo(method, rawURL string, headers http.Header, body []byte) (resp *http.Response, err error) {
+	return client.HTTPDoWithContext(context.Background(), method, rawURL, headers, bytes.NewBuffer(body))
+}
+
+// HTTPDoWithContext is a method used for http connection, the body is read while it is sent
+// and the request is canceled with ctx
+func (client *URLClient) HTTPDoWithContext(ctx context.Context, method, rawURL string, headers http.Header, body io.Reader) (resp *http.Response, err error) {
 	client.clientHasPrefix(rawURL, "https")
 
 	if headers == nil {
@@ -45,7 +53,7 @@ func (client *URLClient) HTTPDo(method, rawURL string, headers http.Header, body
 		headers["Accept-Encoding"] = []string{"deflate, gzip"}
 	}
 
-	req, err := http.NewRequest(method, rawURL, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +114,7 @@ function :copy:to:destination {
   for entry in `ls /tmp/crds/*.yaml`; do
       CRD_NAME=$(echo ${entry} | cut -d'.' -f3 | cut -d'_' -f2)
 
-      if [ "$CRD_NAME" == "devices" ] || [ "$CRD_NAME" == "devicemodels" ] || [ "$CRD_NAME" == "devicestatuses" ]; then
+      if [ "$CRD_NAME" == "devices" ] || [ "$CRD_NAME" == "devicemodels" ] || [ "$CRD_NAME" == "devicestatuses" ] || [ "$CRD_NAME" == "discovereddevices" ] || [ "$CRD_NAME" == "devicediscoveryrules" ]; then
           if [ "$CRD_NAME" == "devicestatuses" ]; then
               CRD_NAME=$(remove_suffix_es "$CRD_NAME")
           else
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+var (
+	revokeLongDescription = `
+"keadm revoke" command revokes the certificates of edge nodes. The revoked certificates are recorded in the secret
+"revokedcerts" in the kubeedge namespace. CloudCore rejects the revoked certificates in the TLS handshakes of CloudHub
+and CloudStream and in the certificate renewal, and disconnects the edge nodes connected with them.
+Revoking a node revokes all its certificates issued so far, the node has to join the cluster again with a token
+to get a new certificate. The time of revocation is set by CloudCore when it loads the list, so it is compared with
+the certificates on the clock of CloudCore rather than that of this machine. Note that the QUIC connections of CloudHub
+don't carry client certificates, so a revoked node is refused over QUIC until its key "node.<node name>" is removed
+from the secret, and the revoked serial numbers are not enforced on them.
+`
+	revokeExample = `
+keadm revoke --edgenode-name edge-node-1 --kube-config /root/.kube/config
+- revoke all the certificates of edge node edge-node-1 and disconnect it.
+
+keadm revoke --serial 1a2b3c
+- revoke the edge certificate with the serial number 1a2b3c (hex).
+`
+)
+
+// NewRevoke revokes the certificates of edge nodes
+func NewRevoke() *cobra.Command {
+	opts := newRevokeOptions()
+
+	cmd := &cobra.Command{
+		Use:     "revoke",
+		Short:   "To revoke the certificates of edge nodes and disconnect them",
+		Long:    revokeLongDescription,
+		Example: revokeExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.NodeNames) == 0 && len(opts.Serials) == 0 {
+				return errors.New("at least one edge node or serial number is required")
+			}
+			client, err := util.KubeClient(opts.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			if err := revokeCerts(client, opts); err != nil {
+				return fmt.Errorf("failed to revoke certificates, err: %v", err)
+			}
+			fmt.Println("certificates are revoked")
+			return nil
+		},
+	}
+	addRevokeFlags(cmd, opts)
+	return cmd
+}
+
+func addRevokeFlags(cmd *cobra.Command, revokeOptions *common.RevokeOptions) {
+	cmd.Flags().StringVar(&revokeOptions.Kubeconfig, common.FlagNameKubeConfig, revokeOptions.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringSliceVar(&revokeOptions.NodeNames, common.FlagNameEdgeNodeName, revokeOptions.NodeNames,
+		"Use this key to set the edge nodes whose certificates are revoked")
+	cmd.Flags().StringSliceVar(&revokeOptions.Serials, common.FlagNameSerial, revokeOptions.Serials,
+		"Use this key to set the serial numbers (hex) of the revoked certificates")
+}
+
+// newRevokeOptions return common options
+func newRevokeOptions() *common.RevokeOptions {
+	opts := &common.RevokeOptions{}
+	opts.Kubeconfig = common.DefaultKubeConfig
+	return opts
+}
+
+// revokeCerts adds the nodes and serial numbers to the revocation list in the secret
+func revokeCerts(client kubernetes.Interface, opts *common.RevokeOptions) error {
+	secrets := client.CoreV1().Secrets(constants.SystemNamespace)
+	secret, err := secrets.Get(context.Background(), certs.RevokedCertsSecretName, metaV1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exist := err == nil
+	if !exist {
+		secret = &v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      certs.RevokedCertsSecretName,
+				Namespace: constants.SystemNamespace,
+			},
+		}
+	}
+
+	l, err := certs.ParseRevocationList(secret.Data)
+	if err != nil {
+		return fmt.Errorf("invalid revocation list in secret %s: %v", certs.RevokedCertsSecretName, err)
+	}
+	for _, serial := range opts.Serials {
+		n, ok := new(big.Int).SetString(serial, 16)
+		if !ok {
+			return fmt.Errorf("invalid serial number %q", serial)
+		}
+		l.RevokeSerial(n)
+	}
+	for _, nodeName := range opts.NodeNames {
+		l.RevokeNode(nodeName)
+	}
+	secret.Data = l.Data()
+
+	if exist {
+		_, err = secrets.Update(context.Background(), secret, metaV1.UpdateOptions{})
+	} else {
+		_, err = secrets.Create(context.Background(), secret, metaV1.CreateOptions{})
+	}
+	return err
+}
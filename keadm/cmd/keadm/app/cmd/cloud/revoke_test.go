@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestNewRevoke(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := NewRevoke()
+
+	assert.NotNil(cmd)
+	assert.Equal("revoke", cmd.Use)
+	assert.Equal(revokeLongDescription, cmd.Long)
+	assert.Equal(revokeExample, cmd.Example)
+
+	flag := cmd.Flags().Lookup(common.FlagNameKubeConfig)
+	assert.NotNil(flag)
+	assert.Equal(common.DefaultKubeConfig, flag.DefValue)
+	assert.NotNil(cmd.Flags().Lookup(common.FlagNameEdgeNodeName))
+	assert.NotNil(cmd.Flags().Lookup(common.FlagNameSerial))
+
+	// nothing to revoke
+	assert.Error(cmd.RunE(cmd, nil))
+}
+
+func TestRevokeCerts(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	// the secret is created on the first revocation
+	err := revokeCerts(client, &common.RevokeOptions{NodeNames: []string{"edge-1"}})
+	require.NoError(t, err)
+
+	// and updated later
+	err = revokeCerts(client, &common.RevokeOptions{Serials: []string{"1A2b"}})
+	require.NoError(t, err)
+
+	secret, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(context.Background(),
+		certs.RevokedCertsSecretName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	l, err := certs.ParseRevocationList(secret.Data)
+	require.NoError(t, err)
+	// the time of revocation is left to CloudCore
+	assert.Equal(t, map[string]time.Time{"edge-1": {}}, l.Nodes)
+	assert.Equal(t, map[string]struct{}{"1a2b": {}}, l.Serials)
+
+	err = revokeCerts(client, &common.RevokeOptions{Serials: []string{"xyz"}})
+	assert.Error(t, err)
+}
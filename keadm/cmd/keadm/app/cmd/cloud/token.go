@@ -0,0 +1,245 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+var (
+	tokenLongDescription = `
+"keadm token" command manages the bootstrap tokens for edge nodes to join the cluster.
+Unlike the shared token printed by "keadm gettoken", a bootstrap token can be limited to some edge nodes
+and a number of joins, and expires at an explicit time. The bootstrap tokens are stored as secrets in the
+kubeedge namespace, and the edge nodes joined with them are recorded in the secrets.
+`
+	tokenCreateExample = `
+keadm token create --edgenode-name edge-node-1 --ttl 1h
+- create a single-use token for edge node edge-node-1, which expires in an hour.
+
+keadm token create --labels region=east --usage-limit 10 --ttl 24h
+- create a token for 10 edge nodes registered in advance with label region=east.
+`
+)
+
+// NewToken manages the bootstrap tokens for edge nodes to join the cluster
+func NewToken() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage the bootstrap tokens for edge nodes to join the cluster",
+		Long:  tokenLongDescription,
+	}
+	cmd.AddCommand(newTokenCreate())
+	cmd.AddCommand(newTokenList())
+	cmd.AddCommand(newTokenDelete())
+	return cmd
+}
+
+func newTokenCreate() *cobra.Command {
+	opts := newTokenCreateOptions()
+
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create a bootstrap token and print it",
+		Example: tokenCreateExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := util.KubeClient(opts.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			t, err := createBootstrapToken(client, opts, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to create bootstrap token, err: %v", err)
+			}
+			fmt.Println(t)
+			return nil
+		},
+	}
+	addTokenCreateFlags(cmd, opts)
+	return cmd
+}
+
+func addTokenCreateFlags(cmd *cobra.Command, tokenCreateOptions *common.TokenCreateOptions) {
+	cmd.Flags().StringVar(&tokenCreateOptions.Kubeconfig, common.FlagNameKubeConfig, tokenCreateOptions.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().DurationVar(&tokenCreateOptions.TTL, common.FlagNameTTL, tokenCreateOptions.TTL,
+		"Use this key to set the duration before the token expires")
+	cmd.Flags().IntVar(&tokenCreateOptions.UsageLimit, common.FlagNameUsageLimit, tokenCreateOptions.UsageLimit,
+		"Use this key to set the maximum number of edge nodes joined with the token, 0 means no limit")
+	cmd.Flags().StringSliceVar(&tokenCreateOptions.NodeNames, common.FlagNameEdgeNodeName, tokenCreateOptions.NodeNames,
+		"Use this key to set the edge nodes allowed to join with the token, any node is allowed if it is not set")
+	cmd.Flags().StringSliceVarP(&tokenCreateOptions.Labels, common.FlagNameLabels, "l", tokenCreateOptions.Labels,
+		`Use this key to set the labels the edge nodes must have been registered with, e.g. "region=east,zone=a"`)
+}
+
+// newTokenCreateOptions return common options
+func newTokenCreateOptions() *common.TokenCreateOptions {
+	opts := &common.TokenCreateOptions{}
+	opts.Kubeconfig = common.DefaultKubeConfig
+	opts.TTL = 24 * time.Hour
+	opts.UsageLimit = 1
+	return opts
+}
+
+// createBootstrapToken creates the secret of a new bootstrap token and returns the token
+func createBootstrapToken(client kubernetes.Interface, opts *common.TokenCreateOptions, now time.Time) (string, error) {
+	if opts.TTL <= 0 {
+		return "", errors.New("ttl must be positive")
+	}
+	if opts.UsageLimit < 0 {
+		return "", errors.New("usage limit must not be negative")
+	}
+	nodeLabels, err := labels.ConvertSelectorToLabelsMap(strings.Join(opts.Labels, ","))
+	if err != nil {
+		return "", fmt.Errorf("invalid labels: %v", err)
+	}
+
+	caSecret, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(context.Background(),
+		common.CaSecretName, metaV1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get CA, err: %v", err)
+	}
+
+	t, err := token.NewBootstrapToken(now.Add(opts.TTL))
+	if err != nil {
+		return "", err
+	}
+	t.UsageLimit = opts.UsageLimit
+	t.NodeNames = opts.NodeNames
+	t.NodeLabels = nodeLabels
+	secret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      t.SecretName(),
+			Namespace: constants.SystemNamespace,
+			Labels:    map[string]string{token.BootstrapTokenLabel: "true"},
+		},
+		Data: t.Data(),
+		Type: "Opaque",
+	}
+	if _, err := client.CoreV1().Secrets(constants.SystemNamespace).Create(context.Background(),
+		secret, metaV1.CreateOptions{}); err != nil {
+		return "", err
+	}
+	return t.String(caSecret.Data[common.CaDataName]), nil
+}
+
+func newTokenList() *cobra.Command {
+	var kubeconfig string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the bootstrap tokens",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := util.KubeClient(kubeconfig)
+			if err != nil {
+				return err
+			}
+			return listBootstrapTokens(client, os.Stdout, time.Now())
+		},
+	}
+	cmd.Flags().StringVar(&kubeconfig, common.FlagNameKubeConfig, common.DefaultKubeConfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	return cmd
+}
+
+// listBootstrapTokens prints the bootstrap tokens without their secrets
+func listBootstrapTokens(client kubernetes.Interface, out io.Writer, now time.Time) error {
+	secrets, err := client.CoreV1().Secrets(constants.SystemNamespace).List(context.Background(), metaV1.ListOptions{
+		LabelSelector: token.BootstrapTokenLabel + "=true",
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEXPIRES\tUSAGES\tNODES\tLABELS\tUSED BY")
+	for _, secret := range secrets.Items {
+		t, err := token.ParseBootstrapToken(secret.Data)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t<invalid: %v>\t\t\t\t\n", strings.TrimPrefix(secret.Name, token.BootstrapTokenSecretPrefix), err)
+			continue
+		}
+		expires := t.Expiration.Format(time.RFC3339)
+		if now.After(t.Expiration) {
+			expires += " (expired)"
+		}
+		limit := "unlimited"
+		if t.UsageLimit > 0 {
+			limit = strconv.Itoa(t.UsageLimit)
+		}
+		usedBy := make([]string, 0, len(t.UsedBy))
+		for _, u := range t.UsedBy {
+			usedBy = append(usedBy, fmt.Sprintf("%s@%s", u.NodeName, u.Time.Format(time.RFC3339)))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d/%s\t%s\t%s\t%s\n", t.ID, expires, len(t.UsedBy), limit,
+			valueOrAny(strings.Join(t.NodeNames, ",")), valueOrAny(t.NodeLabels.String()), strings.Join(usedBy, ","))
+	}
+	return w.Flush()
+}
+
+func valueOrAny(value string) string {
+	if value == "" {
+		return "<any>"
+	}
+	return value
+}
+
+func newTokenDelete() *cobra.Command {
+	var kubeconfig string
+
+	cmd := &cobra.Command{
+		Use:   "delete <token id>...",
+		Short: "Delete the bootstrap tokens, the edge nodes joined with them are not affected",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := util.KubeClient(kubeconfig)
+			if err != nil {
+				return err
+			}
+			for _, id := range args {
+				if err := client.CoreV1().Secrets(constants.SystemNamespace).Delete(context.Background(),
+					token.BootstrapTokenSecretPrefix+id, metaV1.DeleteOptions{}); err != nil {
+					return fmt.Errorf("failed to delete bootstrap token %s, err: %v", id, err)
+				}
+				fmt.Printf("bootstrap token %s deleted\n", id)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&kubeconfig, common.FlagNameKubeConfig, common.DefaultKubeConfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	return cmd
+}
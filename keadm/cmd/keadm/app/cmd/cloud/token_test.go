@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+func TestNewToken(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := NewToken()
+	assert.Equal("token", cmd.Use)
+	assert.Equal(tokenLongDescription, cmd.Long)
+	for _, name := range []string{"create", "list", "delete"} {
+		sub, _, err := cmd.Find([]string{name})
+		assert.NoError(err)
+		assert.Equal(name, sub.Name())
+	}
+
+	create := newTokenCreate()
+	for _, flag := range []string{common.FlagNameKubeConfig, common.FlagNameTTL, common.FlagNameUsageLimit,
+		common.FlagNameEdgeNodeName, common.FlagNameLabels} {
+		assert.NotNil(create.Flags().Lookup(flag), flag)
+	}
+	assert.Equal("1", create.Flags().Lookup(common.FlagNameUsageLimit).DefValue)
+	assert.Equal("24h0m0s", create.Flags().Lookup(common.FlagNameTTL).DefValue)
+}
+
+func TestCreateAndListBootstrapToken(t *testing.T) {
+	ca := []byte("ca")
+	client := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: common.CaSecretName, Namespace: constants.SystemNamespace},
+		Data:       map[string][]byte{common.CaDataName: ca},
+	})
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	opts := newTokenCreateOptions()
+	opts.NodeNames = []string{"edge-1"}
+	opts.Labels = []string{"region=east"}
+	tokenString, err := createBootstrapToken(client, opts, now)
+	require.NoError(t, err)
+
+	// the token can be used by edge nodes as the shared token
+	realToken, err := token.VerifyCAAndGetRealToken(tokenString, ca)
+	require.NoError(t, err)
+	id, _, ok := token.ParseBootstrapTokenString(realToken)
+	require.True(t, ok)
+
+	secret, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(context.Background(),
+		token.BootstrapTokenSecretPrefix+id, metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "true", secret.Labels[token.BootstrapTokenLabel])
+	bt, err := token.ParseBootstrapToken(secret.Data)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(24*time.Hour), bt.Expiration)
+	assert.Equal(t, 1, bt.UsageLimit)
+	assert.Equal(t, []string{"edge-1"}, bt.NodeNames)
+	assert.Equal(t, labels.Set{"region": "east"}, bt.NodeLabels)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, listBootstrapTokens(client, out, now.Add(48*time.Hour)))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], id)
+	assert.Contains(t, lines[1], "(expired)")
+	assert.Contains(t, lines[1], "0/1")
+	assert.NotContains(t, out.String(), bt.Secret)
+
+	for _, invalid := range []*common.TokenCreateOptions{
+		{TTL: 0, UsageLimit: 1},
+		{TTL: time.Hour, UsageLimit: -1},
+		{TTL: time.Hour, Labels: []string{"a=b=c"}},
+	} {
+		_, err := createBootstrapToken(client, invalid, now)
+		assert.Error(t, err)
+	}
+}
@@ -77,6 +77,8 @@ func NewKubeedgeCommand() *cobra.Command {
 
 	cmds.AddCommand(NewCmdVersion())
 	cmds.AddCommand(cloud.NewGettoken())
+	cmds.AddCommand(cloud.NewToken())
+	cmds.AddCommand(cloud.NewRevoke())
 	cmds.AddCommand(debug.NewEdgeDebug())
 
 	// recommended cmds
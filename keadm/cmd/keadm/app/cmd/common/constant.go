@@ -92,6 +92,15 @@ const (
 	// FlagNameEdgeNodeName is KubeEdge node unique identification string
 	FlagNameEdgeNodeName = "edgenode-name"
 
+	// FlagNameSerial sets the serial number of the edge certificate to revoke
+	FlagNameSerial = "serial"
+
+	// FlagNameTTL sets the duration before the bootstrap token expires
+	FlagNameTTL = "ttl"
+
+	// FlagNameUsageLimit sets the maximum number of the edge nodes joined with the bootstrap token
+	FlagNameUsageLimit = "usage-limit"
+
 	// FlagNameRemoteRuntimeEndpoint is KubeEdge remote-runtime-endpoint string
 	FlagNameRemoteRuntimeEndpoint = "remote-runtime-endpoint"
 
@@ -137,6 +146,10 @@ const (
 	TokenSecretName = "tokensecret"
 	TokenDataName   = "tokendata"
 
+	// CA secret
+	CaSecretName = "casecret"
+	CaDataName   = "cadata"
+
 	StrCheck    = "check"
 	StrDiagnose = "diagnose"
 
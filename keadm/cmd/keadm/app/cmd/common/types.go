@@ -19,6 +19,7 @@ package common
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 )
@@ -139,6 +140,20 @@ type GettokenOptions struct {
 	Kubeconfig string
 }
 
+type TokenCreateOptions struct {
+	Kubeconfig string
+	TTL        time.Duration
+	UsageLimit int
+	NodeNames  []string
+	Labels     []string
+}
+
+type RevokeOptions struct {
+	Kubeconfig string
+	NodeNames  []string
+	Serials    []string
+}
+
 type DiagnoseOptions struct {
 	Pod          string
 	Namespace    string
@@ -147,6 +162,21 @@ type DiagnoseOptions struct {
 	DBPath       string
 }
 
+// MigrateDBOptions has the kubeedge debug migrate-db information filled by CLI
+type MigrateDBOptions struct {
+	FromDriver string
+	From       string
+	ToDriver   string
+	To         string
+}
+
+// DBOptions has the kubeedge debug db information filled by CLI
+type DBOptions struct {
+	Driver     string
+	DataSource string
+	Force      bool
+}
+
 type DiagnoseObject struct {
 	Desc string
 	Use  string
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+var (
+	dbLongDescription = `Check, compact or repair the edge database.
+Edgecore should be stopped before compacting or repairing the database.
+`
+	dbExample = `
+# Check the integrity of the edge database and count its records
+keadm debug db check
+
+# Compact the bbolt database to reclaim the free pages
+keadm debug db compact --driver bbolt --data-source /var/lib/kubeedge/edgecore.bolt
+
+# Move the corrupted database aside, edgecore rebuilds it from the cloud after restart
+keadm debug db repair
+`
+)
+
+// NewDB returns KubeEdge db command.
+func NewDB() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "db",
+		Short:   "Check, compact or repair the edge database",
+		Long:    dbLongDescription,
+		Example: dbExample,
+	}
+	cmd.AddCommand(newDBCheck())
+	cmd.AddCommand(newDBCompact())
+	cmd.AddCommand(newDBRepair())
+	return cmd
+}
+
+func newDBCheck() *cobra.Command {
+	opts := newDBOptions()
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the integrity of the edge database and count its records",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecuteDBCheck(opts)
+		},
+	}
+	addDBFlags(cmd.Flags(), opts)
+	return cmd
+}
+
+func newDBCompact() *cobra.Command {
+	opts := newDBOptions()
+	cmd := &cobra.Command{
+		Use:   "compact",
+		Short: "Compact the edge database to reclaim the free pages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecuteDBCompact(opts)
+		},
+	}
+	addDBFlags(cmd.Flags(), opts)
+	return cmd
+}
+
+func newDBRepair() *cobra.Command {
+	opts := newDBOptions()
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Quarantine the corrupted edge database, which is rebuilt from the cloud when edgecore restarts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecuteDBRepair(opts)
+		},
+	}
+	addDBFlags(cmd.Flags(), opts)
+	cmd.Flags().BoolVar(&opts.Force, "force", opts.Force, "Quarantine the database even if it passes the integrity check")
+	return cmd
+}
+
+func newDBOptions() *common.DBOptions {
+	return &common.DBOptions{
+		Driver:     v1alpha2.DataBaseDriverName,
+		DataSource: v1alpha2.DataBaseDataSource,
+	}
+}
+
+func addDBFlags(fs *pflag.FlagSet, opts *common.DBOptions) {
+	fs.StringVar(&opts.Driver, "driver", opts.Driver, "Specify the driver of the database, sqlite3 or bbolt")
+	fs.StringVar(&opts.DataSource, "data-source", opts.DataSource, "Specify the data source of the database")
+}
+
+// openDB opens the existing database, so that a mistyped data source does not create an empty one
+func openDB(opts *common.DBOptions) (dao.Storage, error) {
+	if _, err := os.Stat(opts.DataSource); err != nil {
+		return nil, fmt.Errorf("failed to find the database %s: %v", opts.DataSource, err)
+	}
+	return dao.NewStorage(opts.Driver, opts.DataSource)
+}
+
+// ExecuteDBCheck checks the integrity of the database and prints the number of records of each table
+func ExecuteDBCheck(opts *common.DBOptions) error {
+	db, err := openDB(opts)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Check(); err != nil {
+		return err
+	}
+	fmt.Printf("Database %s passed the integrity check\n", opts.DataSource)
+	for _, model := range dao.AllModels() {
+		if !db.HasTable(model) {
+			continue
+		}
+		count, err := db.Count(model)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-24s %d\n", model.(interface{ TableName() string }).TableName(), count)
+	}
+	return nil
+}
+
+// ExecuteDBCompact compacts the database
+func ExecuteDBCompact(opts *common.DBOptions) error {
+	db, err := openDB(opts)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	before := fileSize(opts.DataSource)
+	if err := db.Compact(); err != nil {
+		return fmt.Errorf("failed to compact database %s: %v", opts.DataSource, err)
+	}
+	fmt.Printf("Database %s is compacted from %d to %d bytes\n", opts.DataSource, before, fileSize(opts.DataSource))
+	return nil
+}
+
+// ExecuteDBRepair quarantines the corrupted database and marks it to be resynced, edgecore
+// creates a new database and requests the cloud to resend all the objects after restart
+func ExecuteDBRepair(opts *common.DBOptions) error {
+	if _, err := os.Stat(opts.DataSource); err != nil {
+		return fmt.Errorf("failed to find the database %s: %v", opts.DataSource, err)
+	}
+	// a database corrupted badly may even fail to open
+	db, err := dao.NewStorage(opts.Driver, opts.DataSource)
+	if err == nil {
+		err = db.Check()
+		db.Close()
+	}
+	if err == nil && !opts.Force {
+		return fmt.Errorf("database %s passed the integrity check, use --force to repair it anyway", opts.DataSource)
+	}
+	if err != nil {
+		fmt.Printf("Database %s failed the integrity check: %v\n", opts.DataSource, err)
+	}
+
+	quarantined, err := dao.Quarantine(opts.DataSource)
+	if err != nil {
+		return err
+	}
+	if err := dao.MarkResync(opts.DataSource); err != nil {
+		return err
+	}
+	fmt.Printf("Database %s is moved to %s, restart edgecore to rebuild it from the cloud\n", opts.DataSource, quarantined)
+	return nil
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
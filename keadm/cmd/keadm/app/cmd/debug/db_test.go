@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestNewDB(t *testing.T) {
+	cmd := NewDB()
+	var subCommands []string
+	for _, c := range cmd.Commands() {
+		subCommands = append(subCommands, c.Name())
+	}
+	assert.ElementsMatch(t, []string{"check", "compact", "repair"}, subCommands)
+}
+
+func TestExecuteDB(t *testing.T) {
+	for _, driverName := range []string{v1alpha2.DataBaseDriverName, v1alpha2.DataBaseDriverBbolt} {
+		t.Run(driverName, func(t *testing.T) {
+			opts := &common.DBOptions{
+				Driver:     driverName,
+				DataSource: filepath.Join(t.TempDir(), "edgecore.db"),
+			}
+			assert.Error(t, ExecuteDBCheck(opts))
+
+			db, err := dao.NewStorage(opts.Driver, opts.DataSource)
+			require.NoError(t, err)
+			require.NoError(t, db.Migrate(&models.Meta{}))
+			require.NoError(t, db.Create(&models.Meta{Key: "default/pod/p1", Type: "pod", Value: "v"}))
+			require.NoError(t, db.Close())
+
+			require.NoError(t, ExecuteDBCheck(opts))
+			require.NoError(t, ExecuteDBCompact(opts))
+			// the healthy database is only repaired with --force
+			assert.Error(t, ExecuteDBRepair(opts))
+
+			require.NoError(t, os.WriteFile(opts.DataSource, []byte("corrupted database"), 0600))
+			assert.Error(t, ExecuteDBCheck(opts))
+			require.NoError(t, ExecuteDBRepair(opts))
+			_, err = os.Stat(opts.DataSource)
+			assert.True(t, os.IsNotExist(err))
+			quarantined, err := filepath.Glob(opts.DataSource + ".corrupt-*")
+			require.NoError(t, err)
+			assert.Len(t, quarantined, 1)
+		})
+	}
+}
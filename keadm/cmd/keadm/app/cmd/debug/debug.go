@@ -34,5 +34,7 @@ func NewEdgeDebug() *cobra.Command {
 	cmd.AddCommand(NewDiagnose())
 	cmd.AddCommand(NewCheck())
 	cmd.AddCommand(NewCollect())
+	cmd.AddCommand(NewMigrateDB())
+	cmd.AddCommand(NewDB())
 	return cmd
 }
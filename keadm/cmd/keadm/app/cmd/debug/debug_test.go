@@ -32,7 +32,7 @@ func TestNewEdgeDebug(t *testing.T) {
 	assert.Equal(edgeDebugShortDescription, cmd.Short)
 	assert.Equal(edgeDebugLongDescription, cmd.Long)
 
-	expectedSubCommands := []string{"get", "diagnose", "check", "collect"}
+	expectedSubCommands := []string{"get", "diagnose", "check", "collect", "migrate-db", "db"}
 	for _, subCmd := range expectedSubCommands {
 		found := false
 		for _, cmd := range cmd.Commands() {
@@ -35,12 +35,17 @@ import (
 	printersinternal "k8s.io/kubernetes/pkg/printers/internalversion"
 	"k8s.io/kubernetes/pkg/printers/storage"
 
+	apiconsts "github.com/kubeedge/api/apis/common/constants"
 	edgecoreCfg "github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/common/constants"
 	commonmsg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/dbclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/encryption"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
 )
 
 const (
@@ -143,6 +148,8 @@ func addGetOtherFlags(cmd *cobra.Command, getOption *GetOptions) {
 	cmd.Flags().StringVarP(&getOption.LabelSelector, "selector", "l", getOption.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
 	cmd.Flags().StringVarP(&getOption.DataPath, "edgedb-path", "p", getOption.DataPath, "Indicate the edge node database path, the default path is \"/var/lib/kubeedge/edgecore.db\"")
 	cmd.Flags().BoolVarP(&getOption.AllNamespace, "all-namespaces", "A", getOption.AllNamespace, "List the requested object(s) across all namespaces")
+	cmd.Flags().StringVarP(&getOption.Config, common.EdgecoreConfig, "c", getOption.Config,
+		fmt.Sprintf("Specify configuration file, which is used to decrypt the encrypted database, default is %s", apiconsts.EdgecoreConfigPath))
 }
 
 // NewGetOptions returns a GetOptions with default EdgeCore database source.
@@ -150,6 +157,7 @@ func NewGetOptions() *GetOptions {
 	opts := &GetOptions{
 		Namespace:  "default",
 		DataPath:   edgecoreCfg.DataBaseDataSource,
+		Config:     apiconsts.EdgecoreConfigPath,
 		PrintFlags: NewGetPrintFlags(),
 	}
 
@@ -162,6 +170,7 @@ type GetOptions struct {
 	Namespace     string
 	LabelSelector string
 	DataPath      string
+	Config        string
 
 	PrintFlags *PrintFlags
 }
@@ -233,7 +242,7 @@ func (g *GetOptions) Validate(args []string) error {
 		return fmt.Errorf("edgeCore database file %v not exist. ", g.DataPath)
 	}
 
-	if err := InitDB(edgecoreCfg.DataBaseDriverName, edgecoreCfg.DataBaseAliasName, g.DataPath); err != nil {
+	if err := g.initStorage(); err != nil {
 		return fmt.Errorf("failed to initialize database: %v ", err)
 	}
 	if len(*g.PrintFlags.OutputFormat) > 0 {
@@ -250,6 +259,36 @@ func (g *GetOptions) Validate(args []string) error {
 	return nil
 }
 
+// initStorage opens the EdgeCore database and loads its data encryption key with the
+// database config of EdgeCore, so that the encrypted secrets can be read
+func (g *GetOptions) initStorage() error {
+	db := &edgecoreCfg.DataBase{
+		DriverName: edgecoreCfg.DataBaseDriverName,
+		AliasName:  edgecoreCfg.DataBaseAliasName,
+		DataSource: g.DataPath,
+	}
+	var encryptionCfg *edgecoreCfg.DataBaseEncryption
+	if isFileExist(g.Config) {
+		config, err := util.ParseEdgecoreConfig(g.Config)
+		if err != nil {
+			return fmt.Errorf("failed to parse EdgeCore config %s: %v", g.Config, err)
+		}
+		if config.DataBase != nil {
+			if config.DataBase.DriverName != "" {
+				db.DriverName = config.DataBase.DriverName
+			}
+			encryptionCfg = config.DataBase.Encryption
+		}
+	}
+
+	dao.Init(db)
+	ms = dbclient.NewMetaService()
+	if err := encryption.Load(dao.GetStorage(), encryptionCfg); err != nil {
+		return fmt.Errorf("failed to load data encryption key, specify the EdgeCore config with --%s: %v", common.EdgecoreConfig, err)
+	}
+	return nil
+}
+
 func (g *GetOptions) queryDataFromDatabase(resType string, resNames []string) ([]models.Meta, error) {
 	var result []models.Meta
 
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+var (
+	migrateDBLongDescription = `Migrate the edge metadata from one storage backend to another.
+Edgecore must be stopped before the migration, and the database.driverName and
+database.dataSource of edgecore config must be updated to the new storage after it.
+`
+	migrateDBExample = `
+# Migrate the SQLite database to bbolt
+keadm debug migrate-db --to-driver bbolt --to /var/lib/kubeedge/edgecore.bolt
+
+# Migrate the bbolt database back to SQLite
+keadm debug migrate-db --from-driver bbolt --from /var/lib/kubeedge/edgecore.bolt --to-driver sqlite3 --to /var/lib/kubeedge/edgecore.db
+`
+)
+
+// NewMigrateDB returns KubeEdge migrate-db command.
+func NewMigrateDB() *cobra.Command {
+	opts := newMigrateDBOptions()
+	cmd := &cobra.Command{
+		Use:     "migrate-db",
+		Short:   "Migrate the edge metadata to another storage backend",
+		Long:    migrateDBLongDescription,
+		Example: migrateDBExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecuteMigrateDB(opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.FromDriver, "from-driver", opts.FromDriver, "Specify the driver of the source database, sqlite3 or bbolt")
+	cmd.Flags().StringVar(&opts.From, "from", opts.From, "Specify the data source of the source database")
+	cmd.Flags().StringVar(&opts.ToDriver, "to-driver", opts.ToDriver, "Specify the driver of the target database, sqlite3 or bbolt")
+	cmd.Flags().StringVar(&opts.To, "to", opts.To, "Specify the data source of the target database, it must not exist")
+	return cmd
+}
+
+func newMigrateDBOptions() *common.MigrateDBOptions {
+	return &common.MigrateDBOptions{
+		FromDriver: v1alpha2.DataBaseDriverName,
+		From:       v1alpha2.DataBaseDataSource,
+		ToDriver:   v1alpha2.DataBaseDriverBbolt,
+	}
+}
+
+// ExecuteMigrateDB copies all the edge metadata from the source database to the target one
+func ExecuteMigrateDB(opts *common.MigrateDBOptions) error {
+	if opts.To == "" {
+		return fmt.Errorf("the target database must be specified by --to")
+	}
+	if opts.From == opts.To {
+		return fmt.Errorf("the source and target database must be different")
+	}
+	if _, err := os.Stat(opts.From); err != nil {
+		return fmt.Errorf("failed to find the source database %s: %v", opts.From, err)
+	}
+	// the target is not merged into, so that a failed migration can be simply retried
+	if _, err := os.Stat(opts.To); err == nil {
+		return fmt.Errorf("the target database %s already exists", opts.To)
+	}
+
+	src, err := dao.NewStorage(opts.FromDriver, opts.From)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := dao.NewStorage(opts.ToDriver, opts.To)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	copied, err := dao.Copy(src, dst, dao.AllModels()...)
+	if err != nil {
+		os.Remove(opts.To)
+		return fmt.Errorf("failed to migrate database: %v", err)
+	}
+	fmt.Printf("%d records are migrated from %s to %s\n", copied, opts.From, opts.To)
+	fmt.Printf("Update database.driverName to %s and database.dataSource to %s in edgecore config, then restart edgecore\n",
+		opts.ToDriver, opts.To)
+	return nil
+}
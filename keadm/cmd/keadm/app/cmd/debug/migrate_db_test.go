@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/models"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestExecuteMigrateDB(t *testing.T) {
+	dir := t.TempDir()
+	from := filepath.Join(dir, "edgecore.db")
+	src, err := dao.NewStorage(v1alpha2.DataBaseDriverName, from)
+	require.NoError(t, err)
+	require.NoError(t, src.Migrate(&models.Meta{}))
+	require.NoError(t, src.Create(&models.Meta{Key: "default/pod/p1", Type: "pod", Value: "v"}))
+	require.NoError(t, src.Close())
+
+	opts := &common.MigrateDBOptions{
+		FromDriver: v1alpha2.DataBaseDriverName,
+		From:       from,
+		ToDriver:   v1alpha2.DataBaseDriverBbolt,
+		To:         filepath.Join(dir, "edgecore.bolt"),
+	}
+	require.NoError(t, ExecuteMigrateDB(opts))
+	// the existing target is not overwritten
+	assert.Error(t, ExecuteMigrateDB(opts))
+
+	dst, err := dao.NewStorage(opts.ToDriver, opts.To)
+	require.NoError(t, err)
+	defer dst.Close()
+	var meta models.Meta
+	require.NoError(t, dst.First(&meta, dao.Query{Conditions: []dao.Condition{dao.Eq("key", "default/pod/p1")}}))
+	assert.Equal(t, "v", meta.Value)
+}
@@ -50,6 +50,9 @@ type Application struct {
 	Option      []byte
 	ReqBody     []byte
 	Subresource string
+	// ServiceAccount is the username of the service account which sends the request to
+	// edge MetaServer, it is authorized when the node itself is not allowed to access the resource
+	ServiceAccount string
 
 	// The following field defines the Application response result
 	RespBody []byte
@@ -106,6 +109,7 @@ func (a *Application) Identifier() string {
 	b = append(b, a.Option...)
 	b = append(b, a.ReqBody...)
 	b = append(b, []byte(a.Subresource)...)
+	b = append(b, []byte(a.ServiceAccount)...)
 	a.ID = fmt.Sprintf("%x", sha256.Sum256(b))
 	return a.ID
 }
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// digestResources are the resources compared by object digest, their objects are sent to
+// edge with ObjectSyncs and stored in meta_v2
+var digestResources = map[string]bool{
+	"/v1, Resource=pods":       true,
+	"/v1, Resource=configmaps": true,
+	"/v1, Resource=secrets":    true,
+}
+
+// IsDigestResource returns whether the objects of the resource, formatted as
+// schema.GroupVersionResource.String(), are compared by object digest
+func IsDigestResource(gvr string) bool {
+	return digestResources[gvr]
+}
+
+// DigestKey returns the key of the object in the object digest
+func DigestKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// NewResourceDigest returns the digest of the objects of one resource, versions are the
+// resource versions keyed by DigestKey. Edge and cloud compute the same digest for the
+// same objects and versions, regardless of the order.
+func NewResourceDigest(versions map[string]string) types.ResourceDigest {
+	keys := make([]string, 0, len(versions))
+	for key := range versions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(versions[key]))
+		h.Write([]byte{'\n'})
+	}
+	return types.ResourceDigest{
+		Count: len(versions),
+		Hash:  hex.EncodeToString(h.Sum(nil)),
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDigestKey(t *testing.T) {
+	if got := DigestKey("default", "p1"); got != "default/p1" {
+		t.Errorf("DigestKey() = %v, want default/p1", got)
+	}
+	if got := DigestKey("", "n1"); got != "n1" {
+		t.Errorf("DigestKey() = %v, want n1", got)
+	}
+}
+
+func TestNewResourceDigest(t *testing.T) {
+	versions := map[string]string{"default/p1": "1", "default/p2": "2"}
+	digest := NewResourceDigest(versions)
+	if digest.Count != 2 {
+		t.Errorf("NewResourceDigest().Count = %d, want 2", digest.Count)
+	}
+	if same := NewResourceDigest(map[string]string{"default/p2": "2", "default/p1": "1"}); same != digest {
+		t.Errorf("NewResourceDigest() = %v, want %v", same, digest)
+	}
+
+	for _, changed := range []map[string]string{
+		{"default/p1": "1", "default/p2": "3"},
+		{"default/p1": "1"},
+		{"default/p1": "1", "default/p2": "2", "default/p3": "3"},
+		// the separators make the keys and versions unambiguous
+		{"default/p1": "1", "default/p2": "2\n"},
+	} {
+		if got := NewResourceDigest(changed); got.Hash == digest.Hash {
+			t.Errorf("NewResourceDigest(%v) has the same hash as %v", changed, versions)
+		}
+	}
+	if empty := NewResourceDigest(nil); empty.Count != 0 || empty.Hash == "" {
+		t.Errorf("NewResourceDigest(nil) = %v", empty)
+	}
+}
+
+func TestIsDigestResource(t *testing.T) {
+	for gvr, want := range map[string]bool{
+		schema.GroupVersionResource{Version: "v1", Resource: "pods"}.String():                              true,
+		schema.GroupVersionResource{Version: "v1", Resource: "secrets"}.String():                           true,
+		schema.GroupVersionResource{Group: "rules.kubeedge.io", Version: "v1", Resource: "rules"}.String(): false,
+		schema.GroupVersionResource{Version: "v1", Resource: "services"}.String():                          false,
+	} {
+		if got := IsDigestResource(gvr); got != want {
+			t.Errorf("IsDigestResource(%q) = %v, want %v", gvr, got, want)
+		}
+	}
+}
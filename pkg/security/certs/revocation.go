@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certs
+
+import (
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// RevokedCertsSecretName is the name of the secret which stores the revocation list
+	RevokedCertsSecretName = "revokedcerts"
+
+	revokedSerialPrefix  = "serial."
+	revokedNodePrefix    = "node."
+	nodeCommonNamePrefix = "system:node:"
+)
+
+// RevocationList is the list of the revoked edge certificates. It is stored in the data of
+// a secret, with a key "serial.<serial number in hex>" for each revoked certificate and a key
+// "node.<node name>" with the time of revocation for each revoked node. The time is empty until
+// it is set by CloudCore, so that it is compared with the certificates issued on the same clock.
+type RevocationList struct {
+	// Serials are the serial numbers (hex) of the revoked certificates
+	Serials map[string]struct{}
+	// Nodes are the times of revocation of the revoked nodes, the certificates of
+	// a node issued no later than the time are revoked. The zero time means the time
+	// is not set yet, all the certificates of the node are revoked until then.
+	Nodes map[string]time.Time
+}
+
+// NewRevocationList returns an empty revocation list
+func NewRevocationList() *RevocationList {
+	return &RevocationList{
+		Serials: make(map[string]struct{}),
+		Nodes:   make(map[string]time.Time),
+	}
+}
+
+// ParseRevocationList parses the revocation list from the data of the secret
+func ParseRevocationList(data map[string][]byte) (*RevocationList, error) {
+	l := NewRevocationList()
+	for key, value := range data {
+		switch {
+		case strings.HasPrefix(key, revokedSerialPrefix):
+			serial, ok := new(big.Int).SetString(strings.TrimPrefix(key, revokedSerialPrefix), 16)
+			if !ok {
+				return nil, fmt.Errorf("invalid serial number in %q", key)
+			}
+			l.Serials[SerialString(serial)] = struct{}{}
+		case strings.HasPrefix(key, revokedNodePrefix):
+			var at time.Time
+			if len(value) > 0 {
+				var err error
+				at, err = time.Parse(time.RFC3339, string(value))
+				if err != nil {
+					return nil, fmt.Errorf("invalid revocation time of %q: %v", key, err)
+				}
+			}
+			l.Nodes[strings.TrimPrefix(key, revokedNodePrefix)] = at
+		default:
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+	}
+	return l, nil
+}
+
+// Data returns the revocation list as the data of the secret
+func (l *RevocationList) Data() map[string][]byte {
+	data := make(map[string][]byte, len(l.Serials)+len(l.Nodes))
+	for serial := range l.Serials {
+		data[revokedSerialPrefix+serial] = nil
+	}
+	for node, at := range l.Nodes {
+		if at.IsZero() {
+			data[revokedNodePrefix+node] = nil
+			continue
+		}
+		data[revokedNodePrefix+node] = []byte(at.UTC().Format(time.RFC3339))
+	}
+	return data
+}
+
+// RevokeSerial revokes the certificate with the serial number
+func (l *RevocationList) RevokeSerial(serial *big.Int) {
+	l.Serials[SerialString(serial)] = struct{}{}
+}
+
+// RevokeNode revokes all the certificates of the node issued so far, the time of
+// revocation is left to be set by SetRevocationTime
+func (l *RevocationList) RevokeNode(nodeName string) {
+	l.Nodes[nodeName] = time.Time{}
+}
+
+// SetRevocationTime sets the time of revocation of the nodes whose time is not set yet,
+// it returns whether any node is changed
+func (l *RevocationList) SetRevocationTime(now time.Time) bool {
+	changed := false
+	for node, at := range l.Nodes {
+		if at.IsZero() {
+			l.Nodes[node] = now.UTC().Truncate(time.Second)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// IsRevoked returns whether the certificate is revoked
+func (l *RevocationList) IsRevoked(cert *x509.Certificate) bool {
+	if _, ok := l.Serials[SerialString(cert.SerialNumber)]; ok {
+		return true
+	}
+	nodeName, ok := strings.CutPrefix(cert.Subject.CommonName, nodeCommonNamePrefix)
+	if !ok {
+		return false
+	}
+	at, ok := l.Nodes[nodeName]
+	return ok && (at.IsZero() || !cert.NotBefore.After(at))
+}
+
+// IsRevokedNode returns whether the node is in the list, it is used to check
+// the connections without client certificates
+func (l *RevocationList) IsRevokedNode(nodeName string) bool {
+	_, ok := l.Nodes[nodeName]
+	return ok
+}
+
+// SerialString returns the serial number in the revocation list
+func SerialString(serial *big.Int) string {
+	return serial.Text(16)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocationList(t *testing.T) {
+	revokedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	l := NewRevocationList()
+	l.RevokeSerial(big.NewInt(0xabc))
+	l.RevokeNode("edge-1")
+	assert.True(t, l.SetRevocationTime(revokedAt))
+	assert.False(t, l.SetRevocationTime(revokedAt.Add(time.Hour)))
+	l.RevokeNode("edge-3")
+
+	// round trip through the data of the secret
+	parsed, err := ParseRevocationList(l.Data())
+	require.NoError(t, err)
+	assert.Equal(t, l, parsed)
+
+	newCert := func(serial int64, cn string, notBefore time.Time) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: cn},
+			NotBefore:    notBefore,
+		}
+	}
+	cases := []struct {
+		name    string
+		cert    *x509.Certificate
+		revoked bool
+	}{
+		{
+			name:    "revoked serial",
+			cert:    newCert(0xabc, "system:node:edge-2", revokedAt.Add(time.Hour)),
+			revoked: true,
+		},
+		{
+			name:    "issued before the node is revoked",
+			cert:    newCert(1, "system:node:edge-1", revokedAt.Add(-time.Hour)),
+			revoked: true,
+		},
+		{
+			name:    "issued when the node is revoked",
+			cert:    newCert(1, "system:node:edge-1", revokedAt),
+			revoked: true,
+		},
+		{
+			name: "issued after the node is revoked",
+			cert: newCert(1, "system:node:edge-1", revokedAt.Add(time.Second)),
+		},
+		{
+			name:    "revocation time not set",
+			cert:    newCert(1, "system:node:edge-3", revokedAt.Add(time.Hour)),
+			revoked: true,
+		},
+		{
+			name: "other node",
+			cert: newCert(1, "system:node:edge-2", revokedAt.Add(-time.Hour)),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.revoked, parsed.IsRevoked(c.cert))
+		})
+	}
+
+	assert.True(t, parsed.IsRevokedNode("edge-1"))
+	assert.True(t, parsed.IsRevokedNode("edge-3"))
+	assert.False(t, parsed.IsRevokedNode("edge-2"))
+}
+
+func TestParseRevocationListInvalid(t *testing.T) {
+	cases := map[string]map[string][]byte{
+		"invalid serial": {"serial.xyz": nil},
+		"invalid time":   {"node.edge-1": []byte("yesterday")},
+		"unknown key":    {"foo": nil},
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRevocationList(data)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package token
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// BootstrapTokenSecretPrefix is the prefix of the names of the secrets which store the bootstrap tokens
+	BootstrapTokenSecretPrefix = "bootstraptoken-"
+	// BootstrapTokenLabel is the label of the secrets which store the bootstrap tokens
+	BootstrapTokenLabel = "kubeedge.io/bootstrap-token"
+
+	// bootstrapTokenMarker distinguishes the bootstrap tokens from the shared jwt token,
+	// a bootstrap token is in the form "<ca hash>.bootstrap.<id>.<secret>"
+	bootstrapTokenMarker = "bootstrap"
+
+	bootstrapTokenIDKey         = "token-id"
+	bootstrapTokenSecretKey     = "token-secret"
+	bootstrapTokenExpirationKey = "expiration"
+	bootstrapTokenUsageLimitKey = "usage-limit"
+	bootstrapTokenNodeNamesKey  = "node-names"
+	bootstrapTokenNodeLabelsKey = "node-labels"
+	bootstrapTokenUsedByKey     = "used-by"
+
+	bootstrapTokenIDBytes     = 3
+	bootstrapTokenSecretBytes = 8
+)
+
+// BootstrapToken is a token for edge nodes to join the cluster, which can be
+// limited to some nodes and a number of usages.
+type BootstrapToken struct {
+	ID     string
+	Secret string
+	// Expiration is the time after which the token can't be used
+	Expiration time.Time
+	// UsageLimit is the maximum number of the nodes joined with the token, 0 means no limit
+	UsageLimit int
+	// NodeNames are the names of the nodes allowed to join with the token, empty means any node
+	NodeNames []string
+	// NodeLabels are the labels the nodes joined with the token must have, empty means any labels
+	NodeLabels labels.Set
+	// UsedBy records the nodes joined with the token
+	UsedBy []BootstrapTokenUsage
+}
+
+// BootstrapTokenUsage records an edge node joined with the bootstrap token
+type BootstrapTokenUsage struct {
+	NodeName string    `json:"nodeName"`
+	Time     time.Time `json:"time"`
+}
+
+// NewBootstrapToken generates a bootstrap token with random ID and secret
+func NewBootstrapToken(expiration time.Time) (*BootstrapToken, error) {
+	id, err := randomHex(bootstrapTokenIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(bootstrapTokenSecretBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &BootstrapToken{ID: id, Secret: secret, Expiration: expiration.UTC().Truncate(time.Second)}, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// SecretName returns the name of the secret which stores the bootstrap token
+func (t *BootstrapToken) SecretName() string {
+	return BootstrapTokenSecretPrefix + t.ID
+}
+
+// String returns the token used by edge nodes, which is prefixed with the CA hash like the shared token
+func (t *BootstrapToken) String(ca []byte) string {
+	return strings.Join([]string{hashCA(ca), bootstrapTokenMarker, t.ID, t.Secret}, ".")
+}
+
+// Data returns the bootstrap token as the data of the secret
+func (t *BootstrapToken) Data() map[string][]byte {
+	usedBy, _ := json.Marshal(t.UsedBy)
+	return map[string][]byte{
+		bootstrapTokenIDKey:         []byte(t.ID),
+		bootstrapTokenSecretKey:     []byte(t.Secret),
+		bootstrapTokenExpirationKey: []byte(t.Expiration.UTC().Format(time.RFC3339)),
+		bootstrapTokenUsageLimitKey: []byte(strconv.Itoa(t.UsageLimit)),
+		bootstrapTokenNodeNamesKey:  []byte(strings.Join(t.NodeNames, ",")),
+		bootstrapTokenNodeLabelsKey: []byte(t.NodeLabels.String()),
+		bootstrapTokenUsedByKey:     usedBy,
+	}
+}
+
+// ParseBootstrapToken parses the bootstrap token from the data of the secret
+func ParseBootstrapToken(data map[string][]byte) (*BootstrapToken, error) {
+	t := &BootstrapToken{
+		ID:     string(data[bootstrapTokenIDKey]),
+		Secret: string(data[bootstrapTokenSecretKey]),
+	}
+	if t.ID == "" || t.Secret == "" {
+		return nil, errors.New("token id and secret are required")
+	}
+	expiration, err := time.Parse(time.RFC3339, string(data[bootstrapTokenExpirationKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration: %v", err)
+	}
+	t.Expiration = expiration
+	if t.UsageLimit, err = strconv.Atoi(string(data[bootstrapTokenUsageLimitKey])); err != nil {
+		return nil, fmt.Errorf("invalid usage limit: %v", err)
+	}
+	if names := string(data[bootstrapTokenNodeNamesKey]); names != "" {
+		t.NodeNames = strings.Split(names, ",")
+	}
+	if t.NodeLabels, err = labels.ConvertSelectorToLabelsMap(string(data[bootstrapTokenNodeLabelsKey])); err != nil {
+		return nil, fmt.Errorf("invalid node labels: %v", err)
+	}
+	if usedBy := data[bootstrapTokenUsedByKey]; len(usedBy) > 0 {
+		if err := json.Unmarshal(usedBy, &t.UsedBy); err != nil {
+			return nil, fmt.Errorf("invalid usage records: %v", err)
+		}
+	}
+	return t, nil
+}
+
+// ParseBootstrapTokenString returns the ID and secret of the bootstrap token without the CA hash,
+// ok is false if it is not a bootstrap token.
+func ParseBootstrapTokenString(realToken string) (id, secret string, ok bool) {
+	parts := strings.Split(realToken, ".")
+	if len(parts) != 3 || parts[0] != bootstrapTokenMarker || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// Verify checks whether the node with the labels can join the cluster with the secret at now,
+// nodeLabels is nil if the node has not been registered.
+func (t *BootstrapToken) Verify(secret, nodeName string, nodeLabels labels.Set, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(t.Secret), []byte(secret)) != 1 {
+		return errors.New("invalid token secret")
+	}
+	if now.After(t.Expiration) {
+		return fmt.Errorf("token expired at %s", t.Expiration.Format(time.RFC3339))
+	}
+	if t.UsageLimit > 0 && len(t.UsedBy) >= t.UsageLimit {
+		return fmt.Errorf("token has been used %d times", len(t.UsedBy))
+	}
+	if len(t.NodeNames) > 0 && !slices.Contains(t.NodeNames, nodeName) {
+		return fmt.Errorf("node %s is not allowed by the token", nodeName)
+	}
+	if len(t.NodeLabels) > 0 && !labels.SelectorFromSet(t.NodeLabels).Matches(nodeLabels) {
+		return fmt.Errorf("node %s does not have the labels %s required by the token", nodeName, t.NodeLabels)
+	}
+	return nil
+}
+
+// Use records the node joined with the token
+func (t *BootstrapToken) Use(nodeName string, now time.Time) {
+	t.UsedBy = append(t.UsedBy, BootstrapTokenUsage{NodeName: nodeName, Time: now.UTC().Truncate(time.Second)})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestBootstrapToken(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	bt, err := NewBootstrapToken(now.Add(time.Hour))
+	require.NoError(t, err)
+	bt.UsageLimit = 1
+	bt.NodeNames = []string{"edge-1", "edge-2"}
+	bt.NodeLabels = labels.Set{"region": "east"}
+
+	// the token is verified by the cloud after the CA hash is cut
+	ca := []byte("ca")
+	realToken, err := VerifyCAAndGetRealToken(bt.String(ca), ca)
+	require.NoError(t, err)
+	id, secret, ok := ParseBootstrapTokenString(realToken)
+	require.True(t, ok)
+	assert.Equal(t, bt.ID, id)
+	assert.Equal(t, bt.Secret, secret)
+	assert.Equal(t, "bootstraptoken-"+bt.ID, bt.SecretName())
+
+	parsed, err := ParseBootstrapToken(bt.Data())
+	require.NoError(t, err)
+	assert.Equal(t, bt, parsed)
+
+	east := labels.Set{"region": "east", "zone": "a"}
+	cases := []struct {
+		name     string
+		secret   string
+		nodeName string
+		labels   labels.Set
+		now      time.Time
+		wantErr  bool
+	}{
+		{name: "allowed", secret: secret, nodeName: "edge-1", labels: east, now: now},
+		{name: "invalid secret", secret: "0000000000000000", nodeName: "edge-1", labels: east, now: now, wantErr: true},
+		{name: "expired", secret: secret, nodeName: "edge-1", labels: east, now: now.Add(2 * time.Hour), wantErr: true},
+		{name: "other node", secret: secret, nodeName: "edge-3", labels: east, now: now, wantErr: true},
+		{name: "other labels", secret: secret, nodeName: "edge-1", labels: labels.Set{"region": "west"}, now: now, wantErr: true},
+		{name: "not registered", secret: secret, nodeName: "edge-1", now: now, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := parsed.Verify(c.secret, c.nodeName, c.labels, c.now)
+			assert.Equal(t, c.wantErr, err != nil, "unexpected error: %v", err)
+		})
+	}
+
+	// the single-use token can't be used again
+	parsed.Use("edge-1", now)
+	parsed, err = ParseBootstrapToken(parsed.Data())
+	require.NoError(t, err)
+	assert.Equal(t, []BootstrapTokenUsage{{NodeName: "edge-1", Time: now}}, parsed.UsedBy)
+	assert.Error(t, parsed.Verify(secret, "edge-2", east, now))
+}
+
+func TestParseBootstrapTokenString(t *testing.T) {
+	for _, token := range []string{"a.b.c", "bootstrap..c", "bootstrap.b", "bootstrap.b.c.d"} {
+		_, _, ok := ParseBootstrapTokenString(token)
+		assert.False(t, ok, token)
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpstream carries the http bodies which are too large for a single message
+// between cloud and edge, the body is split into ordered HTTPStreamChunk messages.
+// The receiver sends window updates as the body is read, and the sender blocks when
+// the data not read reaches WindowSize, so a slow reader slows down the sender.
+package httpstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+const (
+	// ChunkSize is the max size of data carried by a chunk
+	ChunkSize = 256 * 1024
+	// WindowSize is the max size of data sent but not read by the receiver yet, the messages
+	// are received in a single loop, so the receiver can not block it and relies on the sender
+	WindowSize = 64 * ChunkSize
+	// windowUpdateBytes is the size of data read before the receiver sends a window update
+	windowUpdateBytes = WindowSize / 4
+	// maxPendingChunks is the max number of chunks received out of order
+	maxPendingChunks = 64
+	// progressInterval is the interval in bytes of logging the progress of stream
+	progressInterval = 16 * (1 << 20)
+)
+
+var (
+	// ErrBodyTooLarge is returned when the body exceeds the max size of stream
+	ErrBodyTooLarge = errors.New("stream body too large")
+	// ErrWindowExceeded is returned when the sender sends more data than the window allows
+	ErrWindowExceeded = errors.New("stream window exceeded")
+	// ErrClosed is returned when the stream is read after closed
+	ErrClosed = errors.New("stream closed")
+)
+
+// Window is the flow control window of a sender, it is moved forward by the window
+// updates from the receiver
+type Window struct {
+	mu       sync.Mutex
+	consumed int64
+	// updated is closed and replaced when the window is moved
+	updated chan struct{}
+}
+
+// NewWindow returns the window of a stream which nothing is read from
+func NewWindow() *Window {
+	return &Window{updated: make(chan struct{})}
+}
+
+// Update moves the window with the window update from the receiver, the updates
+// received late are ignored.
+func (w *Window) Update(chunk *commonType.HTTPStreamChunk) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if chunk.Consumed <= w.consumed {
+		return
+	}
+	w.consumed = chunk.Consumed
+	close(w.updated)
+	w.updated = make(chan struct{})
+}
+
+// wait blocks until the data up to end can be sent or ctx is done
+func (w *Window) wait(ctx context.Context, end int64) error {
+	for {
+		w.mu.Lock()
+		if end <= w.consumed+WindowSize {
+			w.mu.Unlock()
+			return nil
+		}
+		updated := w.updated
+		w.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-updated:
+		}
+	}
+}
+
+// IsWindowUpdate returns whether the chunk is a window update sent by the receiver
+func IsWindowUpdate(chunk *commonType.HTTPStreamChunk) bool {
+	return chunk.Consumed > 0
+}
+
+// Send reads body and sends it in ordered chunks of stream id by send, it blocks when the
+// data not read by the receiver reaches the window, no flow control if window is nil. The
+// last chunk is marked with EOF, or carries the error if the body can not be sent completely,
+// which is also returned. It returns the size of body sent, no limit of size if maxSize is 0.
+func Send(ctx context.Context, id string, body io.Reader, maxSize int64, window *Window,
+	send func(*commonType.HTTPStreamChunk) error) (int64, error) {
+	var seq, sent int64
+	nextProgress := int64(progressInterval)
+	abort := func(err error) (int64, error) {
+		if sendErr := send(&commonType.HTTPStreamChunk{ID: id, Seq: seq, Error: err.Error()}); sendErr != nil {
+			klog.Errorf("failed to abort stream %s: %v", id, sendErr)
+		}
+		return sent, err
+	}
+
+	buf := make([]byte, ChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return abort(err)
+		}
+		n, err := io.ReadFull(body, buf)
+		eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !eof {
+			return abort(err)
+		}
+		if maxSize > 0 && sent+int64(n) > maxSize {
+			return abort(ErrBodyTooLarge)
+		}
+		if window != nil {
+			if err := window.wait(ctx, sent+int64(n)); err != nil {
+				return abort(err)
+			}
+		}
+		// the chunk is held by message until it is sent, so the data can not share buf
+		chunk := &commonType.HTTPStreamChunk{ID: id, Seq: seq, EOF: eof}
+		if n > 0 {
+			chunk.Data = append([]byte(nil), buf[:n]...)
+		}
+		if err := send(chunk); err != nil {
+			return sent, fmt.Errorf("failed to send chunk %d of stream %s: %v", seq, id, err)
+		}
+		seq++
+		sent += int64(n)
+		if sent >= nextProgress {
+			klog.V(4).Infof("stream %s sent %d bytes", id, sent)
+			nextProgress += progressInterval
+		}
+		if eof {
+			klog.V(4).Infof("stream %s completed, %d bytes sent", id, sent)
+			return sent, nil
+		}
+	}
+}
+
+// ParseChunk gets the chunk carried by message
+func ParseChunk(msg *model.Message) (*commonType.HTTPStreamChunk, error) {
+	content, err := msg.GetContentData()
+	if err != nil {
+		return nil, err
+	}
+	chunk := &commonType.HTTPStreamChunk{}
+	if err := json.Unmarshal(content, chunk); err != nil {
+		return nil, fmt.Errorf("message %s content can not convert to HTTPStreamChunk: %v", msg.GetID(), err)
+	}
+	return chunk, nil
+}
+
+// Receiver reassembles the chunks of a stream into the body, it is read as io.ReadCloser
+type Receiver struct {
+	id      string
+	maxSize int64
+	// sendUpdate sends the window update to the sender
+	sendUpdate func(*commonType.HTTPStreamChunk)
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	next     int64
+	pending  map[int64]*commonType.HTTPStreamChunk
+	queue    [][]byte
+	queued   int
+	received int64
+	// consumed is the size of data read, and updated is the size sent in the last window update
+	consumed int64
+	updated  int64
+	eof      bool
+	err      error
+}
+
+// NewReceiver returns the receiver of stream id, no limit of body size if maxSize is 0.
+// The window updates are sent by sendUpdate as the body is read.
+func NewReceiver(id string, maxSize int64, sendUpdate func(*commonType.HTTPStreamChunk)) *Receiver {
+	r := &Receiver{
+		id:         id,
+		maxSize:    maxSize,
+		sendUpdate: sendUpdate,
+		pending:    make(map[int64]*commonType.HTTPStreamChunk),
+	}
+	r.cond = sync.NewCond(&r.mu)
+	return r
+}
+
+// Push adds the received chunk to stream, it never blocks. The chunks received out of order
+// are held until the missing ones arrive, and the duplicated chunks are ignored. It returns
+// the error if the stream is aborted.
+func (r *Receiver) Push(chunk *commonType.HTTPStreamChunk) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer r.cond.Broadcast()
+
+	if r.err != nil {
+		return r.err
+	}
+	// the error aborts the stream immediately, the data before it is useless
+	if chunk.Error != "" {
+		r.fail(errors.New(chunk.Error))
+		return r.err
+	}
+	if r.eof || chunk.Seq < r.next {
+		return nil
+	}
+	r.pending[chunk.Seq] = chunk
+	if len(r.pending) > maxPendingChunks {
+		r.fail(fmt.Errorf("stream %s misses chunk %d", r.id, r.next))
+		return r.err
+	}
+
+	for c, ok := r.pending[r.next]; ok; c, ok = r.pending[r.next] {
+		delete(r.pending, r.next)
+		r.next++
+		r.received += int64(len(c.Data))
+		if r.maxSize > 0 && r.received > r.maxSize {
+			r.fail(ErrBodyTooLarge)
+			return r.err
+		}
+		if len(c.Data) > 0 {
+			r.queue = append(r.queue, c.Data)
+			r.queued += len(c.Data)
+		}
+		if r.queued > WindowSize {
+			r.fail(ErrWindowExceeded)
+			return r.err
+		}
+		if c.EOF {
+			r.eof = true
+			r.pending = nil
+			klog.V(4).Infof("stream %s completed, %d bytes received", r.id, r.received)
+			break
+		}
+	}
+	return nil
+}
+
+// Read reads the body in order, it blocks until the data is received or the stream is
+// completed or aborted.
+func (r *Receiver) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	for len(r.queue) == 0 && !r.eof && r.err == nil {
+		r.cond.Wait()
+	}
+	if r.err != nil {
+		r.mu.Unlock()
+		return 0, r.err
+	}
+	if len(r.queue) == 0 {
+		r.mu.Unlock()
+		return 0, io.EOF
+	}
+	n := copy(p, r.queue[0])
+	if r.queue[0] = r.queue[0][n:]; len(r.queue[0]) == 0 {
+		r.queue = r.queue[1:]
+	}
+	r.queued -= n
+	r.consumed += int64(n)
+	// the window is not updated after all data is received, the sender has finished
+	var update *commonType.HTTPStreamChunk
+	if !r.eof && r.sendUpdate != nil && r.consumed-r.updated >= windowUpdateBytes {
+		r.updated = r.consumed
+		update = &commonType.HTTPStreamChunk{ID: r.id, Consumed: r.consumed}
+	}
+	r.mu.Unlock()
+
+	if update != nil {
+		r.sendUpdate(update)
+	}
+	return n, nil
+}
+
+// Close closes the stream, the blocked and later reads return ErrClosed
+func (r *Receiver) Close() error {
+	r.CloseWithError(ErrClosed)
+	return nil
+}
+
+// CloseWithError aborts the stream with err, the blocked and later reads return err
+func (r *Receiver) CloseWithError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fail(err)
+	r.cond.Broadcast()
+}
+
+// Completed returns whether all chunks of the stream are received
+func (r *Receiver) Completed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.eof
+}
+
+// fail aborts the stream with err, it must be called with r.mu held
+func (r *Receiver) fail(err error) {
+	if r.err != nil {
+		return
+	}
+	r.err = err
+	r.queue = nil
+	r.queued = 0
+	r.pending = nil
+}
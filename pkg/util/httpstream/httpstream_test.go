@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpstream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commonType "github.com/kubeedge/kubeedge/common/types"
+)
+
+func collect(t *testing.T, body []byte, maxSize int64) ([]*commonType.HTTPStreamChunk, int64, error) {
+	t.Helper()
+	var chunks []*commonType.HTTPStreamChunk
+	sent, err := Send(context.Background(), "id", bytes.NewReader(body), maxSize, nil, func(c *commonType.HTTPStreamChunk) error {
+		chunks = append(chunks, c)
+		return nil
+	})
+	return chunks, sent, err
+}
+
+func TestSendAndReceive(t *testing.T) {
+	body := bytes.Repeat([]byte("0123456789"), ChunkSize/4)
+	chunks, sent, err := collect(t, body, 0)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if sent != int64(len(body)) {
+		t.Errorf("Send() sent %d, want %d", sent, len(body))
+	}
+	if len(chunks) != 3 || !chunks[2].EOF {
+		t.Fatalf("Send() got %d chunks, want 3 with the last EOF", len(chunks))
+	}
+
+	// the chunks received out of order or duplicated are reassembled
+	r := NewReceiver("id", int64(len(body)), nil)
+	for _, i := range []int{1, 0, 1, 2} {
+		if err := r.Push(chunks[i]); err != nil {
+			t.Fatalf("Push(%d) error = %v", i, err)
+		}
+	}
+	if !r.Completed() {
+		t.Error("Completed() = false, want true")
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("ReadAll() got %d bytes, want %d", len(got), len(body))
+	}
+}
+
+func TestSendEmptyBody(t *testing.T) {
+	chunks, _, err := collect(t, nil, 0)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(chunks) != 1 || !chunks[0].EOF || len(chunks[0].Data) != 0 {
+		t.Fatalf("Send() got %+v, want a single empty EOF chunk", chunks)
+	}
+}
+
+func TestSendTooLarge(t *testing.T) {
+	chunks, _, err := collect(t, make([]byte, ChunkSize+1), ChunkSize)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Fatalf("Send() error = %v, want %v", err, ErrBodyTooLarge)
+	}
+	last := chunks[len(chunks)-1]
+	if last.Error != ErrBodyTooLarge.Error() {
+		t.Errorf("last chunk error = %q, want %q", last.Error, ErrBodyTooLarge.Error())
+	}
+}
+
+func TestSendCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var chunks []*commonType.HTTPStreamChunk
+	_, err := Send(ctx, "id", bytes.NewReader([]byte("data")), 0, nil, func(c *commonType.HTTPStreamChunk) error {
+		chunks = append(chunks, c)
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Send() error = %v, want %v", err, context.Canceled)
+	}
+	if len(chunks) != 1 || chunks[0].Error == "" {
+		t.Errorf("Send() got %+v, want a single error chunk", chunks)
+	}
+}
+
+func TestSendFlowControl(t *testing.T) {
+	body := make([]byte, 2*WindowSize)
+	for i := range body {
+		body[i] = byte(i)
+	}
+	window := NewWindow()
+	r := NewReceiver("id", 0, window.Update)
+	var sent atomic.Int64
+	done := make(chan error, 1)
+	go func() {
+		_, err := Send(context.Background(), "id", bytes.NewReader(body), 0, window, func(c *commonType.HTTPStreamChunk) error {
+			sent.Add(int64(len(c.Data)))
+			return r.Push(c)
+		})
+		done <- err
+	}()
+
+	// the sender blocks when the data not read reaches the window
+	for deadline := time.Now().Add(5 * time.Second); sent.Load() < WindowSize && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := sent.Load(); got != WindowSize {
+		t.Fatalf("sent %d bytes before reading, want %d", got, WindowSize)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("ReadAll() got %d bytes, want %d", len(got), len(body))
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Send() error = %v", err)
+	}
+}
+
+func TestSendWindowCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var chunks []*commonType.HTTPStreamChunk
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	_, err := Send(ctx, "id", bytes.NewReader(make([]byte, WindowSize+1)), 0, NewWindow(), func(c *commonType.HTTPStreamChunk) error {
+		chunks = append(chunks, c)
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Send() error = %v, want %v", err, context.Canceled)
+	}
+	if last := chunks[len(chunks)-1]; last.Error == "" {
+		t.Errorf("last chunk %+v, want error chunk", last)
+	}
+}
+
+func TestReceiverAbort(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int64
+		chunks  []*commonType.HTTPStreamChunk
+		wantErr string
+	}{
+		{
+			name:    "error chunk",
+			chunks:  []*commonType.HTTPStreamChunk{{Seq: 0, Data: []byte("a")}, {Seq: 1, Error: "canceled"}},
+			wantErr: "canceled",
+		},
+		{
+			name:    "too large",
+			maxSize: 1,
+			chunks:  []*commonType.HTTPStreamChunk{{Seq: 0, Data: []byte("ab"), EOF: true}},
+			wantErr: ErrBodyTooLarge.Error(),
+		},
+		{
+			name:    "window exceeded",
+			chunks:  makeChunks(WindowSize/ChunkSize+1, ChunkSize),
+			wantErr: ErrWindowExceeded.Error(),
+		},
+		{
+			name:    "missing chunk",
+			chunks:  makeChunks(maxPendingChunks+2, 1)[1:],
+			wantErr: "stream id misses chunk 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReceiver("id", tt.maxSize, nil)
+			var err error
+			for _, c := range tt.chunks {
+				if err = r.Push(c); err != nil {
+					break
+				}
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Push() error = %v, want %s", err, tt.wantErr)
+			}
+			if _, err := r.Read(make([]byte, 1)); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Read() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReceiverClose(t *testing.T) {
+	r := NewReceiver("id", 0, nil)
+	done := make(chan error)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		done <- err
+	}()
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Errorf("Read() error = %v, want %v", err, ErrClosed)
+	}
+	if r.Completed() {
+		t.Error("Completed() = true, want false")
+	}
+}
+
+func TestParseChunk(t *testing.T) {
+	msg := model.NewMessage("").FillBody(commonType.HTTPStreamChunk{ID: "id", Seq: 1, Data: []byte("data"), EOF: true})
+	chunk, err := ParseChunk(msg)
+	if err != nil {
+		t.Fatalf("ParseChunk() error = %v", err)
+	}
+	if chunk.ID != "id" || chunk.Seq != 1 || string(chunk.Data) != "data" || !chunk.EOF {
+		t.Errorf("ParseChunk() got %+v", chunk)
+	}
+
+	if _, err := ParseChunk(model.NewMessage("").FillBody("invalid")); err == nil {
+		t.Error("ParseChunk() error = nil, want error")
+	}
+}
+
+func makeChunks(n, size int) []*commonType.HTTPStreamChunk {
+	chunks := make([]*commonType.HTTPStreamChunk, n)
+	for i := range chunks {
+		chunks[i] = &commonType.HTTPStreamChunk{ID: "id", Seq: int64(i), Data: make([]byte, size)}
+	}
+	return chunks
+}
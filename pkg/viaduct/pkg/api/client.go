@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -14,6 +15,8 @@ type QuicClientOption struct {
 	Header http.Header
 	// the max incoming stream
 	MaxIncomingStreams int
+	// the max duration without incoming packet, keepalive packets are sent at half of it
+	IdleTimeout time.Duration
 }
 
 // you can do some additional processes after successful dialing
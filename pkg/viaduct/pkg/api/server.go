@@ -1,6 +1,9 @@
 package api
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // quic server option
 // including the extend options when getting server instance
@@ -8,6 +11,8 @@ import "net/http"
 type QuicServerOption struct {
 	// the max incoming stream
 	MaxIncomingStreams int
+	// the max duration without incoming packet, keepalive packets are sent at half of it
+	IdleTimeout time.Duration
 }
 
 // the filter function before upgrading the http to websocket
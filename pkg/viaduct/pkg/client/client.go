@@ -38,6 +38,9 @@ type Options struct {
 	HandshakeTimeout time.Duration
 	// consumer for raw data
 	Consumer io.Writer
+	// Batch indicates how the messages written asynchronously are batched,
+	// they are only batched if the server tells that it is able to unpack them
+	Batch *conn.BatchOptions
 }
 
 // client including common options and extend options
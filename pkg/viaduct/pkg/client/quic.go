@@ -1,7 +1,9 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"sync"
 
 	"github.com/lucas-clemente/quic-go"
@@ -41,7 +43,8 @@ func (c *QuicClient) getQuicConfig() *quic.Config {
 	return &quic.Config{
 		HandshakeTimeout: c.options.HandshakeTimeout,
 		// keep the session by default
-		KeepAlive: true,
+		KeepAlive:   true,
+		IdleTimeout: c.exOpts.IdleTimeout,
 	}
 }
 
@@ -65,29 +68,36 @@ func (c *QuicClient) getControlLane(s quic.Session) error {
 	return nil
 }
 
-// send the headers
+// send the headers and return the headers of the server
 // TODO: add timeout?
-func (c *QuicClient) sendHeader() error {
+func (c *QuicClient) sendHeader() (http.Header, error) {
 	msg := model.NewMessage("").
 		BuildRouter("", "", comm.ControlTypeHeader, comm.ControlTypeHeader).
 		FillBody(c.exOpts.Header)
 	err := c.ctrlLane.WriteMessage(msg)
 	if err != nil {
 		klog.Errorf("failed to write message, error: %+v", err)
-		return err
+		return nil, err
 	}
 
 	// receive the response
-	// ignore the response
-	// TODO: check the response content
 	var response model.Message
 	err = c.ctrlLane.ReadMessage(&response)
 	if err != nil {
 		klog.Errorf("failed to read message, error: %+v", err)
-		return err
+		return nil, err
 	}
 	klog.Infof("get response: %+v", response)
-	return nil
+
+	// the server sends its headers back with the ack, while the old
+	// server only sends the response type
+	header := make(http.Header)
+	if content, ok := response.GetContent().([]byte); ok {
+		if err := json.Unmarshal(content, &header); err != nil {
+			klog.V(4).Infof("no headers in the response: %v", err)
+		}
+	}
+	return header, nil
 }
 
 // try to dial server and get connection interface for operations
@@ -106,8 +116,14 @@ func (c *QuicClient) Connect() (conn.Connection, error) {
 		return nil, err
 	}
 
+	// tell the server that the batches are able to be unpacked
+	if c.exOpts.Header == nil {
+		c.exOpts.Header = make(http.Header)
+	}
+	c.exOpts.Header.Set(comm.HeaderMessageBatch, "true")
+
 	// send headers
-	err = c.sendHeader()
+	serverHeader, err := c.sendHeader()
 	if err != nil {
 		klog.Warningf("failed to send headers, error: %+v", err)
 	}
@@ -126,5 +142,6 @@ func (c *QuicClient) Connect() (conn.Connection, error) {
 			PeerCertificates: session.ConnectionState().PeerCertificates,
 		},
 		AutoRoute: c.options.AutoRoute,
+		Batch:     conn.NegotiateBatch(c.options.Batch, serverHeader),
 	}), nil
 }
@@ -4,6 +4,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/gorilla/websocket"
 	"k8s.io/klog/v2"
@@ -42,6 +43,8 @@ func NewWSClient(options Options, exOpts interface{}) *WSClient {
 func (c *WSClient) Connect() (conn.Connection, error) {
 	header := c.exOpts.Header
 	header.Add("ConnectionUse", string(c.options.ConnUse))
+	// tell the server that the batches are able to be unpacked
+	header.Set(comm.HeaderMessageBatch, "true")
 	wsConn, resp, err := c.dialer.Dial(c.options.Addr, header)
 	if err == nil {
 		klog.Infof("dial %s successfully", c.options.Addr)
@@ -54,6 +57,10 @@ func (c *WSClient) Connect() (conn.Connection, error) {
 		if resp != nil && resp.TLS != nil {
 			peerCerts = resp.TLS.PeerCertificates
 		}
+		var respHeader http.Header
+		if resp != nil {
+			respHeader = resp.Header
+		}
 		return conn.NewConnection(&conn.ConnectionOptions{
 			ConnType: api.ProtocolTypeWS,
 			ConnUse:  c.options.ConnUse,
@@ -67,6 +74,7 @@ func (c *WSClient) Connect() (conn.Connection, error) {
 				PeerCertificates: peerCerts,
 			},
 			AutoRoute: c.options.AutoRoute,
+			Batch:     conn.NegotiateBatch(c.options.Batch, respHeader),
 		}), nil
 	}
 
@@ -6,12 +6,19 @@ const (
 	ControlTypeConfig = "config"
 	ControlTypePing   = "ping"
 	ControlTypePong   = "pong"
+	ControlTypeBatch  = "batch"
 
 	// control message action
 	ControlActionHeader = "/control/header"
 	ControlActionConfig = "/control/config"
 	ControlActionPing   = "/control/ping"
 	ControlActionPong   = "/control/pong"
+	ControlActionBatch  = "/control/batch"
+
+	// HeaderMessageBatch is the header with which a peer tells that it can unpack the batch
+	// messages, it is sent by both the client and the server when connecting, and the messages
+	// are only batched to the peer which sends it
+	HeaderMessageBatch = "Message-Batch"
 
 	// response type
 	RespTypeAck  = "ack"
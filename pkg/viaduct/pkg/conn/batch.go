@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conn
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/translator"
+)
+
+// BatchOptions indicates how the messages written asynchronously are coalesced into one frame
+type BatchOptions struct {
+	// Window is how long a message waits for the following messages
+	Window time.Duration
+	// MaxMessages is the max number of messages in a batch
+	MaxMessages int
+	// MaxBytes is the max size of a batch, the message larger than it is written alone
+	MaxBytes int
+}
+
+// NegotiateBatch returns the batch options of a connection, the messages are batched
+// only if the peer tells with its headers that it is able to unpack them
+func NegotiateBatch(batch *BatchOptions, header http.Header) *BatchOptions {
+	if header.Get(comm.HeaderMessageBatch) != "true" {
+		return nil
+	}
+	return batch
+}
+
+// batcher coalesces the messages written within the window into a batch message,
+// which is unpacked by the peer with translator.Unbatch. The messages of each lane
+// are batched separately. A message is encoded as soon as it is added, so the caller
+// is free to change it afterwards.
+type batcher struct {
+	opts  BatchOptions
+	codec translator.Codec
+	// write writes the encoded message of lane to the connection
+	write func(lane int, raw []byte) error
+
+	lock  sync.Mutex
+	lanes []*pendingBatch
+	// results are the results of the messages written, they are reported once the lock is released
+	results []batchResult
+	// err is the error of the last write, the following messages are rejected with it
+	// since the connection is broken
+	err error
+}
+
+// pendingBatch is the batch of a lane waiting to be written
+type pendingBatch struct {
+	entries []batchEntry
+	size    int
+	timer   *time.Timer
+	// generation is increased on every flush, so that the timer of a flushed batch does nothing
+	generation uint64
+}
+
+// batchEntry is an encoded message in a batch, done is called with the result of writing it
+type batchEntry struct {
+	raw  []byte
+	done func(error)
+}
+
+type batchResult struct {
+	done func(error)
+	err  error
+}
+
+// newBatcher returns a batcher of the given number of lanes
+func newBatcher(opts BatchOptions, lanes int, codec translator.Codec, write func(lane int, raw []byte) error) *batcher {
+	if lanes < 1 {
+		lanes = 1
+	}
+	b := &batcher{
+		opts:  opts,
+		codec: codec,
+		write: write,
+	}
+	for i := 0; i < lanes; i++ {
+		b.lanes = append(b.lanes, &pendingBatch{})
+	}
+	return b
+}
+
+// add adds msg into the pending batch of lane, the batch is written once it is full or
+// the window ends. done is called with the result of writing msg, it may be called before
+// add returns. An error is returned and done is not called if msg is not added.
+func (b *batcher) add(msg *model.Message, lane int, done func(error)) error {
+	raw, err := b.codec.Encode(msg)
+	if err != nil {
+		return err
+	}
+	if lane < 0 || lane >= len(b.lanes) {
+		lane = len(b.lanes) - 1
+	}
+
+	b.lock.Lock()
+	defer b.unlock()
+	if b.err != nil {
+		return b.err
+	}
+
+	batch := b.lanes[lane]
+	if len(batch.entries) > 0 && batch.size+len(raw) > b.opts.MaxBytes {
+		if err := b.flush(lane); err != nil {
+			return err
+		}
+	}
+	entry := batchEntry{raw: raw, done: done}
+	if len(raw) > b.opts.MaxBytes {
+		b.err = b.write(lane, raw)
+		b.report([]batchEntry{entry}, b.err)
+		return nil
+	}
+
+	batch.entries = append(batch.entries, entry)
+	batch.size += len(raw)
+	if len(batch.entries) >= b.opts.MaxMessages {
+		_ = b.flush(lane)
+		return nil
+	}
+	if len(batch.entries) == 1 {
+		generation := batch.generation
+		batch.timer = time.AfterFunc(b.opts.Window, func() {
+			b.lock.Lock()
+			defer b.unlock()
+			if batch.generation == generation {
+				_ = b.flush(lane)
+			}
+		})
+	}
+	return nil
+}
+
+// Flush writes the pending batches at once, from the highest lane to the lowest one
+func (b *batcher) Flush() error {
+	b.lock.Lock()
+	defer b.unlock()
+	for lane := range b.lanes {
+		if err := b.flush(lane); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush writes the pending batch of lane, it must be called with the lock held
+func (b *batcher) flush(lane int) error {
+	batch := b.lanes[lane]
+	if len(batch.entries) == 0 {
+		return b.err
+	}
+	entries := batch.entries
+	batch.entries, batch.size = nil, 0
+	batch.generation++
+	if batch.timer != nil {
+		batch.timer.Stop()
+		batch.timer = nil
+	}
+	if b.err != nil {
+		b.report(entries, b.err)
+		return b.err
+	}
+
+	raw := entries[0].raw
+	if len(entries) > 1 {
+		pending := make([][]byte, 0, len(entries))
+		for _, entry := range entries {
+			pending = append(pending, entry.raw)
+		}
+		var err error
+		if raw, err = b.codec.Encode(translator.NewBatchMessage(pending)); err != nil {
+			b.err = err
+			b.report(entries, err)
+			return err
+		}
+	}
+	b.err = b.write(lane, raw)
+	b.report(entries, b.err)
+	return b.err
+}
+
+// report records the result of the entries, it must be called with the lock held
+func (b *batcher) report(entries []batchEntry, err error) {
+	for _, entry := range entries {
+		if entry.done != nil {
+			b.results = append(b.results, batchResult{done: entry.done, err: err})
+		}
+	}
+}
+
+// unlock releases the lock and reports the results of the messages written
+func (b *batcher) unlock() {
+	results := b.results
+	b.results = nil
+	b.lock.Unlock()
+	for _, result := range results {
+		result.done(result.err)
+	}
+}
+
+// addAsync adds msg like add and returns the error of writing msg if it is written at once,
+// the error of a later write is returned by the following calls
+func (b *batcher) addAsync(msg *model.Message, lane int) error {
+	result := make(chan error, 1)
+	if err := b.add(msg, lane, func(err error) { result <- err }); err != nil {
+		return err
+	}
+	select {
+	case err := <-result:
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conn
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/translator"
+)
+
+type frameRecorder struct {
+	lock   sync.Mutex
+	frames [][]byte
+	lanes  []int
+	err    error
+}
+
+func (r *frameRecorder) write(lane int, raw []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.frames = append(r.frames, raw)
+	r.lanes = append(r.lanes, lane)
+	return r.err
+}
+
+// messages returns the messages carried by each frame
+func (r *frameRecorder) messages(t *testing.T) [][]*model.Message {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var result [][]*model.Message
+	for _, frame := range r.frames {
+		msg := &model.Message{}
+		if err := translator.NewTran().Decode(frame, msg); err != nil {
+			t.Fatalf("failed to decode frame: %v", err)
+		}
+		msgs, err := translator.Unbatch(translator.NewTran(), msg)
+		if err != nil {
+			t.Fatalf("failed to unbatch frame: %v", err)
+		}
+		result = append(result, msgs)
+	}
+	return result
+}
+
+func newTestMessage(content string) *model.Message {
+	return model.NewMessage("").BuildRouter("edged", "resource", "ns/pod/p", model.UpdateOperation).
+		FillBody([]byte(content))
+}
+
+func TestBatcherMaxMessages(t *testing.T) {
+	recorder := &frameRecorder{}
+	b := newBatcher(BatchOptions{Window: time.Hour, MaxMessages: 3, MaxBytes: 1 << 20},
+		1, translator.NewTran(), recorder.write)
+
+	for i := 0; i < 4; i++ {
+		if err := b.addAsync(newTestMessage("m"), 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	frames := recorder.messages(t)
+	if len(frames) != 1 || len(frames[0]) != 3 {
+		t.Fatalf("expected a batch of 3 messages, got %v", frames)
+	}
+
+	// the last message is written alone as it is
+	if err := b.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frames = recorder.messages(t)
+	if len(frames) != 2 || len(frames[1]) != 1 || translator.IsBatchMessage(frames[1][0]) {
+		t.Fatalf("expected a single message, got %v", frames)
+	}
+}
+
+func TestBatcherWindow(t *testing.T) {
+	recorder := &frameRecorder{}
+	b := newBatcher(BatchOptions{Window: 20 * time.Millisecond, MaxMessages: 100, MaxBytes: 1 << 20},
+		1, translator.NewTran(), recorder.write)
+
+	first, second := newTestMessage("first"), newTestMessage("second")
+	_ = b.addAsync(first, 0)
+	_ = b.addAsync(second, 0)
+	if frames := recorder.messages(t); len(frames) != 0 {
+		t.Fatalf("expected no frame before the window ends, got %d", len(frames))
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	frames := recorder.messages(t)
+	if len(frames) != 1 || len(frames[0]) != 2 {
+		t.Fatalf("expected a batch of 2 messages, got %v", frames)
+	}
+	if frames[0][0].GetID() != first.GetID() || frames[0][1].GetID() != second.GetID() {
+		t.Errorf("expected the messages in order of writing")
+	}
+}
+
+func TestBatcherMaxBytes(t *testing.T) {
+	recorder := &frameRecorder{}
+	b := newBatcher(BatchOptions{Window: time.Hour, MaxMessages: 100, MaxBytes: 200},
+		1, translator.NewTran(), recorder.write)
+
+	_ = b.addAsync(newTestMessage("small"), 0)
+	// the large message flushes the pending one and is written alone
+	if err := b.addAsync(newTestMessage(strings.Repeat("x", 300)), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frames := recorder.messages(t)
+	if len(frames) != 2 || len(frames[0]) != 1 || len(frames[1]) != 1 {
+		t.Fatalf("expected 2 single messages, got %v", frames)
+	}
+	if string(frames[1][0].GetContent().([]byte)) != strings.Repeat("x", 300) {
+		t.Errorf("unexpected content of the large message")
+	}
+}
+
+func TestBatcherWriteError(t *testing.T) {
+	writeErr := errors.New("broken")
+	recorder := &frameRecorder{err: writeErr}
+	b := newBatcher(BatchOptions{Window: time.Hour, MaxMessages: 1, MaxBytes: 1 << 20},
+		1, translator.NewTran(), recorder.write)
+
+	if err := b.addAsync(newTestMessage("m"), 0); !errors.Is(err, writeErr) {
+		t.Fatalf("expected write error, got %v", err)
+	}
+	// the following messages are rejected as the connection is broken
+	if err := b.addAsync(newTestMessage("m"), 0); !errors.Is(err, writeErr) {
+		t.Fatalf("expected write error, got %v", err)
+	}
+	if len(recorder.frames) != 1 {
+		t.Errorf("expected 1 frame written, got %d", len(recorder.frames))
+	}
+}
+
+func TestBatcherLanes(t *testing.T) {
+	recorder := &frameRecorder{}
+	b := newBatcher(BatchOptions{Window: time.Hour, MaxMessages: 100, MaxBytes: 1 << 20},
+		2, translator.NewTran(), recorder.write)
+
+	bulk, control := newTestMessage("bulk"), newTestMessage("control")
+	_ = b.addAsync(bulk, 1)
+	_ = b.addAsync(newTestMessage("bulk"), 1)
+	_ = b.addAsync(control, 0)
+	// the lanes out of range are the lowest lane
+	_ = b.addAsync(newTestMessage("bulk"), 5)
+	if err := b.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	frames := recorder.messages(t)
+	if len(frames) != 2 || len(frames[0]) != 1 || len(frames[1]) != 3 {
+		t.Fatalf("expected a message of lane 0 and a batch of lane 1, got %v", frames)
+	}
+	if recorder.lanes[0] != 0 || recorder.lanes[1] != 1 {
+		t.Errorf("expected the frames written in lanes [0 1], got %v", recorder.lanes)
+	}
+	if frames[0][0].GetID() != control.GetID() || frames[1][0].GetID() != bulk.GetID() {
+		t.Errorf("expected the higher lane flushed first")
+	}
+}
+
+func TestBatcherReportResults(t *testing.T) {
+	writeErr := errors.New("broken")
+	recorder := &frameRecorder{}
+	b := newBatcher(BatchOptions{Window: 20 * time.Millisecond, MaxMessages: 100, MaxBytes: 1 << 20},
+		1, translator.NewTran(), recorder.write)
+
+	results := make(chan error, 4)
+	done := func(err error) { results <- err }
+	for i := 0; i < 2; i++ {
+		if err := b.add(newTestMessage("m"), 0, done); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	select {
+	case err := <-results:
+		t.Fatalf("expected no result before the batch is written, got %v", err)
+	default:
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-results:
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the result once the batch is written")
+		}
+	}
+
+	// the messages of the batch failed in the timer are all reported
+	recorder.lock.Lock()
+	recorder.err = writeErr
+	recorder.lock.Unlock()
+	for i := 0; i < 2; i++ {
+		if err := b.add(newTestMessage("m"), 0, done); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-results:
+			if !errors.Is(err, writeErr) {
+				t.Errorf("expected write error, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the failure of the batch reported")
+		}
+	}
+	// the following messages are rejected at once
+	if err := b.add(newTestMessage("m"), 0, done); !errors.Is(err, writeErr) {
+		t.Errorf("expected write error, got %v", err)
+	}
+}
+
+func TestNegotiateBatch(t *testing.T) {
+	batch := &BatchOptions{Window: time.Millisecond, MaxMessages: 10, MaxBytes: 1024}
+	header := make(http.Header)
+	if got := NegotiateBatch(batch, header); got != nil {
+		t.Errorf("expected no batch to the peer without the header, got %v", got)
+	}
+	if got := NegotiateBatch(batch, nil); got != nil {
+		t.Errorf("expected no batch to the peer without headers, got %v", got)
+	}
+	header.Set(comm.HeaderMessageBatch, "true")
+	if got := NegotiateBatch(batch, header); got != batch {
+		t.Errorf("expected the batch options, got %v", got)
+	}
+	if got := NegotiateBatch(nil, header); got != nil {
+		t.Errorf("expected no batch if disabled, got %v", got)
+	}
+}
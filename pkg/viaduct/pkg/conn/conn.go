@@ -67,3 +67,10 @@ type Connection interface {
 	// Any blocked Read or Write operations will be unblocked and return errors.
 	Close() error
 }
+
+// BatchWriter is implemented by the connections which are able to batch the messages
+type BatchWriter interface {
+	// WriteMessageBatched writes msg asynchronously, done is called with the result once msg is
+	// written, which is after the batch carrying msg is flushed if the messages are batched.
+	WriteMessageBatched(msg *model.Message, done func(error))
+}
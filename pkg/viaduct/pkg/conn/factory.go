@@ -30,6 +30,12 @@ type ConnectionOptions struct {
 	AutoRoute bool
 	// OnReadTransportErr
 	OnReadTransportErr func(nodeID, projectID string)
+	// Batch indicates how the messages written asynchronously are batched,
+	// they are written one by one if it is nil
+	Batch *BatchOptions
+	// Lanes indicates how the messages are scheduled in priority lanes,
+	// they are written in the order of arrival if it is nil
+	Lanes *LaneOptions
 }
 
 // get connection interface by ConnTye
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conn
+
+import (
+	"sync"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+// LaneOptions indicates how the messages written concurrently to a connection are
+// scheduled in priority lanes, so that the urgent messages do not wait behind the bulk ones
+type LaneOptions struct {
+	// Classify returns the lane of the message, lane 0 is the highest one,
+	// the lanes out of range are treated as the lowest lane
+	Classify func(msg *model.Message) int
+	// Weights are the relative shares of the lanes when the writers of several lanes
+	// are waiting for the connection, the weights less than 1 are treated as 1
+	Weights []int
+}
+
+// writeScheduler serializes the writes to a connection. The writers waiting for the
+// connection are granted in the order of smooth weighted round-robin across the lanes
+// and in the order of arrival within a lane. The zero value is a scheduler of one lane.
+type writeScheduler struct {
+	lock sync.Mutex
+	// busy is true if the connection is granted to a writer
+	busy    bool
+	weights []int
+	// credits are the current credits of the lanes in weighted round-robin
+	credits []int
+	waiters [][]chan struct{}
+}
+
+func newWriteScheduler(opts *LaneOptions) *writeScheduler {
+	s := &writeScheduler{}
+	if opts == nil {
+		return s
+	}
+	for _, weight := range opts.Weights {
+		if weight < 1 {
+			weight = 1
+		}
+		s.weights = append(s.weights, weight)
+	}
+	return s
+}
+
+// laneOf returns the lane of msg by opts
+func laneOf(opts *LaneOptions, msg *model.Message) int {
+	if opts == nil || opts.Classify == nil {
+		return 0
+	}
+	return opts.Classify(msg)
+}
+
+// acquire blocks until the connection is granted to the writer in lane
+func (s *writeScheduler) acquire(lane int) {
+	s.lock.Lock()
+	if !s.busy {
+		s.busy = true
+		s.lock.Unlock()
+		return
+	}
+	if s.waiters == nil {
+		lanes := len(s.weights)
+		if lanes == 0 {
+			lanes = 1
+		}
+		s.waiters = make([][]chan struct{}, lanes)
+		s.credits = make([]int, lanes)
+	}
+	if lane < 0 || lane >= len(s.waiters) {
+		lane = len(s.waiters) - 1
+	}
+	granted := make(chan struct{})
+	s.waiters[lane] = append(s.waiters[lane], granted)
+	s.lock.Unlock()
+
+	<-granted
+}
+
+// release passes the connection to the next waiting writer
+func (s *writeScheduler) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	next, total := -1, 0
+	for i := range s.waiters {
+		if len(s.waiters[i]) == 0 {
+			continue
+		}
+		weight := 1
+		if i < len(s.weights) {
+			weight = s.weights[i]
+		}
+		s.credits[i] += weight
+		total += weight
+		if next < 0 || s.credits[i] > s.credits[next] {
+			next = i
+		}
+	}
+	if next < 0 {
+		s.busy = false
+		return
+	}
+
+	s.credits[next] -= total
+	granted := s.waiters[next][0]
+	s.waiters[next] = s.waiters[next][1:]
+	// the connection is still busy, it is owned by the granted writer now
+	close(granted)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conn
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waiting returns the number of writers waiting in lane
+func (s *writeScheduler) waiting(lane int) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if lane >= len(s.waiters) {
+		return 0
+	}
+	return len(s.waiters[lane])
+}
+
+func TestWriteSchedulerLanes(t *testing.T) {
+	s := newWriteScheduler(&LaneOptions{Weights: []int{2, 1}})
+	// the connection is busy, so the following writers wait in their lanes
+	s.acquire(0)
+
+	var lock sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	// enqueue starts a writer of lane, which waits in the lane queued
+	enqueue := func(name string, lane, queued int) {
+		waiting := s.waiting(queued)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.acquire(lane)
+			lock.Lock()
+			order = append(order, name)
+			lock.Unlock()
+			s.release()
+		}()
+		deadline := time.Now().Add(time.Second)
+		for s.waiting(queued) == waiting {
+			if time.Now().After(deadline) {
+				t.Fatalf("writer %s is not waiting", name)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for _, name := range []string{"bulk-1", "bulk-2", "bulk-3"} {
+		enqueue(name, 1, 1)
+	}
+	enqueue("control-1", 0, 0)
+	enqueue("control-2", 0, 0)
+	// the lanes out of range are the lowest lane
+	enqueue("bulk-4", 3, 1)
+
+	s.release()
+	wg.Wait()
+
+	want := []string{"control-1", "bulk-1", "control-2", "bulk-2", "bulk-3", "bulk-4"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("expected writers granted in %v, got %v", want, order)
+	}
+	if s.busy {
+		t.Errorf("expected the connection released")
+	}
+}
+
+func TestWriteSchedulerZeroValue(t *testing.T) {
+	s := newWriteScheduler(nil)
+	s.acquire(0)
+
+	acquired := make(chan struct{})
+	go func() {
+		s.acquire(2)
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("expected the writer to wait for the connection")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("expected the writer to be granted the connection")
+	}
+	s.release()
+}
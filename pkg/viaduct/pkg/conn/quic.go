@@ -7,7 +7,6 @@ import (
 	"io"
 	"net"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/lucas-clemente/quic-go"
@@ -20,7 +19,9 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/keeper"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/lane"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/mux"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/packer"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/smgr"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/translator"
 )
 
 var (
@@ -43,13 +44,17 @@ type QuicConnection struct {
 	messageFifo        *fifo.MessageFifo
 	autoRoute          bool
 	OnReadTransportErr func(nodeID, projectID string)
-	locker             sync.Mutex
+	// writer schedules the writes in the priority lanes
+	writer *writeScheduler
+	lanes  *LaneOptions
+	// batcher is nil if the messages are not batched
+	batcher *batcher
 }
 
 // NewQuicConn new quic connection
 func NewQuicConn(options *ConnectionOptions) *QuicConnection {
 	quicSession := options.Base.(quic.Session)
-	return &QuicConnection{
+	conn := &QuicConnection{
 		session:            smgr.Session{Sess: quicSession},
 		handler:            options.Handler,
 		ctrlLan:            options.CtrlLane.(lane.Lane),
@@ -61,7 +66,13 @@ func NewQuicConn(options *ConnectionOptions) *QuicConnection {
 		messageFifo:        fifo.NewMessageFifo(),
 		OnReadTransportErr: options.OnReadTransportErr,
 		streamManager:      smgr.NewStreamManager(smgr.NumStreamsMax, autoFree, quicSession),
+		writer:             newWriteScheduler(options.Lanes),
+		lanes:              options.Lanes,
 	}
+	if options.Batch != nil {
+		conn.batcher = newBatcher(*options.Batch, len(conn.writer.weights), translator.NewTran(), conn.writeRaw)
+	}
+	return conn
 }
 
 // process header message
@@ -184,8 +195,8 @@ func (conn *QuicConnection) acceptStream(_ api.UseType, autoDispatch bool) (*smg
 
 // Write write raw data into stream
 func (conn *QuicConnection) Write(raw []byte) (int, error) {
-	conn.locker.Lock()
-	defer conn.locker.Unlock()
+	conn.writer.acquire(0)
+	defer conn.writer.release()
 
 	stream, err := conn.streamManager.GetStream(api.UseTypeStream, false, conn.openStreamSync)
 	if err != nil {
@@ -226,41 +237,72 @@ func (conn *QuicConnection) handleMessage(stream *smgr.Stream) {
 			return
 		}
 
-		// to check whether the message is a response or not
-		if matched := conn.syncKeeper.MatchAndNotify(*msg); matched {
+		if !translator.IsBatchMessage(msg) {
+			conn.dispatchMessage(msg, stream)
 			continue
 		}
-
-		// put the messages into fifo and wait for reading
-		if !conn.autoRoute {
-			conn.messageFifo.Put(msg)
+		msgs, err := translator.Unbatch(translator.NewTran(), msg)
+		if err != nil {
+			klog.Errorf("failed to unbatch message, error: %+v", err)
 			continue
 		}
-
-		// user do not set  message handle, use the default mux
-		if conn.handler == nil {
-			// use default mux
-			conn.handler = mux.MuxDefault
+		for _, m := range msgs {
+			conn.dispatchMessage(m, stream)
 		}
-		conn.handler.ServeConn(&mux.MessageRequest{
-			Header:           conn.state.Headers,
-			PeerCertificates: conn.state.PeerCertificates,
-			Message:          msg,
-		}, &responseWriter{
-			Type: api.ProtocolTypeQuic,
-			Van:  stream.Stream,
-		})
 	}
 }
 
+func (conn *QuicConnection) dispatchMessage(msg *model.Message, stream *smgr.Stream) {
+	// to check whether the message is a response or not
+	if matched := conn.syncKeeper.MatchAndNotify(*msg); matched {
+		return
+	}
+
+	// put the messages into fifo and wait for reading
+	if !conn.autoRoute {
+		conn.messageFifo.Put(msg)
+		return
+	}
+
+	// user do not set  message handle, use the default mux
+	if conn.handler == nil {
+		// use default mux
+		conn.handler = mux.MuxDefault
+	}
+	conn.handler.ServeConn(&mux.MessageRequest{
+		Header:           conn.state.Headers,
+		PeerCertificates: conn.state.PeerCertificates,
+		Message:          msg,
+	}, &responseWriter{
+		Type: api.ProtocolTypeQuic,
+		Van:  stream.Stream,
+	})
+}
+
 // Close will cancel write and read
 // close the session
 func (conn *QuicConnection) Close() error {
+	if conn.batcher != nil {
+		_ = conn.batcher.Flush()
+	}
 	conn.state.State = api.StatDisconnected
 	conn.streamManager.Destroy()
 	return conn.session.Close()
 }
 
+// WriteMessageBatched writes msg asynchronously, done is called with the result once msg is
+// written, which is after the batch carrying msg is flushed if the messages are batched
+func (conn *QuicConnection) WriteMessageBatched(msg *model.Message, done func(error)) {
+	if conn.batcher == nil {
+		done(conn.WriteMessageAsync(msg))
+		return
+	}
+	msg.Header.Sync = false
+	if err := conn.batcher.add(msg, laneOf(conn.lanes, msg), done); err != nil {
+		done(err)
+	}
+}
+
 // WriteMessageSync write sync message
 // please set write deadline before WriteMessageSync called
 func (conn *QuicConnection) WriteMessageSync(msg *model.Message) (*model.Message, error) {
@@ -269,8 +311,15 @@ func (conn *QuicConnection) WriteMessageSync(msg *model.Message) (*model.Message
 		return nil, fmt.Errorf("bad connection session")
 	}
 
-	conn.locker.Lock()
-	defer conn.locker.Unlock()
+	// keep the order of the messages written before
+	if conn.batcher != nil {
+		if err := conn.batcher.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	conn.writer.acquire(laneOf(conn.lanes, msg))
+	defer conn.writer.release()
 
 	stream, err := conn.streamManager.GetStream(api.UseTypeMessage, true, conn.openStreamSync)
 	if err != nil {
@@ -302,8 +351,14 @@ func (conn *QuicConnection) WriteMessageAsync(msg *model.Message) error {
 		return fmt.Errorf("bad connection session")
 	}
 
-	conn.locker.Lock()
-	defer conn.locker.Unlock()
+	priority := laneOf(conn.lanes, msg)
+	if conn.batcher != nil {
+		msg.Header.Sync = false
+		return conn.batcher.addAsync(msg, priority)
+	}
+
+	conn.writer.acquire(priority)
+	defer conn.writer.release()
 
 	stream, err := conn.streamManager.GetStream(api.UseTypeMessage, true, conn.openStreamSync)
 	if err != nil {
@@ -319,6 +374,23 @@ func (conn *QuicConnection) WriteMessageAsync(msg *model.Message) error {
 	return lane.WriteMessage(msg)
 }
 
+// writeRaw writes the message of priority lane encoded in protobuf
+func (conn *QuicConnection) writeRaw(priority int, raw []byte) error {
+	conn.writer.acquire(priority)
+	defer conn.writer.release()
+
+	stream, err := conn.streamManager.GetStream(api.UseTypeMessage, true, conn.openStreamSync)
+	if err != nil {
+		klog.Errorf("failed to acquire stream sync, error:%+v", err)
+		return fmt.Errorf("failed to acquire stream sync, error:%+v", err)
+	}
+	defer conn.streamManager.ReleaseStream(api.UseTypeMessage, stream)
+
+	_ = lane.NewLane(api.ProtocolTypeQuic, stream).SetWriteDeadline(conn.writeDeadline)
+	_, err = packer.NewWriter(stream).Write(raw)
+	return err
+}
+
 // ReadMessage read message from fifo
 // it will blocked when no message received
 func (conn *QuicConnection) ReadMessage(msg *model.Message) error {
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"io"
 	"net"
-	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -17,6 +16,7 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/keeper"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/lane"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/mux"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/translator"
 )
 
 type WSConnection struct {
@@ -30,12 +30,16 @@ type WSConnection struct {
 	consumer           io.Writer
 	autoRoute          bool
 	messageFifo        *fifo.MessageFifo
-	locker             sync.Mutex
 	OnReadTransportErr func(nodeID, projectID string)
+	// writer schedules the writes in the priority lanes
+	writer *writeScheduler
+	lanes  *LaneOptions
+	// batcher is nil if the messages are not batched
+	batcher *batcher
 }
 
 func NewWSConn(options *ConnectionOptions) *WSConnection {
-	return &WSConnection{
+	conn := &WSConnection{
 		wsConn:             options.Base.(*websocket.Conn),
 		handler:            options.Handler,
 		syncKeeper:         keeper.NewSyncKeeper(),
@@ -44,7 +48,13 @@ func NewWSConn(options *ConnectionOptions) *WSConnection {
 		autoRoute:          options.AutoRoute,
 		messageFifo:        fifo.NewMessageFifo(),
 		OnReadTransportErr: options.OnReadTransportErr,
+		writer:             newWriteScheduler(options.Lanes),
+		lanes:              options.Lanes,
 	}
+	if options.Batch != nil {
+		conn.batcher = newBatcher(*options.Batch, len(conn.writer.weights), translator.JSONCodec{}, conn.writeRaw)
+	}
+	return conn
 }
 
 // ServeConn start to receive message from connection
@@ -70,9 +80,9 @@ func (conn *WSConnection) filterControlMessage(msg *model.Message) bool {
 
 	// feedback the response
 	resp := msg.NewRespByMessage(msg, comm.RespTypeAck)
-	conn.locker.Lock()
+	conn.writer.acquire(0)
 	err := lane.NewLane(api.ProtocolTypeWS, conn.wsConn).WriteMessage(resp)
-	conn.locker.Unlock()
+	conn.writer.release()
 	if err != nil {
 		klog.Errorf("failed to send response back, error:%+v", err)
 	}
@@ -123,30 +133,41 @@ func (conn *WSConnection) handleMessage() {
 			continue
 		}
 
-		// to check whether the message is a response or not
-		if matched := conn.syncKeeper.MatchAndNotify(*msg); matched {
+		msgs, err := translator.Unbatch(translator.JSONCodec{}, msg)
+		if err != nil {
+			klog.Errorf("failed to unbatch message, error: %+v", err)
 			continue
 		}
-
-		// put the messages into fifo and wait for reading
-		if !conn.autoRoute {
-			conn.messageFifo.Put(msg)
-			continue
+		for _, msg := range msgs {
+			conn.dispatchMessage(msg)
 		}
+	}
+}
 
-		if conn.handler == nil {
-			// use default mux
-			conn.handler = mux.MuxDefault
-		}
-		conn.handler.ServeConn(&mux.MessageRequest{
-			Header:           conn.state.Headers,
-			PeerCertificates: conn.state.PeerCertificates,
-			Message:          msg,
-		}, &responseWriter{
-			Type: api.ProtocolTypeWS,
-			Van:  conn.wsConn,
-		})
+func (conn *WSConnection) dispatchMessage(msg *model.Message) {
+	// to check whether the message is a response or not
+	if matched := conn.syncKeeper.MatchAndNotify(*msg); matched {
+		return
 	}
+
+	// put the messages into fifo and wait for reading
+	if !conn.autoRoute {
+		conn.messageFifo.Put(msg)
+		return
+	}
+
+	if conn.handler == nil {
+		// use default mux
+		conn.handler = mux.MuxDefault
+	}
+	conn.handler.ServeConn(&mux.MessageRequest{
+		Header:           conn.state.Headers,
+		PeerCertificates: conn.state.PeerCertificates,
+		Message:          msg,
+	}, &responseWriter{
+		Type: api.ProtocolTypeWS,
+		Van:  conn.wsConn,
+	})
 }
 
 func (conn *WSConnection) SetReadDeadline(t time.Time) error {
@@ -168,22 +189,58 @@ func (conn *WSConnection) Write(raw []byte) (int, error) {
 }
 
 func (conn *WSConnection) WriteMessageAsync(msg *model.Message) error {
+	priority := laneOf(conn.lanes, msg)
+	if conn.batcher != nil {
+		msg.Header.Sync = false
+		return conn.batcher.addAsync(msg, priority)
+	}
+
 	lane := lane.NewLane(api.ProtocolTypeWS, conn.wsConn)
 	_ = lane.SetWriteDeadline(conn.WriteDeadline)
 	msg.Header.Sync = false
-	conn.locker.Lock()
-	defer conn.locker.Unlock()
+	conn.writer.acquire(priority)
+	defer conn.writer.release()
 	return lane.WriteMessage(msg)
 }
 
+// writeRaw writes the message of priority lane encoded in JSON
+func (conn *WSConnection) writeRaw(priority int, raw []byte) error {
+	lane := lane.NewLane(api.ProtocolTypeWS, conn.wsConn)
+	_ = lane.SetWriteDeadline(conn.WriteDeadline)
+	conn.writer.acquire(priority)
+	defer conn.writer.release()
+	_, err := lane.Write(raw)
+	return err
+}
+
+// WriteMessageBatched writes msg asynchronously, done is called with the result once msg is
+// written, which is after the batch carrying msg is flushed if the messages are batched
+func (conn *WSConnection) WriteMessageBatched(msg *model.Message, done func(error)) {
+	if conn.batcher == nil {
+		done(conn.WriteMessageAsync(msg))
+		return
+	}
+	msg.Header.Sync = false
+	if err := conn.batcher.add(msg, laneOf(conn.lanes, msg), done); err != nil {
+		done(err)
+	}
+}
+
 func (conn *WSConnection) WriteMessageSync(msg *model.Message) (*model.Message, error) {
+	// keep the order of the messages written before
+	if conn.batcher != nil {
+		if err := conn.batcher.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
 	lane := lane.NewLane(api.ProtocolTypeWS, conn.wsConn)
 	// send msg
 	_ = lane.SetWriteDeadline(conn.WriteDeadline)
 	msg.Header.Sync = true
-	conn.locker.Lock()
+	conn.writer.acquire(laneOf(conn.lanes, msg))
 	err := lane.WriteMessage(msg)
-	conn.locker.Unlock()
+	conn.writer.release()
 	if err != nil {
 		klog.Errorf("write message error(%+v)", err)
 		return nil, err
@@ -206,6 +263,9 @@ func (conn *WSConnection) LocalAddr() net.Addr {
 }
 
 func (conn *WSConnection) Close() error {
+	if conn.batcher != nil {
+		_ = conn.batcher.Flush()
+	}
 	conn.messageFifo.Close()
 	return conn.wsConn.Close()
 }
@@ -99,10 +99,12 @@ type MessageHeader struct {
 	// the flag will be set in send sync
 	Sync bool `protobuf:"varint,4,opt,name=Sync,proto3" json:"Sync,omitempty"`
 	// message type
-	MessageType          string   `protobuf:"bytes,5,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	MessageType string `protobuf:"bytes,5,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	// user defined properties of the message
+	Properties           map[string]string `protobuf:"bytes,6,rep,name=Properties,proto3" json:"Properties,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *MessageHeader) Reset()         { *m = MessageHeader{} }
@@ -165,6 +167,13 @@ func (m *MessageHeader) GetMessageType() string {
 	return ""
 }
 
+func (m *MessageHeader) GetProperties() map[string]string {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
 type Message struct {
 	Header               *MessageHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	Router               *MessageRouter `protobuf:"bytes,2,opt,name=router,proto3" json:"router,omitempty"`
@@ -223,28 +232,32 @@ func (m *Message) GetContent() []byte {
 func init() {
 	proto.RegisterType((*MessageRouter)(nil), "message.MessageRouter")
 	proto.RegisterType((*MessageHeader)(nil), "message.MessageHeader")
+	proto.RegisterMapType((map[string]string)(nil), "message.MessageHeader.PropertiesEntry")
 	proto.RegisterType((*Message)(nil), "message.Message")
 }
 
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 261 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xb1, 0x4e, 0xc3, 0x30,
-	0x10, 0x86, 0x95, 0xd0, 0x26, 0xed, 0x95, 0x32, 0x9c, 0x50, 0x65, 0x21, 0x86, 0x2a, 0x13, 0x53,
-	0x06, 0x78, 0x04, 0x22, 0x41, 0x06, 0x04, 0x72, 0xfb, 0x02, 0x26, 0x9c, 0xa0, 0x43, 0x6c, 0xcb,
-	0x76, 0x86, 0xcc, 0x3c, 0x00, 0xaf, 0x8c, 0x72, 0x71, 0x02, 0x48, 0x6c, 0xfe, 0xee, 0x7e, 0xdd,
-	0xff, 0xeb, 0x37, 0x6c, 0x5b, 0xf2, 0x5e, 0xbd, 0x53, 0x69, 0x9d, 0x09, 0x06, 0xf3, 0x88, 0x85,
-	0x87, 0xed, 0xd3, 0xf8, 0x94, 0xa6, 0x0b, 0xe4, 0x70, 0x07, 0xd9, 0xc1, 0x74, 0xae, 0x21, 0x91,
-	0xec, 0x93, 0x9b, 0xb5, 0x8c, 0x84, 0x97, 0xb0, 0x7c, 0x70, 0xa6, 0xb3, 0x22, 0xe5, 0xf1, 0x08,
-	0x78, 0x05, 0xab, 0x67, 0x4b, 0x4e, 0x9d, 0x8c, 0x16, 0x67, 0xbc, 0x98, 0x19, 0x05, 0xe4, 0x92,
-	0xbc, 0xe9, 0x1a, 0x12, 0x0b, 0x5e, 0x4d, 0x58, 0x7c, 0x25, 0xb3, 0xeb, 0x23, 0xa9, 0x37, 0x72,
-	0x78, 0x01, 0x69, 0x5d, 0x45, 0xc7, 0xb4, 0xae, 0x86, 0xbb, 0x2f, 0xca, 0x91, 0x0e, 0x75, 0x15,
-	0x0d, 0x67, 0xc6, 0x6b, 0x58, 0x1f, 0x4f, 0x2d, 0xf9, 0xa0, 0x5a, 0xcb, 0xa6, 0x28, 0x7f, 0x06,
-	0x88, 0xb0, 0x38, 0xf4, 0xba, 0x61, 0xcb, 0x95, 0xe4, 0x37, 0xee, 0x61, 0x13, 0xed, 0x8e, 0xbd,
-	0x25, 0xb1, 0xe4, 0x83, 0xbf, 0x47, 0xc5, 0x67, 0x02, 0x79, 0x64, 0x2c, 0x21, 0xfb, 0xe0, 0x54,
-	0x9c, 0x67, 0x73, 0xbb, 0x2b, 0xa7, 0xee, 0xfe, 0x64, 0x96, 0x51, 0x35, 0xe8, 0x1d, 0x77, 0xc7,
-	0x49, 0xff, 0xd1, 0x8f, 0xcd, 0xca, 0xa8, 0x1a, 0x7a, 0xb9, 0x37, 0x3a, 0x90, 0x0e, 0x9c, 0xfe,
-	0x5c, 0x4e, 0xf8, 0x9a, 0xf1, 0xe7, 0xdc, 0x7d, 0x07, 0x00, 0x00, 0xff, 0xff, 0xd1, 0xe5, 0x28,
-	0xb7, 0xad, 0x01, 0x00, 0x00,
+	// 319 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xcf, 0x4e, 0x02, 0x31,
+	0x10, 0xc6, 0xb3, 0x0b, 0x2c, 0x30, 0x88, 0x9a, 0x89, 0x21, 0x0d, 0xf1, 0x40, 0x38, 0x18, 0x4e,
+	0x7b, 0xc0, 0x8b, 0x31, 0xf1, 0x24, 0xfe, 0xe1, 0x60, 0x24, 0x85, 0x17, 0x58, 0x71, 0xa2, 0x44,
+	0x69, 0x9b, 0xb6, 0x6b, 0xb2, 0x67, 0xdf, 0xc1, 0xe7, 0x35, 0x3b, 0x74, 0x01, 0x0d, 0xb7, 0xf9,
+	0x66, 0xbe, 0xf6, 0xd7, 0xfd, 0x66, 0xa1, 0xbb, 0x26, 0xe7, 0xb2, 0x37, 0x4a, 0x8d, 0xd5, 0x5e,
+	0x63, 0x33, 0xc8, 0xa1, 0x83, 0xee, 0xd3, 0xa6, 0x94, 0x3a, 0xf7, 0x64, 0xb1, 0x07, 0xc9, 0x5c,
+	0xe7, 0x76, 0x49, 0x22, 0x1a, 0x44, 0xa3, 0xb6, 0x0c, 0x0a, 0xcf, 0xa0, 0xf1, 0x60, 0x75, 0x6e,
+	0x44, 0xcc, 0xed, 0x8d, 0xc0, 0x3e, 0xb4, 0x9e, 0x0d, 0xd9, 0x6c, 0xa5, 0x95, 0xa8, 0xf1, 0x60,
+	0xab, 0x51, 0x40, 0x53, 0x92, 0xd3, 0xf9, 0x92, 0x44, 0x9d, 0x47, 0x95, 0x1c, 0xfe, 0xc4, 0x5b,
+	0xea, 0x23, 0x65, 0xaf, 0x64, 0xf1, 0x18, 0xe2, 0xe9, 0x24, 0x10, 0xe3, 0xe9, 0xa4, 0xbc, 0x77,
+	0x96, 0x59, 0x52, 0x7e, 0x3a, 0x09, 0xc0, 0xad, 0xc6, 0x73, 0x68, 0x2f, 0x56, 0x6b, 0x72, 0x3e,
+	0x5b, 0x1b, 0x86, 0xa2, 0xdc, 0x35, 0x10, 0xa1, 0x3e, 0x2f, 0xd4, 0x92, 0x91, 0x2d, 0xc9, 0x35,
+	0x0e, 0xa0, 0x13, 0x70, 0x8b, 0xc2, 0x90, 0x68, 0xf0, 0x85, 0xfb, 0x2d, 0xbc, 0x07, 0x98, 0x59,
+	0x6d, 0xc8, 0xfa, 0x15, 0x39, 0x91, 0x0c, 0x6a, 0xa3, 0xce, 0xf8, 0x22, 0xad, 0x32, 0xfb, 0xf3,
+	0xd6, 0x74, 0x67, 0xbc, 0x53, 0xde, 0x16, 0x72, 0xef, 0x64, 0xff, 0x06, 0x4e, 0xfe, 0x8d, 0xf1,
+	0x14, 0x6a, 0x1f, 0x54, 0x84, 0x6f, 0x2b, 0xcb, 0x32, 0xca, 0xaf, 0xec, 0x33, 0xa7, 0x2a, 0x4a,
+	0x16, 0xd7, 0xf1, 0x55, 0x34, 0xfc, 0x8e, 0xa0, 0x19, 0x60, 0x98, 0x42, 0xf2, 0xce, 0x40, 0x3e,
+	0xda, 0x19, 0xf7, 0x0e, 0x3f, 0x47, 0x06, 0x57, 0xe9, 0xb7, 0xbc, 0x42, 0x11, 0x1f, 0xf6, 0x6f,
+	0x16, 0x2c, 0x83, 0xab, 0x5c, 0xcf, 0xad, 0x56, 0x9e, 0x94, 0xe7, 0x10, 0x8f, 0x64, 0x25, 0x5f,
+	0x12, 0xfe, 0x47, 0x2e, 0x7f, 0x07, 0x00, 0x79, 0xff, 0xa3, 0x6d, 0x34, 0x02, 0x00, 0x00,
 }
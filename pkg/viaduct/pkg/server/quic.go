@@ -88,8 +88,12 @@ func (srv *QuicServer) receiveHeader(lane lane.Lane) (http.Header, error) {
 		result = comm.RespTypeNack
 	}
 
-	// feedback the response
-	resp := msg.NewRespByMessage(&msg, result)
+	// feedback the response, the headers of the server are sent back with the ack
+	var content interface{} = result
+	if result == comm.RespTypeAck {
+		content = responseHeader()
+	}
+	resp := msg.NewRespByMessage(&msg, content)
 	err = lane.WriteMessage(resp)
 	if err != nil {
 		klog.Errorf("failed to send response back, error:%+v", err)
@@ -131,6 +135,8 @@ func (srv *QuicServer) handleSession(session quic.Session) {
 		},
 		AutoRoute:          srv.options.AutoRoute,
 		OnReadTransportErr: srv.options.OnReadTransportErr,
+		Batch:              conn.NegotiateBatch(srv.options.Batch, header),
+		Lanes:              srv.options.Lanes,
 	})
 
 	// connection callback
@@ -152,6 +158,7 @@ func (srv *QuicServer) getQuicConfig() *quic.Config {
 		HandshakeTimeout:   srv.options.HandshakeTimeout,
 		KeepAlive:          true,
 		MaxIncomingStreams: srv.exOpts.MaxIncomingStreams,
+		IdleTimeout:        srv.exOpts.IdleTimeout,
 	}
 }
 
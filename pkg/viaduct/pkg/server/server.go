@@ -4,10 +4,12 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"net/http"
 	"time"
 
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/cmgr"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/mux"
 )
@@ -33,6 +35,8 @@ type Options struct {
 	HandshakeTimeout   time.Duration
 	Handler            mux.Handler
 	Consumer           io.Writer
+	Batch              *conn.BatchOptions
+	Lanes              *conn.LaneOptions
 }
 
 type Server struct {
@@ -56,6 +60,10 @@ type Server struct {
 	Handler mux.Handler
 	// consumer for raw data
 	Consumer io.Writer
+	// Batch indicates how the messages are batched to the clients which are able to unpack them
+	Batch *conn.BatchOptions
+	// Lanes indicates how the messages are scheduled in priority lanes
+	Lanes *conn.LaneOptions
 	// extend options
 	ExOpts interface{}
 
@@ -90,6 +98,14 @@ func (s *Server) getTLSConfig(cert, key string) (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// responseHeader returns the headers sent back to the clients, which tell that
+// the batches sent by the clients are able to be unpacked
+func responseHeader() http.Header {
+	header := make(http.Header)
+	header.Set(comm.HeaderMessageBatch, "true")
+	return header
+}
+
 // get the protocol server by protocol type
 func (s *Server) getProtoServer(opts Options) error {
 	switch s.Type {
@@ -120,6 +136,8 @@ func (s *Server) ListenAndServeTLS(certFile, keyFile string) error {
 		Handler:            s.Handler,
 		Consumer:           s.Consumer,
 		OnReadTransportErr: s.OnReadTransportErr,
+		Batch:              s.Batch,
+		Lanes:              s.Lanes,
 	})
 	if err != nil {
 		return err
@@ -58,7 +58,7 @@ func (srv *WSServer) upgrade(w http.ResponseWriter, r *http.Request) *websocket.
 	upgrader := websocket.Upgrader{
 		HandshakeTimeout: srv.options.HandshakeTimeout,
 	}
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := upgrader.Upgrade(w, r, responseHeader())
 	if err != nil {
 		klog.Error("failed to upgrade to websocket")
 		return nil
@@ -93,6 +93,8 @@ func (srv *WSServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		},
 		AutoRoute:          srv.options.AutoRoute,
 		OnReadTransportErr: srv.options.OnReadTransportErr,
+		Batch:              conn.NegotiateBatch(srv.options.Batch, req.Header),
+		Lanes:              srv.options.Lanes,
 	})
 
 	// connection callback
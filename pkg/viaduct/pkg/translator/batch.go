@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
+)
+
+// Codec encodes and decodes the messages written to a lane,
+// MessageTranslator is the codec of the lanes in protobuf
+type Codec interface {
+	Encode(msg interface{}) ([]byte, error)
+	Decode(raw []byte, msg interface{}) error
+}
+
+// JSONCodec is the codec of the lanes which write the messages in JSON
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(msg interface{}) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec) Decode(raw []byte, msg interface{}) error {
+	return json.Unmarshal(raw, msg)
+}
+
+// NewBatchMessage returns the message which carries the messages encoded by the codec
+// of the lane, so that they are decoded the same as the messages written alone
+func NewBatchMessage(raws [][]byte) *model.Message {
+	size := 0
+	for _, raw := range raws {
+		size += binary.MaxVarintLen64 + len(raw)
+	}
+	content := make([]byte, 0, size)
+	for _, raw := range raws {
+		content = binary.AppendUvarint(content, uint64(len(raw)))
+		content = append(content, raw...)
+	}
+	return model.NewMessage("").
+		BuildRouter("", "", comm.ControlActionBatch, comm.ControlTypeBatch).
+		FillBody(content)
+}
+
+// IsBatchMessage returns whether msg carries a batch of messages
+func IsBatchMessage(msg *model.Message) bool {
+	return msg.GetOperation() == comm.ControlTypeBatch && msg.GetResource() == comm.ControlActionBatch
+}
+
+// Unbatch returns the messages carried by msg decoded by codec, or msg itself if it is
+// not a batch message
+func Unbatch(codec Codec, msg *model.Message) ([]*model.Message, error) {
+	if !IsBatchMessage(msg) {
+		return []*model.Message{msg}, nil
+	}
+
+	var content []byte
+	switch c := msg.GetContent().(type) {
+	case []byte:
+		content = c
+	case string:
+		// []byte is written as base64 string in JSON
+		decoded, err := base64.StdEncoding.DecodeString(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode batch content: %v", err)
+		}
+		content = decoded
+	default:
+		return nil, fmt.Errorf("bad batch content type %T", c)
+	}
+
+	var msgs []*model.Message
+	for len(content) > 0 {
+		size, n := binary.Uvarint(content)
+		if n <= 0 || uint64(len(content)-n) < size {
+			return nil, fmt.Errorf("bad batch content")
+		}
+		content = content[n:]
+		m := &model.Message{}
+		if err := codec.Decode(content[:size], m); err != nil {
+			return nil, fmt.Errorf("failed to decode message in batch: %v", err)
+		}
+		msgs = append(msgs, m)
+		content = content[size:]
+	}
+	return msgs, nil
+}
@@ -25,6 +25,7 @@ func (t *MessageTranslator) protoToModel(src *message.Message, dst *model.Messag
 
 	// TODO:
 	dst.Header.Sync = src.Header.Sync
+	dst.Header.Properties = src.Header.Properties
 
 	return nil
 }
@@ -34,6 +35,7 @@ func (t *MessageTranslator) modelToProto(src *model.Message, dst *message.Messag
 	dst.Header.ParentID = src.GetParentID()
 	dst.Header.Timestamp = int64(src.GetTimestamp())
 	dst.Header.Sync = src.IsSync()
+	dst.Header.Properties = src.GetProperties()
 	dst.Router.Source = src.GetSource()
 	dst.Router.Group = src.GetGroup()
 	dst.Router.Resouce = src.GetResource()
@@ -97,3 +99,15 @@ func (t *MessageTranslator) Encode(msg interface{}) ([]byte, error) {
 
 	return msgBytes, nil
 }
+
+// Size returns the size of msg after it is encoded, it is 0 if msg can not be encoded
+func Size(msg *model.Message) int {
+	protoMessage := message.Message{
+		Header: &message.MessageHeader{},
+		Router: &message.MessageRouter{},
+	}
+	if err := NewTran().modelToProto(msg, &protoMessage); err != nil {
+		return 0
+	}
+	return proto.Size(&protoMessage)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	properties := map[string]string{"trace-id": "1", "mqtt-response-topic": "a/b"}
+	msg := model.NewMessage("parent").BuildRouter("bus", "user", "a/b", "publish").
+		SetProperties(properties).FillBody([]byte("payload"))
+	msg.Header.Sync = true
+
+	raw, err := NewTran().Encode(msg)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	got := &model.Message{}
+	if err := NewTran().Decode(raw, got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got.GetID() != msg.GetID() || got.GetParentID() != "parent" || !got.IsSync() {
+		t.Errorf("Decode() got header %+v, want %+v", got.Header, msg.Header)
+	}
+	if got.GetResource() != "a/b" || got.GetOperation() != "publish" {
+		t.Errorf("Decode() got router %+v, want %+v", got.Router, msg.Router)
+	}
+	if !reflect.DeepEqual(got.GetProperties(), properties) {
+		t.Errorf("Decode() got properties %v, want %v", got.GetProperties(), properties)
+	}
+	if content, _ := got.GetContentData(); string(content) != "payload" {
+		t.Errorf("Decode() got content %q, want %q", content, "payload")
+	}
+}
+
+func TestSize(t *testing.T) {
+	msg := model.NewMessage("parent").BuildRouter("edgecontroller", "resource", "default/pod/test", "update").
+		FillBody(map[string]string{"name": "test"})
+
+	raw, err := NewTran().Encode(msg)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if got := Size(msg); got != len(raw) {
+		t.Errorf("Size() = %d, want %d", got, len(raw))
+	}
+}
+
+func TestUnbatch(t *testing.T) {
+	msgs := []*model.Message{
+		model.NewMessage("").BuildRouter("edgecontroller", "resource", "default/pod/a", "update").
+			FillBody(map[string]interface{}{"name": "a"}),
+		model.NewMessage("").BuildRouter("devicecontroller", "twin", "device/b/twin/cloud_updated", "update").
+			FillBody("b"),
+	}
+	for _, codec := range []Codec{NewTran(), JSONCodec{}} {
+		var raws [][]byte
+		for _, msg := range msgs {
+			raw, err := codec.Encode(msg)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			raws = append(raws, raw)
+		}
+		batch := NewBatchMessage(raws)
+		if !IsBatchMessage(batch) {
+			t.Fatalf("IsBatchMessage() = false, want true")
+		}
+
+		// the batch message is written by the codec of the lane as well
+		raw, err := codec.Encode(batch)
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		received := &model.Message{}
+		if err := codec.Decode(raw, received); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		got, err := Unbatch(codec, received)
+		if err != nil {
+			t.Fatalf("Unbatch() error = %v", err)
+		}
+		if len(got) != len(msgs) {
+			t.Fatalf("Unbatch() got %d messages, want %d", len(got), len(msgs))
+		}
+		for i := range msgs {
+			want := &model.Message{}
+			if err := codec.Decode(raws[i], want); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got[i], want) {
+				t.Errorf("Unbatch() got message %+v, want %+v", got[i], want)
+			}
+		}
+	}
+
+	single := model.NewMessage("").BuildRouter("bus", "user", "a/b", "publish")
+	if got, err := Unbatch(NewTran(), single); err != nil || len(got) != 1 || got[0] != single {
+		t.Errorf("Unbatch() got %v, %v, want the message itself", got, err)
+	}
+	bad := NewBatchMessage(nil).FillBody([]byte{10, 1})
+	if _, err := Unbatch(NewTran(), bad); err == nil {
+		t.Errorf("Unbatch() expects error for bad batch content")
+	}
+}
@@ -89,6 +89,10 @@ const (
 	DefaultDeviceModelEventBuffer    = 1
 	DefaultUpdateDeviceStatusWorkers = 1
 
+	DefaultReportDiscoveredDevicesBuffer  = 1024
+	DefaultDiscoveredDeviceEventBuffer    = 1
+	DefaultDeviceDiscoveryRuleEventBuffer = 1
+
 	// TaskManager
 	DefaultNodeUpgradeJobStatusBuffer = 1024
 	DefaultNodeUpgradeJobEventBuffer  = 1
@@ -65,6 +65,7 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 					Address:            "0.0.0.0",
 					Port:               10001,
 					MaxIncomingStreams: 10000,
+					IdleTimeout:        30,
 				},
 				UnixSocket: &CloudHubUnixSocket{
 					Enable:  true,
@@ -91,6 +92,29 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 						},
 					},
 				},
+				MessageQoS: &CloudHubMessageQoS{
+					Enable: false,
+					Lanes: map[MessagePriority]MessageLane{
+						MessagePriorityControl:  {Weight: 8},
+						MessagePriorityWorkload: {Weight: 4},
+						MessagePriorityDevice:   {Weight: 2},
+						MessagePriorityBulk:     {Weight: 1},
+					},
+				},
+				BandwidthBudget: &CloudHubBandwidthBudget{
+					Enable:         false,
+					ReportInterval: 60,
+				},
+				MessageBatch: &MessageBatch{
+					Enable:             false,
+					WindowMilliseconds: 10,
+					MaxMessages:        100,
+					MaxBytes:           65536,
+				},
+				SessionResumption: &CloudHubSessionResumption{
+					Enable:  false,
+					Timeout: 60,
+				},
 			},
 			EdgeController: &EdgeController{
 				Enable:              true,
@@ -105,6 +129,10 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 					UpdateDeviceStates: constants.DefaultUpdateDeviceStatesBuffer,
 					DeviceEvent:        constants.DefaultDeviceEventBuffer,
 					DeviceModelEvent:   constants.DefaultDeviceModelEventBuffer,
+
+					ReportDiscoveredDevices:  constants.DefaultReportDiscoveredDevicesBuffer,
+					DiscoveredDeviceEvent:    constants.DefaultDiscoveredDeviceEventBuffer,
+					DeviceDiscoveryRuleEvent: constants.DefaultDeviceDiscoveryRuleEventBuffer,
 				},
 				Load: &DeviceControllerLoad{
 					UpdateDeviceStatusWorkers: constants.DefaultUpdateDeviceStatusWorkers,
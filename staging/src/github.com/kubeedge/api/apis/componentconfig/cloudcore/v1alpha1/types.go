@@ -153,6 +153,16 @@ type CloudHub struct {
 	TokenRefreshDuration time.Duration `json:"tokenRefreshDuration,omitempty"`
 	// Authorization authz configurations
 	Authorization *CloudHubAuthorization `json:"authorization,omitempty"`
+	// MessageQoS indicates the priority lanes of the messages sent to edge node
+	MessageQoS *CloudHubMessageQoS `json:"messageQoS,omitempty"`
+	// BandwidthBudget indicates the bandwidth budgets of the edge nodes
+	BandwidthBudget *CloudHubBandwidthBudget `json:"bandwidthBudget,omitempty"`
+	// MessageBatch indicates how the messages sent to edge node are coalesced into batches,
+	// they are batched only to the edge nodes which are able to unpack them
+	MessageBatch *MessageBatch `json:"messageBatch,omitempty"`
+	// SessionResumption indicates whether the session of an edge node survives the loss of its connection,
+	// so that the node reconnecting from another address keeps the messages waiting to be sent
+	SessionResumption *CloudHubSessionResumption `json:"sessionResumption,omitempty"`
 }
 
 // CloudHubQUIC indicates the quic server config
@@ -169,6 +179,10 @@ type CloudHubQUIC struct {
 	// MaxIncomingStreams set the max incoming stream for quic server
 	// default 10000
 	MaxIncomingStreams int32 `json:"maxIncomingStreams,omitempty"`
+	// IdleTimeout indicates how long (second) a quic session is kept without any incoming packet,
+	// keepalive packets are sent at half of it
+	// default 30
+	IdleTimeout int32 `json:"idleTimeout,omitempty"`
 }
 
 // CloudHubUnixSocket indicates the unix socket config
@@ -233,6 +247,110 @@ type NodeAuthorization struct {
 	Enable bool `json:"enable"`
 }
 
+// MessagePriority is the priority class of the messages sent to edge node
+type MessagePriority string
+
+const (
+	// MessagePriorityControl is the class of the control-critical messages, such as the
+	// deletions, leases, nodes and the responses which edge node waits for
+	MessagePriorityControl MessagePriority = "control"
+	// MessagePriorityWorkload is the class of the workload messages, such as pods and services
+	MessagePriorityWorkload MessagePriority = "workload"
+	// MessagePriorityBulk is the class of the bulk data messages, such as configmaps and secrets
+	MessagePriorityBulk MessagePriority = "bulk"
+	// MessagePriorityDevice is the class of the device data messages
+	MessagePriorityDevice MessagePriority = "device"
+)
+
+// MessagePriorities are all the priority classes, from the highest to the lowest
+var MessagePriorities = []MessagePriority{
+	MessagePriorityControl,
+	MessagePriorityWorkload,
+	MessagePriorityDevice,
+	MessagePriorityBulk,
+}
+
+// CloudHubMessageQoS indicates the priority lanes of the messages sent to edge node
+type CloudHubMessageQoS struct {
+	// Enable indicates whether the messages are sent in priority lanes,
+	// they are sent in the order of arrival if disabled
+	// default false
+	Enable bool `json:"enable"`
+	// Lanes indicates the lane of each priority class, the class without lane
+	// uses weight 1 and no rate limit
+	Lanes map[MessagePriority]MessageLane `json:"lanes,omitempty"`
+}
+
+// MessageLane indicates the config of a priority lane
+type MessageLane struct {
+	// Weight is the relative share of the lane when the messages of several lanes are waiting,
+	// both in the message queues of the edge node and for the connection to it
+	// default control 8, workload 4, device 2, bulk 1
+	Weight int32 `json:"weight,omitempty"`
+	// QPS is the max number of messages sent per second in the lane of each edge node,
+	// 0 means no limit
+	// default 0
+	QPS float32 `json:"qps,omitempty"`
+	// Burst is the max number of messages sent at once in the lane when QPS is set
+	// default 0, it is QPS rounded up if not set
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// CloudHubBandwidthBudget indicates the bandwidth budgets of the edge nodes, the budget of a node
+// is looked up in Nodes first, then in NodeGroups by the node group it belongs to, and Default is
+// used if neither is found
+type CloudHubBandwidthBudget struct {
+	// Enable indicates whether the traffic of the edge nodes is metered and shaped by the budgets
+	// default false
+	Enable bool `json:"enable"`
+	// Default is the budget of the nodes which have no budget of their own
+	Default BandwidthBudget `json:"default,omitempty"`
+	// NodeGroups are the budgets of the nodes in the node groups, keyed by node group name
+	NodeGroups map[string]BandwidthBudget `json:"nodeGroups,omitempty"`
+	// Nodes are the budgets of the nodes, keyed by node name
+	Nodes map[string]BandwidthBudget `json:"nodes,omitempty"`
+	// ReportInterval indicates the interval (second) of reporting the usages to the node annotations
+	// default 60
+	ReportInterval int32 `json:"reportInterval,omitempty"`
+}
+
+// CloudHubSessionResumption indicates how the session of an edge node is resumed on reconnect
+type CloudHubSessionResumption struct {
+	// Enable indicates whether the session waits for the edge node to reconnect once its connection
+	// is lost, instead of being closed at once
+	// default false
+	Enable bool `json:"enable"`
+	// Timeout indicates how long (second) the session waits for the edge node to reconnect
+	// default 60
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// MessageBatch indicates how the messages sent in a short window are coalesced into one frame
+type MessageBatch struct {
+	// Enable indicates whether the messages are batched
+	// default false
+	Enable bool `json:"enable"`
+	// WindowMilliseconds is how long (millisecond) a message waits for the following messages
+	// default 10
+	WindowMilliseconds int32 `json:"windowMilliseconds,omitempty"`
+	// MaxMessages is the max number of messages in a batch
+	// default 100
+	MaxMessages int32 `json:"maxMessages,omitempty"`
+	// MaxBytes is the max size (byte) of a batch, the message larger than it is sent alone
+	// default 65536
+	MaxBytes int32 `json:"maxBytes,omitempty"`
+}
+
+// BandwidthBudget indicates the bandwidth budget of an edge node, 0 means no limit
+type BandwidthBudget struct {
+	// BytesPerSecond is the max rate of the traffic sent to the edge node
+	BytesPerSecond int64 `json:"bytesPerSecond,omitempty"`
+	// BytesPerDay is the max traffic of both directions in a day (UTC), the bulk messages
+	// sent to the edge node are deferred to the next day once it is used up, upstream traffic
+	// is counted only if it is reported by EdgeHub
+	BytesPerDay int64 `json:"bytesPerDay,omitempty"`
+}
+
 // EdgeController indicates the config of EdgeController module
 type EdgeController struct {
 	// Enable indicates whether EdgeController is enabled,
@@ -417,6 +535,15 @@ type DeviceControllerBuffer struct {
 	// DeviceStatusEvent indicates the buffer of device status event
 	// default 1
 	DeviceStatusEvent int32 `json:"deviceStatusEvent,omitempty"`
+	// ReportDiscoveredDevices indicates the buffer of discovered devices reported by mappers
+	// default 1024
+	ReportDiscoveredDevices int32 `json:"reportDiscoveredDevices,omitempty"`
+	// DiscoveredDeviceEvent indicates the buffer of discovered device event
+	// default 1
+	DiscoveredDeviceEvent int32 `json:"discoveredDeviceEvent,omitempty"`
+	// DeviceDiscoveryRuleEvent indicates the buffer of device discovery rule event
+	// default 1
+	DeviceDiscoveryRuleEvent int32 `json:"deviceDiscoveryRuleEvent,omitempty"`
 }
 
 // DeviceControllerLoad indicates the deviceController load
@@ -539,6 +666,20 @@ type Router struct {
 	Address     string `json:"address,omitempty"`
 	Port        uint32 `json:"port,omitempty"`
 	RestTimeout uint32 `json:"restTimeout,omitempty"`
+	// NamespaceRateLimits limits the total message throughput of the rules in each namespace,
+	// the key is the namespace and "*" applies to the namespaces which are not listed.
+	// The throughput is unlimited if it is not set.
+	// +optional
+	NamespaceRateLimits map[string]RouterRateLimit `json:"namespaceRateLimits,omitempty"`
+}
+
+// RouterRateLimit indicates the rate limit of router messages
+type RouterRateLimit struct {
+	// QPS indicates the max count of messages per second
+	QPS int32 `json:"qps"`
+	// Burst indicates the max count of messages in a burst
+	// default QPS
+	Burst int32 `json:"burst,omitempty"`
 }
 
 // IptablesManager indicates the config of Iptables
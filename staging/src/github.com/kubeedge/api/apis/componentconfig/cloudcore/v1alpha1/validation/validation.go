@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -44,6 +45,9 @@ func ValidateCloudCoreConfiguration(c *v1alpha1.CloudCoreConfig) field.ErrorList
 	allErrs = append(allErrs, ValidateModuleSyncController(*c.Modules.SyncController)...)
 	allErrs = append(allErrs, ValidateModuleDynamicController(*c.Modules.DynamicController)...)
 	allErrs = append(allErrs, ValidateModuleCloudStream(*c.Modules.CloudStream)...)
+	if c.Modules.Router != nil {
+		allErrs = append(allErrs, ValidateModuleRouter(*c.Modules.Router)...)
+	}
 	return allErrs
 }
 
@@ -111,6 +115,9 @@ func ValidateModuleCloudHub(c v1alpha1.CloudHub) field.ErrorList {
 			allErrs = append(allErrs, field.Invalid(field.NewPath("Address"), c.Quic.Address, m))
 		}
 	}
+	if c.Quic.IdleTimeout < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("idleTimeout"), c.Quic.IdleTimeout, "idleTimeout must not be negative"))
+	}
 	if !strings.HasPrefix(strings.ToLower(c.UnixSocket.Address), "unix://") {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("address"),
 			c.UnixSocket.Address, "unixSocketAddress must has prefix unix://"))
@@ -127,9 +134,95 @@ func ValidateModuleCloudHub(c v1alpha1.CloudHub) field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("TokenRefreshDuration"),
 			c.TokenRefreshDuration, "TokenRefreshDuration must be positive"))
 	}
+	if c.MessageQoS != nil {
+		allErrs = append(allErrs, validateMessageQoS(*c.MessageQoS, field.NewPath("messageQoS"))...)
+	}
+	if c.BandwidthBudget != nil {
+		allErrs = append(allErrs, validateBandwidthBudget(*c.BandwidthBudget, field.NewPath("bandwidthBudget"))...)
+	}
+	if c.MessageBatch != nil {
+		allErrs = append(allErrs, validateMessageBatch(*c.MessageBatch, field.NewPath("messageBatch"))...)
+	}
+	if c.SessionResumption != nil && c.SessionResumption.Enable && c.SessionResumption.Timeout <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("sessionResumption").Child("timeout"),
+			c.SessionResumption.Timeout, "timeout must be positive"))
+	}
+	return allErrs
+}
+
+// validateMessageQoS validates `q` and returns an errorList if it is invalid
+func validateMessageQoS(q v1alpha1.CloudHubMessageQoS, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for priority, lane := range q.Lanes {
+		lanePath := fldPath.Child("lanes").Key(string(priority))
+		if !slices.Contains(v1alpha1.MessagePriorities, priority) {
+			allErrs = append(allErrs, field.NotSupported(lanePath, priority, messagePriorityNames()))
+		}
+		if lane.Weight < 0 {
+			allErrs = append(allErrs, field.Invalid(lanePath.Child("weight"), lane.Weight, "weight must not be negative"))
+		}
+		if lane.QPS < 0 {
+			allErrs = append(allErrs, field.Invalid(lanePath.Child("qps"), lane.QPS, "qps must not be negative"))
+		}
+		if lane.Burst < 0 {
+			allErrs = append(allErrs, field.Invalid(lanePath.Child("burst"), lane.Burst, "burst must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+// validateBandwidthBudget validates `b` and returns an errorList if it is invalid
+func validateBandwidthBudget(b v1alpha1.CloudHubBandwidthBudget, fldPath *field.Path) field.ErrorList {
+	allErrs := validateBudget(b.Default, fldPath.Child("default"))
+	for name, budget := range b.NodeGroups {
+		allErrs = append(allErrs, validateBudget(budget, fldPath.Child("nodeGroups").Key(name))...)
+	}
+	for name, budget := range b.Nodes {
+		allErrs = append(allErrs, validateBudget(budget, fldPath.Child("nodes").Key(name))...)
+	}
+	if b.Enable && b.ReportInterval <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("reportInterval"), b.ReportInterval, "reportInterval must be positive"))
+	}
+	return allErrs
+}
+
+func validateBudget(b v1alpha1.BandwidthBudget, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if b.BytesPerSecond < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bytesPerSecond"), b.BytesPerSecond, "bytesPerSecond must not be negative"))
+	}
+	if b.BytesPerDay < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bytesPerDay"), b.BytesPerDay, "bytesPerDay must not be negative"))
+	}
+	return allErrs
+}
+
+// validateMessageBatch validates `b` and returns an errorList if it is invalid
+func validateMessageBatch(b v1alpha1.MessageBatch, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !b.Enable {
+		return allErrs
+	}
+	if b.WindowMilliseconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("windowMilliseconds"), b.WindowMilliseconds, "windowMilliseconds must be positive"))
+	}
+	if b.MaxMessages <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxMessages"), b.MaxMessages, "maxMessages must be positive"))
+	}
+	if b.MaxBytes <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxBytes"), b.MaxBytes, "maxBytes must be positive"))
+	}
 	return allErrs
 }
 
+func messagePriorityNames() []string {
+	names := make([]string, 0, len(v1alpha1.MessagePriorities))
+	for _, p := range v1alpha1.MessagePriorities {
+		names = append(names, string(p))
+	}
+	return names
+}
+
 // ValidateModuleEdgeController validates `e` and returns an errorList if it is invalid
 func ValidateModuleEdgeController(e v1alpha1.EdgeController) field.ErrorList {
 	if !e.Enable {
@@ -203,6 +296,24 @@ func ValidateModuleCloudStream(d v1alpha1.CloudStream) field.ErrorList {
 	return allErrs
 }
 
+// ValidateModuleRouter validates `r` and returns an errorList if it is invalid
+func ValidateModuleRouter(r v1alpha1.Router) field.ErrorList {
+	if !r.Enable {
+		return field.ErrorList{}
+	}
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("namespaceRateLimits")
+	for namespace, limit := range r.NamespaceRateLimits {
+		if limit.QPS <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(namespace).Child("qps"), limit.QPS, "qps must be positive"))
+		}
+		if limit.Burst < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(namespace).Child("burst"), limit.Burst, "burst can not be negative"))
+		}
+	}
+	return allErrs
+}
+
 // ValidateKubeAPIConfig validates `k` and returns an errorList if it is invalid
 func ValidateKubeAPIConfig(k v1alpha1.KubeAPIConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
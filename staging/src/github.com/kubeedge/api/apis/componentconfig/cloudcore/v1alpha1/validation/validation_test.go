@@ -225,6 +225,57 @@ func TestValidateModuleCloudHub(t *testing.T) {
 			expected: field.ErrorList{field.Invalid(field.NewPath("TokenRefreshDuration"),
 				time.Duration(0), "TokenRefreshDuration must be positive")},
 		},
+		{
+			name: "case9 invalid quic idleTimeout",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:        10002,
+					Address:     "127.0.0.1",
+					IdleTimeout: -1,
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("idleTimeout"),
+				int32(-1), "idleTimeout must not be negative")},
+		},
+		{
+			name: "case10 session resumption without timeout",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:        10002,
+					Address:     "127.0.0.1",
+					IdleTimeout: 30,
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SessionResumption: &v1alpha1.CloudHubSessionResumption{
+					Enable: true,
+				},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("sessionResumption").Child("timeout"),
+				int32(0), "timeout must be positive")},
+		},
 	}
 
 	for _, c := range cases {
@@ -234,6 +285,123 @@ func TestValidateModuleCloudHub(t *testing.T) {
 	}
 }
 
+func TestValidateMessageQoS(t *testing.T) {
+	fldPath := field.NewPath("messageQoS")
+	cases := []struct {
+		name     string
+		input    v1alpha1.CloudHubMessageQoS
+		expected field.ErrorList
+	}{
+		{
+			name:     "default lanes",
+			input:    *v1alpha1.NewDefaultCloudCoreConfig().Modules.CloudHub.MessageQoS,
+			expected: field.ErrorList{},
+		},
+		{
+			name: "unknown priority",
+			input: v1alpha1.CloudHubMessageQoS{
+				Lanes: map[v1alpha1.MessagePriority]v1alpha1.MessageLane{"urgent": {Weight: 1}},
+			},
+			expected: field.ErrorList{field.NotSupported(fldPath.Child("lanes").Key("urgent"),
+				v1alpha1.MessagePriority("urgent"), []string{"control", "workload", "device", "bulk"})},
+		},
+		{
+			name: "negative lane config",
+			input: v1alpha1.CloudHubMessageQoS{
+				Lanes: map[v1alpha1.MessagePriority]v1alpha1.MessageLane{
+					v1alpha1.MessagePriorityBulk: {Weight: -1, QPS: -1, Burst: -1},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(fldPath.Child("lanes").Key("bulk").Child("weight"), int32(-1), "weight must not be negative"),
+				field.Invalid(fldPath.Child("lanes").Key("bulk").Child("qps"), float32(-1), "qps must not be negative"),
+				field.Invalid(fldPath.Child("lanes").Key("bulk").Child("burst"), int32(-1), "burst must not be negative"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := validateMessageQoS(c.input, fldPath); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestValidateBandwidthBudget(t *testing.T) {
+	fldPath := field.NewPath("bandwidthBudget")
+	cases := []struct {
+		name     string
+		input    v1alpha1.CloudHubBandwidthBudget
+		expected field.ErrorList
+	}{
+		{
+			name:     "default budget",
+			input:    *v1alpha1.NewDefaultCloudCoreConfig().Modules.CloudHub.BandwidthBudget,
+			expected: field.ErrorList{},
+		},
+		{
+			name: "negative budgets",
+			input: v1alpha1.CloudHubBandwidthBudget{
+				Enable:         true,
+				ReportInterval: 60,
+				NodeGroups:     map[string]v1alpha1.BandwidthBudget{"group": {BytesPerSecond: -1}},
+				Nodes:          map[string]v1alpha1.BandwidthBudget{"node": {BytesPerDay: -1}},
+			},
+			expected: field.ErrorList{
+				field.Invalid(fldPath.Child("nodeGroups").Key("group").Child("bytesPerSecond"), int64(-1), "bytesPerSecond must not be negative"),
+				field.Invalid(fldPath.Child("nodes").Key("node").Child("bytesPerDay"), int64(-1), "bytesPerDay must not be negative"),
+			},
+		},
+		{
+			name:  "no report interval",
+			input: v1alpha1.CloudHubBandwidthBudget{Enable: true},
+			expected: field.ErrorList{
+				field.Invalid(fldPath.Child("reportInterval"), int32(0), "reportInterval must be positive"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := validateBandwidthBudget(c.input, fldPath); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestValidateMessageBatch(t *testing.T) {
+	fldPath := field.NewPath("messageBatch")
+	cases := []struct {
+		name     string
+		input    v1alpha1.MessageBatch
+		expected field.ErrorList
+	}{
+		{
+			name:     "default batch",
+			input:    *v1alpha1.NewDefaultCloudCoreConfig().Modules.CloudHub.MessageBatch,
+			expected: field.ErrorList{},
+		},
+		{
+			name:     "disabled",
+			input:    v1alpha1.MessageBatch{},
+			expected: field.ErrorList{},
+		},
+		{
+			name:  "no limits",
+			input: v1alpha1.MessageBatch{Enable: true, WindowMilliseconds: 10},
+			expected: field.ErrorList{
+				field.Invalid(fldPath.Child("maxMessages"), int32(0), "maxMessages must be positive"),
+				field.Invalid(fldPath.Child("maxBytes"), int32(0), "maxBytes must be positive"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := validateMessageBatch(c.input, fldPath); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
 func TestValidateModuleEdgeController(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -437,6 +605,55 @@ func TestValidateModuleCloudStream(t *testing.T) {
 	}
 }
 
+func TestValidateModuleRouter(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha1.Router
+		expected field.ErrorList
+	}{
+		{
+			name: "case1 not enable",
+			input: v1alpha1.Router{
+				Enable:              false,
+				NamespaceRateLimits: map[string]v1alpha1.RouterRateLimit{"default": {QPS: 0}},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 invalid qps",
+			input: v1alpha1.Router{
+				Enable:              true,
+				NamespaceRateLimits: map[string]v1alpha1.RouterRateLimit{"default": {QPS: 0}},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("namespaceRateLimits").Key("default").Child("qps"),
+				int32(0), "qps must be positive")},
+		},
+		{
+			name: "case3 invalid burst",
+			input: v1alpha1.Router{
+				Enable:              true,
+				NamespaceRateLimits: map[string]v1alpha1.RouterRateLimit{"*": {QPS: 10, Burst: -1}},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("namespaceRateLimits").Key("*").Child("burst"),
+				int32(-1), "burst can not be negative")},
+		},
+		{
+			name: "case4 all ok",
+			input: v1alpha1.Router{
+				Enable:              true,
+				NamespaceRateLimits: map[string]v1alpha1.RouterRateLimit{"*": {QPS: 10, Burst: 20}},
+			},
+			expected: field.ErrorList{},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateModuleRouter(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
 func TestValidateKubeAPIConfig(t *testing.T) {
 	dir := t.TempDir()
 
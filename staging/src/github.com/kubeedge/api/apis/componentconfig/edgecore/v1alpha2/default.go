@@ -46,6 +46,17 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 			DriverName: DataBaseDriverName,
 			AliasName:  DataBaseAliasName,
 			DataSource: DataBaseDataSource,
+			Encryption: &DataBaseEncryption{
+				Enable:      false,
+				KeyProvider: DataBaseKeyProviderFile,
+				KeyFile:     DataBaseEncryptionKeyFile,
+				KMSTimeout:  3,
+			},
+			Maintenance: &DataBaseMaintenance{
+				IntegrityCheck:     true,
+				CompactionInterval: 24,
+				CompactionWindow:   "02:00-05:00",
+			},
 		},
 		Modules: &Modules{
 			Edged: &Edged{
@@ -84,6 +95,7 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 					ReadDeadline:     15,
 					Server:           net.JoinHostPort(localIP, "10001"),
 					WriteDeadline:    15,
+					IdleTimeout:      30,
 				},
 				WebSocket: &EdgeHubWebSocket{
 					Enable:           true,
@@ -98,6 +110,12 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 				}).String(),
 				Token:              "",
 				RotateCertificates: true,
+				MessageBatch: &MessageBatch{
+					Enable:             false,
+					WindowMilliseconds: 10,
+					MaxMessages:        100,
+					MaxBytes:           65536,
+				},
 			},
 			EventBus: &EventBus{
 				Enable:               true,
@@ -111,12 +129,28 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 				MqttUsername:         "",
 				MqttPassword:         "",
 				MqttMode:             MqttModeExternal,
+				MqttProtocolVersion:  MqttProtocolVersion311,
 				TLS: &EventBusTLS{
 					Enable:                false,
 					TLSMqttCAFile:         constants.DefaultMqttCAFile,
 					TLSMqttCertFile:       constants.DefaultMqttCertFile,
 					TLSMqttPrivateKeyFile: constants.DefaultMqttKeyFile,
 				},
+				MqttAuth: &EventBusMqttAuth{
+					Enable:    false,
+					MapperACL: true,
+				},
+				MqttPersistence: &EventBusMqttPersistence{
+					Enable:                false,
+					MaxInflightMessages:   1024,
+					MaxRetainedMessages:   1024,
+					MaxMessageSize:        65536,
+					SessionExpiryInterval: 86400,
+				},
+				MqttBridge: &EventBusMqttBridge{
+					Enable:     false,
+					BufferSize: 1000,
+				},
 			},
 			MetaManager: &MetaManager{
 				Enable:             true,
@@ -131,13 +165,20 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 					TLSPrivateKeyFile:     constants.DefaultKeyFile,
 					ServiceAccountIssuers: []string{constants.DefaultServiceAccountIssuer},
 					DummyServer:           constants.DefaultDummyServerAddr,
+					OfflineWrite: &MetaServerOfflineWrite{
+						Enable:           false,
+						Resources:        []string{"configmaps", "leases.coordination.k8s.io", "events"},
+						MaxPendingWrites: 1000,
+						ReconcilePeriod:  10,
+					},
 				},
 			},
 			ServiceBus: &ServiceBus{
-				Enable:  false,
-				Server:  "127.0.0.1",
-				Port:    9060,
-				Timeout: 60,
+				Enable:    false,
+				Server:    "127.0.0.1",
+				Port:      9060,
+				ProxyPort: 9061,
+				Timeout:   60,
 			},
 			DeviceTwin: &DeviceTwin{
 				Enable:      true,
@@ -10,6 +10,8 @@ const (
 
 	// DataBaseDataSource is edge.db
 	DataBaseDataSource = "/var/lib/kubeedge/edgecore.db"
+	// DataBaseEncryptionKeyFile is the key file to encrypt edge.db
+	DataBaseEncryptionKeyFile = "/etc/kubeedge/db/encryption.key"
 
 	DefaultCgroupDriver         = "cgroupfs"
 	DefaultCgroupsPerQOS        = true
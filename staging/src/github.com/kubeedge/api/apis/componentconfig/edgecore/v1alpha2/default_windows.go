@@ -8,6 +8,8 @@ const (
 
 	// DataBaseDataSource is edge.db
 	DataBaseDataSource = "C:\\var\\lib\\kubeedge\\edgecore.db"
+	// DataBaseEncryptionKeyFile is the key file to encrypt edge.db
+	DataBaseEncryptionKeyFile = "C:\\etc\\kubeedge\\db\\encryption.key"
 
 	DefaultCgroupDriver         = ""
 	DefaultCgroupsPerQOS        = false
@@ -19,6 +19,8 @@ package v1alpha2
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"sigs.k8s.io/yaml"
 )
@@ -50,3 +52,21 @@ func (c *EdgeCoreConfig) WriteTo(filename string) error {
 	// Write the file using the original file permissions.
 	return os.WriteFile(filename, data, fileInfo.Mode())
 }
+
+// ParseCompactionWindow returns the start and end of CompactionWindow as the offsets
+// from midnight, the end is before the start if the window spans midnight
+func (m *DataBaseMaintenance) ParseCompactionWindow() (time.Duration, time.Duration, error) {
+	parts := strings.Split(m.CompactionWindow, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("compaction window %q is not in the format of HH:MM-HH:MM", m.CompactionWindow)
+	}
+	var offsets [2]time.Duration
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, fmt.Errorf("compaction window %q is not in the format of HH:MM-HH:MM", m.CompactionWindow)
+		}
+		offsets[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return offsets[0], offsets[1], nil
+}
@@ -31,13 +31,44 @@ const (
 	MqttModeExternal MqttMode = 2
 )
 
+const (
+	MqttProtocolVersion311 uint8 = 4
+	MqttProtocolVersion5   uint8 = 5
+)
+
+// MqttBridgeDirection indicates the direction of messages forwarded by the mqtt bridge
+type MqttBridgeDirection string
+
+const (
+	// MqttBridgeDirectionOut forwards the messages from internal mqtt broker to remote mqtt broker
+	MqttBridgeDirectionOut MqttBridgeDirection = "out"
+	// MqttBridgeDirectionIn forwards the messages from remote mqtt broker to internal mqtt broker
+	MqttBridgeDirectionIn MqttBridgeDirection = "in"
+	// MqttBridgeDirectionBoth forwards the messages in both directions
+	MqttBridgeDirectionBoth MqttBridgeDirection = "both"
+)
+
 const (
 	// DataBaseDriverName is sqlite3
 	DataBaseDriverName = "sqlite3"
+	// DataBaseDriverBbolt is bbolt, an embedded key/value store
+	DataBaseDriverBbolt = "bbolt"
 	// DataBaseAliasName is default
 	DataBaseAliasName = "default"
 )
 
+// DataBaseKeyProvider indicates where the key encryption key of database is sourced from
+type DataBaseKeyProvider string
+
+const (
+	// DataBaseKeyProviderFile reads the key from a local file
+	DataBaseKeyProviderFile DataBaseKeyProvider = "file"
+	// DataBaseKeyProviderTPM unseals the key from a sealed blob which is bound to the node
+	DataBaseKeyProviderTPM DataBaseKeyProvider = "tpm"
+	// DataBaseKeyProviderKMS wraps the key by a KMS v2 plugin
+	DataBaseKeyProviderKMS DataBaseKeyProvider = "kms"
+)
+
 type ProtocolName string
 type MqttMode int
 
@@ -58,7 +89,8 @@ type EdgeCoreConfig struct {
 
 // DataBase indicates the database info
 type DataBase struct {
-	// DriverName indicates database driver name
+	// DriverName indicates database driver name, "sqlite3" or "bbolt".
+	// The metadata can be migrated between the drivers by "keadm debug migrate-db"
 	// default "sqlite3"
 	DriverName string `json:"driverName,omitempty"`
 	// AliasName indicates alias name
@@ -67,6 +99,51 @@ type DataBase struct {
 	// DataSource indicates the data source path
 	// default "/var/lib/kubeedge/edgecore.db"
 	DataSource string `json:"dataSource,omitempty"`
+	// Encryption indicates the encryption at rest of the secrets and service account tokens saved in database
+	// +optional
+	Encryption *DataBaseEncryption `json:"encryption,omitempty"`
+	// Maintenance indicates the integrity check and compaction of database
+	// +optional
+	Maintenance *DataBaseMaintenance `json:"maintenance,omitempty"`
+}
+
+// DataBaseMaintenance indicates the integrity check and compaction of database
+type DataBaseMaintenance struct {
+	// IntegrityCheck indicates whether to check the integrity of database when edgecore starts.
+	// The corrupt database is quarantined, and a new one is created and resynced with the cloud.
+	// default true
+	IntegrityCheck bool `json:"integrityCheck"`
+	// CompactionInterval indicates the interval in hours to compact database, 0 disables the compaction
+	// default 24
+	CompactionInterval int32 `json:"compactionInterval,omitempty"`
+	// CompactionWindow indicates the daily time window in local time when database can be compacted,
+	// in the format of "HH:MM-HH:MM", e.g. "02:00-05:00". The database can be compacted at any time if it is empty.
+	// default "02:00-05:00"
+	CompactionWindow string `json:"compactionWindow,omitempty"`
+}
+
+// DataBaseEncryption indicates the encryption at rest of database.
+// The values are encrypted by a data encryption key, which is saved in database
+// after wrapped by the key encryption key sourced from KeyProvider.
+type DataBaseEncryption struct {
+	// Enable indicates whether the secrets and service account tokens are encrypted in database.
+	// The values saved in plain text are encrypted when edgecore starts, and the encrypted
+	// values are decrypted back when it is disabled later, so KeyProvider must be kept available.
+	// default false
+	Enable bool `json:"enable"`
+	// KeyProvider indicates where the key encryption key is sourced from, "file", "tpm" or "kms"
+	// default "file"
+	KeyProvider DataBaseKeyProvider `json:"keyProvider,omitempty"`
+	// KeyFile indicates the file of the key encryption key for "file" provider,
+	// or the sealed blob of it for "tpm" provider. A new key is generated if the file does not exist.
+	// default "/etc/kubeedge/db/encryption.key"
+	KeyFile string `json:"keyFile,omitempty"`
+	// KMSEndpoint indicates the endpoint of KMS v2 plugin for "kms" provider,
+	// e.g. "unix:///var/run/kmsplugin/socket.sock"
+	KMSEndpoint string `json:"kmsEndpoint,omitempty"`
+	// KMSTimeout indicates the timeout in seconds of the calls to KMS v2 plugin
+	// default 3
+	KMSTimeout int32 `json:"kmsTimeout,omitempty"`
 }
 
 // Modules indicates the modules which edgeCore will be used
@@ -902,6 +979,29 @@ type EdgeHub struct {
 	// RotateCertificates indicates whether edge certificate can be rotated
 	// default true
 	RotateCertificates bool `json:"rotateCertificates,omitempty"`
+	// ReportBandwidthUsage indicates whether the bytes sent to cloudHub are reported to it at every
+	// heartbeat, they are counted into the bandwidth budget of the node
+	// default false
+	ReportBandwidthUsage bool `json:"reportBandwidthUsage,omitempty"`
+	// MessageBatch indicates how the messages sent to cloudHub are coalesced into batches,
+	// they are only batched if cloudHub tells that it is able to unpack them when connecting
+	MessageBatch *MessageBatch `json:"messageBatch,omitempty"`
+}
+
+// MessageBatch indicates how the messages sent in a short window are coalesced into one frame
+type MessageBatch struct {
+	// Enable indicates whether the messages are batched
+	// default false
+	Enable bool `json:"enable"`
+	// WindowMilliseconds is how long (millisecond) a message waits for the following messages
+	// default 10
+	WindowMilliseconds int32 `json:"windowMilliseconds,omitempty"`
+	// MaxMessages is the max number of messages in a batch
+	// default 100
+	MaxMessages int32 `json:"maxMessages,omitempty"`
+	// MaxBytes is the max size (byte) of a batch, the message larger than it is sent alone
+	// default 65536
+	MaxBytes int32 `json:"maxBytes,omitempty"`
 }
 
 // EdgeHubQUIC indicates the quic client config
@@ -921,6 +1021,10 @@ type EdgeHubQUIC struct {
 	// WriteDeadline indicates write deadline (second)
 	// default 15
 	WriteDeadline int32 `json:"writeDeadline,omitempty"`
+	// IdleTimeout indicates how long (second) the quic session is kept without any incoming packet,
+	// keepalive packets are sent at half of it, a shorter one detects a dead path sooner on mobile links
+	// default 30
+	IdleTimeout int32 `json:"idleTimeout,omitempty"`
 }
 
 // EdgeHubWebSocket indicates the websocket client config
@@ -986,8 +1090,18 @@ type EventBus struct {
 	// +Required
 	// default: 2
 	MqttMode MqttMode `json:"mqttMode"`
+	// MqttProtocolVersion indicates the mqtt protocol version of client connecting to external mqtt broker
+	// 4: MQTT 3.1.1, 5: MQTT 5, the internal mqtt broker accepts both of them
+	// default 4
+	MqttProtocolVersion uint8 `json:"mqttProtocolVersion,omitempty"`
 	// Tls indicates tls config for EventBus module
 	TLS *EventBusTLS `json:"eventBusTLS,omitempty"`
+	// MqttAuth indicates the authentication and topic ACLs of clients connecting to internal mqtt broker
+	MqttAuth *EventBusMqttAuth `json:"mqttAuth,omitempty"`
+	// MqttPersistence indicates the persistence of sessions and messages of internal mqtt broker
+	MqttPersistence *EventBusMqttPersistence `json:"mqttPersistence,omitempty"`
+	// MqttBridge indicates the bridge forwarding the messages between internal mqtt broker and a remote mqtt broker
+	MqttBridge *EventBusMqttBridge `json:"mqttBridge,omitempty"`
 }
 
 // EventBusTLS indicates the EventBus tls config with MQTT broker
@@ -1006,6 +1120,143 @@ type EventBusTLS struct {
 	TLSMqttPrivateKeyFile string `json:"tlsMqttPrivateKeyFile,omitempty"`
 }
 
+// EventBusMqttAuth indicates the authentication and topic ACLs of internal mqtt broker
+type EventBusMqttAuth struct {
+	// Enable indicates whether the clients of internal mqtt broker need to be authenticated,
+	// if set to true, the clients can only publish and subscribe the topics allowed by ACLs
+	// default false
+	Enable bool `json:"enable"`
+	// Users indicates the clients authenticated with username and password
+	Users []EventBusMqttUser `json:"users,omitempty"`
+	// ServiceAccountToken indicates whether the pods can connect with the username
+	// "system:serviceaccount:<namespace>:<name>" and the token of the service account as password,
+	// the token is verified with the service account configs of MetaServer
+	// default false
+	ServiceAccountToken bool `json:"serviceAccountToken,omitempty"`
+	// MapperACL indicates whether the registered mappers, connecting with the mapper name as username,
+	// are allowed to publish and subscribe the topics of devices using the protocol of the mapper
+	// default true
+	MapperACL bool `json:"mapperACL,omitempty"`
+	// ACLs indicates the topics which the clients are allowed to publish and subscribe
+	ACLs []EventBusMqttACL `json:"acls,omitempty"`
+	// TLSCertFile indicates the file containing x509 Certificate of internal mqtt broker,
+	// the broker listens with TLS if it is set
+	// default ""
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	// TLSPrivateKeyFile indicates the file containing x509 private key matching tlsCertFile
+	// default ""
+	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
+	// TLSClientCAFile indicates the ca file verifying the client certificates, if it is set, the clients
+	// must connect with the certificates and the common name of certificate is used as username.
+	// It is not supported if the internal broker listens on websocket
+	// default ""
+	TLSClientCAFile string `json:"tlsClientCAFile,omitempty"`
+}
+
+// EventBusMqttPersistence indicates the persistence of internal mqtt broker in the edge database,
+// the sessions, subscriptions, retained and inflight QoS 1/2 messages are restored after edgecore restarts
+type EventBusMqttPersistence struct {
+	// Enable indicates whether the internal mqtt broker persists its sessions and messages
+	// default false
+	Enable bool `json:"enable"`
+	// MaxInflightMessages indicates the max number of inflight QoS 1/2 messages of each client,
+	// the messages exceeding the limit are dropped
+	// default 1024
+	MaxInflightMessages int32 `json:"maxInflightMessages,omitempty"`
+	// MaxRetainedMessages indicates the max number of retained messages persisted
+	// default 1024
+	MaxRetainedMessages int32 `json:"maxRetainedMessages,omitempty"`
+	// MaxMessageSize indicates the max payload size in bytes of messages persisted
+	// default 65536
+	MaxMessageSize int32 `json:"maxMessageSize,omitempty"`
+	// SessionExpiryInterval indicates the max seconds the sessions of disconnected clients are kept
+	// default 86400
+	SessionExpiryInterval int32 `json:"sessionExpiryInterval,omitempty"`
+}
+
+// EventBusMqttBridge indicates the bridge between internal mqtt broker and a remote mqtt broker,
+// the bridge connects to the remote mqtt broker with MQTT 5
+type EventBusMqttBridge struct {
+	// Enable indicates whether the messages of internal mqtt broker are bridged to the remote mqtt broker,
+	// it requires the internal mqtt broker to be enabled
+	// default false
+	Enable bool `json:"enable"`
+	// RemoteServer indicates the url of remote mqtt broker, the schemes tcp, mqtt, ssl, tls, ws and wss are supported
+	// default ""
+	RemoteServer string `json:"remoteServer,omitempty"`
+	// ClientID indicates the client id of bridge connecting to the remote mqtt broker,
+	// "kubeedge-bridge-<node name>" is used if it is not set
+	// default ""
+	ClientID string `json:"clientID,omitempty"`
+	// Username indicates the username of bridge connecting to the remote mqtt broker
+	// default ""
+	Username string `json:"username,omitempty"`
+	// Password indicates the password of bridge connecting to the remote mqtt broker
+	// default ""
+	Password string `json:"password,omitempty"`
+	// TLSCAFile indicates the ca file verifying the certificate of remote mqtt broker,
+	// the system ca certificates are used if it is not set
+	// default ""
+	TLSCAFile string `json:"tlsCAFile,omitempty"`
+	// TLSCertFile indicates the file containing x509 Certificate of bridge
+	// default ""
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	// TLSPrivateKeyFile indicates the file containing x509 private key matching tlsCertFile
+	// default ""
+	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
+	// BufferSize indicates the max number of messages buffered while the remote mqtt broker is unreachable,
+	// the oldest messages are dropped when the buffer is full. The buffer is in memory unless MqttPersistence
+	// is enabled, then the buffered QoS 1/2 messages are persisted in the edge database
+	// default 1000
+	BufferSize int32 `json:"bufferSize,omitempty"`
+	// Topics indicates the topics forwarded by the bridge
+	Topics []EventBusMqttBridgeTopic `json:"topics,omitempty"`
+}
+
+// EventBusMqttBridgeTopic indicates the topics forwarded by the mqtt bridge, the topic
+// "<LocalPrefix><Topic>" of internal mqtt broker is remapped to "<RemotePrefix><Topic>" of
+// remote mqtt broker, and vice versa
+type EventBusMqttBridgeTopic struct {
+	// Topic indicates the topic filter forwarded, wildcards "+" and "#" are supported
+	Topic string `json:"topic"`
+	// Direction indicates the direction of messages forwarded, "out", "in" or "both"
+	// default "out"
+	Direction MqttBridgeDirection `json:"direction,omitempty"`
+	// QoS indicates the qos of messages forwarded
+	// 0: QOSAtMostOnce, 1: QOSAtLeastOnce, 2: QOSExactlyOnce
+	// default 0
+	QoS uint8 `json:"qos,omitempty"`
+	// LocalPrefix indicates the topic prefix in internal mqtt broker
+	// default ""
+	LocalPrefix string `json:"localPrefix,omitempty"`
+	// RemotePrefix indicates the topic prefix in remote mqtt broker
+	// default ""
+	RemotePrefix string `json:"remotePrefix,omitempty"`
+}
+
+// EventBusMqttUser indicates the client of internal mqtt broker authenticated with username and password
+type EventBusMqttUser struct {
+	// Username indicates the username of client
+	Username string `json:"username"`
+	// Password indicates the password of client
+	Password string `json:"password"`
+}
+
+// EventBusMqttACL indicates the topics which the clients of internal mqtt broker are allowed to access
+type EventBusMqttACL struct {
+	// Usernames indicates the clients the ACL applies to, shell patterns such as
+	// "system:serviceaccount:default:*" are supported
+	Usernames []string `json:"usernames"`
+	// Publish indicates the topic filters the clients are allowed to publish to,
+	// "%u" and "%c" in filters are replaced with the username and client id, the filters
+	// never match if they are empty or contain "+", "#" or "/"
+	Publish []string `json:"publish,omitempty"`
+	// Subscribe indicates the topic filters the clients are allowed to subscribe,
+	// "%u" and "%c" in filters are replaced with the username and client id, the filters
+	// never match if they are empty or contain "+", "#" or "/"
+	Subscribe []string `json:"subscribe,omitempty"`
+}
+
 // MetaManager indicates the MetaManager module config
 type MetaManager struct {
 	// Enable indicates whether MetaManager is enabled,
@@ -1035,6 +1286,28 @@ type MetaServer struct {
 	// DummyServer defines the IP address of dummy interface and port
 	// that MetaServer listen on for edge pods to connect, format: ip:port
 	DummyServer string `json:"dummyServer"`
+	// OfflineWrite indicates the writes served by MetaServer when EdgeCore is disconnected from CloudCore
+	// +optional
+	OfflineWrite *MetaServerOfflineWrite `json:"offlineWrite,omitempty"`
+}
+
+// MetaServerOfflineWrite indicates the config of offline writes in MetaServer.
+// The writes to the configured resources are applied to the local cache while EdgeCore
+// is disconnected from CloudCore, and they are reconciled with the cloud after reconnected.
+type MetaServerOfflineWrite struct {
+	// Enable indicates whether the offline writes are enabled
+	// default false
+	Enable bool `json:"enable"`
+	// Resources indicates the resources which can be written offline,
+	// format: <resource>[.<group>], e.g. configmaps, leases.coordination.k8s.io
+	// default configmaps, leases.coordination.k8s.io, events
+	Resources []string `json:"resources,omitempty"`
+	// MaxPendingWrites indicates the max number of offline writes waiting to be reconciled
+	// default 1000
+	MaxPendingWrites int32 `json:"maxPendingWrites,omitempty"`
+	// ReconcilePeriod indicates the period to reconcile the offline writes with cloud (second)
+	// default 10
+	ReconcilePeriod int32 `json:"reconcilePeriod,omitempty"`
 }
 
 // ServiceBus indicates the ServiceBus module config
@@ -1047,6 +1320,13 @@ type ServiceBus struct {
 	Server string `json:"server"`
 	// Port indicates port for http server
 	Port int `json:"port"`
+	// ProxyPort indicates port for the http server which proxies requests of edge
+	// applications to cloud services, the request path is the path of servicebus rule.
+	// It is served separately so that it never shadows requests to Port.
+	// Set to 0 to disable the proxy.
+	// default 9061
+	// +optional
+	ProxyPort int `json:"proxyPort,omitempty"`
 	// Timeout indicates timeout for servicebus receive mseeage
 	Timeout int `json:"timeout"`
 	// TLSCertFile is the path to the PEM-encoded server certificate for the
@@ -18,9 +18,13 @@ package validation
 
 import (
 	"fmt"
+	"math"
+	"net/url"
 	"os"
 	"path"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/apis/core/validation"
@@ -54,6 +58,55 @@ func ValidateDataBase(db v1alpha2.DataBase) field.ErrorList {
 				fmt.Sprintf("create DataSoure dir %v error ", sourceDir)))
 		}
 	}
+	switch db.DriverName {
+	case "", v1alpha2.DataBaseDriverName, v1alpha2.DataBaseDriverBbolt:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("DriverName"), db.DriverName,
+			[]string{v1alpha2.DataBaseDriverName, v1alpha2.DataBaseDriverBbolt}))
+	}
+	if db.Encryption != nil {
+		allErrs = append(allErrs, validateDataBaseEncryption(*db.Encryption, field.NewPath("Encryption"))...)
+	}
+	if db.Maintenance != nil {
+		allErrs = append(allErrs, validateDataBaseMaintenance(*db.Maintenance, field.NewPath("Maintenance"))...)
+	}
+	return allErrs
+}
+
+func validateDataBaseMaintenance(m v1alpha2.DataBaseMaintenance, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if m.CompactionInterval < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("CompactionInterval"), m.CompactionInterval, "must be greater than or equal to 0"))
+	}
+	if m.CompactionWindow != "" {
+		if _, _, err := m.ParseCompactionWindow(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("CompactionWindow"), m.CompactionWindow, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateDataBaseEncryption(e v1alpha2.DataBaseEncryption, fldPath *field.Path) field.ErrorList {
+	if !e.Enable {
+		return field.ErrorList{}
+	}
+	allErrs := field.ErrorList{}
+	switch e.KeyProvider {
+	case v1alpha2.DataBaseKeyProviderFile, v1alpha2.DataBaseKeyProviderTPM:
+		if e.KeyFile == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("KeyFile"), fmt.Sprintf("KeyFile is required by %s key provider", e.KeyProvider)))
+		}
+	case v1alpha2.DataBaseKeyProviderKMS:
+		if e.KMSEndpoint == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("KMSEndpoint"), "KMSEndpoint is required by kms key provider"))
+		}
+		if e.KMSTimeout <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("KMSTimeout"), e.KMSTimeout, "must be greater than 0"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("KeyProvider"), e.KeyProvider,
+			[]string{string(v1alpha2.DataBaseKeyProviderFile), string(v1alpha2.DataBaseKeyProviderTPM), string(v1alpha2.DataBaseKeyProviderKMS)}))
+	}
 	return allErrs
 }
 
@@ -98,6 +151,33 @@ func ValidateModuleEdgeHub(h v1alpha2.EdgeHub) field.ErrorList {
 			"MessageBurst must not be a negative number"))
 	}
 
+	if h.Quic.IdleTimeout < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("quic").Child("idleTimeout"), h.Quic.IdleTimeout,
+			"idleTimeout must not be negative"))
+	}
+
+	if h.MessageBatch != nil {
+		allErrs = append(allErrs, validateMessageBatch(*h.MessageBatch, field.NewPath("messageBatch"))...)
+	}
+
+	return allErrs
+}
+
+// validateMessageBatch validates `b` and returns an errorList if it is invalid
+func validateMessageBatch(b v1alpha2.MessageBatch, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !b.Enable {
+		return allErrs
+	}
+	if b.WindowMilliseconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("windowMilliseconds"), b.WindowMilliseconds, "windowMilliseconds must be positive"))
+	}
+	if b.MaxMessages <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxMessages"), b.MaxMessages, "maxMessages must be positive"))
+	}
+	if b.MaxBytes <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxBytes"), b.MaxBytes, "maxBytes must be positive"))
+	}
 	return allErrs
 }
 
@@ -112,15 +192,220 @@ func ValidateModuleEventBus(m v1alpha2.EventBus) field.ErrorList {
 			fmt.Sprintf("Mode need in [%v,%v] range", v1alpha2.MqttModeInternal,
 				v1alpha2.MqttModeExternal)))
 	}
+	switch m.MqttProtocolVersion {
+	case 0, v1alpha2.MqttProtocolVersion311, v1alpha2.MqttProtocolVersion5:
+	default:
+		allErrs = append(allErrs, field.Invalid(field.NewPath("MqttProtocolVersion"), m.MqttProtocolVersion,
+			fmt.Sprintf("MqttProtocolVersion need to be %v or %v", v1alpha2.MqttProtocolVersion311,
+				v1alpha2.MqttProtocolVersion5)))
+	}
+	if m.MqttAuth != nil && m.MqttAuth.Enable {
+		allErrs = append(allErrs, validateEventBusMqttAuth(*m.MqttAuth, field.NewPath("MqttAuth"))...)
+	}
+	if m.MqttBridge != nil && m.MqttBridge.Enable {
+		if m.MqttMode > v1alpha2.MqttModeBoth {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("MqttBridge").Child("Enable"), m.MqttBridge.Enable,
+				"MqttBridge need the internal mqtt broker, Mode need to be 0 or 1"))
+		}
+		allErrs = append(allErrs, validateEventBusMqttBridge(*m.MqttBridge, field.NewPath("MqttBridge"))...)
+	}
+	if m.MqttPersistence != nil && m.MqttPersistence.Enable {
+		p := m.MqttPersistence
+		fldPath := field.NewPath("MqttPersistence")
+		if p.MaxInflightMessages <= 0 || p.MaxInflightMessages > math.MaxUint16 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxInflightMessages"), p.MaxInflightMessages,
+				fmt.Sprintf("MaxInflightMessages need in [1,%v] range", math.MaxUint16)))
+		}
+		for _, f := range []struct {
+			name  string
+			value int32
+		}{
+			{"MaxRetainedMessages", p.MaxRetainedMessages},
+			{"MaxMessageSize", p.MaxMessageSize},
+			{"SessionExpiryInterval", p.SessionExpiryInterval},
+		} {
+			if f.value <= 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child(f.name), f.value,
+					fmt.Sprintf("%s need to be positive", f.name)))
+			}
+		}
+	}
 	return allErrs
 }
 
+// validateEventBusMqttBridge validates the remote mqtt broker and topics of mqtt bridge
+func validateEventBusMqttBridge(b v1alpha2.EventBusMqttBridge, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if u, err := url.Parse(b.RemoteServer); err != nil || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("RemoteServer"), b.RemoteServer, "invalid mqtt url"))
+	} else {
+		switch strings.ToLower(u.Scheme) {
+		case "tcp", "mqtt", "ssl", "tls", "ws", "wss":
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("RemoteServer"), b.RemoteServer,
+				fmt.Sprintf("unsupported scheme %q", u.Scheme)))
+		}
+	}
+	if (b.TLSCertFile == "") != (b.TLSPrivateKeyFile == "") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("TLSCertFile"), b.TLSCertFile,
+			"TLSCertFile and TLSPrivateKeyFile need to be set together"))
+	}
+	for _, f := range []struct{ name, file string }{
+		{"TLSCAFile", b.TLSCAFile},
+		{"TLSCertFile", b.TLSCertFile},
+		{"TLSPrivateKeyFile", b.TLSPrivateKeyFile},
+	} {
+		if f.file != "" && !utilvalidation.FileIsExist(f.file) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(f.name), f.file, fmt.Sprintf("%v not exist", f.file)))
+		}
+	}
+	if b.BufferSize <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("BufferSize"), b.BufferSize, "BufferSize need to be positive"))
+	}
+
+	if len(b.Topics) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("Topics"), ""))
+	}
+	for i, t := range b.Topics {
+		topicPath := fldPath.Child("Topics").Index(i)
+		if !isValidMqttTopicFilter(t.Topic) {
+			allErrs = append(allErrs, field.Invalid(topicPath.Child("Topic"), t.Topic, "invalid mqtt topic filter"))
+		}
+		for _, prefix := range []struct{ name, value string }{
+			{"LocalPrefix", t.LocalPrefix},
+			{"RemotePrefix", t.RemotePrefix},
+		} {
+			if strings.ContainsAny(prefix.value, "+#") {
+				allErrs = append(allErrs, field.Invalid(topicPath.Child(prefix.name), prefix.value,
+					"wildcards are not allowed in topic prefix"))
+			}
+		}
+		switch t.Direction {
+		case "", v1alpha2.MqttBridgeDirectionOut, v1alpha2.MqttBridgeDirectionIn, v1alpha2.MqttBridgeDirectionBoth:
+		default:
+			allErrs = append(allErrs, field.NotSupported(topicPath.Child("Direction"), t.Direction,
+				[]string{string(v1alpha2.MqttBridgeDirectionOut), string(v1alpha2.MqttBridgeDirectionIn),
+					string(v1alpha2.MqttBridgeDirectionBoth)}))
+		}
+		if t.QoS > 2 {
+			allErrs = append(allErrs, field.Invalid(topicPath.Child("QoS"), t.QoS, "QoS need in [0,2] range"))
+		}
+	}
+	return allErrs
+}
+
+// validateEventBusMqttAuth validates the authentication and ACLs of internal mqtt broker
+func validateEventBusMqttAuth(a v1alpha2.EventBusMqttAuth, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(a.Users) == 0 && !a.ServiceAccountToken && a.TLSClientCAFile == "" {
+		allErrs = append(allErrs, field.Required(fldPath,
+			"at least one of Users, ServiceAccountToken and TLSClientCAFile need to be set"))
+	}
+
+	usernames := make(map[string]bool, len(a.Users))
+	for i, user := range a.Users {
+		if user.Username == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("Users").Index(i).Child("Username"), ""))
+			continue
+		}
+		if usernames[user.Username] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("Users").Index(i).Child("Username"), user.Username))
+		}
+		usernames[user.Username] = true
+	}
+
+	if (a.TLSCertFile == "") != (a.TLSPrivateKeyFile == "") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("TLSCertFile"), a.TLSCertFile,
+			"TLSCertFile and TLSPrivateKeyFile need to be set together"))
+	}
+	if a.TLSClientCAFile != "" && a.TLSCertFile == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("TLSCertFile"),
+			"TLSCertFile need to be set when TLSClientCAFile is set"))
+	}
+	for _, f := range []struct{ name, file string }{
+		{"TLSCertFile", a.TLSCertFile},
+		{"TLSPrivateKeyFile", a.TLSPrivateKeyFile},
+		{"TLSClientCAFile", a.TLSClientCAFile},
+	} {
+		if f.file != "" && !utilvalidation.FileIsExist(f.file) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(f.name), f.file, fmt.Sprintf("%v not exist", f.file)))
+		}
+	}
+
+	for i, acl := range a.ACLs {
+		aclPath := fldPath.Child("ACLs").Index(i)
+		if len(acl.Usernames) == 0 {
+			allErrs = append(allErrs, field.Required(aclPath.Child("Usernames"), ""))
+		}
+		for j, username := range acl.Usernames {
+			if _, err := path.Match(username, ""); err != nil {
+				allErrs = append(allErrs, field.Invalid(aclPath.Child("Usernames").Index(j), username, err.Error()))
+			}
+		}
+		for j, filter := range acl.Publish {
+			if !isValidMqttTopicFilter(filter) {
+				allErrs = append(allErrs, field.Invalid(aclPath.Child("Publish").Index(j), filter, "invalid mqtt topic filter"))
+			}
+		}
+		for j, filter := range acl.Subscribe {
+			if !isValidMqttTopicFilter(filter) {
+				allErrs = append(allErrs, field.Invalid(aclPath.Child("Subscribe").Index(j), filter, "invalid mqtt topic filter"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// isValidMqttTopicFilter checks the wildcards of mqtt topic filter, "#" can only be the last
+// level and "+" must occupy an entire level
+func isValidMqttTopicFilter(filter string) bool {
+	if filter == "" {
+		return false
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidateModuleMetaManager validates `m` and returns an errorList if it is invalid
 func ValidateModuleMetaManager(m v1alpha2.MetaManager) field.ErrorList {
 	if !m.Enable {
 		return field.ErrorList{}
 	}
 	allErrs := field.ErrorList{}
+	if m.MetaServer != nil && m.MetaServer.OfflineWrite != nil {
+		allErrs = append(allErrs, validateMetaServerOfflineWrite(*m.MetaServer.OfflineWrite,
+			field.NewPath("Modules", "MetaManager", "MetaServer", "OfflineWrite"))...)
+	}
+	return allErrs
+}
+
+func validateMetaServerOfflineWrite(w v1alpha2.MetaServerOfflineWrite, fldPath *field.Path) field.ErrorList {
+	if !w.Enable {
+		return field.ErrorList{}
+	}
+	allErrs := field.ErrorList{}
+	if len(w.Resources) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("Resources"), "at least one resource is required"))
+	}
+	for i, r := range w.Resources {
+		if schema.ParseGroupResource(r).Resource == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Resources").Index(i), r, "must be in the format <resource>[.<group>]"))
+		}
+	}
+	if w.MaxPendingWrites <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxPendingWrites"), w.MaxPendingWrites, "must be greater than 0"))
+	}
+	if w.ReconcilePeriod <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ReconcilePeriod"), w.ReconcilePeriod, "must be greater than 0"))
+	}
 	return allErrs
 }
 
@@ -130,6 +415,10 @@ func ValidateModuleServiceBus(s v1alpha2.ServiceBus) field.ErrorList {
 		return field.ErrorList{}
 	}
 	allErrs := field.ErrorList{}
+	if s.ProxyPort != 0 && s.ProxyPort == s.Port {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("ProxyPort"), s.ProxyPort,
+			"ProxyPort must be different from Port"))
+	}
 	return allErrs
 }
 
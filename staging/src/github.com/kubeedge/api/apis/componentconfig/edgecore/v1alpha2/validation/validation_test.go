@@ -71,6 +71,102 @@ func TestValidateDataBase(t *testing.T) {
 	}
 }
 
+func TestValidateDataBaseDriverName(t *testing.T) {
+	dataSource := filepath.Join(t.TempDir(), "edgecore.db")
+	for _, driver := range []string{"", v1alpha2.DataBaseDriverName, v1alpha2.DataBaseDriverBbolt} {
+		if errs := ValidateDataBase(v1alpha2.DataBase{DriverName: driver, DataSource: dataSource}); len(errs) > 0 {
+			t.Errorf("driver %q should be supported, err is %v", driver, errs)
+		}
+	}
+	if errs := ValidateDataBase(v1alpha2.DataBase{DriverName: "mysql", DataSource: dataSource}); len(errs) != 1 {
+		t.Errorf("driver mysql should not be supported, errs are %v", errs)
+	}
+}
+
+func TestValidateDataBaseEncryption(t *testing.T) {
+	cases := []struct {
+		name       string
+		encryption v1alpha2.DataBaseEncryption
+		wantErrs   int
+	}{
+		{
+			name:       "disabled",
+			encryption: v1alpha2.DataBaseEncryption{KeyProvider: "unknown"},
+		},
+		{
+			name: "file",
+			encryption: v1alpha2.DataBaseEncryption{
+				Enable:      true,
+				KeyProvider: v1alpha2.DataBaseKeyProviderFile,
+				KeyFile:     "/etc/kubeedge/db/encryption.key",
+			},
+		},
+		{
+			name:       "tpm without key file",
+			encryption: v1alpha2.DataBaseEncryption{Enable: true, KeyProvider: v1alpha2.DataBaseKeyProviderTPM},
+			wantErrs:   1,
+		},
+		{
+			name:       "kms without endpoint and timeout",
+			encryption: v1alpha2.DataBaseEncryption{Enable: true, KeyProvider: v1alpha2.DataBaseKeyProviderKMS},
+			wantErrs:   2,
+		},
+		{
+			name:       "unknown provider",
+			encryption: v1alpha2.DataBaseEncryption{Enable: true, KeyProvider: "unknown"},
+			wantErrs:   1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := v1alpha2.DataBase{
+				DataSource: filepath.Join(t.TempDir(), "edgecore.db"),
+				Encryption: &c.encryption,
+			}
+			if errs := ValidateDataBase(db); len(errs) != c.wantErrs {
+				t.Errorf("expected %d errors, got %v", c.wantErrs, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDataBaseMaintenance(t *testing.T) {
+	cases := []struct {
+		name        string
+		maintenance v1alpha2.DataBaseMaintenance
+		wantErrs    int
+	}{
+		{
+			name:        "default",
+			maintenance: v1alpha2.DataBaseMaintenance{IntegrityCheck: true, CompactionInterval: 24, CompactionWindow: "02:00-05:00"},
+		},
+		{
+			name:        "window spans midnight",
+			maintenance: v1alpha2.DataBaseMaintenance{CompactionInterval: 24, CompactionWindow: "23:30-01:00"},
+		},
+		{
+			name:        "any time",
+			maintenance: v1alpha2.DataBaseMaintenance{CompactionInterval: 1},
+		},
+		{
+			name:        "invalid window and interval",
+			maintenance: v1alpha2.DataBaseMaintenance{CompactionInterval: -1, CompactionWindow: "2am-5am"},
+			wantErrs:    2,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := v1alpha2.DataBase{
+				DataSource:  filepath.Join(t.TempDir(), "edgecore.db"),
+				Maintenance: &c.maintenance,
+			}
+			if errs := ValidateDataBase(db); len(errs) != c.wantErrs {
+				t.Errorf("expected %d errors, got %v", c.wantErrs, errs)
+			}
+		})
+	}
+}
+
 func TestValidateModuleEdged(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -203,6 +299,40 @@ func TestValidateModuleEdgeHub(t *testing.T) {
 			result: field.ErrorList{field.Invalid(field.NewPath("messageBurst"),
 				int32(-1), "MessageBurst must not be a negative number")},
 		},
+		{
+			name: "case6 message batch without window",
+			input: v1alpha2.EdgeHub{
+				Enable: true,
+				WebSocket: &v1alpha2.EdgeHubWebSocket{
+					Enable: true,
+				},
+				Quic: &v1alpha2.EdgeHubQUIC{
+					Enable: false,
+				},
+				MessageBatch: &v1alpha2.MessageBatch{
+					Enable:      true,
+					MaxMessages: 100,
+					MaxBytes:    65536,
+				},
+			},
+			result: field.ErrorList{field.Invalid(field.NewPath("messageBatch").Child("windowMilliseconds"),
+				int32(0), "windowMilliseconds must be positive")},
+		},
+		{
+			name: "case7 negative quic idleTimeout",
+			input: v1alpha2.EdgeHub{
+				Enable: true,
+				WebSocket: &v1alpha2.EdgeHubWebSocket{
+					Enable: false,
+				},
+				Quic: &v1alpha2.EdgeHubQUIC{
+					Enable:      true,
+					IdleTimeout: -1,
+				},
+			},
+			result: field.ErrorList{field.Invalid(field.NewPath("quic").Child("idleTimeout"),
+				int32(-1), "idleTimeout must not be negative")},
+		},
 	}
 
 	for _, c := range cases {
@@ -243,6 +373,145 @@ func TestValidateModuleEventBus(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 mqtt protocol version not right",
+			input: v1alpha2.EventBus{
+				Enable:              true,
+				MqttMode:            2,
+				MqttProtocolVersion: 3,
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("MqttProtocolVersion"), uint8(3),
+				fmt.Sprintf("MqttProtocolVersion need to be %v or %v", v1alpha2.MqttProtocolVersion311,
+					v1alpha2.MqttProtocolVersion5))},
+		},
+		{
+			name: "case4 mqtt 5",
+			input: v1alpha2.EventBus{
+				Enable:              true,
+				MqttMode:            2,
+				MqttProtocolVersion: v1alpha2.MqttProtocolVersion5,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case5 mqtt auth without authentication",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 2,
+				MqttAuth: &v1alpha2.EventBusMqttAuth{Enable: true},
+			},
+			expected: field.ErrorList{field.Required(field.NewPath("MqttAuth"),
+				"at least one of Users, ServiceAccountToken and TLSClientCAFile need to be set")},
+		},
+		{
+			name: "case6 mqtt auth invalid users and acls",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 2,
+				MqttAuth: &v1alpha2.EventBusMqttAuth{
+					Enable: true,
+					Users: []v1alpha2.EventBusMqttUser{
+						{Username: "mapper", Password: "p1"},
+						{Username: "mapper", Password: "p2"},
+					},
+					ACLs: []v1alpha2.EventBusMqttACL{
+						{
+							Usernames: []string{"mapper"},
+							Publish:   []string{"$hw/events/device/#/twin"},
+							Subscribe: []string{"$hw/events/device/+/twin/+"},
+						},
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Duplicate(field.NewPath("MqttAuth").Child("Users").Index(1).Child("Username"), "mapper"),
+				field.Invalid(field.NewPath("MqttAuth").Child("ACLs").Index(0).Child("Publish").Index(0),
+					"$hw/events/device/#/twin", "invalid mqtt topic filter"),
+			},
+		},
+		{
+			name: "case7 mqtt auth with client ca but no cert",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 2,
+				MqttAuth: &v1alpha2.EventBusMqttAuth{
+					Enable:          true,
+					TLSClientCAFile: "/tmp/not-exist-ca.crt",
+				},
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("MqttAuth").Child("TLSCertFile"),
+					"TLSCertFile need to be set when TLSClientCAFile is set"),
+				field.Invalid(field.NewPath("MqttAuth").Child("TLSClientCAFile"), "/tmp/not-exist-ca.crt",
+					"/tmp/not-exist-ca.crt not exist"),
+			},
+		},
+		{
+			name: "case8 mqtt persistence invalid limits",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 2,
+				MqttPersistence: &v1alpha2.EventBusMqttPersistence{
+					Enable:                true,
+					MaxInflightMessages:   70000,
+					MaxRetainedMessages:   1024,
+					MaxMessageSize:        0,
+					SessionExpiryInterval: 86400,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("MqttPersistence").Child("MaxInflightMessages"), int32(70000),
+					"MaxInflightMessages need in [1,65535] range"),
+				field.Invalid(field.NewPath("MqttPersistence").Child("MaxMessageSize"), int32(0),
+					"MaxMessageSize need to be positive"),
+			},
+		},
+		{
+			name: "case9 mqtt bridge valid",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 1,
+				MqttBridge: &v1alpha2.EventBusMqttBridge{
+					Enable:       true,
+					RemoteServer: "tls://broker.example.com:8883",
+					BufferSize:   1000,
+					Topics: []v1alpha2.EventBusMqttBridgeTopic{
+						{Topic: "devices/+/telemetry", QoS: 1, RemotePrefix: "edge/node1/"},
+						{Topic: "devices/+/command", Direction: v1alpha2.MqttBridgeDirectionIn, RemotePrefix: "edge/node1/"},
+					},
+				},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case10 mqtt bridge invalid",
+			input: v1alpha2.EventBus{
+				Enable:   true,
+				MqttMode: 2,
+				MqttBridge: &v1alpha2.EventBusMqttBridge{
+					Enable:       true,
+					RemoteServer: "http://broker.example.com",
+					BufferSize:   1000,
+					Topics: []v1alpha2.EventBusMqttBridgeTopic{
+						{Topic: "devices/#/command", Direction: "up", QoS: 3, LocalPrefix: "+/"},
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("MqttBridge").Child("Enable"), true,
+					"MqttBridge need the internal mqtt broker, Mode need to be 0 or 1"),
+				field.Invalid(field.NewPath("MqttBridge").Child("RemoteServer"), "http://broker.example.com",
+					`unsupported scheme "http"`),
+				field.Invalid(field.NewPath("MqttBridge").Child("Topics").Index(0).Child("Topic"), "devices/#/command",
+					"invalid mqtt topic filter"),
+				field.Invalid(field.NewPath("MqttBridge").Child("Topics").Index(0).Child("LocalPrefix"), "+/",
+					"wildcards are not allowed in topic prefix"),
+				field.NotSupported(field.NewPath("MqttBridge").Child("Topics").Index(0).Child("Direction"),
+					v1alpha2.MqttBridgeDirection("up"), []string{"out", "in", "both"}),
+				field.Invalid(field.NewPath("MqttBridge").Child("Topics").Index(0).Child("QoS"), uint8(3),
+					"QoS need in [0,2] range"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -272,6 +541,41 @@ func TestValidateModuleMetaManager(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 offline write valid",
+			input: v1alpha2.MetaManager{
+				Enable: true,
+				MetaServer: &v1alpha2.MetaServer{
+					OfflineWrite: &v1alpha2.MetaServerOfflineWrite{
+						Enable:           true,
+						Resources:        []string{"configmaps", "leases.coordination.k8s.io"},
+						MaxPendingWrites: 1000,
+						ReconcilePeriod:  10,
+					},
+				},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case4 offline write invalid",
+			input: v1alpha2.MetaManager{
+				Enable: true,
+				MetaServer: &v1alpha2.MetaServer{
+					OfflineWrite: &v1alpha2.MetaServerOfflineWrite{
+						Enable:    true,
+						Resources: []string{".coordination.k8s.io"},
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("Modules", "MetaManager", "MetaServer", "OfflineWrite", "Resources").Index(0),
+					".coordination.k8s.io", "must be in the format <resource>[.<group>]"),
+				field.Invalid(field.NewPath("Modules", "MetaManager", "MetaServer", "OfflineWrite", "MaxPendingWrites"),
+					int32(0), "must be greater than 0"),
+				field.Invalid(field.NewPath("Modules", "MetaManager", "MetaServer", "OfflineWrite", "ReconcilePeriod"),
+					int32(0), "must be greater than 0"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -301,6 +605,16 @@ func TestValidateModuleServiceBus(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 proxy port conflicts with port",
+			input: v1alpha2.ServiceBus{
+				Enable:    true,
+				Port:      9060,
+				ProxyPort: 9060,
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("ProxyPort"), 9060,
+				"ProxyPort must be different from Port")},
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeviceDiscoveryRuleSpec defines which discovered devices are provisioned
+// and how the Device objects are built for them.
+type DeviceDiscoveryRuleSpec struct {
+	// Required: Protocol is the protocol name of the discovered devices this rule applies to.
+	Protocol string `json:"protocol"`
+	// NodeNames restricts the rule to devices discovered on these nodes.
+	// The rule applies to all nodes if it is empty.
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
+	// MatchAttributes are the attributes the discovered device must report with equal values.
+	// +optional
+	MatchAttributes map[string]string `json:"matchAttributes,omitempty"`
+	// Required: DeviceModelRef is reference to the device model, in the namespace of the rule,
+	// which is used by the devices provisioned by this rule.
+	DeviceModelRef *v1.LocalObjectReference `json:"deviceModelRef"`
+	// AutoApprove provisions the matched devices without waiting for DiscoveredDevice.Spec.Approved.
+	// +optional
+	AutoApprove bool `json:"autoApprove,omitempty"`
+	// Template describes the devices provisioned by this rule.
+	// NodeName, Protocol and DeviceModelRef of the template spec are overridden
+	// by the discovered device and the rule.
+	// +optional
+	Template DeviceTemplateSpec `json:"template,omitempty"`
+}
+
+// DeviceTemplateSpec describes the Device created from a template.
+type DeviceTemplateSpec struct {
+	// Standard object's metadata, only labels and annotations are used.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec of the created device.
+	// +optional
+	Spec DeviceSpec `json:"spec,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceDiscoveryRule is the Schema for auto-provisioning discovered devices.
+// +k8s:openapi-gen=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=ddr
+// +kubebuilder:printcolumn:name="Protocol",type="string",JSONPath=".spec.protocol"
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".spec.deviceModelRef.name"
+// +kubebuilder:printcolumn:name="AutoApprove",type="boolean",JSONPath=".spec.autoApprove"
+type DeviceDiscoveryRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DeviceDiscoveryRuleSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceDiscoveryRuleList contains a list of DeviceDiscoveryRule
+type DeviceDiscoveryRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceDiscoveryRule `json:"items"`
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DiscoveredDeviceSpec describes a device found by a mapper on an edge node.
+type DiscoveredDeviceSpec struct {
+	// Required: NodeName is the name of the edge node where the device was discovered.
+	NodeName string `json:"nodeName"`
+	// Required: MapperName is the name of the mapper which reported the device.
+	MapperName string `json:"mapperName"`
+	// Required: DeviceID identifies the device reported by the mapper, it is unique
+	// for the protocol on the node, e.g. the serial number or the MAC address of the device.
+	DeviceID string `json:"deviceID"`
+	// Required: The protocol configuration used to connect to the device.
+	Protocol ProtocolConfig `json:"protocol"`
+	// Attributes describe the device, e.g. manufacturer, model and firmware version.
+	// They are matched against DeviceDiscoveryRule.Spec.MatchAttributes.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Approved requests devicecontroller to provision the device with the
+	// DeviceDiscoveryRule recorded in Status.MatchedRule.
+	// It is only required when the matched rule does not enable AutoApprove.
+	// +optional
+	Approved bool `json:"approved,omitempty"`
+}
+
+// DiscoveredDevicePhase is the provisioning phase of a discovered device.
+// +kubebuilder:validation:Enum=Pending;Matched;Provisioned;Failed
+type DiscoveredDevicePhase string
+
+const (
+	// DiscoveredDevicePending means no DeviceDiscoveryRule matches the discovered device.
+	DiscoveredDevicePending DiscoveredDevicePhase = "Pending"
+	// DiscoveredDeviceMatched means a DeviceDiscoveryRule matches the discovered device
+	// and the device is waiting to be approved.
+	DiscoveredDeviceMatched DiscoveredDevicePhase = "Matched"
+	// DiscoveredDeviceProvisioned means a Device has been created for the discovered device.
+	DiscoveredDeviceProvisioned DiscoveredDevicePhase = "Provisioned"
+	// DiscoveredDeviceFailed means the Device can not be created for the discovered device.
+	DiscoveredDeviceFailed DiscoveredDevicePhase = "Failed"
+)
+
+// DiscoveredDeviceStatus reports the provisioning state of a discovered device.
+type DiscoveredDeviceStatus struct {
+	// Phase is the provisioning phase of the discovered device.
+	// +optional
+	Phase DiscoveredDevicePhase `json:"phase,omitempty"`
+	// MatchedRule is the DeviceDiscoveryRule which matches the discovered device.
+	// +optional
+	MatchedRule *ObjectReference `json:"matchedRule,omitempty"`
+	// DeviceRef is the Device created for the discovered device.
+	// +optional
+	DeviceRef *ObjectReference `json:"deviceRef,omitempty"`
+	// LastSeenTime is the last time the device was reported by the mapper.
+	// +optional
+	LastSeenTime *metav1.Time `json:"lastSeenTime,omitempty"`
+	// Message is a human readable message indicating details about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ObjectReference references a namespaced object.
+type ObjectReference struct {
+	// Namespace of the referent.
+	Namespace string `json:"namespace"`
+	// Name of the referent.
+	Name string `json:"name"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DiscoveredDevice is a device which is found by a mapper but is not registered as a Device.
+// +k8s:openapi-gen=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=ddev
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName"
+// +kubebuilder:printcolumn:name="Protocol",type="string",JSONPath=".spec.protocol.protocolName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DiscoveredDevice struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DiscoveredDeviceSpec   `json:"spec,omitempty"`
+	Status DiscoveredDeviceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DiscoveredDeviceList contains a list of DiscoveredDevice
+type DiscoveredDeviceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DiscoveredDevice `json:"items"`
+}
@@ -69,6 +69,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DeviceModelList{},
 		&DeviceStatus{},
 		&DeviceStatusList{},
+		&DiscoveredDevice{},
+		&DiscoveredDeviceList{},
+		&DeviceDiscoveryRule{},
+		&DeviceDiscoveryRuleList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -84,6 +88,12 @@ func AddDeviceCrds(scheme *runtime.Scheme) error {
 	// Add DeviceStatus
 	scheme.AddKnownTypes(SchemeGroupVersion, &DeviceStatus{}, &DeviceStatusList{})
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	// Add DiscoveredDevice
+	scheme.AddKnownTypes(SchemeGroupVersion, &DiscoveredDevice{}, &DiscoveredDeviceList{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	// Add DeviceDiscoveryRule
+	scheme.AddKnownTypes(SchemeGroupVersion, &DeviceDiscoveryRule{}, &DeviceDiscoveryRuleList{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 
 	return nil
 }
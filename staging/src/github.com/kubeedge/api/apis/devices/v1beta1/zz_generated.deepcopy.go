@@ -179,6 +179,100 @@ func (in *Device) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceDiscoveryRule) DeepCopyInto(out *DeviceDiscoveryRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceDiscoveryRule.
+func (in *DeviceDiscoveryRule) DeepCopy() *DeviceDiscoveryRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceDiscoveryRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceDiscoveryRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceDiscoveryRuleList) DeepCopyInto(out *DeviceDiscoveryRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceDiscoveryRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceDiscoveryRuleList.
+func (in *DeviceDiscoveryRuleList) DeepCopy() *DeviceDiscoveryRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceDiscoveryRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceDiscoveryRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceDiscoveryRuleSpec) DeepCopyInto(out *DeviceDiscoveryRuleSpec) {
+	*out = *in
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchAttributes != nil {
+		in, out := &in.MatchAttributes, &out.MatchAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DeviceModelRef != nil {
+		in, out := &in.DeviceModelRef, &out.DeviceModelRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceDiscoveryRuleSpec.
+func (in *DeviceDiscoveryRuleSpec) DeepCopy() *DeviceDiscoveryRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceDiscoveryRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceList) DeepCopyInto(out *DeviceList) {
 	*out = *in
@@ -501,6 +595,139 @@ func (in *DeviceStatusStatus) DeepCopy() *DeviceStatusStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceTemplateSpec) DeepCopyInto(out *DeviceTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceTemplateSpec.
+func (in *DeviceTemplateSpec) DeepCopy() *DeviceTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredDevice) DeepCopyInto(out *DiscoveredDevice) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredDevice.
+func (in *DiscoveredDevice) DeepCopy() *DiscoveredDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiscoveredDevice) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredDeviceList) DeepCopyInto(out *DiscoveredDeviceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DiscoveredDevice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredDeviceList.
+func (in *DiscoveredDeviceList) DeepCopy() *DiscoveredDeviceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredDeviceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiscoveredDeviceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredDeviceSpec) DeepCopyInto(out *DiscoveredDeviceSpec) {
+	*out = *in
+	in.Protocol.DeepCopyInto(&out.Protocol)
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredDeviceSpec.
+func (in *DiscoveredDeviceSpec) DeepCopy() *DiscoveredDeviceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredDeviceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredDeviceStatus) DeepCopyInto(out *DiscoveredDeviceStatus) {
+	*out = *in
+	if in.MatchedRule != nil {
+		in, out := &in.MatchedRule, &out.MatchedRule
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	if in.DeviceRef != nil {
+		in, out := &in.DeviceRef, &out.DeviceRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	if in.LastSeenTime != nil {
+		in, out := &in.LastSeenTime, &out.LastSeenTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredDeviceStatus.
+func (in *DiscoveredDeviceStatus) DeepCopy() *DiscoveredDeviceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredDeviceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Influxdb2ClientConfig) DeepCopyInto(out *Influxdb2ClientConfig) {
 	*out = *in
@@ -577,6 +804,22 @@ func (in *MySQLClientConfig) DeepCopy() *MySQLClientConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtocolConfig) DeepCopyInto(out *ProtocolConfig) {
 	*out = *in
@@ -11,14 +11,123 @@ type RuleSpec struct {
 	Source string `json:"source"`
 	// SourceResource is a map representing the resource info of source. For rest
 	// ruleendpoint type its value is {"path":"/a/b"}. For eventbus ruleendpoint type its
-	// value is {"topic":"<user define string>","node_name":"xxxx"}
+	// value is {"topic":"<user define string>","node_name":"xxxx"}. For kafka ruleendpoint
+	// type its value is {"topic":"<kafka topic>","node_name":"xxxx"}. For nats ruleendpoint
+	// type its value is {"subject":"<nats subject>","node_name":"xxxx"}, with an optional
+	// "queue" to share the messages with other subscribers of the queue group. For servicebus
+	// ruleendpoint type its value is {"target_url":"/a/b","node_name":"xxxx"}, the edge
+	// applications call it at "/proxy/a/b" of edge servicebus, and "service_accounts" lists
+	// the allowed service accounts of pods as "<namespace>/<name>", "<namespace>/*" for all.
 	SourceResource map[string]string `json:"sourceResource"`
 	// Target represents where the messages go to. its value is the same with ruleendpoint name.
 	// For example, eventbus or api or servicebus.
 	Target string `json:"target"`
 	// targetResource is a map representing the resource info of target. For api
 	// ruleendpoint type its value is {"resource":"http://a.com"}. For eventbus ruleendpoint
-	// type its value is {"topic":"/xxxx"}. For servicebus ruleendpoint type its value is {"path":"/request_path"}.
+	// type its value is {"topic":"/xxxx"}. For servicebus ruleendpoint type its value is {"path":"/request_path"},
+	// with an optional "service" as the edge service <name>.<namespace> resolved by the cached Services and
+	// Endpoints on edge node, and an optional "port" which overrides the service_port of ruleendpoint.
+	// For kafka ruleendpoint type its value is {"topic":"<kafka topic>"}, with an optional "key"
+	// as the partition key of records. For nats ruleendpoint type its value is {"subject":"<nats subject>"}.
+	// The keys with "header." prefix of kafka and nats define the headers of messages, e.g.
+	// {"header.node":"{{.NodeName}}"}. The values of topic, subject, key and headers are go
+	// templates rendered with .MessageID, .NodeName, .Namespace and .Param.
+	TargetResource map[string]string `json:"targetResource"`
+	// Filter is an optional CEL expression evaluated for every message, the message is
+	// forwarded to target only when the expression is true. The variables available in the
+	// expression are topic (string), nodeName (string), headers (map(string, string)) and
+	// payload, which is the parsed value if the message is JSON, otherwise the raw string.
+	// For example: payload.temperature > 30.0 && headers["Content-Type"] == "application/json"
+	// +optional
+	Filter string `json:"filter,omitempty"`
+	// Transform reshapes the payload of messages before they are sent to target.
+	// +optional
+	Transform *RuleTransform `json:"transform,omitempty"`
+	// Delivery defines the retry policy and dead-letter target of the messages which fail to
+	// be delivered to target. The messages are delivered at most once without retry if it is not set.
+	// +optional
+	Delivery *RuleDelivery `json:"delivery,omitempty"`
+	// RateLimit limits the message throughput of rule, the messages over the limit are dropped.
+	// +optional
+	RateLimit *RuleRateLimit `json:"rateLimit,omitempty"`
+}
+
+// RuleRateLimit defines the rate limit of messages.
+type RuleRateLimit struct {
+	// QPS is the max count of messages per second.
+	QPS int32 `json:"qps"`
+	// Burst is the max count of messages in a burst, default is QPS.
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// RuleTransform defines how the JSON payload of messages is reshaped. The steps are
+// applied in order: extract, mapping and envelope.
+type RuleTransform struct {
+	// Extract is a JSON path of the payload, the value at the path replaces the payload.
+	// For example: $.data.items[0]
+	// +optional
+	Extract string `json:"extract,omitempty"`
+	// Mapping builds a new JSON object, whose keys are the field names and values are the
+	// JSON paths of payload. For example: {"temp":"$.data.temperature","unit":"$.data.unit"}
+	// +optional
+	Mapping map[string]string `json:"mapping,omitempty"`
+	// Envelope wraps the payload into a JSON object with the metadata of message.
+	// +optional
+	Envelope *RuleEnvelope `json:"envelope,omitempty"`
+}
+
+// RuleEnvelope defines the JSON object which the payload is wrapped into.
+type RuleEnvelope struct {
+	// PayloadField is the field name of payload in envelope, default is "data".
+	// +optional
+	PayloadField string `json:"payloadField,omitempty"`
+	// Fields are the other fields of envelope. The values are go templates rendered with
+	// .MessageID, .NodeName, .Namespace, .Param and .Topic, for example {"source":"{{.NodeName}}"}.
+	// +optional
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// RuleDeliveryMode defines the delivery guarantee of messages.
+type RuleDeliveryMode string
+
+// Rule delivery modes.
+const (
+	// RuleDeliveryAtMostOnce sends the message to eventbus target without waiting for the acknowledgement from edge.
+	RuleDeliveryAtMostOnce RuleDeliveryMode = "AtMostOnce"
+	// RuleDeliveryAtLeastOnce waits for the acknowledgement from the edge eventbus after the
+	// message is published to MQTT, and retries if it is not received in AckTimeout.
+	RuleDeliveryAtLeastOnce RuleDeliveryMode = "AtLeastOnce"
+)
+
+// RuleDelivery defines how the messages are retried and where they go if all the retries fail.
+type RuleDelivery struct {
+	// Mode is the delivery guarantee of messages: AtMostOnce or AtLeastOnce, default is AtMostOnce.
+	// It only makes difference for eventbus target, the other targets are acknowledged synchronously.
+	// +optional
+	Mode RuleDeliveryMode `json:"mode,omitempty"`
+	// MaxRetries is the max times to retry a message after the first delivery fails, default is 0.
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+	// InitialBackoff is the wait time before the first retry, it doubles for every retry. Default is 1s.
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// MaxBackoff is the upper bound of the wait time between retries. Default is 30s.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+	// AckTimeout is the time to wait for the acknowledgement from edge in AtLeastOnce mode. Default is 10s.
+	// +optional
+	AckTimeout *metav1.Duration `json:"ackTimeout,omitempty"`
+	// DeadLetter is the target where the messages go after all the retries fail.
+	// +optional
+	DeadLetter *RuleDeadLetter `json:"deadLetter,omitempty"`
+}
+
+// RuleDeadLetter defines the dead-letter target of rule.
+type RuleDeadLetter struct {
+	// Target is the name of ruleendpoint which the messages are sent to, it should be in the same namespace with rule.
+	Target string `json:"target"`
+	// TargetResource is the resource info of dead-letter target, the same as RuleSpec.TargetResource.
 	TargetResource map[string]string `json:"targetResource"`
 }
 
@@ -30,6 +139,18 @@ type RuleStatus struct {
 	FailMessages int64 `json:"failMessages"`
 	// Errors represents failed reasons of message delivery of rule.
 	Errors []string `json:"errors"`
+	// LastErrorTime is the time of the last failed message delivery.
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// DeadLetterMessages represents the count of messages sent to the dead-letter target.
+	// +optional
+	DeadLetterMessages int64 `json:"deadLetterMessages,omitempty"`
+	// ThrottledMessages represents the count of messages dropped by rate limits.
+	// +optional
+	ThrottledMessages int64 `json:"throttledMessages,omitempty"`
+	// LastThrottledTime is the time of the last message dropped by rate limits.
+	// +optional
+	LastThrottledTime *metav1.Time `json:"lastThrottledTime,omitempty"`
 }
 
 // +genclient
@@ -54,12 +175,27 @@ type RuleList struct {
 
 // RuleEndpointSpec defines endpoint of rule.
 type RuleEndpointSpec struct {
-	// RuleEndpointType defines type: servicebus, rest
+	// RuleEndpointType defines type: servicebus, rest, eventbus, kafka, nats
 	RuleEndpointType RuleEndpointTypeDef `json:"ruleEndpointType"`
 	// Properties: properties of endpoint. for example:
 	// servicebus:
-	// {"service_port":"8080"}
+	// {"service_port":"8080","scheme":"https","tls_ca":"<PEM>","allowed_targets":"web.default,api.default:443"}
+	// The requests are sent to 127.0.0.1 on edge node unless the rule defines an edge service in
+	// targetResource, which must be one of allowed_targets in the format of <name>.<namespace>[:<port>].
+	// The request and response bodies larger than a message are streamed between cloud and edge,
+	// they are limited by max_body_size, 1Gi by default.
+	// kafka:
+	// {"brokers":"kafka-0:9092,kafka-1:9092","acks":"all","version":"3.6.0","sasl_mechanism":"PLAIN",
+	// "credentials_secret":"kafka-credentials","tls":"true","tls_ca":"<PEM>"}
+	// nats:
+	// {"servers":"nats://nats:4222","credentials_secret":"nats-credentials","tls":"true"}
+	// credentials_secret is a secret in the namespace of the endpoint, whose "username" and
+	// "password" keys (or "token" for nats) are used to authenticate with the servers.
 	Properties map[string]string `json:"properties,omitempty"`
+	// RateLimit limits the total throughput of the messages sent to this ruleendpoint as target
+	// by all the rules, the messages over the limit are dropped.
+	// +optional
+	RateLimit *RuleRateLimit `json:"rateLimit,omitempty"`
 }
 
 // RuleEndpointTypeDef defines ruleEndpoint's type
@@ -70,6 +206,8 @@ const (
 	RuleEndpointTypeRest       RuleEndpointTypeDef = "rest"
 	RuleEndpointTypeEventBus   RuleEndpointTypeDef = "eventbus"
 	RuleEndpointTypeServiceBus RuleEndpointTypeDef = "servicebus"
+	RuleEndpointTypeKafka      RuleEndpointTypeDef = "kafka"
+	RuleEndpointTypeNATS       RuleEndpointTypeDef = "nats"
 )
 
 // +genclient
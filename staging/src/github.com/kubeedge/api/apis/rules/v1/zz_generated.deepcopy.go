@@ -22,6 +22,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -53,6 +54,65 @@ func (in *Rule) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleDeadLetter) DeepCopyInto(out *RuleDeadLetter) {
+	*out = *in
+	if in.TargetResource != nil {
+		in, out := &in.TargetResource, &out.TargetResource
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleDeadLetter.
+func (in *RuleDeadLetter) DeepCopy() *RuleDeadLetter {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleDeadLetter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleDelivery) DeepCopyInto(out *RuleDelivery) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AckTimeout != nil {
+		in, out := &in.AckTimeout, &out.AckTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DeadLetter != nil {
+		in, out := &in.DeadLetter, &out.DeadLetter
+		*out = new(RuleDeadLetter)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleDelivery.
+func (in *RuleDelivery) DeepCopy() *RuleDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuleEndpoint) DeepCopyInto(out *RuleEndpoint) {
 	*out = *in
@@ -123,6 +183,11 @@ func (in *RuleEndpointSpec) DeepCopyInto(out *RuleEndpointSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RuleRateLimit)
+		**out = **in
+	}
 	return
 }
 
@@ -136,6 +201,29 @@ func (in *RuleEndpointSpec) DeepCopy() *RuleEndpointSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleEnvelope) DeepCopyInto(out *RuleEnvelope) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleEnvelope.
+func (in *RuleEnvelope) DeepCopy() *RuleEnvelope {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleEnvelope)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuleList) DeepCopyInto(out *RuleList) {
 	*out = *in
@@ -169,6 +257,22 @@ func (in *RuleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleRateLimit) DeepCopyInto(out *RuleRateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleRateLimit.
+func (in *RuleRateLimit) DeepCopy() *RuleRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuleSpec) DeepCopyInto(out *RuleSpec) {
 	*out = *in
@@ -186,6 +290,21 @@ func (in *RuleSpec) DeepCopyInto(out *RuleSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = new(RuleTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		*out = new(RuleDelivery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RuleRateLimit)
+		**out = **in
+	}
 	return
 }
 
@@ -207,6 +326,14 @@ func (in *RuleStatus) DeepCopyInto(out *RuleStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastThrottledTime != nil {
+		in, out := &in.LastThrottledTime, &out.LastThrottledTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -219,3 +346,31 @@ func (in *RuleStatus) DeepCopy() *RuleStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTransform) DeepCopyInto(out *RuleTransform) {
+	*out = *in
+	if in.Mapping != nil {
+		in, out := &in.Mapping, &out.Mapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Envelope != nil {
+		in, out := &in.Envelope, &out.Envelope
+		*out = new(RuleEnvelope)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTransform.
+func (in *RuleTransform) DeepCopy() *RuleTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTransform)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -29,8 +29,10 @@ import (
 type DevicesV1beta1Interface interface {
 	RESTClient() rest.Interface
 	DevicesGetter
+	DeviceDiscoveryRulesGetter
 	DeviceModelsGetter
 	DeviceStatusesGetter
+	DiscoveredDevicesGetter
 }
 
 // DevicesV1beta1Client is used to interact with features provided by the devices group.
@@ -42,6 +44,10 @@ func (c *DevicesV1beta1Client) Devices(namespace string) DeviceInterface {
 	return newDevices(c, namespace)
 }
 
+func (c *DevicesV1beta1Client) DeviceDiscoveryRules(namespace string) DeviceDiscoveryRuleInterface {
+	return newDeviceDiscoveryRules(c, namespace)
+}
+
 func (c *DevicesV1beta1Client) DeviceModels(namespace string) DeviceModelInterface {
 	return newDeviceModels(c, namespace)
 }
@@ -50,6 +56,10 @@ func (c *DevicesV1beta1Client) DeviceStatuses(namespace string) DeviceStatusInte
 	return newDeviceStatuses(c, namespace)
 }
 
+func (c *DevicesV1beta1Client) DiscoveredDevices() DiscoveredDeviceInterface {
+	return newDiscoveredDevices(c)
+}
+
 // NewForConfig creates a new DevicesV1beta1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
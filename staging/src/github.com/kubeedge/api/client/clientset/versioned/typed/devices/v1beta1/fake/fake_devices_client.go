@@ -32,6 +32,10 @@ func (c *FakeDevicesV1beta1) Devices(namespace string) v1beta1.DeviceInterface {
 	return newFakeDevices(c, namespace)
 }
 
+func (c *FakeDevicesV1beta1) DeviceDiscoveryRules(namespace string) v1beta1.DeviceDiscoveryRuleInterface {
+	return newFakeDeviceDiscoveryRules(c, namespace)
+}
+
 func (c *FakeDevicesV1beta1) DeviceModels(namespace string) v1beta1.DeviceModelInterface {
 	return newFakeDeviceModels(c, namespace)
 }
@@ -40,6 +44,10 @@ func (c *FakeDevicesV1beta1) DeviceStatuses(namespace string) v1beta1.DeviceStat
 	return newFakeDeviceStatuses(c, namespace)
 }
 
+func (c *FakeDevicesV1beta1) DiscoveredDevices() v1beta1.DiscoveredDeviceInterface {
+	return newFakeDiscoveredDevices(c)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeDevicesV1beta1) RESTClient() rest.Interface {
@@ -20,6 +20,10 @@ package v1beta1
 
 type DeviceExpansion interface{}
 
+type DeviceDiscoveryRuleExpansion interface{}
+
 type DeviceModelExpansion interface{}
 
 type DeviceStatusExpansion interface{}
+
+type DiscoveredDeviceExpansion interface{}
@@ -26,10 +26,14 @@ import (
 type Interface interface {
 	// Devices returns a DeviceInformer.
 	Devices() DeviceInformer
+	// DeviceDiscoveryRules returns a DeviceDiscoveryRuleInformer.
+	DeviceDiscoveryRules() DeviceDiscoveryRuleInformer
 	// DeviceModels returns a DeviceModelInformer.
 	DeviceModels() DeviceModelInformer
 	// DeviceStatuses returns a DeviceStatusInformer.
 	DeviceStatuses() DeviceStatusInformer
+	// DiscoveredDevices returns a DiscoveredDeviceInformer.
+	DiscoveredDevices() DiscoveredDeviceInformer
 }
 
 type version struct {
@@ -48,6 +52,11 @@ func (v *version) Devices() DeviceInformer {
 	return &deviceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// DeviceDiscoveryRules returns a DeviceDiscoveryRuleInformer.
+func (v *version) DeviceDiscoveryRules() DeviceDiscoveryRuleInformer {
+	return &deviceDiscoveryRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // DeviceModels returns a DeviceModelInformer.
 func (v *version) DeviceModels() DeviceModelInformer {
 	return &deviceModelInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -57,3 +66,8 @@ func (v *version) DeviceModels() DeviceModelInformer {
 func (v *version) DeviceStatuses() DeviceStatusInformer {
 	return &deviceStatusInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// DiscoveredDevices returns a DiscoveredDeviceInformer.
+func (v *version) DiscoveredDevices() DiscoveredDeviceInformer {
+	return &discoveredDeviceInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
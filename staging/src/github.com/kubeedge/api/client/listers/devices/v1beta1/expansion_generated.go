@@ -26,6 +26,14 @@ type DeviceListerExpansion interface{}
 // DeviceNamespaceLister.
 type DeviceNamespaceListerExpansion interface{}
 
+// DeviceDiscoveryRuleListerExpansion allows custom methods to be added to
+// DeviceDiscoveryRuleLister.
+type DeviceDiscoveryRuleListerExpansion interface{}
+
+// DeviceDiscoveryRuleNamespaceListerExpansion allows custom methods to be added to
+// DeviceDiscoveryRuleNamespaceLister.
+type DeviceDiscoveryRuleNamespaceListerExpansion interface{}
+
 // DeviceModelListerExpansion allows custom methods to be added to
 // DeviceModelLister.
 type DeviceModelListerExpansion interface{}
@@ -41,3 +49,7 @@ type DeviceStatusListerExpansion interface{}
 // DeviceStatusNamespaceListerExpansion allows custom methods to be added to
 // DeviceStatusNamespaceLister.
 type DeviceStatusNamespaceListerExpansion interface{}
+
+// DiscoveredDeviceListerExpansion allows custom methods to be added to
+// DiscoveredDeviceLister.
+type DiscoveredDeviceListerExpansion interface{}
@@ -83,6 +83,8 @@ type MessageHeader struct {
 	// message type indicates the context type that delivers the message, such as channel, unixsocket, etc.
 	// if the value is empty, the channel context type will be used.
 	MessageType string `json:"type,omitempty"`
+	// user defined properties of the message, such as the user properties of MQTT 5 messages
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 // BuildRouter sets route and resource operation in message
@@ -134,6 +136,17 @@ func (msg *Message) SetResourceVersion(resourceVersion string) *Message {
 	return msg
 }
 
+// SetProperties sets user defined properties in message header
+func (msg *Message) SetProperties(properties map[string]string) *Message {
+	msg.Header.Properties = properties
+	return msg
+}
+
+// GetProperties returns user defined properties in message header
+func (msg *Message) GetProperties() map[string]string {
+	return msg.Header.Properties
+}
+
 // IsSync : msg.Header.Sync will be set in sendsync
 func (msg *Message) IsSync() bool {
 	return msg.Header.Sync
@@ -243,6 +256,7 @@ func (msg *Message) Clone(message *Message) *Message {
 	msgID := uuid.New().String()
 	return NewRawMessage().BuildHeader(msgID, message.GetParentID(), message.GetTimestamp()).
 		BuildRouter(message.GetSource(), message.GetGroup(), message.GetResource(), message.GetOperation()).
+		SetProperties(message.GetProperties()).
 		FillBody(message.GetContent())
 }
 